@@ -271,6 +271,10 @@ var JSONRemove = v1.JSONRemove
 
 var JSONPatch = v1.JSONPatch
 
+var JSONPathQuery = v1.JSONPathQuery
+
+var JMESPathQuery = v1.JMESPathQuery
+
 var ObjectSubset = v1.ObjectSubset
 
 var ObjectUnion = v1.ObjectUnion
@@ -324,6 +328,26 @@ var YAMLUnmarshal = v1.YAMLUnmarshal
 // YAMLIsValid verifies the input string is a valid YAML document.
 var YAMLIsValid = v1.YAMLIsValid
 
+var TOMLUnmarshal = v1.TOMLUnmarshal
+
+var TOMLIsValid = v1.TOMLIsValid
+
+var XMLUnmarshal = v1.XMLUnmarshal
+
+var XMLIsValid = v1.XMLIsValid
+
+var CSVParse = v1.CSVParse
+
+var CSVIsValid = v1.CSVIsValid
+
+var HCLUnmarshal = v1.HCLUnmarshal
+
+var HCLIsValid = v1.HCLIsValid
+
+var INIUnmarshal = v1.INIUnmarshal
+
+var INIIsValid = v1.INIIsValid
+
 var HexEncode = v1.HexEncode
 
 var HexDecode = v1.HexDecode
@@ -367,6 +391,14 @@ var JWTEncodeSignRaw = v1.JWTEncodeSignRaw
 // Marked non-deterministic because it relies on RNG internally.
 var JWTEncodeSign = v1.JWTEncodeSign
 
+var JWEDecrypt = v1.JWEDecrypt
+
+var JWKParse = v1.JWKParse
+
+var JWKThumbprint = v1.JWKThumbprint
+
+var JWKSSelect = v1.JWKSSelect
+
 /**
  * Time
  */
@@ -392,6 +424,14 @@ var AddDate = v1.AddDate
 
 var Diff = v1.Diff
 
+var CronMatch = v1.CronMatch
+
+var NextCron = v1.NextCron
+
+var InWindow = v1.InWindow
+
+var Truncate = v1.Truncate
+
 /**
  * Crypto.
  */
@@ -415,6 +455,16 @@ var CryptoSha1 = v1.CryptoSha1
 
 var CryptoSha256 = v1.CryptoSha256
 
+var CryptoSha512 = v1.CryptoSha512
+
+var CryptoSha3256 = v1.CryptoSha3256
+
+var CryptoSha3512 = v1.CryptoSha3512
+
+var CryptoBlake2b256 = v1.CryptoBlake2b256
+
+var CryptoBlake2b512 = v1.CryptoBlake2b512
+
 var CryptoHmacMd5 = v1.CryptoHmacMd5
 
 var CryptoHmacSha1 = v1.CryptoHmacSha1
@@ -425,6 +475,8 @@ var CryptoHmacSha512 = v1.CryptoHmacSha512
 
 var CryptoHmacEqual = v1.CryptoHmacEqual
 
+var CryptoVerify = v1.CryptoVerify
+
 /**
  * Graphs.
  */
@@ -435,6 +487,16 @@ var ReachableBuiltin = v1.ReachableBuiltin
 
 var ReachablePathsBuiltin = v1.ReachablePathsBuiltin
 
+var GraphTopologicalSort = v1.GraphTopologicalSort
+
+var GraphHasCycle = v1.GraphHasCycle
+
+var GraphCycles = v1.GraphCycles
+
+var GraphShortestPath = v1.GraphShortestPath
+
+var GraphSCC = v1.GraphSCC
+
 /**
  * Type
  */
@@ -493,6 +555,13 @@ var GraphQLIsValid = v1.GraphQLIsValid
 // and returns false for all other inputs.
 var GraphQLSchemaIsValid = v1.GraphQLSchemaIsValid
 
+// SQLParse returns the syntax tree of a SQL statement.
+var SQLParse = v1.SQLParse
+
+// SQLIsValid returns true if the input is a SQL statement that sql.parse
+// accepts, and returns false for all other inputs.
+var SQLIsValid = v1.SQLIsValid
+
 /**
  * JSON Schema
  */
@@ -529,6 +598,10 @@ var RegoMetadataRule = v1.RegoMetadataRule
 // Marked non-deterministic because of unpredictable config/environment-dependent results.
 var OPARuntime = v1.OPARuntime
 
+// Marked non-deterministic because the annotations are a side effect of the
+// evaluation, which must not be evaluated during partial evaluation.
+var OPADecisionLogAnnotate = v1.OPADecisionLogAnnotate
+
 /**
  * Trace
  */
@@ -555,6 +628,8 @@ var NetCIDRContains = v1.NetCIDRContains
 
 var NetCIDRContainsMatches = v1.NetCIDRContainsMatches
 
+var NetCIDRLookup = v1.NetCIDRLookup
+
 var NetCIDRMerge = v1.NetCIDRMerge
 
 var NetCIDRIsValid = v1.NetCIDRIsValid
@@ -562,6 +637,14 @@ var NetCIDRIsValid = v1.NetCIDRIsValid
 // Marked non-deterministic because DNS resolution results can be non-deterministic.
 var NetLookupIPAddr = v1.NetLookupIPAddr
 
+var NetLookupTXT = v1.NetLookupTXT
+
+var NetLookupSRV = v1.NetLookupSRV
+
+var NetLookupMX = v1.NetLookupMX
+
+var NetLookupCNAME = v1.NetLookupCNAME
+
 /**
  * Semantic Versions
  */
@@ -570,6 +653,10 @@ var SemVerIsValid = v1.SemVerIsValid
 
 var SemVerCompare = v1.SemVerCompare
 
+var SemVerSatisfies = v1.SemVerSatisfies
+
+var SemVerMaxSatisfying = v1.SemVerMaxSatisfying
+
 /**
  * Printing
  */
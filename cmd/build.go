@@ -50,6 +50,8 @@ type (
 		v0Compatible       bool
 		v1Compatible       bool
 		followSymlinks     bool
+		precompile         bool
+		compression        *util.EnumFlag
 		wasmIncludePrint   bool
 		planAddons         []string
 		stderr             io.Writer
@@ -77,6 +79,7 @@ func newBuildParams() buildParams {
 		capabilities: newCapabilitiesFlag(),
 		target:       util.NewEnumFlag(compile.TargetRego, compile.Targets),
 		planFormat:   util.NewEnumFlag(compile.PlanFormatJSON, compile.PlanFormats),
+		compression:  util.NewEnumFlag(bundle.CompressionGzip, bundle.Compressions),
 		stderr:       os.Stderr,
 	}
 }
@@ -170,6 +173,17 @@ ensure that document is not eliminated by the optimizer.
 Note: Unless the --prune-unused flag is used, any rule transitively referring to a
 package or rule declared as an entrypoint will also be enumerated as an entrypoint.
 
+The --precompile flag includes the compiled policies in the bundle, so that ` + brand + ` can
+activate the bundle without parsing and compiling the policy files. The compiled
+policies are only used by ` + brand + ` instances of the same version and with the same
+capabilities as the 'build' command; other instances compile the policy files as usual.
+The compiled policies keep print statements, which are enabled by default. Instances that
+disable print statements, e.g. with '--log-level=error', compile the policy files as well.
+
+The --compression flag sets the compression of the output bundle archive. Zstandard
+compressed bundles are faster to decompress than gzip compressed ones, which shortens
+the activation of large bundles. ` + brand + ` detects the compression of bundles it reads.
+
 Signing
 -------
 
@@ -283,6 +297,8 @@ against ` + brand + ` v0.22.0:
 	buildCommand.Flags().StringVarP(&buildParams.outputFile, "output", "o", "bundle.tar.gz", "set the output filename")
 	buildCommand.Flags().StringVar(&buildParams.ns, "partial-namespace", "partial", "set the namespace to use for partially evaluated files in an optimized bundle")
 	buildCommand.Flags().BoolVar(&buildParams.followSymlinks, "follow-symlinks", false, "follow symlinks in the input set of paths when building the bundle")
+	buildCommand.Flags().BoolVar(&buildParams.precompile, "precompile", false, "include precompiled policies in the output bundle")
+	buildCommand.Flags().Var(buildParams.compression, "compression", "set the compression of the output bundle archive")
 	buildCommand.Flags().BoolVar(&buildParams.wasmIncludePrint, "wasm-include-print", false, "enable print statements inside of WebAssembly modules compiled by the compiler")
 	buildCommand.Flags().StringArrayVar(&buildParams.planAddons, "plan-addons", []string{}, "include optional extra data in the plan; supported value: unplanned_rules (requires --target=plan)")
 
@@ -364,6 +380,8 @@ func dobuild(params buildParams, args []string) error {
 		WithBundleSigningConfig(bsc).
 		WithPartialNamespace(params.ns).
 		WithFollowSymlinks(params.followSymlinks).
+		WithPrecompile(params.precompile).
+		WithCompression(params.compression.String()).
 		WithPlanAddons(params.planAddons)
 
 	compiler = compiler.WithRegoVersion(params.regoVersion())
@@ -384,6 +402,10 @@ func dobuild(params buildParams, args []string) error {
 		compiler = compiler.WithEnablePrintStatements(true)
 	}
 
+	if params.target.String() == compile.TargetRego && params.precompile {
+		compiler = compiler.WithEnablePrintStatements(true)
+	}
+
 	if params.target.String() == compile.TargetWasm {
 		compiler = compiler.WithEnablePrintStatements(params.wasmIncludePrint)
 	}
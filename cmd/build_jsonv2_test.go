@@ -22,6 +22,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/open-policy-agent/opa/internal/file/archive"
 	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/bundle"
 	"github.com/open-policy-agent/opa/v1/loader"
 	"github.com/open-policy-agent/opa/v1/util"
 	"github.com/open-policy-agent/opa/v1/util/test"
@@ -79,6 +80,68 @@ func TestBuildProducesBundle(t *testing.T) {
 	})
 }
 
+func TestBuildPrecompile(t *testing.T) {
+	files := map[string]string{
+		"test.rego": `package test
+
+p if input.x == 1
+`,
+	}
+
+	test.WithTempFS(files, func(root string) {
+		params := newBuildParams()
+		params.outputFile = path.Join(root, "bundle.tar.gz")
+		params.precompile = true
+
+		if err := dobuild(params, []string{root}); err != nil {
+			t.Fatal(err)
+		}
+
+		b, err := loader.NewFileLoader().WithProcessAnnotation(true).AsBundle(params.outputFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(b.Precompiled) == 0 || !b.IsPrecompiled() {
+			t.Fatal("expected bundle to be loaded from precompiled modules")
+		}
+	})
+}
+
+func TestBuildCompression(t *testing.T) {
+	files := map[string]string{
+		"test.rego": "package test\n\np if input.x == 1\n",
+	}
+
+	test.WithTempFS(files, func(root string) {
+		params := newBuildParams()
+		params.outputFile = path.Join(root, "bundle.tar.zst")
+		if err := params.compression.Set(bundle.CompressionZstd); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := dobuild(params, []string{root}); err != nil {
+			t.Fatal(err)
+		}
+
+		bs, err := os.ReadFile(params.outputFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.HasPrefix(bs, []byte{0x28, 0xb5, 0x2f, 0xfd}) {
+			t.Fatal("expected zstd compressed bundle")
+		}
+
+		b, err := loader.NewFileLoader().AsBundle(params.outputFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(b.Modules) != 1 {
+			t.Fatalf("expected 1 module but got %d", len(b.Modules))
+		}
+	})
+}
+
 func TestBuildRespectsCapabilities(t *testing.T) {
 	tests := []struct {
 		note       string
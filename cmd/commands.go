@@ -36,6 +36,8 @@ func Command(rootCommand *cobra.Command, brand string) *cobra.Command {
 	initBuild(rootCommand, brand)
 	initCapabilities(rootCommand, brand)
 	initCheck(rootCommand, brand)
+	initDecision(rootCommand, brand)
+	initDecrypt(rootCommand, brand)
 	initDeps(rootCommand, brand)
 	initEval(rootCommand, brand)
 	initExec(rootCommand, brand)
@@ -44,6 +46,7 @@ func Command(rootCommand *cobra.Command, brand string) *cobra.Command {
 	initOracle(rootCommand, brand)
 	initParse(rootCommand, brand)
 	initRefactor(rootCommand, brand)
+	initReplay(rootCommand, brand)
 	initRun(rootCommand, brand)
 	initSign(rootCommand, brand)
 	initTest(rootCommand, brand)
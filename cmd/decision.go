@@ -0,0 +1,309 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/open-policy-agent/opa/cmd/formats"
+	"github.com/open-policy-agent/opa/cmd/internal/env"
+	"github.com/open-policy-agent/opa/internal/presentation"
+	"github.com/open-policy-agent/opa/v1/server/types"
+	"github.com/open-policy-agent/opa/v1/util"
+)
+
+const defaultDecisionServer = "http://localhost:8181"
+
+type decisionCommandParams struct {
+	server       string
+	token        string
+	explain      *util.EnumFlag
+	outputFormat *util.EnumFlag
+	stdout       io.Writer
+	stderr       io.Writer
+	client       *http.Client
+}
+
+func newDecisionCommandParams() decisionCommandParams {
+	return decisionCommandParams{
+		server:       defaultDecisionServer,
+		explain:      newExplainFlag([]string{explainModeFull, explainModeNotes, explainModeFails, explainModeDebug}),
+		outputFormat: formats.Flag(formats.Pretty, formats.JSON),
+		stdout:       os.Stdout,
+		stderr:       os.Stderr,
+		client:       http.DefaultClient,
+	}
+}
+
+func initDecision(root *cobra.Command, brand string) {
+	executable := root.Name()
+
+	params := newDecisionCommandParams()
+
+	decisionCommand := &cobra.Command{
+		Use:   "decision <decision_id>",
+		Short: "Explain a recent decision of a running server",
+		Long: `Explain a recent decision of a running ` + brand + ` server.
+
+The 'decision' command fetches the decision with the given ID from the recent
+decisions kept by the server (see the 'decision_logs.recent_decisions'
+configuration option), and evaluates it again against the current policy of the
+server, with the recorded input and explanations enabled. It prints the recorded
+decision, the current result and the explanation.
+
+The recorded decision is the decision log event, after the mask, drop and sample
+decisions were applied. If its input was erased or masked, the decision is
+evaluated with the masked input, and the result may differ from the recorded one.
+
+The server is accessed with the bearer token set by --token, if any.
+`,
+		Example: `
+Explain the decision a user was denied by:
+
+	$ ` + executable + ` decision --server https://opa.example.com:8181 --token "$TOKEN" 4ca636c1-55e4-417a-b1d8-4aceb67960d1
+`,
+		Args: cobra.ExactArgs(1),
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			return env.CmdFlags.CheckEnvironmentVariables(cmd)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+
+			if err := doDecision(cmd.Context(), args[0], params); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return newExitErrorWrap(1, err)
+			}
+			return nil
+		},
+	}
+
+	decisionCommand.Flags().StringVarP(&params.server, "server", "s", params.server, "set the address of the server")
+	decisionCommand.Flags().StringVar(&params.token, "token", "", "set the bearer token to authenticate with the server")
+	setExplainFlag(decisionCommand.Flags(), params.explain)
+	addOutputFormat(decisionCommand.Flags(), params.outputFormat)
+
+	root.AddCommand(decisionCommand)
+}
+
+// decisionReport is the outcome of the evaluation of a recorded decision
+// against the current policy.
+type decisionReport struct {
+	Decision    map[string]any `json:"decision"`
+	Result      *any           `json:"result,omitempty"`
+	Changed     bool           `json:"changed"`
+	Explanation []string       `json:"explanation,omitempty"`
+}
+
+// doDecision fetches the decision from the server and evaluates it again with
+// explanations enabled.
+func doDecision(ctx context.Context, decisionID string, params decisionCommandParams) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	decision, err := fetchDecision(ctx, decisionID, params)
+	if err != nil {
+		return err
+	}
+
+	for _, field := range []string{"erased", "masked"} {
+		if pointers, ok := decision[field].([]any); ok && len(pointers) > 0 {
+			fmt.Fprintf(params.stderr, "warning: decision %v fields %v, evaluating with the logged input\n", field, pointers)
+		}
+	}
+
+	report, err := explainDecision(ctx, decision, params)
+	if err != nil {
+		return err
+	}
+
+	if params.outputFormat.String() == formats.JSON {
+		return presentation.JSON(params.stdout, report)
+	}
+	return report.Pretty(params.stdout)
+}
+
+func fetchDecision(ctx context.Context, decisionID string, params decisionCommandParams) (map[string]any, error) {
+	query := url.Values{types.ParamDecisionIDV1: {decisionID}}
+
+	var response types.DecisionsResponseV1
+	if err := decisionRequest(ctx, http.MethodGet, "/v1/decisions?"+query.Encode(), nil, &response, params); err != nil {
+		return nil, err
+	}
+	if len(response.Result) == 0 {
+		return nil, fmt.Errorf("decision %q not found", decisionID)
+	}
+
+	decision, ok := response.Result[0].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("decision %q: unexpected type %T", decisionID, response.Result[0])
+	}
+	return decision, nil
+}
+
+// explainDecision evaluates the decision, which was made either by the Data
+// API or by the Query API, with the same API.
+func explainDecision(ctx context.Context, decision map[string]any, params decisionCommandParams) (*decisionReport, error) {
+	report := &decisionReport{Decision: decision}
+	input := decision["input"]
+	query := url.Values{
+		types.ParamExplainV1: {params.explain.String()},
+		types.ParamPrettyV1:  {"true"},
+	}
+
+	var explanation types.TraceV1Pretty
+	var err error
+	if path, ok := decision["path"].(string); ok && path != "" {
+		var response types.DataResponseV1
+		request := map[string]any{}
+		if input != nil {
+			request["input"] = input
+		}
+		err = decisionRequest(ctx, http.MethodPost, "/v1/data/"+strings.Trim(path, "/")+"?"+query.Encode(), request, &response, params)
+		if err == nil {
+			report.Result = response.Result
+			err = unmarshalExplanation(response.Explanation, &explanation)
+		}
+	} else if q, ok := decision["query"].(string); ok && q != "" {
+		var response types.QueryResponseV1
+		err = decisionRequest(ctx, http.MethodPost, "/v1/query?"+query.Encode(), map[string]any{"query": q, "input": input}, &response, params)
+		if err == nil {
+			var result any = response.Result
+			if response.Result == nil {
+				result = []any{}
+			}
+			report.Result = &result
+			err = unmarshalExplanation(response.Explanation, &explanation)
+		}
+	} else {
+		return nil, errors.New("decision has neither a path nor a query")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	report.Explanation = explanation
+	report.Changed = changedResult(decision["result"], report.Result)
+	return report, nil
+}
+
+func unmarshalExplanation(trace types.TraceV1, explanation *types.TraceV1Pretty) error {
+	if len(trace) == 0 {
+		return nil
+	}
+	return util.Unmarshal(trace, explanation)
+}
+
+func changedResult(recorded any, current *any) bool {
+	if current == nil {
+		return recorded != nil
+	}
+	// Round trip, so that both results are compared as decoded JSON values.
+	v := *current
+	if err := util.RoundTrip(&v); err != nil {
+		return true
+	}
+	return !reflect.DeepEqual(recorded, v)
+}
+
+func decisionRequest(ctx context.Context, method string, path string, body any, response any, params decisionCommandParams) error {
+	var reader io.Reader
+	if body != nil {
+		bs, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(bs)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(params.server, "/")+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if params.token != "" {
+		req.Header.Set("Authorization", "Bearer "+params.token)
+	}
+
+	resp, err := params.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	bs, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr types.ErrorV1
+		if err := json.Unmarshal(bs, &apiErr); err == nil && apiErr.Message != "" {
+			return fmt.Errorf("%v %v: %v", method, req.URL.Path, apiErr.Message)
+		}
+		return fmt.Errorf("%v %v: %v", method, req.URL.Path, resp.Status)
+	}
+
+	return util.Unmarshal(bs, response)
+}
+
+// Pretty writes the report in a human readable format.
+func (r *decisionReport) Pretty(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Decision:  %v\n", r.Decision["decision_id"])
+	for _, field := range []string{"path", "query", "timestamp"} {
+		if v, ok := r.Decision[field]; ok {
+			fmt.Fprintf(&b, "%-10s %v\n", strings.ToUpper(field[:1])+field[1:]+":", v)
+		}
+	}
+	fmt.Fprintf(&b, "Input:     %s\n", prettyDecisionValue(r.Decision["input"]))
+	fmt.Fprintf(&b, "Recorded:  %s\n", prettyDecisionValue(r.Decision["result"]))
+
+	var current any
+	if r.Result != nil {
+		current = *r.Result
+	}
+	fmt.Fprintf(&b, "Current:   %s", prettyDecisionValue(current))
+	if r.Changed {
+		b.WriteString(" (changed)")
+	}
+	b.WriteString("\n")
+
+	if len(r.Explanation) > 0 {
+		b.WriteString("\nExplanation:\n")
+		for _, line := range r.Explanation {
+			b.WriteString(line)
+			b.WriteString("\n")
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func prettyDecisionValue(x any) string {
+	if x == nil {
+		return "undefined"
+	}
+	bs, err := json.Marshal(x)
+	if err != nil {
+		return fmt.Sprint(x)
+	}
+	return string(bs)
+}
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/open-policy-agent/opa/cmd/formats"
+	"github.com/open-policy-agent/opa/v1/util"
+)
+
+// testDecisionServer serves a recent decision, and evaluates it again with the
+// allow rule negated.
+func testDecisionServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/decisions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"code": "unauthorized", "message": "missing token"}`))
+			return
+		}
+		switch r.URL.Query().Get("decision_id") {
+		case "1":
+			_, _ = w.Write([]byte(`{"result": [{
+				"decision_id": "1",
+				"path": "authz/allow",
+				"input": {"user": "alice"},
+				"result": true,
+				"masked": ["/input/password"],
+				"timestamp": "2026-01-01T00:00:00Z"
+			}]}`))
+		case "2":
+			_, _ = w.Write([]byte(`{"result": [{"decision_id": "2", "query": "data.authz.allow = x", "result": []}]}`))
+		default:
+			_, _ = w.Write([]byte(`{"result": []}`))
+		}
+	})
+	mux.HandleFunc("POST /v1/data/authz/allow", func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Fatal(err)
+		}
+		input, ok := request["input"].(map[string]any)
+		if !ok || input["user"] != "alice" || r.URL.Query().Get("explain") != "full" || r.URL.Query().Get("pretty") != "true" {
+			t.Errorf("unexpected request %v %v", r.URL, request)
+		}
+		_, _ = w.Write([]byte(`{"result": false, "explanation": ["query:1     Enter data.authz.allow = _", "query:1     | Fail data.authz.allow = _"]}`))
+	})
+	mux.HandleFunc("POST /v1/query", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"result": [{"x": true}], "explanation": []}`))
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func TestDoDecision(t *testing.T) {
+	ts := testDecisionServer(t)
+	defer ts.Close()
+
+	var stdout, stderr bytes.Buffer
+	params := newDecisionCommandParams()
+	params.server = ts.URL
+	params.token = "secret"
+	params.stdout = &stdout
+	params.stderr = &stderr
+
+	if err := doDecision(t.Context(), "1", params); err != nil {
+		t.Fatal(err)
+	}
+
+	exp := `Decision:  1
+Path:      authz/allow
+Timestamp: 2026-01-01T00:00:00Z
+Input:     {"user":"alice"}
+Recorded:  true
+Current:   false (changed)
+
+Explanation:
+query:1     Enter data.authz.allow = _
+query:1     | Fail data.authz.allow = _
+`
+	if act := stdout.String(); act != exp {
+		t.Fatalf("expected:\n%v\ngot:\n%v", exp, act)
+	}
+	if !strings.Contains(stderr.String(), "warning: decision masked fields [/input/password]") {
+		t.Fatalf("expected masked warning, got %q", stderr.String())
+	}
+}
+
+func TestDoDecisionQuery(t *testing.T) {
+	ts := testDecisionServer(t)
+	defer ts.Close()
+
+	var stdout bytes.Buffer
+	params := newDecisionCommandParams()
+	params.server = ts.URL
+	params.token = "secret"
+	params.stdout = &stdout
+	params.stderr = io.Discard
+	if err := params.outputFormat.Set(formats.JSON); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := doDecision(t.Context(), "2", params); err != nil {
+		t.Fatal(err)
+	}
+
+	var report map[string]any
+	if err := util.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report["changed"] != true {
+		t.Fatalf("expected changed result, got %v", report)
+	}
+}
+
+func TestDoDecisionErrors(t *testing.T) {
+	ts := testDecisionServer(t)
+	defer ts.Close()
+
+	tests := []struct {
+		note  string
+		id    string
+		token string
+		err   string
+	}{
+		{
+			note:  "not found",
+			id:    "3",
+			token: "secret",
+			err:   `decision "3" not found`,
+		},
+		{
+			note: "unauthorized",
+			id:   "1",
+			err:  "GET /v1/decisions: missing token",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			params := newDecisionCommandParams()
+			params.server = ts.URL
+			params.token = tc.token
+			params.stdout = io.Discard
+			params.stderr = io.Discard
+
+			err := doDecision(t.Context(), tc.id, params)
+			if err == nil || err.Error() != tc.err {
+				t.Fatalf("expected error %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/open-policy-agent/opa/cmd/internal/env"
+	"github.com/open-policy-agent/opa/internal/config"
+	"github.com/open-policy-agent/opa/v1/keys"
+	"github.com/open-policy-agent/opa/v1/plugins/logs"
+	"github.com/open-policy-agent/opa/v1/util"
+)
+
+type decryptCommandParams struct {
+	configFile          string
+	configOverrides     []string
+	configOverrideFiles []string
+	stdin               io.Reader
+	stdout              io.Writer
+}
+
+func newDecryptCommandParams() decryptCommandParams {
+	return decryptCommandParams{
+		stdin:  os.Stdin,
+		stdout: os.Stdout,
+	}
+}
+
+func initDecrypt(root *cobra.Command, brand string) {
+	executable := root.Name()
+
+	params := newDecryptCommandParams()
+
+	decryptCommand := &cobra.Command{
+		Use:   "decrypt [<path> [...]]",
+		Short: "Decrypt decision log events",
+		Long: `Decrypt the values of decision log events encrypted by 'encrypt' mask rules.
+
+The 'decrypt' command reads decision log events from the files, or from stdin if
+no file is given, and writes them with the values listed in their 'encrypted'
+field decrypted, one JSON object per line. The events may be JSON objects or
+arrays of objects, as uploaded by ` + brand + `, and gzip compressed.
+
+The keys are read from the 'keys' section of the configuration, which is set as
+for the 'run' command, with --config-file, --set and --set-file. Values
+encrypted with unknown keys are left encrypted, and reported as errors.
+`,
+		Example: `
+Decrypt the events of a decision log upload:
+
+	$ ` + executable + ` decrypt --config-file config.yaml upload.json.gz
+`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			return env.CmdFlags.CheckEnvironmentVariables(cmd)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+
+			if err := doDecrypt(args, params); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return newExitErrorWrap(1, err)
+			}
+			return nil
+		},
+	}
+
+	addConfigFileFlag(decryptCommand.Flags(), &params.configFile)
+	addConfigOverrides(decryptCommand.Flags(), &params.configOverrides)
+	addConfigOverrideFiles(decryptCommand.Flags(), &params.configOverrideFiles)
+
+	root.AddCommand(decryptCommand)
+}
+
+func doDecrypt(paths []string, params decryptCommandParams) error {
+	bs, err := config.Load(params.configFile, params.configOverrides, params.configOverrideFiles)
+	if err != nil {
+		return err
+	}
+
+	var cfg struct {
+		Keys json.RawMessage `json:"keys"`
+	}
+	if err := util.Unmarshal(bs, &cfg); err != nil {
+		return err
+	}
+	if len(cfg.Keys) == 0 {
+		return errors.New("no keys configured")
+	}
+	kcs, err := keys.ParseKeysConfig(cfg.Keys)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(params.stdout)
+	enc := json.NewEncoder(w)
+
+	var errs []error
+	decrypt := func(name string, r io.Reader) error {
+		return readDecisionEvents(r, func(i int, event map[string]any) error {
+			if err := logs.DecryptEvent(event, kcs); err != nil {
+				errs = append(errs, fmt.Errorf("%v: event %d (%v): %w", name, i, event["decision_id"], err))
+			}
+			return enc.Encode(event)
+		})
+	}
+
+	if len(paths) == 0 {
+		if err := decrypt("stdin", params.stdin); err != nil {
+			return err
+		}
+	}
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		err = decrypt(path, f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%v: %w", path, err)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return errors.Join(errs...)
+}
+
+// readDecisionEvents calls f for each event of the stream of JSON objects or
+// arrays of objects, which may be gzip compressed.
+func readDecisionEvents(r io.Reader, f func(int, map[string]any) error) error {
+	br := bufio.NewReader(r)
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gr, err := gzip.NewReader(br)
+		if err != nil {
+			return err
+		}
+		defer gr.Close()
+		r = gr
+	} else {
+		r = br
+	}
+
+	dec := util.NewJSONDecoder(r)
+	var i int
+	for {
+		var x any
+		if err := dec.Decode(&x); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		events, ok := x.([]any)
+		if !ok {
+			events = []any{x}
+		}
+		for _, e := range events {
+			event, ok := e.(map[string]any)
+			if !ok {
+				return fmt.Errorf("event %d: unexpected type %T", i, e)
+			}
+			if err := f(i, event); err != nil {
+				return err
+			}
+			i++
+		}
+	}
+}
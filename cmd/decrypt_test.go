@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/open-policy-agent/opa/v1/util/test"
+)
+
+const testDecryptKey = "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+
+// testEnvelope returns the envelope of the JSON value encrypted at the pointer,
+// as produced by an encrypt mask rule.
+func testEnvelope(t *testing.T, pointer string, value string) string {
+	t.Helper()
+
+	key, err := base64.StdEncoding.DecodeString(testDecryptKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonce := make([]byte, aead.NonceSize())
+	ciphertext := aead.Seal(nil, nonce, []byte(value), []byte(pointer))
+
+	return `{"kid": "logs", "alg": "A256GCM", "iv": "` + base64.StdEncoding.EncodeToString(nonce) +
+		`", "ciphertext": "` + base64.StdEncoding.EncodeToString(ciphertext) + `"}`
+}
+
+func TestDoDecrypt(t *testing.T) {
+	events := `[
+		{"decision_id": "1", "input": {"user": "alice", "ssn": ` + testEnvelope(t, "/input/ssn", `"123-45-6789"`) + `}, "encrypted": ["/input/ssn"]},
+		{"decision_id": "2", "result": true}
+	]`
+
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	if _, err := w.Write([]byte(events)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{
+		"config.yaml": "keys:\n  logs:\n    algorithm: A256GCM\n    key: " + testDecryptKey + "\n",
+		"events.json": `{"decision_id": "3", "result": ` + testEnvelope(t, "/result", `{"allow": false}`) + `, "encrypted": ["/result"]}`,
+	}
+
+	test.WithTempFS(files, func(root string) {
+		exp := `{"decision_id":"1","input":{"ssn":"123-45-6789","user":"alice"}}
+{"decision_id":"2","result":true}
+`
+
+		var stdout bytes.Buffer
+		params := newDecryptCommandParams()
+		params.configFile = filepath.Join(root, "config.yaml")
+		params.stdin = &gz
+		params.stdout = &stdout
+		if err := doDecrypt(nil, params); err != nil {
+			t.Fatal(err)
+		}
+		if act := stdout.String(); act != exp {
+			t.Fatalf("expected:\n%v\ngot:\n%v", exp, act)
+		}
+
+		stdout.Reset()
+		if err := doDecrypt([]string{filepath.Join(root, "events.json")}, params); err != nil {
+			t.Fatal(err)
+		}
+		if exp, act := `{"decision_id":"3","result":{"allow":false}}`+"\n", stdout.String(); act != exp {
+			t.Fatalf("expected:\n%v\ngot:\n%v", exp, act)
+		}
+
+		// Values encrypted with unknown keys are left encrypted.
+		stdout.Reset()
+		params.configFile = ""
+		params.configOverrides = []string{"keys.logs.algorithm=A128GCM", "keys.logs.key=" + base64.StdEncoding.EncodeToString([]byte("0123456789abcdef"))}
+		err := doDecrypt([]string{filepath.Join(root, "events.json")}, params)
+		if err == nil || !strings.Contains(err.Error(), "event 0 (3): /result: key logs algorithm A128GCM does not match envelope algorithm A256GCM") {
+			t.Fatalf("expected algorithm error, got %v", err)
+		}
+		if !strings.Contains(stdout.String(), `"encrypted":["/result"]`) {
+			t.Fatalf("expected event to stay encrypted, got %v", stdout.String())
+		}
+	})
+}
+
+func TestDoDecryptNoKeys(t *testing.T) {
+	params := newDecryptCommandParams()
+	params.stdin = strings.NewReader(`{}`)
+	if err := doDecrypt(nil, params); err == nil || err.Error() != "no keys configured" {
+		t.Fatalf("expected no keys error, got %v", err)
+	}
+}
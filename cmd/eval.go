@@ -54,6 +54,7 @@ type evalCommandParams struct {
 	strictBuiltinErrors       bool
 	showBuiltinErrors         bool
 	dataPaths                 repeatedStringFlag
+	dataFormats               bool
 	inputPath                 string
 	imports                   repeatedStringFlag
 	pkg                       string
@@ -260,7 +261,8 @@ See https://www.openpolicyagent.org/docs/latest/management-bundles/ for more det
 on bundle directory structures.
 
 The --data flag can be used to recursively load ALL *.rego, *.json, and
-*.yaml files under the specified directory.
+*.yaml files under the specified directory. With the --data-formats flag, *.toml,
+*.xml, *.csv, *.hcl, *.tf, *.tfvars and *.ini files are loaded as data, too.
 
 The -O flag controls the optimization level. By default, optimization is disabled (-O=0).
 When optimization is enabled the 'eval' command generates a bundle from the files provided
@@ -370,6 +372,7 @@ access.
 	addUnknownsFlag(evalCommand.Flags(), &params.unknowns, []string{"input"})
 	addFailFlag(evalCommand.Flags(), &params.fail, false)
 	addDataFlag(evalCommand.Flags(), &params.dataPaths)
+	addDataFormatsFlag(evalCommand.Flags(), &params.dataFormats)
 	addBundleFlag(evalCommand.Flags(), &params.bundlePaths)
 	addInputFlag(evalCommand.Flags(), &params.inputPath)
 	addImportFlag(evalCommand.Flags(), &params.imports)
@@ -600,6 +603,10 @@ type evalContext struct {
 func setupEval(args []string, params evalCommandParams) (*evalContext, error) {
 	var query string
 
+	if params.dataFormats {
+		loader.RegisterDataFormats()
+	}
+
 	if params.stdin {
 		bs, err := io.ReadAll(os.Stdin)
 		if err != nil {
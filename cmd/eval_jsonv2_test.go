@@ -26,6 +26,7 @@ import (
 	"github.com/open-policy-agent/opa/internal/presentation"
 	"github.com/open-policy-agent/opa/v1/ast"
 	"github.com/open-policy-agent/opa/v1/loader"
+	"github.com/open-policy-agent/opa/v1/loader/extension"
 	"github.com/open-policy-agent/opa/v1/rego"
 	"github.com/open-policy-agent/opa/v1/topdown"
 	"github.com/open-policy-agent/opa/v1/util"
@@ -451,6 +452,43 @@ func testEvalWithInputFile(t *testing.T, input string, query string, params eval
 	return err
 }
 
+func TestEvalWithDataFormats(t *testing.T) {
+	t.Cleanup(func() {
+		for _, ext := range []string{".toml", ".xml", ".csv", ".hcl", ".tf", ".tfvars", ".ini"} {
+			extension.RegisterExtension(ext, nil)
+		}
+	})
+
+	files := map[string]string{
+		"config/app.toml": `
+[server]
+port = 8080
+`,
+	}
+
+	test.WithTempFS(files, func(path string) {
+		params := newEvalCommandParams()
+		params.dataPaths = newrepeatedStringFlag([]string{path})
+
+		var buf bytes.Buffer
+		defined, err := eval([]string{"data.config.server.port"}, params, &buf, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if defined {
+			t.Fatalf("expected .toml file to be ignored, got: %s", buf.String())
+		}
+
+		params.dataFormats = true
+
+		buf.Reset()
+		defined, err = eval([]string{"data.config.server.port == 8080"}, params, &buf, nil)
+		if !defined || err != nil {
+			t.Fatalf("unexpected undefined or error: %v, output: %s", err, buf.String())
+		}
+	})
+}
+
 func TestEvalWithInvalidInputFile(t *testing.T) {
 	input := `{badjson`
 	query := "input.b[0].a == 1"
@@ -43,6 +43,10 @@ func addDataFlag(fs *pflag.FlagSet, paths *repeatedStringFlag) {
 	fs.VarP(paths, "data", "d", "set policy or data file(s). This flag can be repeated.")
 }
 
+func addDataFormatsFlag(fs *pflag.FlagSet, dataFormats *bool) {
+	fs.BoolVar(dataFormats, "data-formats", false, "load *.toml, *.xml, *.csv, *.hcl, *.tf, *.tfvars and *.ini files as data")
+}
+
 func addBundleFlag(fs *pflag.FlagSet, paths *repeatedStringFlag) {
 	fs.VarP(paths, "bundle", "b", "set bundle file(s) or directory path(s). This flag can be repeated.")
 }
@@ -0,0 +1,471 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+// Package replay re-evaluates recorded decisions against a candidate policy and
+// reports the decisions whose results would change.
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/rego"
+	"github.com/open-policy-agent/opa/v1/storage"
+	"github.com/open-policy-agent/opa/v1/topdown/builtins"
+	"github.com/open-policy-agent/opa/v1/util"
+)
+
+// Event is a decision log event, as emitted by the decision logs plugin,
+// reduced to the fields needed to replay the decision.
+type Event struct {
+	DecisionID     string   `json:"decision_id"`
+	Path           string   `json:"path,omitempty"`
+	Query          string   `json:"query,omitempty"`
+	Input          *any     `json:"input,omitempty"`
+	Result         *any     `json:"result,omitempty"`
+	NDBuiltinCache *any     `json:"nd_builtin_cache,omitempty"`
+	Erased         []string `json:"erased,omitempty"`
+	Masked         []string `json:"masked,omitempty"`
+	Error          *any     `json:"error,omitempty"`
+}
+
+// ReadEvents reads decision log events from r. The events may be encoded as
+// JSON arrays, as uploaded by the decision logs plugin, or as a stream of JSON
+// objects, as logged to the console. Gzip compressed input is decompressed.
+// Objects that are not decision log events, like other console log messages,
+// are ignored.
+func ReadEvents(r io.Reader) ([]Event, error) {
+	br := bufio.NewReader(r)
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		defer gr.Close()
+		r = gr
+	} else {
+		r = br
+	}
+
+	var events []Event
+	decoder := util.NewJSONDecoder(r)
+	for {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err == io.EOF {
+			return events, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		var batch []Event
+		if bytes.HasPrefix(bytes.TrimSpace(raw), []byte("[")) {
+			if err := util.UnmarshalJSON(raw, &batch); err != nil {
+				return nil, err
+			}
+		} else {
+			var event Event
+			if err := util.UnmarshalJSON(raw, &event); err != nil {
+				return nil, err
+			}
+			batch = append(batch, event)
+		}
+
+		for _, event := range batch {
+			if event.Path != "" || event.Query != "" {
+				events = append(events, event)
+			}
+		}
+	}
+}
+
+// Status is the outcome of replaying a decision.
+type Status string
+
+const (
+	// StatusUnchanged is the status of decisions with the recorded result.
+	StatusUnchanged Status = "unchanged"
+	// StatusChanged is the status of decisions with a different result.
+	StatusChanged Status = "changed"
+	// StatusError is the status of decisions that failed to evaluate.
+	StatusError Status = "error"
+	// StatusSkipped is the status of decisions that cannot be replayed.
+	StatusSkipped Status = "skipped"
+)
+
+// Decision is the outcome of replaying a decision log event.
+type Decision struct {
+	DecisionID string   `json:"decision_id,omitempty"`
+	Query      string   `json:"query"`
+	Status     Status   `json:"status"`
+	Reason     string   `json:"reason,omitempty"`
+	Result     *any     `json:"result,omitempty"`
+	NewResult  *any     `json:"new_result,omitempty"`
+	Diff       []Change `json:"diff,omitempty"`
+}
+
+// Change is a difference between the recorded and the new result of a
+// decision. Path is a JSON pointer into the result. Old or New is nil if the
+// value at the path is undefined in the recorded or new result.
+type Change struct {
+	Path string `json:"path"`
+	Old  *any   `json:"old,omitempty"`
+	New  *any   `json:"new,omitempty"`
+}
+
+// Replayer replays decisions against the policy and data of a store.
+type Replayer struct {
+	compiler *ast.Compiler
+	store    storage.Store
+	txn      storage.Transaction
+	prepared map[string]*rego.PreparedEvalQuery
+}
+
+// New returns a Replayer that evaluates decisions with the given compiler and
+// store, in the transaction txn.
+func New(compiler *ast.Compiler, store storage.Store, txn storage.Transaction) *Replayer {
+	return &Replayer{
+		compiler: compiler,
+		store:    store,
+		txn:      txn,
+		prepared: map[string]*rego.PreparedEvalQuery{},
+	}
+}
+
+// Replay evaluates the decision recorded in the event, using the recorded input
+// and the recorded values of non-deterministic built-in functions, and compares
+// the result with the recorded one.
+func (r *Replayer) Replay(ctx context.Context, event Event) Decision {
+	d := Decision{DecisionID: event.DecisionID, Query: event.Query, Result: event.Result}
+
+	query, isPath, err := parseQuery(event)
+	if err != nil {
+		d.Status, d.Reason = StatusSkipped, err.Error()
+		return d
+	}
+	d.Query = query.String()
+
+	if reason := skipReason(event); reason != "" {
+		d.Status, d.Reason = StatusSkipped, reason
+		return d
+	}
+
+	opts := []rego.EvalOption{rego.EvalTransaction(r.txn)}
+	if event.Input != nil {
+		input, err := ast.InterfaceToValue(*event.Input)
+		if err != nil {
+			d.Status, d.Reason = StatusSkipped, fmt.Sprintf("invalid input: %v", err)
+			return d
+		}
+		opts = append(opts, rego.EvalParsedInput(input))
+	}
+	if event.NDBuiltinCache != nil {
+		cache, err := ndbCache(*event.NDBuiltinCache)
+		if err != nil {
+			d.Status, d.Reason = StatusSkipped, fmt.Sprintf("invalid nd_builtin_cache: %v", err)
+			return d
+		}
+		opts = append(opts, rego.EvalNDBuiltinCache(cache))
+	}
+
+	pq, err := r.prepare(ctx, query)
+	if err != nil {
+		d.Status, d.Reason = StatusError, err.Error()
+		return d
+	}
+
+	rs, err := pq.Eval(ctx, opts...)
+	if err != nil {
+		if event.Error != nil {
+			d.Status = StatusUnchanged
+		} else {
+			d.Status, d.Reason = StatusError, err.Error()
+		}
+		return d
+	}
+
+	result, err := resultValue(rs, isPath)
+	if err != nil {
+		d.Status, d.Reason = StatusError, err.Error()
+		return d
+	}
+	d.NewResult = result
+
+	switch {
+	case event.Error != nil:
+		d.Status, d.Reason = StatusChanged, "recorded decision failed"
+	case !equal(event.Result, result):
+		d.Status, d.Diff = StatusChanged, Diff(event.Result, result)
+	default:
+		d.Status = StatusUnchanged
+	}
+
+	return d
+}
+
+func (r *Replayer) prepare(ctx context.Context, query ast.Body) (*rego.PreparedEvalQuery, error) {
+	key := query.String()
+	if pq, ok := r.prepared[key]; ok {
+		return pq, nil
+	}
+
+	pq, err := rego.New(
+		rego.ParsedQuery(query),
+		rego.Compiler(r.compiler),
+		rego.Store(r.store),
+		rego.Transaction(r.txn),
+	).PrepareForEval(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.prepared[key] = &pq
+	return &pq, nil
+}
+
+// skipReason returns why the event cannot be replayed faithfully, if it
+// cannot: decisions with erased or masked inputs are evaluated against other
+// inputs, and decisions with erased or masked results cannot be compared.
+func skipReason(event Event) string {
+	for _, p := range slices.Concat(event.Erased, event.Masked) {
+		for _, root := range []string{"/input", "/result"} {
+			if p == root || strings.HasPrefix(p, root+"/") {
+				return fmt.Sprintf("%v erased or masked", root[1:])
+			}
+		}
+	}
+	return ""
+}
+
+// parseQuery returns the query of the event, and whether it is a path query,
+// which evaluates to the value of the document at the path.
+func parseQuery(event Event) (ast.Body, bool, error) {
+	if event.Path == "" {
+		query, err := ast.ParseBody(event.Query)
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid query: %w", err)
+		}
+		return query, false, nil
+	}
+
+	path, ok := storage.ParsePathEscaped("/" + strings.Trim(event.Path, "/"))
+	if !ok {
+		return nil, false, fmt.Errorf("invalid path %q", event.Path)
+	}
+	return ast.NewBody(ast.NewExpr(ast.NewTerm(path.Ref(ast.DefaultRootDocument)))), true, nil
+}
+
+// ndbCache converts the recorded non-deterministic built-in cache to an
+// NDBCache. The cache is decoded by NDBCache itself, so that it is read in the
+// format it is recorded in. NDBCache keeps the JSON encoded argument arrays
+// that are the keys of the cached calls as strings, so they are decoded again
+// for the calls to be found.
+func ndbCache(x any) (builtins.NDBCache, error) {
+	bs, err := json.Marshal(x)
+	if err != nil {
+		return nil, err
+	}
+	var recorded builtins.NDBCache
+	if err := json.Unmarshal(bs, &recorded); err != nil {
+		return nil, err
+	}
+
+	cache := make(builtins.NDBCache, len(recorded))
+	for name, calls := range recorded {
+		args := ast.NewObject()
+		err := calls.Iter(func(k, v *ast.Term) error {
+			key, ok := k.Value.(ast.String)
+			if !ok {
+				args.Insert(k, v)
+				return nil
+			}
+			var x any
+			if err := util.UnmarshalJSON([]byte(key), &x); err != nil {
+				return fmt.Errorf("%v: invalid arguments %v: %w", name, string(key), err)
+			}
+			value, err := ast.InterfaceToValue(x)
+			if err != nil {
+				return err
+			}
+			args.Insert(ast.NewTerm(value), v)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		cache[name] = args
+	}
+	return cache, nil
+}
+
+// resultValue returns the result in the form recorded by the server: the value
+// of the document for path queries, or the bindings of each result otherwise.
+// Undefined path queries have no result.
+func resultValue(rs rego.ResultSet, isPath bool) (*any, error) {
+	var x any
+	if isPath {
+		if len(rs) == 0 {
+			return nil, nil
+		}
+		x = rs[0].Expressions[0].Value
+	} else {
+		bindings := make([]rego.Vars, 0, len(rs))
+		for _, r := range rs {
+			bindings = append(bindings, r.Bindings.WithoutWildcards())
+		}
+		x = bindings
+	}
+
+	if err := util.RoundTrip(&x); err != nil {
+		return nil, err
+	}
+	return &x, nil
+}
+
+func equal(a, b *any) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return util.Compare(*a, *b) == 0
+}
+
+// Diff returns the changes between the values a and b, where nil means the
+// value is undefined. Objects and arrays are compared element by element.
+func Diff(a, b *any) []Change {
+	var changes []Change
+	diff("", a, b, &changes)
+	return changes
+}
+
+func diff(path string, a, b *any, changes *[]Change) {
+	if a != nil && b != nil {
+		switch a := (*a).(type) {
+		case map[string]any:
+			if b, ok := (*b).(map[string]any); ok {
+				keys := maps.Clone(a)
+				maps.Copy(keys, b)
+				for _, k := range util.KeysSorted(keys) {
+					diff(path+"/"+escapePointer(k), lookup(a, k), lookup(b, k), changes)
+				}
+				return
+			}
+		case []any:
+			if b, ok := (*b).([]any); ok {
+				for i := range max(len(a), len(b)) {
+					diff(fmt.Sprintf("%v/%d", path, i), index(a, i), index(b, i), changes)
+				}
+				return
+			}
+		}
+	}
+
+	if !equal(a, b) {
+		if path == "" {
+			path = "/"
+		}
+		*changes = append(*changes, Change{Path: path, Old: a, New: b})
+	}
+}
+
+func lookup(m map[string]any, k string) *any {
+	if v, ok := m[k]; ok {
+		return &v
+	}
+	return nil
+}
+
+func index(a []any, i int) *any {
+	if i < len(a) {
+		return &a[i]
+	}
+	return nil
+}
+
+func escapePointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
+
+// Summary counts the replayed decisions by status.
+type Summary struct {
+	Total     int `json:"total"`
+	Unchanged int `json:"unchanged"`
+	Changed   int `json:"changed"`
+	Errors    int `json:"errors"`
+	Skipped   int `json:"skipped"`
+}
+
+// Report is the outcome of replaying a set of decisions. It contains the
+// decisions that did not replay unchanged.
+type Report struct {
+	Summary   Summary    `json:"summary"`
+	Decisions []Decision `json:"decisions"`
+}
+
+// Add adds the outcome of a replayed decision to the report.
+func (r *Report) Add(d Decision) {
+	r.Summary.Total++
+	switch d.Status {
+	case StatusUnchanged:
+		r.Summary.Unchanged++
+		return
+	case StatusChanged:
+		r.Summary.Changed++
+	case StatusError:
+		r.Summary.Errors++
+	case StatusSkipped:
+		r.Summary.Skipped++
+	}
+	r.Decisions = append(r.Decisions, d)
+}
+
+// Pretty writes a human readable report to w.
+func (r *Report) Pretty(w io.Writer) error {
+	var errs []error
+	printf := func(format string, args ...any) {
+		_, err := fmt.Fprintf(w, format, args...)
+		errs = append(errs, err)
+	}
+
+	for _, d := range r.Decisions {
+		id := d.DecisionID
+		if id == "" {
+			id = "-"
+		}
+		printf("%-9s %v %v\n", strings.ToUpper(string(d.Status)), id, d.Query)
+		if d.Reason != "" {
+			printf("  %v\n", d.Reason)
+		}
+		for _, c := range d.Diff {
+			printf("  %v: %v => %v\n", c.Path, prettyValue(c.Old), prettyValue(c.New))
+		}
+	}
+
+	if len(r.Decisions) > 0 {
+		printf("\n")
+	}
+	s := r.Summary
+	printf("%d decisions replayed: %d unchanged, %d changed, %d errors, %d skipped\n", s.Total, s.Unchanged, s.Changed, s.Errors, s.Skipped)
+
+	return errors.Join(errs...)
+}
+
+func prettyValue(x *any) string {
+	if x == nil {
+		return "undefined"
+	}
+	bs, err := json.Marshal(*x)
+	if err != nil {
+		return fmt.Sprint(*x)
+	}
+	return string(bs)
+}
@@ -0,0 +1,325 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package replay
+
+import (
+	"bytes"
+	"compress/gzip"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/storage"
+	"github.com/open-policy-agent/opa/v1/storage/inmem"
+	"github.com/open-policy-agent/opa/v1/util"
+)
+
+func TestReadEvents(t *testing.T) {
+	batch := `[
+		{"decision_id": "1", "path": "test/p", "result": true},
+		{"decision_id": "2", "query": "data.test.p = x"}
+	]`
+
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	if _, err := w.Write([]byte(batch)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		note  string
+		input string
+		exp   []string
+		err   string
+	}{
+		{
+			note:  "batch",
+			input: batch,
+			exp:   []string{"1", "2"},
+		},
+		{
+			note:  "batches",
+			input: batch + "\n" + batch,
+			exp:   []string{"1", "2", "1", "2"},
+		},
+		{
+			note:  "gzip",
+			input: gz.String(),
+			exp:   []string{"1", "2"},
+		},
+		{
+			note: "console",
+			input: `{"level": "info", "msg": "Initializing server."}
+{"decision_id": "1", "level": "info", "msg": "Decision Log", "path": "test/p", "result": true, "type": "openpolicyagent.org/decision_logs"}
+{"decision_id": "2", "level": "info", "msg": "Decision Log", "query": "data.test.p = x", "type": "openpolicyagent.org/decision_logs"}`,
+			exp: []string{"1", "2"},
+		},
+		{
+			note:  "empty",
+			input: "",
+		},
+		{
+			note:  "invalid",
+			input: `[{"decision_id": "1"}`,
+			err:   "unexpected EOF",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			events, err := ReadEvents(strings.NewReader(tc.input))
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("expected error %q but got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var ids []string
+			for _, e := range events {
+				ids = append(ids, e.DecisionID)
+			}
+			if !reflect.DeepEqual(ids, tc.exp) {
+				t.Fatalf("expected decisions %v but got %v", tc.exp, ids)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		note string
+		a, b string
+		exp  []Change
+	}{
+		{
+			note: "equal",
+			a:    `{"a": [1, {"b": 2}]}`,
+			b:    `{"a": [1, {"b": 2}]}`,
+		},
+		{
+			note: "scalar",
+			a:    `true`,
+			b:    `false`,
+			exp:  []Change{{Path: "/", Old: value(t, `true`), New: value(t, `false`)}},
+		},
+		{
+			note: "undefined",
+			a:    `true`,
+			exp:  []Change{{Path: "/", Old: value(t, `true`)}},
+		},
+		{
+			note: "nested",
+			a:    `{"a": [1, {"b": 2}], "c/d": 3, "e": "x"}`,
+			b:    `{"a": [1, {"b": 3}, 4], "e": "x", "f": null}`,
+			exp: []Change{
+				{Path: "/a/1/b", Old: value(t, `2`), New: value(t, `3`)},
+				{Path: "/a/2", New: value(t, `4`)},
+				{Path: "/c~1d", Old: value(t, `3`)},
+				{Path: "/f", New: value(t, `null`)},
+			},
+		},
+		{
+			note: "type change",
+			a:    `{"a": [1]}`,
+			b:    `{"a": {"0": 1}}`,
+			exp:  []Change{{Path: "/a", Old: value(t, `[1]`), New: value(t, `{"0": 1}`)}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			var a, b *any
+			if tc.a != "" {
+				a = value(t, tc.a)
+			}
+			if tc.b != "" {
+				b = value(t, tc.b)
+			}
+			if act := Diff(a, b); !reflect.DeepEqual(act, tc.exp) {
+				t.Fatalf("expected %v but got %v", tc.exp, act)
+			}
+		})
+	}
+}
+
+func TestReplay(t *testing.T) {
+	policy := `package test
+
+allow if input.user == "alice"
+
+now := time.now_ns()
+
+roll := rand.intn("dice", 6)
+
+users[name] := role if {
+	some name, role in data.roles
+}
+
+fail := 1 if input.x >= 0
+
+fail := 2 if input.x == 0
+`
+
+	tests := []struct {
+		note   string
+		event  string
+		status Status
+		reason string
+		diff   []Change
+	}{
+		{
+			note:   "path unchanged",
+			event:  `{"path": "test/allow", "input": {"user": "alice"}, "result": true}`,
+			status: StatusUnchanged,
+		},
+		{
+			note:   "path changed",
+			event:  `{"path": "test/allow", "input": {"user": "bob"}, "result": true}`,
+			status: StatusChanged,
+			diff:   []Change{{Path: "/", Old: value(t, `true`)}},
+		},
+		{
+			note:   "path undefined",
+			event:  `{"path": "/test/allow", "input": {"user": "bob"}}`,
+			status: StatusUnchanged,
+		},
+		{
+			note:   "data",
+			event:  `{"path": "test/users", "result": {"alice": "admin", "bob": "viewer"}}`,
+			status: StatusChanged,
+			diff:   []Change{{Path: "/bob", Old: value(t, `"viewer"`), New: value(t, `"admin"`)}},
+		},
+		{
+			note:   "query",
+			event:  `{"query": "data.test.users[x] = \"admin\"", "result": [{"x": "alice"}]}`,
+			status: StatusChanged,
+			diff:   []Change{{Path: "/1", New: value(t, `{"x": "bob"}`)}},
+		},
+		{
+			note:   "nd builtin cache",
+			event:  `{"path": "test/now", "result": 42, "nd_builtin_cache": {"time.now_ns": {"[]": 42}}}`,
+			status: StatusUnchanged,
+		},
+		{
+			note:   "nd builtin cache with arguments",
+			event:  `{"path": "test/roll", "result": 7, "nd_builtin_cache": {"rand.intn": {"[\"dice\",6]": 7}}}`,
+			status: StatusUnchanged,
+		},
+		{
+			note:   "invalid nd builtin cache",
+			event:  `{"path": "test/now", "result": 42, "nd_builtin_cache": {"time.now_ns": 42}}`,
+			status: StatusSkipped,
+			reason: "invalid nd_builtin_cache: expected Object, got other Value type in conversion",
+		},
+		{
+			note:   "invalid nd builtin cache arguments",
+			event:  `{"path": "test/now", "result": 42, "nd_builtin_cache": {"time.now_ns": {"[": 42}}}`,
+			status: StatusSkipped,
+			reason: "invalid nd_builtin_cache: time.now_ns: invalid arguments [",
+		},
+		{
+			note:   "error",
+			event:  `{"path": "test/fail", "input": {"x": 0}, "result": 1}`,
+			status: StatusError,
+			reason: "eval_conflict_error",
+		},
+		{
+			note:   "recorded error",
+			event:  `{"path": "test/fail", "input": {"x": 0}, "error": {"code": "internal_error"}}`,
+			status: StatusUnchanged,
+		},
+		{
+			note:   "recorded error fixed",
+			event:  `{"path": "test/fail", "input": {"x": 1}, "error": {"code": "internal_error"}}`,
+			status: StatusChanged,
+			reason: "recorded decision failed",
+		},
+		{
+			note:   "masked input",
+			event:  `{"path": "test/allow", "input": {"user": "alice"}, "result": true, "masked": ["/input/password"]}`,
+			status: StatusSkipped,
+			reason: "input erased or masked",
+		},
+		{
+			note:   "erased result",
+			event:  `{"path": "test/allow", "input": {"user": "alice"}, "erased": ["/result"]}`,
+			status: StatusSkipped,
+			reason: "result erased or masked",
+		},
+		{
+			note:   "invalid query",
+			event:  `{"query": "data.test.allow[", "result": []}`,
+			status: StatusSkipped,
+			reason: "invalid query",
+		},
+	}
+
+	ctx := t.Context()
+	compiler := ast.MustCompileModules(map[string]string{"test.rego": policy})
+	store := inmem.NewFromObject(map[string]any{"roles": map[string]any{"alice": "admin", "bob": "admin"}})
+	txn := storage.NewTransactionOrDie(ctx, store)
+	defer store.Abort(ctx, txn)
+	replayer := New(compiler, store, txn)
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			var event Event
+			if err := util.UnmarshalJSON([]byte(tc.event), &event); err != nil {
+				t.Fatal(err)
+			}
+
+			d := replayer.Replay(ctx, event)
+			if d.Status != tc.status {
+				t.Fatalf("expected status %v but got %v (%v)", tc.status, d.Status, d.Reason)
+			}
+			if !strings.Contains(d.Reason, tc.reason) {
+				t.Fatalf("expected reason %q but got %q", tc.reason, d.Reason)
+			}
+			if !reflect.DeepEqual(d.Diff, tc.diff) {
+				t.Fatalf("expected diff %v but got %v", tc.diff, d.Diff)
+			}
+		})
+	}
+}
+
+func TestReportPretty(t *testing.T) {
+	r := &Report{}
+	r.Add(Decision{DecisionID: "1", Query: "data.test.allow", Status: StatusUnchanged})
+	r.Add(Decision{DecisionID: "2", Query: "data.test.allow", Status: StatusChanged, Diff: []Change{{Path: "/", Old: value(t, `true`)}}})
+	r.Add(Decision{Query: "data.test.fail", Status: StatusError, Reason: "div: divide by zero"})
+
+	var buf bytes.Buffer
+	if err := r.Pretty(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	exp := `CHANGED   2 data.test.allow
+  /: true => undefined
+ERROR     - data.test.fail
+  div: divide by zero
+
+3 decisions replayed: 1 unchanged, 1 changed, 1 errors, 0 skipped
+`
+	if buf.String() != exp {
+		t.Fatalf("expected:\n%v\ngot:\n%v", exp, buf.String())
+	}
+}
+
+func value(t *testing.T, s string) *any {
+	t.Helper()
+	var x any
+	if err := util.UnmarshalJSON([]byte(s), &x); err != nil {
+		t.Fatal(err)
+	}
+	return &x
+}
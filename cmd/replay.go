@@ -0,0 +1,238 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/open-policy-agent/opa/cmd/formats"
+	"github.com/open-policy-agent/opa/cmd/internal/env"
+	"github.com/open-policy-agent/opa/cmd/internal/replay"
+	"github.com/open-policy-agent/opa/internal/presentation"
+	initload "github.com/open-policy-agent/opa/internal/runtime/init"
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/bundle"
+	"github.com/open-policy-agent/opa/v1/storage"
+	"github.com/open-policy-agent/opa/v1/storage/inmem"
+	"github.com/open-policy-agent/opa/v1/util"
+)
+
+type replayCommandParams struct {
+	dataPaths       repeatedStringFlag
+	bundlePaths     repeatedStringFlag
+	ignore          []string
+	outputFormat    *util.EnumFlag
+	exitCodeChanged int
+	exitCodeError   int
+	v0Compatible    bool
+	v1Compatible    bool
+	stdin           io.Reader
+	stdout          io.Writer
+	capabilities    *capabilitiesFlag
+}
+
+func (p *replayCommandParams) regoVersion() ast.RegoVersion {
+	// The '--v0-compatible' flag takes precedence over the '--v1-compatible' flag.
+	if p.v0Compatible {
+		return ast.RegoV0
+	}
+	if p.v1Compatible {
+		return ast.RegoV1
+	}
+	return ast.DefaultRegoVersion
+}
+
+func newReplayCommandParams() replayCommandParams {
+	return replayCommandParams{
+		outputFormat:    formats.Flag(formats.Pretty, formats.JSON),
+		exitCodeChanged: 1,
+		exitCodeError:   2,
+		stdin:           os.Stdin,
+		stdout:          os.Stdout,
+		capabilities:    newCapabilitiesFlag(),
+	}
+}
+
+func initReplay(root *cobra.Command, brand string) {
+	executable := root.Name()
+
+	params := newReplayCommandParams()
+
+	replayCommand := &cobra.Command{
+		Use:   "replay [<path> [...]]",
+		Short: "Replay decisions against a policy",
+		Long: `Replay recorded decisions against a policy.
+
+The 'replay' command reads decision log events from the files at the given paths,
+or from stdin if no paths are given, and evaluates each decision again against the
+policy and data loaded with the --bundle and --data flags. It reports the decisions
+whose results change, which helps to assess the impact of a policy change before
+rolling it out.
+
+Decision log events are read as emitted by the decision logs plugin of ` + brand + `: JSON
+arrays of events, as uploaded to a decision log service (optionally gzip compressed),
+or one event per line, as logged to the console.
+
+Each decision is evaluated with the recorded input. Non-deterministic built-in
+functions, like http.send or time.now_ns, return the values they returned when the
+decision was made if the event contains them in its 'nd_builtin_cache' field (see
+the 'nd_builtin_cache' configuration option). Calls that are not recorded are
+evaluated again.
+
+Decisions whose input or result was erased or masked are skipped, as they cannot be
+evaluated against the original input or compared with the original result.
+
+The command exits with the code set by --exit-code-changed if any decision
+changed, or by --exit-code-error if any decision failed to evaluate. Set either
+to 0 to ignore changed or failed decisions.
+`,
+		Example: `
+Replay the decisions uploaded to a decision log service against a new bundle:
+
+	$ ` + executable + ` replay --bundle bundle.tar.gz decisions.json
+
+Replay the decisions logged to the console by a running ` + brand + `:
+
+	$ ` + executable + ` run --server --set decision_logs.console=true ... | ` + executable + ` replay -b policies/
+`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			if len(params.dataPaths.v) == 0 && len(params.bundlePaths.v) == 0 {
+				return errors.New("specify the policy to replay decisions against with --data or --bundle")
+			}
+			return env.CmdFlags.CheckEnvironmentVariables(cmd)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+
+			exit, err := doReplay(cmd.Context(), args, params)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return newExitErrorWrap(1, err)
+			}
+			if exit != 0 {
+				return newExitError(exit)
+			}
+			return nil
+		},
+	}
+
+	addDataFlag(replayCommand.Flags(), &params.dataPaths)
+	addBundleFlag(replayCommand.Flags(), &params.bundlePaths)
+	addIgnoreFlag(replayCommand.Flags(), &params.ignore)
+	addOutputFormat(replayCommand.Flags(), params.outputFormat)
+	addCapabilitiesFlag(replayCommand.Flags(), params.capabilities)
+	addV0CompatibleFlag(replayCommand.Flags(), &params.v0Compatible, false)
+	addV1CompatibleFlag(replayCommand.Flags(), &params.v1Compatible, false)
+	replayCommand.Flags().IntVar(&params.exitCodeChanged, "exit-code-changed", params.exitCodeChanged, "set the exit code if any decision changed")
+	replayCommand.Flags().IntVar(&params.exitCodeError, "exit-code-error", params.exitCodeError, "set the exit code if any decision failed to evaluate")
+
+	root.AddCommand(replayCommand)
+}
+
+// doReplay replays the decisions read from the files at paths, or stdin, and
+// returns the exit code for the outcome.
+func doReplay(ctx context.Context, paths []string, params replayCommandParams) (int, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	events, err := readReplayEvents(paths, params.stdin)
+	if err != nil {
+		return 0, err
+	}
+
+	popts := ast.ParserOptions{
+		RegoVersion:  params.regoVersion(),
+		Capabilities: params.capabilities.C,
+	}
+	filter := ignored(params.ignore).Apply
+
+	files, err := initload.LoadPathsForRegoVersion(popts, params.dataPaths.v, filter, false, nil, true, false, false, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	bundles := map[string]*bundle.Bundle{}
+	if len(params.bundlePaths.v) > 0 {
+		loaded, err := initload.LoadPathsForRegoVersion(popts, params.bundlePaths.v, filter, true, nil, true, false, false, nil)
+		if err != nil {
+			return 0, err
+		}
+		maps.Copy(bundles, loaded.Bundles)
+	}
+	maps.Copy(bundles, files.Bundles)
+
+	store := inmem.NewWithOpts(inmem.OptRoundTripOnWrite(false))
+	txn, err := store.NewTransaction(ctx, storage.WriteParams)
+	if err != nil {
+		return 0, err
+	}
+	defer store.Abort(ctx, txn)
+
+	compiled, err := initload.InsertAndCompile(ctx, initload.InsertAndCompileOptions{
+		Store:         store,
+		Txn:           txn,
+		Files:         files.Files,
+		Bundles:       bundles,
+		MaxErrors:     -1,
+		ParserOptions: popts,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	replayer := replay.New(compiled.Compiler, store, txn)
+	report := &replay.Report{Decisions: []replay.Decision{}}
+	for _, event := range events {
+		report.Add(replayer.Replay(ctx, event))
+	}
+
+	switch params.outputFormat.String() {
+	case formats.JSON:
+		err = presentation.JSON(params.stdout, report)
+	default:
+		err = report.Pretty(params.stdout)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	switch {
+	case report.Summary.Errors > 0 && params.exitCodeError != 0:
+		return params.exitCodeError, nil
+	case report.Summary.Changed > 0:
+		return params.exitCodeChanged, nil
+	}
+	return 0, nil
+}
+
+func readReplayEvents(paths []string, stdin io.Reader) ([]replay.Event, error) {
+	if len(paths) == 0 {
+		return replay.ReadEvents(stdin)
+	}
+
+	var events []replay.Event
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		es, err := replay.ReadEvents(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", path, err)
+		}
+		events = append(events, es...)
+	}
+	return events, nil
+}
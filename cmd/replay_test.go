@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/open-policy-agent/opa/cmd/internal/replay"
+	"github.com/open-policy-agent/opa/v1/util"
+	"github.com/open-policy-agent/opa/v1/util/test"
+)
+
+func TestDoReplay(t *testing.T) {
+	files := map[string]string{
+		"policy/test.rego": `package test
+
+allow if input.user in data.admins
+
+now := time.now_ns()
+`,
+		"policy/data.json": `{"admins": ["alice", "bob"]}`,
+		"decisions.json": `[
+	{"decision_id": "1", "path": "test/allow", "input": {"user": "alice"}, "result": true},
+	{"decision_id": "2", "path": "test/allow", "input": {"user": "bob"}},
+	{"decision_id": "3", "path": "test/allow", "input": {"user": "carol"}, "masked": ["/input/password"]},
+	{"decision_id": "4", "path": "test/now", "result": 42, "nd_builtin_cache": {"time.now_ns": {"[]": 42}}}
+]`,
+		"console.log": `{"decision_id": "5", "msg": "Decision Log", "path": "test/allow", "input": {"user": "alice"}, "result": true}`,
+	}
+
+	tests := []struct {
+		note     string
+		logs     []string
+		stdin    string
+		format   string
+		changed  int
+		exit     int
+		expected string
+	}{
+		{
+			note:    "changed",
+			logs:    []string{"decisions.json"},
+			changed: 1,
+			exit:    1,
+			expected: `CHANGED   2 data.test.allow
+  /: undefined => true
+SKIPPED   3 data.test.allow
+  input erased or masked
+
+4 decisions replayed: 2 unchanged, 1 changed, 0 errors, 1 skipped
+`,
+		},
+		{
+			note:     "unchanged",
+			logs:     []string{"console.log"},
+			changed:  1,
+			expected: "1 decisions replayed: 1 unchanged, 0 changed, 0 errors, 0 skipped\n",
+		},
+		{
+			note:     "stdin",
+			stdin:    files["console.log"],
+			changed:  1,
+			expected: "1 decisions replayed: 1 unchanged, 0 changed, 0 errors, 0 skipped\n",
+		},
+		{
+			note:    "exit code changed",
+			logs:    []string{"decisions.json", "console.log"},
+			changed: 3,
+			exit:    3,
+		},
+		{
+			note: "ignore changed",
+			logs: []string{"decisions.json"},
+		},
+		{
+			note:    "json",
+			logs:    []string{"decisions.json"},
+			format:  "json",
+			changed: 1,
+			exit:    1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			test.WithTempFS(files, func(root string) {
+				params := newReplayCommandParams()
+				params.dataPaths = newrepeatedStringFlag([]string{filepath.Join(root, "policy")})
+				params.exitCodeChanged = tc.changed
+				if tc.format != "" {
+					if err := params.outputFormat.Set(tc.format); err != nil {
+						t.Fatal(err)
+					}
+				}
+				var stdout bytes.Buffer
+				params.stdout = &stdout
+				params.stdin = strings.NewReader(tc.stdin)
+
+				var paths []string
+				for _, p := range tc.logs {
+					paths = append(paths, filepath.Join(root, p))
+				}
+
+				exit, err := doReplay(t.Context(), paths, params)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if exit != tc.exit {
+					t.Fatalf("expected exit code %d but got %d", tc.exit, exit)
+				}
+
+				if tc.format == "json" {
+					var report replay.Report
+					if err := util.UnmarshalJSON(stdout.Bytes(), &report); err != nil {
+						t.Fatal(err)
+					}
+					exp := replay.Summary{Total: 4, Unchanged: 2, Changed: 1, Skipped: 1}
+					if report.Summary != exp || len(report.Decisions) != 2 {
+						t.Fatalf("unexpected report: %v", stdout.String())
+					}
+					if d := report.Decisions[0]; d.DecisionID != "2" || d.Status != replay.StatusChanged || len(d.Diff) != 1 {
+						t.Fatalf("unexpected decision: %+v", d)
+					}
+				} else if tc.expected != "" && stdout.String() != tc.expected {
+					t.Fatalf("expected:\n%v\ngot:\n%v", tc.expected, stdout.String())
+				}
+			})
+		})
+	}
+}
+
+func TestDoReplayError(t *testing.T) {
+	files := map[string]string{
+		"test.rego": `package test
+
+p := 1 if input.x >= 0
+
+p := 2 if input.x == 0
+`,
+		"decisions.json": `[{"decision_id": "1", "path": "test/p", "input": {"x": 0}, "result": 1}]`,
+	}
+
+	test.WithTempFS(files, func(root string) {
+		for _, exitCodeError := range []int{2, 0} {
+			params := newReplayCommandParams()
+			params.bundlePaths = newrepeatedStringFlag([]string{root})
+			params.ignore = []string{"*.json"}
+			params.exitCodeError = exitCodeError
+			params.stdout = &bytes.Buffer{}
+
+			exit, err := doReplay(t.Context(), []string{filepath.Join(root, "decisions.json")}, params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if exit != exitCodeError {
+				t.Fatalf("expected exit code %d but got %d", exitCodeError, exit)
+			}
+		}
+	})
+}
@@ -20,6 +20,7 @@ import (
 
 	"github.com/open-policy-agent/opa/cmd/internal/env"
 	fileurl "github.com/open-policy-agent/opa/internal/file/url"
+	"github.com/open-policy-agent/opa/v1/loader"
 	"github.com/open-policy-agent/opa/v1/runtime"
 	"github.com/open-policy-agent/opa/v1/server"
 	"github.com/open-policy-agent/opa/v1/util"
@@ -55,6 +56,7 @@ type runCmdParams struct {
 	skipKnownSchemaCheck bool
 	excludeVerifyFiles   []string
 	cipherSuites         []string
+	dataFormats          bool
 }
 
 func newRunParams() runCmdParams {
@@ -107,6 +109,14 @@ The current set of file extensions that ` + brand + ` will consider are:
     .yaml or .yml  # YAML data
     .rego          # Rego file
 
+With the '--data-formats' flag, the following file extensions are considered, too:
+
+    .toml                 # TOML data
+    .xml                  # XML data
+    .csv                  # CSV data, the first record names the fields
+    .hcl, .tf or .tfvars  # HCL data
+    .ini                  # INI data
+
 Non-bundle data file and directory paths can be prefixed with the desired
 destination in the data document with the following syntax:
 
@@ -250,6 +260,7 @@ See https://godoc.org/crypto/tls#pkg-constants for more information.
 	addConfigOverrides(runCommand.Flags(), &cmdParams.rt.ConfigOverrides)
 	addConfigOverrideFiles(runCommand.Flags(), &cmdParams.rt.ConfigOverrideFiles)
 	addBundleModeFlag(runCommand.Flags(), &cmdParams.rt.BundleMode, false)
+	addDataFormatsFlag(runCommand.Flags(), &cmdParams.dataFormats)
 	addReadAstValuesFromStoreFlag(runCommand.Flags(), &cmdParams.rt.ReadAstValuesFromStore, false)
 
 	runCommand.Flags().BoolVar(&cmdParams.skipVersionCheck, "skip-version-check", false, "disables version check against GitHub releases (see: https://www.openpolicyagent.org/docs/privacy)")
@@ -283,6 +294,10 @@ Flags:
 }
 
 func initRuntime(ctx context.Context, params runCmdParams, args []string, addrSetByUser bool) (*runtime.Runtime, error) {
+	if params.dataFormats {
+		loader.RegisterDataFormats()
+	}
+
 	authenticationSchemes := map[string]server.AuthenticationScheme{
 		"token": server.AuthenticationToken,
 		"tls":   server.AuthenticationTLS,
@@ -50,6 +50,7 @@ type testCommandParams struct {
 	timeout      time.Duration
 	ignore       []string
 	bundleMode   bool
+	dataFormats  bool
 	benchmark    bool
 	benchMem     bool
 	runRegex     string
@@ -122,6 +123,10 @@ func opaTest(args []string, testParams testCommandParams) int {
 		ProcessAnnotation: true,
 	}
 
+	if testParams.dataFormats {
+		loader.RegisterDataFormats()
+	}
+
 	var err error
 	if testParams.bundleMode {
 		bundles, store, err = tester.LoadBundlesWithParserOptions(args, ignored(testParams.ignore).Apply, popts)
@@ -500,6 +505,8 @@ If the '--bundle' option is specified the paths will be treated as policy bundle
 and loaded following standard bundle conventions. The path can be a compressed archive
 file or a directory which will be treated as a bundle. Without the '--bundle' flag OPA
 will recursively load ALL *.rego, *.json, and *.yaml files for evaluating the test cases.
+With the '--data-formats' flag, *.toml, *.xml, *.csv, *.hcl, *.tf, *.tfvars and *.ini
+files are loaded as data, too.
 
 Test cases under development may be prefixed "todo_" in order to skip their execution,
 while still getting marked as skipped in the test results.
@@ -614,6 +621,7 @@ recommended as some updates might cause them to be dropped by OPA.
 	// Shared flags
 	addOutputFormat(testCommand.Flags(), testParams.outputFormat)
 	addBundleModeFlag(testCommand.Flags(), &testParams.bundleMode, false)
+	addDataFormatsFlag(testCommand.Flags(), &testParams.dataFormats)
 	addBenchmemFlag(testCommand.Flags(), &testParams.benchMem, true)
 	addCountFlag(testCommand.Flags(), &testParams.count, "test")
 	addMaxErrorsFlag(testCommand.Flags(), &testParams.errLimit)
@@ -0,0 +1,11 @@
+package crypto_detached_signature_verification
+
+# Public key of the webhook sender, often loaded from data or a JWKS
+public_key := `-----BEGIN PUBLIC KEY-----
+MCowBQYDK2VwAyEAGeZj+qY7O0L3SxyLEu1PHFHUHTzcnxycysH7Q5QaQds=
+-----END PUBLIC KEY-----`
+
+# The signature is computed over the raw request body
+signature_valid := crypto.verify("EdDSA", public_key, input.body, input.headers["x-signature"])
+
+event := json.unmarshal(input.body) if signature_valid
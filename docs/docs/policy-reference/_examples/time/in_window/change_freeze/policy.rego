@@ -0,0 +1,14 @@
+package play
+
+request_time := time.parse_rfc3339_ns(input.request_time)
+
+frozen := time.in_window(data.freeze.start, data.freeze.end, request_time, data.freeze.tz)
+
+default allow := false
+
+allow if not frozen
+
+next_shift := time.format([ns, data.freeze.tz]) if {
+	frozen
+	ns := time.next_cron(data.on_call[input.user], request_time, data.freeze.tz)
+}
@@ -0,0 +1,22 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+// Package astbinary gives packages in this module access to the binary module
+// encoding of the ast package without making it part of the public ast API.
+// The encoding is implemented in the ast package, as it has to read and set
+// unexported fields of the AST, which registers it here when initialized. As
+// the ast package imports this package, the module type cannot be referenced
+// and is passed as a *ast.Module in an any.
+package astbinary
+
+var (
+	// Marshal returns the binary encoding of the *ast.Module mod. If src is
+	// the source the module was parsed from, the same source must be passed to
+	// Unmarshal.
+	Marshal func(mod any, src []byte) ([]byte, error)
+
+	// Unmarshal decodes a module encoded by Marshal into a *ast.Module. If
+	// file is not empty, it replaces the file of all locations in the module.
+	Unmarshal func(bs []byte, src []byte, file string) (any, error)
+)
@@ -0,0 +1,159 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+// Package cidrtree implements a radix tree of IP prefixes, for longest-prefix
+// match lookups in time proportional to the address length rather than the
+// number of prefixes.
+package cidrtree
+
+import (
+	"math/bits"
+	"net/netip"
+)
+
+// Tree maps IPv4 and IPv6 prefixes to values. The zero value is an empty tree.
+// A Tree is safe for concurrent lookups once all prefixes are inserted.
+type Tree[V any] struct {
+	v4, v6 *node[V]
+	n      int
+}
+
+// node is a path-compressed binary trie node. Its key has all bits after the
+// first length bits cleared, and the nodes below it share that prefix.
+type node[V any] struct {
+	key      key
+	length   int
+	set      bool
+	prefix   netip.Prefix
+	value    V
+	children [2]*node[V]
+}
+
+// key holds an address in the high bits of a 128-bit integer.
+type key struct {
+	hi, lo uint64
+}
+
+func keyOf(a netip.Addr) key {
+	if a.Is4() {
+		b := a.As4()
+		return key{hi: uint64(b[0])<<56 | uint64(b[1])<<48 | uint64(b[2])<<40 | uint64(b[3])<<32}
+	}
+	b := a.As16()
+	var k key
+	for i := range 8 {
+		k.hi = k.hi<<8 | uint64(b[i])
+		k.lo = k.lo<<8 | uint64(b[i+8])
+	}
+	return k
+}
+
+// bit returns the i-th bit of k, counting from the most significant one.
+func (k key) bit(i int) int {
+	if i < 64 {
+		return int(k.hi >> (63 - i) & 1)
+	}
+	return int(k.lo >> (127 - i) & 1)
+}
+
+// common returns the length of the longest common prefix of k and o.
+func (k key) common(o key) int {
+	if x := k.hi ^ o.hi; x != 0 {
+		return bits.LeadingZeros64(x)
+	}
+	return 64 + bits.LeadingZeros64(k.lo^o.lo)
+}
+
+// mask clears all bits of k after the first n.
+func (k key) mask(n int) key {
+	switch {
+	case n == 0:
+		return key{}
+	case n < 64:
+		return key{hi: k.hi &^ (1<<(64-n) - 1)}
+	case n == 64:
+		return key{hi: k.hi}
+	case n < 128:
+		return key{hi: k.hi, lo: k.lo &^ (1<<(128-n) - 1)}
+	}
+	return k
+}
+
+// Len returns the number of prefixes in t.
+func (t *Tree[V]) Len() int {
+	return t.n
+}
+
+// Insert maps p to v, and reports whether it did. If p, ignoring any bits after
+// its length, is already in t, t is not modified.
+func (t *Tree[V]) Insert(p netip.Prefix, v V) bool {
+	k, length := keyOf(p.Addr()), p.Bits()
+	k = k.mask(length)
+	leaf := &node[V]{key: k, length: length, set: true, prefix: p, value: v}
+
+	cur := &t.v6
+	if p.Addr().Is4() {
+		cur = &t.v4
+	}
+	for {
+		n := *cur
+		if n == nil {
+			*cur = leaf
+			t.n++
+			return true
+		}
+
+		c := min(k.common(n.key), length, n.length)
+		switch {
+		case c == n.length && c == length:
+			if n.set {
+				return false
+			}
+			n.set, n.prefix, n.value = true, p, v
+			t.n++
+			return true
+		case c == n.length:
+			cur = &n.children[k.bit(c)]
+			continue
+		case c == length:
+			leaf.children[n.key.bit(c)] = n
+		default:
+			split := &node[V]{key: k.mask(c), length: c}
+			split.children[k.bit(c)] = leaf
+			split.children[n.key.bit(c)] = n
+			leaf = split
+		}
+		*cur = leaf
+		t.n++
+		return true
+	}
+}
+
+// Lookup returns the longest prefix in t that contains a, and its value.
+// IPv4-mapped IPv6 addresses are looked up as IPv4 addresses.
+func (t *Tree[V]) Lookup(a netip.Addr) (netip.Prefix, V, bool) {
+	a = a.Unmap()
+	n, length := t.v6, 128
+	if a.Is4() {
+		n, length = t.v4, 32
+	}
+
+	k := keyOf(a)
+	var match *node[V]
+	for n != nil && k.common(n.key) >= n.length {
+		if n.set {
+			match = n
+		}
+		if n.length == length {
+			break
+		}
+		n = n.children[k.bit(n.length)]
+	}
+
+	if match == nil {
+		var zero V
+		return netip.Prefix{}, zero, false
+	}
+	return match.prefix, match.value, true
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package cidrtree
+
+import (
+	"math/rand"
+	"net/netip"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	t.Parallel()
+
+	var tree Tree[string]
+	for _, p := range []string{
+		"0.0.0.0/0",
+		"10.0.0.0/8",
+		"10.1.0.0/16",
+		"10.1.2.0/24",
+		"10.1.2.3/32",
+		"192.168.0.0/16",
+		"2001:db8::/32",
+		"2001:db8:1::/48",
+	} {
+		tree.Insert(netip.MustParsePrefix(p), p)
+	}
+
+	tests := []struct {
+		addr string
+		exp  string
+	}{
+		{"10.1.2.3", "10.1.2.3/32"},
+		{"10.1.2.4", "10.1.2.0/24"},
+		{"10.1.3.1", "10.1.0.0/16"},
+		{"10.2.0.1", "10.0.0.0/8"},
+		{"11.0.0.1", "0.0.0.0/0"},
+		{"::ffff:10.1.2.3", "10.1.2.3/32"},
+		{"2001:db8:1::1", "2001:db8:1::/48"},
+		{"2001:db8:2::1", "2001:db8::/32"},
+		{"2001:db9::1", ""},
+	}
+
+	for _, tc := range tests {
+		p, v, ok := tree.Lookup(netip.MustParseAddr(tc.addr))
+		if tc.exp == "" {
+			if ok {
+				t.Errorf("%s: expected no match, got %v", tc.addr, p)
+			}
+			continue
+		}
+		if !ok || p.String() != tc.exp || v != tc.exp {
+			t.Errorf("%s: expected %s, got %v (%v, %v)", tc.addr, tc.exp, p, v, ok)
+		}
+	}
+
+	if tree.Len() != 8 {
+		t.Errorf("expected 8 prefixes, got %d", tree.Len())
+	}
+}
+
+func TestInsertExisting(t *testing.T) {
+	t.Parallel()
+
+	var tree Tree[int]
+	if !tree.Insert(netip.MustParsePrefix("10.0.0.0/8"), 1) {
+		t.Fatal("expected 10.0.0.0/8 to be inserted")
+	}
+	if tree.Insert(netip.MustParsePrefix("10.1.2.3/8"), 2) {
+		t.Fatal("expected 10.1.2.3/8 not to be inserted")
+	}
+	if p, v, _ := tree.Lookup(netip.MustParseAddr("10.9.9.9")); p.String() != "10.0.0.0/8" || v != 1 {
+		t.Fatalf("expected first value, got %v %v", p, v)
+	}
+	if tree.Len() != 1 {
+		t.Fatalf("expected 1 prefix, got %d", tree.Len())
+	}
+}
+
+// TestLookupRandom compares lookups against a linear scan of the prefixes.
+func TestLookupRandom(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+	randomAddr := func(v4 bool) netip.Addr {
+		if v4 {
+			var b [4]byte
+			r.Read(b[:])
+			// Keep addresses close together, so that prefixes nest.
+			b[0] = 10
+			return netip.AddrFrom4(b)
+		}
+		var b [16]byte
+		r.Read(b[:])
+		b[0], b[1] = 0x20, 0x01
+		return netip.AddrFrom16(b)
+	}
+
+	var tree Tree[netip.Prefix]
+	prefixes := map[netip.Prefix]struct{}{}
+	for i := range 2000 {
+		v4 := i%2 == 0
+		length := 8 + r.Intn(25)
+		if !v4 {
+			length = 16 + r.Intn(113)
+		}
+		p := netip.PrefixFrom(randomAddr(v4), length).Masked()
+		tree.Insert(p, p)
+		prefixes[p] = struct{}{}
+	}
+	if tree.Len() != len(prefixes) {
+		t.Fatalf("expected %d prefixes, got %d", len(prefixes), tree.Len())
+	}
+
+	for i := range 20000 {
+		a := randomAddr(i%2 == 0)
+
+		var exp netip.Prefix
+		for p := range prefixes {
+			if p.Contains(a) && (!exp.IsValid() || p.Bits() > exp.Bits()) {
+				exp = p
+			}
+		}
+
+		p, v, ok := tree.Lookup(a)
+		if ok != exp.IsValid() || p != exp || v != exp {
+			t.Fatalf("%v: expected %v, got %v (%v)", a, exp, p, ok)
+		}
+	}
+}
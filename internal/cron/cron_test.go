@@ -0,0 +1,187 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package cron
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"* * * *":       "cron expression must have 5 fields, found 4",
+		"60 * * * *":    `invalid minute "60"`,
+		"* 24 * * *":    `invalid hour "24"`,
+		"* * 0 * *":     `invalid day of month "0"`,
+		"* * * foo *":   `invalid month "foo"`,
+		"* * * * 8":     `invalid day of week "8"`,
+		"5-1 * * * *":   `invalid minute range "5-1"`,
+		"*/0 * * * *":   `invalid minute step "0"`,
+		"@fortnightly":  `unknown cron macro "@fortnightly"`,
+		"1,,2 * * * *":  `invalid minute ""`,
+		"* * * jan-x *": `invalid month "x"`,
+	}
+
+	for expr, exp := range tests {
+		if _, err := Parse(expr); err == nil || err.Error() != exp {
+			t.Errorf("%s: expected error %q, got %v", expr, exp, err)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		expr     string
+		time     time.Time
+		expected bool
+	}{
+		{"* * * * *", time.Date(2026, 3, 6, 17, 42, 59, 0, time.UTC), true},
+		{"*/15 9-17 * * MON-FRI", time.Date(2026, 3, 6, 17, 45, 0, 0, time.UTC), true},
+		{"*/15 9-17 * * MON-FRI", time.Date(2026, 3, 6, 17, 46, 0, 0, time.UTC), false},
+		{"*/15 9-17 * * MON-FRI", time.Date(2026, 3, 7, 17, 45, 0, 0, time.UTC), false},
+		{"0 0 * * 7", time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC), true},
+		{"0 0 * * sun", time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC), true},
+		{"10-40/10 * * * *", time.Date(2026, 3, 8, 0, 30, 0, 0, time.UTC), true},
+		{"10-40/10 * * * *", time.Date(2026, 3, 8, 0, 50, 0, 0, time.UTC), false},
+		{"5/20 * * * *", time.Date(2026, 3, 8, 0, 45, 0, 0, time.UTC), true},
+		// either of the restricted day fields matches
+		{"0 0 13 * FRI", time.Date(2026, 3, 13, 0, 0, 0, 0, time.UTC), true},
+		{"0 0 13 * FRI", time.Date(2026, 3, 20, 0, 0, 0, 0, time.UTC), true},
+		{"0 0 13 * FRI", time.Date(2026, 3, 21, 0, 0, 0, 0, time.UTC), false},
+		{"0 0 13 * *", time.Date(2026, 3, 20, 0, 0, 0, 0, time.UTC), false},
+		// a day field starting with "*" is unrestricted, so both must match
+		{"0 0 */2 * MON", time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC), true},
+		{"0 0 */2 * MON", time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC), false},
+		{"0 0 */2 * MON", time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC), false},
+		{"0 0 1 * */2", time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), false},
+		{"@monthly", time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), true},
+		// the wall clock of the location is used
+		{"0 9 * * *", time.Date(2026, 7, 1, 7, 0, 0, 0, time.UTC).In(berlin), true},
+		{"0 9 * * *", time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC).In(berlin), true},
+	}
+
+	for _, tc := range tests {
+		s, err := Parse(tc.expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if act := s.Match(tc.time); act != tc.expected {
+			t.Errorf("%s at %v: expected %v, got %v", tc.expr, tc.time, tc.expected, act)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	t.Parallel()
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		note     string
+		expr     string
+		from     time.Time
+		expected time.Time
+	}{
+		{
+			note:     "next minute",
+			expr:     "* * * * *",
+			from:     time.Date(2026, 3, 6, 17, 42, 30, 0, time.UTC),
+			expected: time.Date(2026, 3, 6, 17, 43, 0, 0, time.UTC),
+		},
+		{
+			note:     "strictly after",
+			expr:     "0 * * * *",
+			from:     time.Date(2026, 3, 6, 17, 0, 0, 0, time.UTC),
+			expected: time.Date(2026, 3, 6, 18, 0, 0, 0, time.UTC),
+		},
+		{
+			note:     "next weekday",
+			expr:     "30 8 * * MON-FRI",
+			from:     time.Date(2026, 3, 6, 9, 0, 0, 0, time.UTC),
+			expected: time.Date(2026, 3, 9, 8, 30, 0, 0, time.UTC),
+		},
+		{
+			note:     "leap day",
+			expr:     "0 0 29 2 *",
+			from:     time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+			expected: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			note:     "half hour offset",
+			expr:     "0 9 * * *",
+			from:     time.Date(2026, 3, 6, 12, 0, 0, 0, kolkata),
+			expected: time.Date(2026, 3, 7, 9, 0, 0, 0, kolkata),
+		},
+		{
+			note:     "skipped by dst",
+			expr:     "30 2 * * *",
+			from:     time.Date(2026, 3, 29, 0, 0, 0, 0, berlin),
+			expected: time.Date(2026, 3, 30, 2, 30, 0, 0, berlin),
+		},
+		{
+			note:     "repeated by dst, first",
+			expr:     "30 2 * * *",
+			from:     time.Date(2026, 10, 25, 0, 0, 0, 0, berlin),
+			expected: time.Date(2026, 10, 25, 0, 30, 0, 0, time.UTC).In(berlin),
+		},
+		{
+			note:     "repeated by dst, second",
+			expr:     "30 2 * * *",
+			from:     time.Date(2026, 10, 25, 0, 30, 0, 0, time.UTC).In(berlin),
+			expected: time.Date(2026, 10, 25, 1, 30, 0, 0, time.UTC).In(berlin),
+		},
+		{
+			note:     "after dst",
+			expr:     "0 9 * * *",
+			from:     time.Date(2026, 3, 28, 9, 0, 0, 0, berlin),
+			expected: time.Date(2026, 3, 29, 9, 0, 0, 0, berlin),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			s, err := Parse(tc.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			act, err := s.Next(tc.from)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !act.Equal(tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, act)
+			}
+		})
+	}
+}
+
+func TestNextNoActivation(t *testing.T) {
+	t.Parallel()
+
+	s, err := Parse("0 0 30 2 *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Next(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)); !errors.Is(err, ErrNoActivation) {
+		t.Fatalf("expected ErrNoActivation, got %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package dataformat
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"unicode/utf8"
+)
+
+// CSVOptions control the parsing of CSV documents.
+type CSVOptions struct {
+	// Header makes the first record name the fields of the others, which are
+	// then returned as objects.
+	Header bool
+	// Delimiter separates the fields of a record. The default is ','.
+	Delimiter rune
+}
+
+// ParseCSV parses a CSV document (RFC 4180) into an array of records. Records
+// are arrays of strings, or objects if opts.Header is set. All records must
+// have the same number of fields.
+func ParseCSV(bs []byte, opts CSVOptions) ([]any, error) {
+	r := csv.NewReader(bytes.NewReader(bs))
+	if opts.Delimiter != 0 {
+		if opts.Delimiter == '"' || opts.Delimiter == '\r' || opts.Delimiter == '\n' || !utf8.ValidRune(opts.Delimiter) || opts.Delimiter == utf8.RuneError {
+			return nil, fmt.Errorf("csv: invalid delimiter %q", opts.Delimiter)
+		}
+		r.Comma = opts.Delimiter
+	}
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("csv: %w", err)
+	}
+
+	if !opts.Header {
+		result := make([]any, len(records))
+		for i, record := range records {
+			result[i] = csvRecord(record)
+		}
+		return result, nil
+	}
+
+	if len(records) == 0 {
+		return []any{}, nil
+	}
+
+	header := records[0]
+	seen := make(map[string]struct{}, len(header))
+	for _, name := range header {
+		if _, ok := seen[name]; ok {
+			return nil, fmt.Errorf("csv: duplicate column %q in header", name)
+		}
+		seen[name] = struct{}{}
+	}
+
+	result := make([]any, len(records)-1)
+	for i, record := range records[1:] {
+		obj := make(map[string]any, len(header))
+		for j, name := range header {
+			obj[name] = record[j]
+		}
+		result[i] = obj
+	}
+	return result, nil
+}
+
+func csvRecord(record []string) []any {
+	fields := make([]any, len(record))
+	for i, field := range record {
+		fields[i] = field
+	}
+	return fields
+}
@@ -0,0 +1,274 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package dataformat
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+type testCase struct {
+	note     string
+	input    string
+	expected string
+	err      string
+}
+
+func run(t *testing.T, tests []testCase, f func([]byte) (any, error)) {
+	t.Helper()
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			act, err := f([]byte(tc.input))
+			if tc.err != "" {
+				if err == nil || err.Error() != tc.err {
+					t.Fatalf("expected error %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			exp, err := decodeJSON([]byte(tc.expected))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(exp, act) {
+				bs, _ := json.Marshal(act)
+				t.Fatalf("expected %s, got %s", tc.expected, bs)
+			}
+		})
+	}
+}
+
+func TestUnmarshalTOML(t *testing.T) {
+	t.Parallel()
+
+	run(t, []testCase{
+		{
+			note:     "empty",
+			input:    "",
+			expected: `{}`,
+		},
+		{
+			note: "types",
+			input: `
+int = 9007199254740993
+float = 1.5e3
+bool = true
+offset = 1979-05-27T07:32:00-08:00
+local = 1979-05-27T07:32:00
+date = 1979-05-27
+time = 07:32:00.999
+
+[[servers]]
+name = "a"
+ports = [80, 443]
+
+[[servers]]
+name = "b"
+`,
+			expected: `{
+				"int": 9007199254740993,
+				"float": 1500,
+				"bool": true,
+				"offset": "1979-05-27T07:32:00-08:00",
+				"local": "1979-05-27T07:32:00",
+				"date": "1979-05-27",
+				"time": "07:32:00.999",
+				"servers": [{"name": "a", "ports": [80, 443]}, {"name": "b"}]
+			}`,
+		},
+		{
+			note:  "duplicate key",
+			input: "a = 1\na = 2",
+			err:   "toml: key a is already defined",
+		},
+		{
+			note:  "syntax error",
+			input: "a = 1\nb c = 2\n",
+			err:   "toml: line 2, column 3: expected character =",
+		},
+		{
+			note:  "infinity",
+			input: "[a]\nb = inf",
+			err:   "toml: a: b: unsupported number +Inf",
+		},
+	}, UnmarshalTOML)
+}
+
+func TestUnmarshalXML(t *testing.T) {
+	t.Parallel()
+
+	run(t, []testCase{
+		{
+			note: "mapping",
+			input: `<?xml version="1.0"?>
+<!-- comment -->
+<project xmlns="http://maven.apache.org/POM/4.0.0" id="x">
+  <version> 1.0 </version>
+  <dependency scope="test">junit</dependency>
+  <dependency><artifactId>guava</artifactId></dependency>
+  <empty/>
+</project>`,
+			expected: `{"project": {
+				"@id": "x",
+				"version": "1.0",
+				"dependency": [{"@scope": "test", "#text": "junit"}, {"artifactId": "guava"}],
+				"empty": ""
+			}}`,
+		},
+		{
+			note:  "no root",
+			input: `<!-- comment -->`,
+			err:   "xml: no root element",
+		},
+		{
+			note:  "two roots",
+			input: `<a/><b/>`,
+			err:   "xml: more than one root element",
+		},
+		{
+			note:  "text outside of the root",
+			input: `<a/>b`,
+			err:   "xml: text outside of the root element",
+		},
+		{
+			note:  "unclosed",
+			input: `<a><b></a>`,
+			err:   "XML syntax error on line 1: element <b> closed by </a>",
+		},
+	}, UnmarshalXML)
+}
+
+func TestParseCSV(t *testing.T) {
+	t.Parallel()
+
+	parse := func(opts CSVOptions) func([]byte) (any, error) {
+		return func(bs []byte) (any, error) {
+			return ParseCSV(bs, opts)
+		}
+	}
+
+	run(t, []testCase{
+		{
+			note:     "records",
+			input:    "a,b\n1,\"x, y\"\n",
+			expected: `[["a", "b"], ["1", "x, y"]]`,
+		},
+		{
+			note:     "empty",
+			input:    "",
+			expected: `[]`,
+		},
+		{
+			note:  "field count",
+			input: "a,b\n1\n",
+			err:   "csv: record on line 2: wrong number of fields",
+		},
+	}, parse(CSVOptions{}))
+
+	run(t, []testCase{
+		{
+			note:     "header",
+			input:    "name;port\nweb;80\ndb;5432\n",
+			expected: `[{"name": "web", "port": "80"}, {"name": "db", "port": "5432"}]`,
+		},
+		{
+			note:     "header only",
+			input:    "name;port\n",
+			expected: `[]`,
+		},
+		{
+			note:  "duplicate column",
+			input: "a;a\n1;2\n",
+			err:   `csv: duplicate column "a" in header`,
+		},
+	}, parse(CSVOptions{Header: true, Delimiter: ';'}))
+}
+
+func TestUnmarshalHCL(t *testing.T) {
+	t.Parallel()
+
+	run(t, []testCase{
+		{
+			note: "terraform",
+			input: `
+terraform {
+  required_version = ">= 1.0"
+}
+
+resource "aws_s3_bucket" "logs" {
+  bucket = "${var.prefix}-logs"
+  tags   = { Name = var.name, Env = "prod" }
+  acl    = var.acl
+  count  = 2 * 3
+}
+
+resource "aws_s3_bucket" "logs" {
+  policy = <<EOT
+{"Id": "${var.id}"}
+EOT
+}
+`,
+			expected: `{
+				"terraform": [{"required_version": ">= 1.0"}],
+				"resource": {"aws_s3_bucket": {"logs": [
+					{
+						"bucket": "${var.prefix}-logs",
+						"tags": {"Name": "${var.name}", "Env": "prod"},
+						"acl": "${var.acl}",
+						"count": 6
+					},
+					{"policy": "{\"Id\": \"${var.id}\"}\n"}
+				]}}
+			}`,
+		},
+		{
+			note:  "syntax error",
+			input: "a = ",
+			err:   "hcl: line 1, column 5: Missing expression; Expected the start of an expression, but found the end of the file.",
+		},
+		{
+			note:  "attribute and block",
+			input: "a = 1\na {}",
+			err:   `hcl: line 2, column 1: "a" is both an attribute and a block`,
+		},
+		{
+			note:  "labels",
+			input: "a {}\na \"b\" {}",
+			err:   `hcl: line 2, column 1: blocks of type "a" have different numbers of labels`,
+		},
+	}, UnmarshalHCL)
+}
+
+func TestUnmarshalINI(t *testing.T) {
+	t.Parallel()
+
+	run(t, []testCase{
+		{
+			note: "sections",
+			input: `
+; comment
+region = eu-west-1
+
+[profile dev]
+output = json
+output = text
+
+[DEFAULT]
+retries = 3
+`,
+			expected: `{"region": "eu-west-1", "retries": "3", "profile dev": {"output": "text"}}`,
+		},
+		{
+			note:  "conflict",
+			input: "a = 1\n[a]\nb = 2",
+			err:   `ini: section "a" conflicts with a key of the same name`,
+		},
+	}, UnmarshalINI)
+}
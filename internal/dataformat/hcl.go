@@ -0,0 +1,156 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package dataformat
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// UnmarshalHCL decodes a document in HCL native syntax, like Terraform
+// configurations, into an object.
+//
+// Attributes are keys of the object of their body. Blocks are nested by their
+// type and labels, and the innermost value is an array of the bodies of the
+// blocks of that type and labels, in document order:
+//
+//	resource "aws_s3_bucket" "logs" { bucket = "logs" }
+//
+// becomes {"resource": {"aws_s3_bucket": {"logs": [{"bucket": "logs"}]}}}.
+//
+// Expressions that can be evaluated without variables and functions are
+// replaced by their values. Other expressions are kept as strings of their
+// source: templates as written between the quotes or heredoc markers, like
+// "${var.name}-logs", and any other expression as an interpolation, like
+// "${var.tags}". Tuples and objects are decoded element by element.
+func UnmarshalHCL(bs []byte) (any, error) {
+	file, diags := hclsyntax.ParseConfig(bs, "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, hclError(diags[0])
+	}
+	return hclBody(file.Body.(*hclsyntax.Body), bs)
+}
+
+func hclBody(body *hclsyntax.Body, src []byte) (map[string]any, error) {
+	obj := make(map[string]any, len(body.Attributes)+len(body.Blocks))
+	for name, attr := range body.Attributes {
+		v, err := hclExpression(attr.Expr, src)
+		if err != nil {
+			return nil, err
+		}
+		obj[name] = v
+	}
+
+	for _, block := range body.Blocks {
+		if _, ok := body.Attributes[block.Type]; ok {
+			return nil, hclRangeError(block.TypeRange, fmt.Sprintf("%q is both an attribute and a block", block.Type))
+		}
+
+		content, err := hclBody(block.Body, src)
+		if err != nil {
+			return nil, err
+		}
+
+		parent, key := obj, block.Type
+		for _, label := range block.Labels {
+			switch next := parent[key].(type) {
+			case nil:
+				m := map[string]any{}
+				parent[key] = m
+				parent = m
+			case map[string]any:
+				parent = next
+			default:
+				return nil, hclRangeError(block.TypeRange, fmt.Sprintf("blocks of type %q have different numbers of labels", block.Type))
+			}
+			key = label
+		}
+
+		switch prev := parent[key].(type) {
+		case nil:
+			parent[key] = []any{content}
+		case []any:
+			parent[key] = append(prev, content)
+		default:
+			return nil, hclRangeError(block.TypeRange, fmt.Sprintf("blocks of type %q have different numbers of labels", block.Type))
+		}
+	}
+
+	return obj, nil
+}
+
+func hclExpression(expr hclsyntax.Expression, src []byte) (any, error) {
+	if v, diags := expr.Value(nil); !diags.HasErrors() && v.IsWhollyKnown() {
+		bs, err := ctyjson.SimpleJSONValue{Value: v}.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		return decodeJSON(bs)
+	}
+
+	switch e := expr.(type) {
+	case *hclsyntax.TupleConsExpr:
+		arr := make([]any, len(e.Exprs))
+		for i, x := range e.Exprs {
+			v, err := hclExpression(x, src)
+			if err != nil {
+				return nil, err
+			}
+			arr[i] = v
+		}
+		return arr, nil
+
+	case *hclsyntax.ObjectConsExpr:
+		obj := make(map[string]any, len(e.Items))
+		for _, item := range e.Items {
+			var key string
+			if k, diags := item.KeyExpr.Value(nil); !diags.HasErrors() && k.Type() == cty.String && k.IsKnown() && !k.IsNull() {
+				key = k.AsString()
+			} else {
+				key = "${" + string(item.KeyExpr.Range().SliceBytes(src)) + "}"
+			}
+			v, err := hclExpression(item.ValueExpr, src)
+			if err != nil {
+				return nil, err
+			}
+			obj[key] = v
+		}
+		return obj, nil
+
+	case *hclsyntax.TemplateExpr, *hclsyntax.TemplateWrapExpr:
+		s := expr.Range().SliceBytes(src)
+		if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+			return string(s[1 : len(s)-1]), nil
+		}
+		// heredocs: the lines between the opening and the closing marker
+		if bytes.HasPrefix(s, []byte("<<")) {
+			if i, j := bytes.IndexByte(s, '\n'), bytes.LastIndexByte(s, '\n'); i >= 0 && j > i {
+				return string(s[i+1 : j+1]), nil
+			}
+		}
+	}
+
+	return "${" + string(expr.Range().SliceBytes(src)) + "}", nil
+}
+
+func hclError(d *hcl.Diagnostic) error {
+	msg := d.Summary
+	if d.Detail != "" {
+		msg += "; " + d.Detail
+	}
+	if d.Subject == nil {
+		return fmt.Errorf("hcl: %s", msg)
+	}
+	return hclRangeError(*d.Subject, msg)
+}
+
+func hclRangeError(r hcl.Range, msg string) error {
+	return fmt.Errorf("hcl: line %d, column %d: %s", r.Start.Line, r.Start.Column, msg)
+}
@@ -0,0 +1,43 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package dataformat
+
+import (
+	"fmt"
+
+	"gopkg.in/ini.v1"
+)
+
+// UnmarshalINI decodes an INI document into an object. Keys outside of any
+// section (or in the DEFAULT section) are keys of the object, sections are
+// objects keyed by their name. All values are strings; if a key is repeated
+// in a section, the last value is used.
+func UnmarshalINI(bs []byte) (any, error) {
+	f, err := ini.LoadSources(ini.LoadOptions{}, bs)
+	if err != nil {
+		return nil, fmt.Errorf("ini: %w", err)
+	}
+
+	doc := map[string]any{}
+	for _, k := range f.Section(ini.DefaultSection).Keys() {
+		doc[k.Name()] = k.Value()
+	}
+
+	for _, section := range f.Sections() {
+		if section.Name() == ini.DefaultSection {
+			continue
+		}
+		if _, ok := doc[section.Name()]; ok {
+			return nil, fmt.Errorf("ini: section %q conflicts with a key of the same name", section.Name())
+		}
+		keys := make(map[string]any, len(section.Keys()))
+		for _, k := range section.Keys() {
+			keys[k.Name()] = k.Value()
+		}
+		doc[section.Name()] = keys
+	}
+
+	return doc, nil
+}
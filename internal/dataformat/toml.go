@@ -0,0 +1,75 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package dataformat
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// UnmarshalTOML decodes a TOML document into an object. Offset date-times are
+// formatted as RFC 3339 strings, local dates, times and date-times as their
+// TOML representation.
+func UnmarshalTOML(bs []byte) (any, error) {
+	var doc map[string]any
+	if err := toml.Unmarshal(bs, &doc); err != nil {
+		var de *toml.DecodeError
+		if errors.As(err, &de) {
+			line, column := de.Position()
+			return nil, fmt.Errorf("toml: line %d, column %d: %s", line, column, strings.TrimPrefix(de.Error(), "toml: "))
+		}
+		return nil, err
+	}
+	if doc == nil {
+		doc = map[string]any{}
+	}
+	v, err := tomlValue(doc)
+	if err != nil {
+		return nil, fmt.Errorf("toml: %w", err)
+	}
+	return v, nil
+}
+
+func tomlValue(x any) (any, error) {
+	switch x := x.(type) {
+	case map[string]any:
+		for k, v := range x {
+			v, err := tomlValue(v)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", k, err)
+			}
+			x[k] = v
+		}
+		return x, nil
+	case []any:
+		for i, v := range x {
+			v, err := tomlValue(v)
+			if err != nil {
+				return nil, err
+			}
+			x[i] = v
+		}
+		return x, nil
+	case int64:
+		return json.Number(strconv.FormatInt(x, 10)), nil
+	case float64:
+		return number(x)
+	case time.Time:
+		return x.Format(time.RFC3339Nano), nil
+	case toml.LocalDate:
+		return x.String(), nil
+	case toml.LocalTime:
+		return x.String(), nil
+	case toml.LocalDateTime:
+		return x.String(), nil
+	}
+	return x, nil
+}
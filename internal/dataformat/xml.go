@@ -0,0 +1,111 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package dataformat
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+)
+
+// UnmarshalXML decodes an XML document into an object with the name of the
+// root element as its only key. Elements are mapped as follows:
+//
+//   - an element without attributes and child elements is the string of its
+//     text content,
+//   - any other element is an object with its attributes as keys prefixed by
+//     "@", its child elements by name, and its text content, if any, as
+//     "#text",
+//   - child elements that occur more than once are collected into an array,
+//     in document order.
+//
+// Text content is trimmed of leading and trailing white space. Names are
+// local names, namespace prefixes and declarations are dropped. Comments,
+// processing instructions and directives are ignored.
+func UnmarshalXML(bs []byte) (any, error) {
+	dec := xml.NewDecoder(bytes.NewReader(bs))
+
+	var root any
+	var name string
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if root != nil {
+				return nil, errors.New("xml: more than one root element")
+			}
+			name = t.Name.Local
+			root, err = xmlElement(dec, t)
+			if err != nil {
+				return nil, err
+			}
+		case xml.CharData:
+			if len(bytes.TrimSpace(t)) > 0 {
+				return nil, errors.New("xml: text outside of the root element")
+			}
+		}
+	}
+
+	if root == nil {
+		return nil, errors.New("xml: no root element")
+	}
+	return map[string]any{name: root}, nil
+}
+
+func xmlElement(dec *xml.Decoder, start xml.StartElement) (any, error) {
+	obj := map[string]any{}
+	for _, attr := range start.Attr {
+		if attr.Name.Space == "xmlns" || attr.Name.Space == "" && attr.Name.Local == "xmlns" {
+			continue
+		}
+		obj["@"+attr.Name.Local] = attr.Value
+	}
+
+	var text strings.Builder
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			child, err := xmlElement(dec, t)
+			if err != nil {
+				return nil, err
+			}
+			// element values are strings or objects, so an array is a
+			// repeated element
+			switch prev := obj[t.Name.Local].(type) {
+			case nil:
+				obj[t.Name.Local] = child
+			case []any:
+				obj[t.Name.Local] = append(prev, child)
+			default:
+				obj[t.Name.Local] = []any{prev, child}
+			}
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			s := strings.TrimSpace(text.String())
+			if len(obj) == 0 {
+				return s, nil
+			}
+			if s != "" {
+				obj["#text"] = s
+			}
+			return obj, nil
+		}
+	}
+}
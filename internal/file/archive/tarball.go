@@ -8,26 +8,54 @@ import (
 	"errors"
 	"io"
 
+	"github.com/klauspost/compress/zstd"
+
 	"github.com/open-policy-agent/opa/v1/util"
 )
 
-type TarGzWriter struct {
+// TarWriter writes files to a tarball, compressing it if the tarball was
+// created with NewTarGzWriter or NewTarZstdWriter.
+type TarWriter struct {
 	*tar.Writer
 
-	gw *gzip.Writer
+	cw io.WriteCloser
 }
 
-func NewTarGzWriter(w io.Writer) *TarGzWriter {
-	gw := gzip.NewWriter(w)
-	tw := tar.NewWriter(gw)
+// NewTarGzWriter returns a TarWriter that writes a gzip compressed tarball.
+func NewTarGzWriter(w io.Writer) *TarWriter {
+	return newTarWriter(gzip.NewWriter(w))
+}
 
-	return &TarGzWriter{
-		Writer: tw,
-		gw:     gw,
+// NewTarZstdWriter returns a TarWriter that writes a zstd compressed tarball.
+func NewTarZstdWriter(w io.Writer) (*TarWriter, error) {
+	zw, err := zstd.NewWriter(w, zstd.WithEncoderConcurrency(1))
+	if err != nil {
+		return nil, err
 	}
+	return newTarWriter(zw), nil
+}
+
+// NewTarWriter returns a TarWriter that writes an uncompressed tarball.
+func NewTarWriter(w io.Writer) *TarWriter {
+	return newTarWriter(nopCloser{w})
+}
+
+func newTarWriter(cw io.WriteCloser) *TarWriter {
+	return &TarWriter{
+		Writer: tar.NewWriter(cw),
+		cw:     cw,
+	}
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
 }
 
-func (tgw *TarGzWriter) WriteFile(path string, bs []byte) (err error) {
+func (tgw *TarWriter) WriteFile(path string, bs []byte) (err error) {
 	hdr := &tar.Header{
 		Name:     path,
 		Mode:     0600,
@@ -42,7 +70,7 @@ func (tgw *TarGzWriter) WriteFile(path string, bs []byte) (err error) {
 	return err
 }
 
-func (tgw *TarGzWriter) WriteJSONFile(path string, v any) error {
+func (tgw *TarWriter) WriteJSONFile(path string, v any) error {
 	buf := &bytes.Buffer{}
 	if err := json.NewEncoder(buf).Encode(v); err != nil {
 		return err
@@ -51,8 +79,8 @@ func (tgw *TarGzWriter) WriteJSONFile(path string, v any) error {
 	return tgw.WriteFile(path, buf.Bytes())
 }
 
-func (tgw *TarGzWriter) Close() error {
-	return errors.Join(tgw.Writer.Close(), tgw.gw.Close())
+func (tgw *TarWriter) Close() error {
+	return errors.Join(tgw.Writer.Close(), tgw.cw.Close())
 }
 
 // MustWriteTarGz writes the list of file names and content into a tarball.
@@ -0,0 +1,142 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package jsonpath
+
+import (
+	"github.com/open-policy-agent/opa/v1/ast"
+)
+
+// logicalExpr is a filter expression, or an argument of LogicalType.
+type logicalExpr interface {
+	test(root, current ast.Value) bool
+}
+
+type orExpr []logicalExpr
+
+func (e orExpr) test(root, current ast.Value) bool {
+	for _, x := range e {
+		if x.test(root, current) {
+			return true
+		}
+	}
+	return false
+}
+
+type andExpr []logicalExpr
+
+func (e andExpr) test(root, current ast.Value) bool {
+	for _, x := range e {
+		if !x.test(root, current) {
+			return false
+		}
+	}
+	return true
+}
+
+type notExpr struct {
+	expr logicalExpr
+}
+
+func (e notExpr) test(root, current ast.Value) bool {
+	return !e.expr.test(root, current)
+}
+
+// existsExpr tests that a query selects at least one node.
+type existsExpr struct {
+	query *filterQuery
+}
+
+func (e existsExpr) test(root, current ast.Value) bool {
+	return len(e.query.nodes(root, current)) > 0
+}
+
+type comparisonExpr struct {
+	op          string
+	left, right valueExpr
+}
+
+func (e comparisonExpr) test(root, current ast.Value) bool {
+	a, b := e.left.value(root, current), e.right.value(root, current)
+	switch e.op {
+	case "==":
+		return equal(a, b)
+	case "!=":
+		return !equal(a, b)
+	case "<":
+		return less(a, b)
+	case "<=":
+		return less(a, b) || equal(a, b)
+	case ">":
+		return less(b, a)
+	case ">=":
+		return less(b, a) || equal(a, b)
+	}
+	return false
+}
+
+// equal compares two values, where nil stands for Nothing: the result of a
+// query that selects no node, or of a function that yields no value.
+func equal(a, b ast.Value) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return ast.Compare(a, b) == 0
+}
+
+// less orders numbers and strings. Values of other types are not ordered.
+func less(a, b ast.Value) bool {
+	switch a.(type) {
+	case ast.Number:
+		if _, ok := b.(ast.Number); ok {
+			return ast.Compare(a, b) < 0
+		}
+	case ast.String:
+		if _, ok := b.(ast.String); ok {
+			return ast.Compare(a, b) < 0
+		}
+	}
+	return false
+}
+
+// valueExpr is a comparable, or an argument of ValueType. A nil value is
+// Nothing.
+type valueExpr interface {
+	value(root, current ast.Value) ast.Value
+}
+
+type literal struct {
+	v ast.Value
+}
+
+func (e literal) value(ast.Value, ast.Value) ast.Value {
+	return e.v
+}
+
+// filterQuery is a query embedded in a filter expression, relative to the
+// current node (@) or to the root node ($).
+type filterQuery struct {
+	relative bool
+	segments []*segment
+}
+
+func (q *filterQuery) nodes(root, current ast.Value) []ast.Value {
+	if q.relative {
+		return selectSegments(q.segments, root, current)
+	}
+	return selectSegments(q.segments, root, root)
+}
+
+// singularQuery is a filter query that selects at most one node, used as a
+// value.
+type singularQuery struct {
+	query *filterQuery
+}
+
+func (e singularQuery) value(root, current ast.Value) ast.Value {
+	if nodes := e.query.nodes(root, current); len(nodes) == 1 {
+		return nodes[0]
+	}
+	return nil
+}
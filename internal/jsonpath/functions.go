@@ -0,0 +1,177 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package jsonpath
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+)
+
+// exprType is the declared type of a function parameter or result (RFC 9535,
+// Section 2.4.1).
+type exprType int
+
+const (
+	valueType exprType = iota
+	logicalType
+	nodesType
+)
+
+func (t exprType) String() string {
+	switch t {
+	case logicalType:
+		return "LogicalType"
+	case nodesType:
+		return "NodesType"
+	}
+	return "ValueType"
+}
+
+// result holds the result of a function, or one of its arguments, according
+// to its declared type.
+type result struct {
+	value   ast.Value
+	logical bool
+	nodes   []ast.Value
+}
+
+type function struct {
+	params []exprType
+	result exprType
+	call   func(args []result) result
+}
+
+// functions are the function extensions defined by RFC 9535, Section 2.4.
+var functions = map[string]*function{
+	"length": {
+		params: []exprType{valueType},
+		result: valueType,
+		call: func(args []result) result {
+			switch v := args[0].value.(type) {
+			case ast.String:
+				return result{value: ast.InternedTerm(utf8.RuneCountInString(string(v))).Value}
+			case *ast.Array:
+				return result{value: ast.InternedTerm(v.Len()).Value}
+			case ast.Object:
+				return result{value: ast.InternedTerm(v.Len()).Value}
+			}
+			return result{}
+		},
+	},
+	"count": {
+		params: []exprType{nodesType},
+		result: valueType,
+		call: func(args []result) result {
+			return result{value: ast.InternedTerm(len(args[0].nodes)).Value}
+		},
+	},
+	"match": {
+		params: []exprType{valueType, valueType},
+		result: logicalType,
+		call: func(args []result) result {
+			return result{logical: matchRegexp(args, true)}
+		},
+	},
+	"search": {
+		params: []exprType{valueType, valueType},
+		result: logicalType,
+		call: func(args []result) result {
+			return result{logical: matchRegexp(args, false)}
+		},
+	},
+	"value": {
+		params: []exprType{nodesType},
+		result: valueType,
+		call: func(args []result) result {
+			if len(args[0].nodes) == 1 {
+				return result{value: args[0].nodes[0]}
+			}
+			return result{}
+		},
+	},
+}
+
+// matchRegexp implements match and search. Patterns that are not valid yield
+// false rather than an error.
+func matchRegexp(args []result, anchored bool) bool {
+	s, ok := args[0].value.(ast.String)
+	if !ok {
+		return false
+	}
+	pattern, ok := args[1].value.(ast.String)
+	if !ok {
+		return false
+	}
+	re, err := compileIRegexp(string(pattern), anchored)
+	if err != nil {
+		return false
+	}
+	return re.MatchString(string(s))
+}
+
+// compileIRegexp compiles an I-Regexp (RFC 9485). The only difference in the
+// syntax RE2 accepts is that '.' matches any character but line terminators.
+func compileIRegexp(pattern string, anchored bool) (*regexp.Regexp, error) {
+	var sb strings.Builder
+	class := false
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '\\' && i+1 < len(pattern):
+			sb.WriteByte(c)
+			i++
+			sb.WriteByte(pattern[i])
+			continue
+		case c == '[':
+			class = true
+		case c == ']':
+			class = false
+		case c == '.' && !class:
+			sb.WriteString(`[^\n\r]`)
+			continue
+		}
+		sb.WriteByte(c)
+	}
+
+	// The pattern is compiled on its own first, so that it can't close the
+	// group it's anchored by.
+	re, err := regexp.Compile(sb.String())
+	if err != nil || !anchored {
+		return re, err
+	}
+	return regexp.Compile(`\A(?:` + sb.String() + `)\z`)
+}
+
+// functionExpr is a function call in a filter expression. Its arguments have
+// been converted to the declared parameter types.
+type functionExpr struct {
+	fn   *function
+	args []argument
+}
+
+func (e *functionExpr) call(root, current ast.Value) result {
+	args := make([]result, len(e.args))
+	for i, arg := range e.args {
+		args[i] = arg(root, current)
+	}
+	return e.fn.call(args)
+}
+
+func (e *functionExpr) value(root, current ast.Value) ast.Value {
+	return e.call(root, current).value
+}
+
+func (e *functionExpr) test(root, current ast.Value) bool {
+	r := e.call(root, current)
+	if e.fn.result == nodesType {
+		return len(r.nodes) > 0
+	}
+	return r.logical
+}
+
+type argument func(root, current ast.Value) result
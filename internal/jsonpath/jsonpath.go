@@ -0,0 +1,182 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+// Package jsonpath implements JSONPath queries (RFC 9535) over AST values.
+package jsonpath
+
+import (
+	"github.com/open-policy-agent/opa/v1/ast"
+)
+
+// Query is a parsed JSONPath query. Queries are safe for concurrent use.
+type Query struct {
+	segments []*segment
+}
+
+// Select returns the nodelist the query selects from root, in the order
+// defined by RFC 9535. Object members are visited in key order.
+func (q *Query) Select(root ast.Value) []ast.Value {
+	return selectSegments(q.segments, root, root)
+}
+
+// selectSegments applies the segments to the nodelist of the single node v.
+func selectSegments(segments []*segment, root, v ast.Value) []ast.Value {
+	nodes := []ast.Value{v}
+	for _, seg := range segments {
+		var next []ast.Value
+		for _, node := range nodes {
+			if seg.descendant {
+				next = seg.descend(root, node, next)
+			} else {
+				next = seg.apply(root, node, next)
+			}
+		}
+		if len(next) == 0 {
+			return nil
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+type segment struct {
+	descendant bool
+	selectors  []selector
+}
+
+func (s *segment) apply(root, v ast.Value, out []ast.Value) []ast.Value {
+	for _, sel := range s.selectors {
+		out = sel.selectFrom(root, v, out)
+	}
+	return out
+}
+
+// descend applies the selectors to v and all of its descendants, visiting
+// nodes before their children.
+func (s *segment) descend(root, v ast.Value, out []ast.Value) []ast.Value {
+	out = s.apply(root, v, out)
+	forEachChild(v, func(child ast.Value) {
+		out = s.descend(root, child, out)
+	})
+	return out
+}
+
+// singular reports whether the segments select at most one node.
+func singular(segments []*segment) bool {
+	for _, seg := range segments {
+		if seg.descendant || len(seg.selectors) != 1 {
+			return false
+		}
+		switch seg.selectors[0].(type) {
+		case nameSelector, indexSelector:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func forEachChild(v ast.Value, f func(ast.Value)) {
+	switch v := v.(type) {
+	case *ast.Array:
+		for i := range v.Len() {
+			f(v.Elem(i).Value)
+		}
+	case ast.Object:
+		v.Foreach(func(_, val *ast.Term) {
+			f(val.Value)
+		})
+	}
+}
+
+type selector interface {
+	selectFrom(root, v ast.Value, out []ast.Value) []ast.Value
+}
+
+type nameSelector string
+
+func (s nameSelector) selectFrom(_, v ast.Value, out []ast.Value) []ast.Value {
+	if obj, ok := v.(ast.Object); ok {
+		if val := obj.Get(ast.StringTerm(string(s))); val != nil {
+			out = append(out, val.Value)
+		}
+	}
+	return out
+}
+
+type wildcardSelector struct{}
+
+func (wildcardSelector) selectFrom(_, v ast.Value, out []ast.Value) []ast.Value {
+	forEachChild(v, func(child ast.Value) {
+		out = append(out, child)
+	})
+	return out
+}
+
+type indexSelector int
+
+func (s indexSelector) selectFrom(_, v ast.Value, out []ast.Value) []ast.Value {
+	if arr, ok := v.(*ast.Array); ok {
+		i := int(s)
+		if i < 0 {
+			i += arr.Len()
+		}
+		if i >= 0 && i < arr.Len() {
+			out = append(out, arr.Elem(i).Value)
+		}
+	}
+	return out
+}
+
+type sliceSelector struct {
+	start, end *int
+	step       int
+}
+
+func (s sliceSelector) selectFrom(_, v ast.Value, out []ast.Value) []ast.Value {
+	arr, ok := v.(*ast.Array)
+	if !ok || s.step == 0 {
+		return out
+	}
+
+	n := arr.Len()
+	normalize := func(i *int, def int) int {
+		switch {
+		case i == nil:
+			return def
+		case *i < 0:
+			return n + *i
+		}
+		return *i
+	}
+
+	if s.step > 0 {
+		lower := min(max(normalize(s.start, 0), 0), n)
+		upper := min(max(normalize(s.end, n), 0), n)
+		for i := lower; i < upper; i += s.step {
+			out = append(out, arr.Elem(i).Value)
+		}
+		return out
+	}
+
+	upper := min(max(normalize(s.start, n-1), -1), n-1)
+	lower := min(max(normalize(s.end, -n-1), -1), n-1)
+	for i := upper; lower < i; i += s.step {
+		out = append(out, arr.Elem(i).Value)
+	}
+	return out
+}
+
+type filterSelector struct {
+	expr logicalExpr
+}
+
+func (s filterSelector) selectFrom(root, v ast.Value, out []ast.Value) []ast.Value {
+	forEachChild(v, func(child ast.Value) {
+		if s.expr.test(root, child) {
+			out = append(out, child)
+		}
+	})
+	return out
+}
@@ -0,0 +1,274 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package jsonpath
+
+import (
+	"testing"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+)
+
+// Examples from RFC 9535, Section 1.5 and Section 2.
+const (
+	bookstore = `{"store": {
+		"book": [
+			{"category": "reference", "author": "Nigel Rees", "title": "Sayings of the Century", "price": 8.95},
+			{"category": "fiction", "author": "Evelyn Waugh", "title": "Sword of Honour", "price": 12.99},
+			{"category": "fiction", "author": "Herman Melville", "title": "Moby Dick", "isbn": "0-553-21311-3", "price": 8.99},
+			{"category": "fiction", "author": "J. R. R. Tolkien", "title": "The Lord of the Rings", "isbn": "0-395-19395-8", "price": 22.99}
+		],
+		"bicycle": {"color": "red", "price": 399}
+	}}`
+	filterDoc = `{
+		"a": [3, 5, 1, 2, 4, 6, {"b": "j"}, {"b": "k"}, {"b": {}}, {"b": "kilo"}],
+		"o": {"p": 1, "q": 2, "r": 3, "s": 5, "t": {"u": 6}},
+		"e": "f"
+	}`
+	comparisonDoc = `{"obj": {"x": "y"}, "arr": [2, 3]}`
+	letters       = `["a", "b", "c", "d", "e", "f", "g"]`
+)
+
+func TestSelect(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		doc   string
+		query string
+		exp   string
+	}{
+		{bookstore, `$.store.book[*].author`, `["Nigel Rees", "Evelyn Waugh", "Herman Melville", "J. R. R. Tolkien"]`},
+		{bookstore, `$..author`, `["Nigel Rees", "Evelyn Waugh", "Herman Melville", "J. R. R. Tolkien"]`},
+		{bookstore, `$.store..price`, `[399, 8.95, 12.99, 8.99, 22.99]`},
+		{bookstore, `$..book[2].author`, `["Herman Melville"]`},
+		{bookstore, `$..book[2].publisher`, `[]`},
+		{bookstore, `$..book[-1].title`, `["The Lord of the Rings"]`},
+		{bookstore, `$..book[0,1].title`, `["Sayings of the Century", "Sword of Honour"]`},
+		{bookstore, `$..book[:2].title`, `["Sayings of the Century", "Sword of Honour"]`},
+		{bookstore, `$..book[?@.isbn].title`, `["Moby Dick", "The Lord of the Rings"]`},
+		{bookstore, `$..book[?@.price<10].title`, `["Sayings of the Century", "Moby Dick"]`},
+		{bookstore, `$..*.color`, `["red"]`},
+		{bookstore, `$`, `[` + bookstore + `]`},
+
+		// Name selectors and shorthands.
+		{`{"o": {"j j": {"k.k": 3}}, "'": {"@": 2}}`, `$.o['j j']`, `[{"k.k": 3}]`},
+		{`{"o": {"j j": {"k.k": 3}}, "'": {"@": 2}}`, `$.o['j j']['k.k']`, `[3]`},
+		{`{"o": {"j j": {"k.k": 3}}, "'": {"@": 2}}`, `$.o["j j"]["k.k"]`, `[3]`},
+		{`{"o": {"j j": {"k.k": 3}}, "'": {"@": 2}}`, `$["'"]["@"]`, `[2]`},
+		{`{"o": {"j j": {"k.k": 3}}, "'": {"@": 2}}`, `$['\'']['@']`, `[2]`},
+		{`{"☺": 1, "😀": 2}`, `$.☺`, `[1]`},
+		{`{"☺": 1, "😀": 2}`, `$["😀"]`, `[2]`},
+		{`{"a": {"b": 1}}`, "$ .a\n\t[ 'b' ]", `[1]`},
+
+		// Wildcards.
+		{`{"o": {"j": 1, "k": 2}, "a": [5, 3]}`, `$[*]`, `[[5, 3], {"j": 1, "k": 2}]`},
+		{`{"o": {"j": 1, "k": 2}, "a": [5, 3]}`, `$.o[*, *]`, `[1, 2, 1, 2]`},
+		{`{"o": {"j": 1, "k": 2}, "a": [5, 3]}`, `$.a[*]`, `[5, 3]`},
+		{`{"o": {"j": 1, "k": 2}, "a": [5, 3]}`, `$.o.j[*]`, `[]`},
+
+		// Index selectors.
+		{`["a", "b"]`, `$[1]`, `["b"]`},
+		{`["a", "b"]`, `$[-2]`, `["a"]`},
+		{`["a", "b"]`, `$[2]`, `[]`},
+		{`["a", "b"]`, `$[-3]`, `[]`},
+		{`{"0": "a"}`, `$[0]`, `[]`},
+
+		// Slice selectors.
+		{letters, `$[1:3]`, `["b", "c"]`},
+		{letters, `$[5:]`, `["f", "g"]`},
+		{letters, `$[1:5:2]`, `["b", "d"]`},
+		{letters, `$[5:1:-2]`, `["f", "d"]`},
+		{letters, `$[::-1]`, `["g", "f", "e", "d", "c", "b", "a"]`},
+		{letters, `$[-2:]`, `["f", "g"]`},
+		{letters, `$[-100:100:3]`, `["a", "d", "g"]`},
+		{letters, `$[1:3:0]`, `[]`},
+		{letters, `$[ 1 : 3 : 1 ]`, `["b", "c"]`},
+		{letters, `$[:]`, `["a", "b", "c", "d", "e", "f", "g"]`},
+		{`{"a": 1}`, `$[:]`, `[]`},
+
+		// Filter selectors.
+		{filterDoc, `$.a[?@.b == 'kilo']`, `[{"b": "kilo"}]`},
+		{filterDoc, `$.a[?(@.b == 'kilo')]`, `[{"b": "kilo"}]`},
+		{filterDoc, `$.a[?@>3.5]`, `[5, 4, 6]`},
+		{filterDoc, `$.a[?@.b]`, `[{"b": "j"}, {"b": "k"}, {"b": {}}, {"b": "kilo"}]`},
+		{filterDoc, `$[?@.*]`, `[[3, 5, 1, 2, 4, 6, {"b": "j"}, {"b": "k"}, {"b": {}}, {"b": "kilo"}], {"p": 1, "q": 2, "r": 3, "s": 5, "t": {"u": 6}}]`},
+		{filterDoc, `$[?@[?@.b]]`, `[[3, 5, 1, 2, 4, 6, {"b": "j"}, {"b": "k"}, {"b": {}}, {"b": "kilo"}]]`},
+		{filterDoc, `$.o[?@<3, ?@<3]`, `[1, 2, 1, 2]`},
+		{filterDoc, `$.a[?@<2 || @.b == "k"]`, `[1, {"b": "k"}]`},
+		{filterDoc, `$.a[?match(@.b, "[jk]")]`, `[{"b": "j"}, {"b": "k"}]`},
+		{filterDoc, `$.a[?search(@.b, "[jk]")]`, `[{"b": "j"}, {"b": "k"}, {"b": "kilo"}]`},
+		{filterDoc, `$.o[?@>1 && @<4]`, `[2, 3]`},
+		{filterDoc, `$.o[?@.u || @.x]`, `[{"u": 6}]`},
+		{filterDoc, `$.a[?@.b == $.x]`, `[3, 5, 1, 2, 4, 6]`},
+		{filterDoc, `$.a[?@ == @]`, `[3, 5, 1, 2, 4, 6, {"b": "j"}, {"b": "k"}, {"b": {}}, {"b": "kilo"}]`},
+		{filterDoc, `$.a[?!@.b]`, `[3, 5, 1, 2, 4, 6]`},
+		{filterDoc, `$.a[?!(@.b || @ > 2)]`, `[1, 2]`},
+		{filterDoc, `$.a[?@ == 1.0]`, `[1]`},
+		{filterDoc, `$.a[?@ == 1e0]`, `[1]`},
+		{filterDoc, `$.a[?@ == -0]`, `[]`},
+
+		// Descendant segments.
+		{`{"o": {"j": 1, "k": 2}, "a": [5, 3, [{"j": 4}, {"k": 6}]]}`, `$..j`, `[4, 1]`},
+		{`{"o": {"j": 1, "k": 2}, "a": [5, 3, [{"j": 4}, {"k": 6}]]}`, `$..[0]`, `[5, {"j": 4}]`},
+		{`{"o": {"j": 1, "k": 2}, "a": [5, 3, [{"j": 4}, {"k": 6}]]}`, `$.a..*`, `[5, 3, [{"j": 4}, {"k": 6}], {"j": 4}, {"k": 6}, 4, 6]`},
+		{`{"o": {"j": 1, "k": 2}, "a": [5, 3, [{"j": 4}, {"k": 6}]]}`, `$..o`, `[{"j": 1, "k": 2}]`},
+		{`{"o": {"j": 1, "k": 2}, "a": [5, 3, [{"j": 4}, {"k": 6}]]}`, `$.o..[*, *]`, `[1, 2, 1, 2]`},
+
+		// Null semantics.
+		{`{"a": null, "b": [null], "c": [{}], "null": 1}`, `$.a`, `[null]`},
+		{`{"a": null, "b": [null], "c": [{}], "null": 1}`, `$.a[0]`, `[]`},
+		{`{"a": null, "b": [null], "c": [{}], "null": 1}`, `$.b[0]`, `[null]`},
+		{`{"a": null, "b": [null], "c": [{}], "null": 1}`, `$.b[*]`, `[null]`},
+		{`{"a": null, "b": [null], "c": [{}], "null": 1}`, `$.b[?@]`, `[null]`},
+		{`{"a": null, "b": [null], "c": [{}], "null": 1}`, `$.b[?@==null]`, `[null]`},
+		{`{"a": null, "b": [null], "c": [{}], "null": 1}`, `$.c[?@.d==null]`, `[]`},
+		{`{"a": null, "b": [null], "c": [{}], "null": 1}`, `$.null`, `[1]`},
+
+		// Functions.
+		{`[{"a": "☺☺"}, {"a": [1, 2, 3]}, {"a": {"b": 1}}, {"a": 2}]`, `$[?length(@.a) >= 2]`, `[{"a": "☺☺"}, {"a": [1, 2, 3]}]`},
+		{`[{"a": [1]}, {"a": [1, 2]}]`, `$[?count(@.a[*]) == 2]`, `[{"a": [1, 2]}]`},
+		{`[{"a": [1]}, {"a": [1, 2]}]`, `$[?value(@..a[1]) == 2]`, `[{"a": [1, 2]}]`},
+		{`["ab", "a\nb", "axb"]`, `$[?match(@, "a.b")]`, `["axb"]`},
+		{`["ab", "a\nb", "axb"]`, `$[?match(@, "a.b|ab")]`, `["ab", "axb"]`},
+		{`["ab", "a\nb", "axb"]`, `$[?search(@, "[.]")]`, `[]`},
+		{`["ab", "a\nb", "axb"]`, `$[?match(@, "(")]`, `[]`},
+		{`["ab", "a\nb", "axb"]`, `$[?match(@, "a)|(b")]`, `[]`},
+		{`[{"s": "ab", "p": "a."}, {"s": "ab", "p": "b"}]`, `$[?match(@.s, @.p)]`, `[{"s": "ab", "p": "a."}]`},
+		{`[{"s": "ab", "p": "a."}, {"s": "ab", "p": "b"}]`, `$[?search(@.s, @.p)]`, `[{"s": "ab", "p": "a."}, {"s": "ab", "p": "b"}]`},
+		{`[1, [1]]`, `$[?match(@, "1")]`, `[]`},
+		{`[[1], [1, 2]]`, `$[?count(@[?@ > 1]) > 0]`, `[[1, 2]]`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.query, func(t *testing.T) {
+			t.Parallel()
+
+			q, err := Parse(tc.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			doc := ast.MustParseTerm(tc.doc).Value
+			nodes := q.Select(doc)
+			terms := make([]*ast.Term, len(nodes))
+			for i, node := range nodes {
+				terms[i] = ast.NewTerm(node)
+			}
+
+			if exp, act := ast.MustParseTerm(tc.exp), ast.ArrayTerm(terms...); !exp.Equal(act) {
+				t.Fatalf("expected %v but got %v", exp, act)
+			}
+		})
+	}
+}
+
+// Comparison examples from RFC 9535, Table 11.
+func TestComparisons(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		expr string
+		exp  bool
+	}{
+		{`$.absent1 == $.absent2`, true},
+		{`$.absent1 <= $.absent2`, true},
+		{`$.absent == 'g'`, false},
+		{`$.absent1 != $.absent2`, false},
+		{`$.absent != 'g'`, true},
+		{`1 <= 2`, true},
+		{`1 > 2`, false},
+		{`13 == '13'`, false},
+		{`'a' <= 'b'`, true},
+		{`'a' > 'b'`, false},
+		{`$.obj == $.arr`, false},
+		{`$.obj != $.arr`, true},
+		{`$.obj == $.obj`, true},
+		{`$.obj != $.obj`, false},
+		{`$.arr == $.arr`, true},
+		{`$.arr != $.arr`, false},
+		{`$.obj == 17`, false},
+		{`$.obj != 17`, true},
+		{`$.obj <= $.arr`, false},
+		{`$.obj < $.arr`, false},
+		{`$.obj <= $.obj`, true},
+		{`$.arr <= $.arr`, true},
+		{`1 <= $.arr`, false},
+		{`1 >= $.arr`, false},
+		{`1 > $.arr`, false},
+		{`1 < $.arr`, false},
+		{`true <= true`, true},
+		{`true > true`, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.expr, func(t *testing.T) {
+			t.Parallel()
+
+			q, err := Parse("$[?" + tc.expr + "]")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			doc := ast.MustParseTerm(comparisonDoc).Value
+			if act := len(q.Select(doc)) == 2; act != tc.exp {
+				t.Fatalf("expected %v but got %v", tc.exp, act)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		query string
+		err   string
+	}{
+		{``, `jsonpath: query must start with '$' at offset 0`},
+		{`a.b`, `jsonpath: query must start with '$' at offset 0`},
+		{`$ `, `jsonpath: unexpected character ' ' at offset 1`},
+		{`$.`, `jsonpath: unexpected end of query at offset 2`},
+		{`$.1`, `jsonpath: unexpected character '1' at offset 2`},
+		{`$..`, `jsonpath: unexpected end of query at offset 3`},
+		{`$[`, `jsonpath: unexpected end of query at offset 2`},
+		{`$[]`, `jsonpath: unexpected character ']' at offset 2`},
+		{`$[0,]`, `jsonpath: unexpected character ']' at offset 4`},
+		{`$[01]`, `jsonpath: invalid integer at offset 2`},
+		{`$[-0]`, `jsonpath: invalid integer at offset 2`},
+		{`$[9007199254740992]`, `jsonpath: integer out of range at offset 2`},
+		{`$[1.0]`, `jsonpath: unexpected character '.' at offset 3`},
+		{`$['a`, `jsonpath: unterminated string at offset 4`},
+		{`$['\"']`, `jsonpath: invalid escape sequence at offset 4`},
+		{`$["\uD800"]`, `jsonpath: invalid unicode escape sequence at offset 3`},
+		{`$["\uDC00"]`, `jsonpath: invalid unicode escape sequence at offset 3`},
+		{"$['\n']", `jsonpath: invalid control character in string at offset 3`},
+		{`$[?@.a == 01]`, `jsonpath: invalid number at offset 10`},
+		{`$[?1]`, `jsonpath: literal must be compared at offset 3`},
+		{`$[?@.* == 1]`, `jsonpath: query in comparison must be singular at offset 3`},
+		{`$[?@..a == 1]`, `jsonpath: query in comparison must be singular at offset 3`},
+		{`$[?!@.a == 1]`, `jsonpath: unexpected character '=' at offset 8`},
+		{`$[?length(@.a)]`, `jsonpath: function of ValueType must be compared at offset 3`},
+		{`$[?match(@.a, 'a') == true]`, `jsonpath: function in comparison must be of ValueType at offset 3`},
+		{`$[?length(@.*) == 1]`, `jsonpath: argument 1 of length must be of ValueType at offset 10`},
+		{`$[?count(1) == 1]`, `jsonpath: argument 1 of count must be of NodesType at offset 9`},
+		{`$[?count(@.a, @.b) == 1]`, `jsonpath: too many arguments to count at offset 3`},
+		{`$[?match(@.a)]`, `jsonpath: too few arguments to match at offset 3`},
+		{`$[?foo(@.a)]`, `jsonpath: unknown function foo at offset 3`},
+		{`$[?@.a == nil]`, `jsonpath: unexpected character 'n' at offset 10`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.query, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Parse(tc.query)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if err.Error() != tc.err {
+				t.Fatalf("expected error %q but got %q", tc.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,762 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package jsonpath
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+)
+
+// maxInt is the largest integer in the I-JSON range (RFC 7493), which bounds
+// array indices and slice parameters.
+const maxInt = 1<<53 - 1
+
+// Error is returned for queries that are not well-formed or not well-typed.
+type Error struct {
+	Offset  int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("jsonpath: %s at offset %d", e.Message, e.Offset)
+}
+
+// Parse parses a JSONPath query.
+func Parse(query string) (*Query, error) {
+	p := &parser{s: query}
+
+	if !p.consume("$") {
+		return nil, p.errorf("query must start with '$'")
+	}
+	segments, err := p.parseSegments()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.s) {
+		return nil, p.unexpected()
+	}
+	return &Query{segments: segments}, nil
+}
+
+type parser struct {
+	s   string
+	pos int
+}
+
+func (p *parser) errorf(format string, a ...any) error {
+	return &Error{Offset: p.pos, Message: fmt.Sprintf(format, a...)}
+}
+
+func (p *parser) unexpected() error {
+	if p.pos >= len(p.s) {
+		return p.errorf("unexpected end of query")
+	}
+	r, _ := utf8.DecodeRuneInString(p.s[p.pos:])
+	return p.errorf("unexpected character %q", r)
+}
+
+func (p *parser) peek() byte {
+	if p.pos < len(p.s) {
+		return p.s[p.pos]
+	}
+	return 0
+}
+
+func (p *parser) consume(token string) bool {
+	if strings.HasPrefix(p.s[p.pos:], token) {
+		p.pos += len(token)
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(token string) error {
+	if !p.consume(token) {
+		return p.unexpected()
+	}
+	return nil
+}
+
+func (p *parser) skipBlank() {
+	for p.pos < len(p.s) {
+		switch p.s[p.pos] {
+		case ' ', '\t', '\n', '\r':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+// parseSegments parses the segments following a root or current node
+// identifier. Blank space may precede each segment.
+func (p *parser) parseSegments() ([]*segment, error) {
+	var segments []*segment
+	for {
+		start := p.pos
+		p.skipBlank()
+		if c := p.peek(); c != '.' && c != '[' {
+			p.pos = start
+			return segments, nil
+		}
+		seg, err := p.parseSegment()
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, seg)
+	}
+}
+
+func (p *parser) parseSegment() (*segment, error) {
+	if p.peek() == '[' {
+		selectors, err := p.parseBracketed()
+		if err != nil {
+			return nil, err
+		}
+		return &segment{selectors: selectors}, nil
+	}
+
+	seg := &segment{}
+	if p.consume("..") {
+		seg.descendant = true
+		if p.peek() == '[' {
+			selectors, err := p.parseBracketed()
+			if err != nil {
+				return nil, err
+			}
+			seg.selectors = selectors
+			return seg, nil
+		}
+	} else {
+		p.pos++ // '.'
+	}
+
+	if p.consume("*") {
+		seg.selectors = []selector{wildcardSelector{}}
+		return seg, nil
+	}
+
+	name, ok := p.parseMemberName()
+	if !ok {
+		return nil, p.unexpected()
+	}
+	seg.selectors = []selector{nameSelector(name)}
+	return seg, nil
+}
+
+// parseMemberName parses the name in a member name shorthand, like .foo.
+func (p *parser) parseMemberName() (string, bool) {
+	start := p.pos
+	for p.pos < len(p.s) {
+		r, size := utf8.DecodeRuneInString(p.s[p.pos:])
+		switch {
+		case r == '_', 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z':
+		case '0' <= r && r <= '9':
+			if p.pos == start {
+				return "", false
+			}
+		case r >= utf8.RuneSelf && r != utf8.RuneError:
+		default:
+			return p.s[start:p.pos], p.pos > start
+		}
+		p.pos += size
+	}
+	return p.s[start:p.pos], p.pos > start
+}
+
+func (p *parser) parseBracketed() ([]selector, error) {
+	p.pos++ // '['
+
+	var selectors []selector
+	for {
+		p.skipBlank()
+		sel, err := p.parseSelector()
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, sel)
+
+		p.skipBlank()
+		if p.consume("]") {
+			return selectors, nil
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) parseSelector() (selector, error) {
+	switch c := p.peek(); {
+	case c == '\'' || c == '"':
+		name, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return nameSelector(name), nil
+	case c == '*':
+		p.pos++
+		return wildcardSelector{}, nil
+	case c == '?':
+		p.pos++
+		p.skipBlank()
+		expr, err := p.parseLogicalOr()
+		if err != nil {
+			return nil, err
+		}
+		return filterSelector{expr: expr}, nil
+	}
+
+	start, err := p.parseOptionalInt()
+	if err != nil {
+		return nil, err
+	}
+	p.skipBlank()
+	if !p.consume(":") {
+		if start == nil {
+			return nil, p.unexpected()
+		}
+		return indexSelector(*start), nil
+	}
+
+	sel := sliceSelector{start: start, step: 1}
+	p.skipBlank()
+	if sel.end, err = p.parseOptionalInt(); err != nil {
+		return nil, err
+	}
+	p.skipBlank()
+	if p.consume(":") {
+		p.skipBlank()
+		step, err := p.parseOptionalInt()
+		if err != nil {
+			return nil, err
+		}
+		if step != nil {
+			sel.step = *step
+		}
+	}
+	return sel, nil
+}
+
+// parseOptionalInt parses an integer, if there is one: a number without
+// fraction, exponent or leading zeros, other than -0.
+func (p *parser) parseOptionalInt() (*int, error) {
+	start := p.pos
+	p.consume("-")
+	digits := p.pos
+	for c := p.peek(); '0' <= c && c <= '9'; c = p.peek() {
+		p.pos++
+	}
+
+	switch {
+	case p.pos == digits:
+		if p.pos > start {
+			return nil, p.unexpected()
+		}
+		return nil, nil
+	case p.s[digits] == '0' && (p.pos > digits+1 || digits > start):
+		p.pos = start
+		return nil, p.errorf("invalid integer")
+	}
+
+	i, err := strconv.ParseInt(p.s[start:p.pos], 10, 64)
+	if err != nil || i > maxInt || i < -maxInt {
+		p.pos = start
+		return nil, p.errorf("integer out of range")
+	}
+	n := int(i)
+	return &n, nil
+}
+
+// parseString parses a string literal, delimited by single or double quotes.
+func (p *parser) parseString() (string, error) {
+	quote := p.s[p.pos]
+	p.pos++
+
+	var sb strings.Builder
+	for {
+		if p.pos >= len(p.s) {
+			return "", p.errorf("unterminated string")
+		}
+		c := p.s[p.pos]
+		switch {
+		case c == quote:
+			p.pos++
+			return sb.String(), nil
+		case c < 0x20:
+			return "", p.errorf("invalid control character in string")
+		case c != '\\':
+			r, size := utf8.DecodeRuneInString(p.s[p.pos:])
+			if r == utf8.RuneError && size == 1 {
+				return "", p.errorf("invalid UTF-8 in string")
+			}
+			sb.WriteString(p.s[p.pos : p.pos+size])
+			p.pos += size
+			continue
+		}
+
+		p.pos++ // '\\'
+		switch c := p.peek(); c {
+		case 'b':
+			sb.WriteByte('\b')
+		case 'f':
+			sb.WriteByte('\f')
+		case 'n':
+			sb.WriteByte('\n')
+		case 'r':
+			sb.WriteByte('\r')
+		case 't':
+			sb.WriteByte('\t')
+		case '/', '\\', quote:
+			sb.WriteByte(c)
+		case 'u':
+			r, err := p.parseUnicodeEscape()
+			if err != nil {
+				return "", err
+			}
+			sb.WriteRune(r)
+			continue
+		default:
+			return "", p.errorf("invalid escape sequence")
+		}
+		p.pos++
+	}
+}
+
+// parseUnicodeEscape parses the hexadecimal digits of a \u escape sequence,
+// and of the low surrogate that must follow a high surrogate.
+func (p *parser) parseUnicodeEscape() (rune, error) {
+	start := p.pos - 1
+	r, ok := p.parseHex4()
+	switch {
+	case !ok:
+	case utf16.IsSurrogate(r) && r < 0xdc00:
+		if p.consume(`\`) && p.peek() == 'u' {
+			if low, ok := p.parseHex4(); ok && 0xdc00 <= low && low <= 0xdfff {
+				return utf16.DecodeRune(r, low), nil
+			}
+		}
+	case !utf16.IsSurrogate(r):
+		return r, nil
+	}
+	p.pos = start
+	return 0, p.errorf("invalid unicode escape sequence")
+}
+
+func (p *parser) parseHex4() (rune, bool) {
+	p.pos++ // 'u'
+	if p.pos+4 > len(p.s) {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(p.s[p.pos:p.pos+4], 16, 32)
+	if err != nil {
+		return 0, false
+	}
+	p.pos += 4
+	return rune(n), true
+}
+
+func (p *parser) parseLogicalOr() (logicalExpr, error) {
+	var exprs orExpr
+	for {
+		expr, err := p.parseLogicalAnd()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+
+		start := p.pos
+		p.skipBlank()
+		if !p.consume("||") {
+			p.pos = start
+			break
+		}
+		p.skipBlank()
+	}
+	if len(exprs) == 1 {
+		return exprs[0], nil
+	}
+	return exprs, nil
+}
+
+func (p *parser) parseLogicalAnd() (logicalExpr, error) {
+	var exprs andExpr
+	for {
+		expr, err := p.parseBasic()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+
+		start := p.pos
+		p.skipBlank()
+		if !p.consume("&&") {
+			p.pos = start
+			break
+		}
+		p.skipBlank()
+	}
+	if len(exprs) == 1 {
+		return exprs[0], nil
+	}
+	return exprs, nil
+}
+
+// parseBasic parses a parenthesized, comparison or test expression, possibly
+// negated.
+func (p *parser) parseBasic() (logicalExpr, error) {
+	if p.consume("!") {
+		p.skipBlank()
+		expr, err := p.parseNegatable()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{expr: expr}, nil
+	}
+
+	if p.peek() == '(' {
+		return p.parseParen()
+	}
+
+	start := p.pos
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	end := p.pos
+	p.skipBlank()
+	op := p.parseComparisonOp()
+	if op == "" {
+		p.pos = end
+		return p.testExpr(left, start)
+	}
+
+	p.skipBlank()
+	rightStart := p.pos
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := p.comparable(left, start)
+	if err != nil {
+		return nil, err
+	}
+	r, err := p.comparable(right, rightStart)
+	if err != nil {
+		return nil, err
+	}
+	return comparisonExpr{op: op, left: l, right: r}, nil
+}
+
+// parseNegatable parses what may follow a logical not: a parenthesized or a
+// test expression.
+func (p *parser) parseNegatable() (logicalExpr, error) {
+	if p.peek() == '(' {
+		return p.parseParen()
+	}
+
+	start := p.pos
+	operand, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	return p.testExpr(operand, start)
+}
+
+func (p *parser) parseParen() (logicalExpr, error) {
+	p.pos++ // '('
+	p.skipBlank()
+	expr, err := p.parseLogicalOr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipBlank()
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	return expr, nil
+}
+
+func (p *parser) parseComparisonOp() string {
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.consume(op) {
+			return op
+		}
+	}
+	return ""
+}
+
+// operand is a literal, filter query or function call, before it's known
+// whether it's used as a comparable, a test or a function argument.
+type operand struct {
+	literal  ast.Value
+	query    *filterQuery
+	function *functionExpr
+}
+
+func (p *parser) parseOperand() (*operand, error) {
+	switch c := p.peek(); {
+	case c == '$' || c == '@':
+		p.pos++
+		segments, err := p.parseSegments()
+		if err != nil {
+			return nil, err
+		}
+		return &operand{query: &filterQuery{relative: c == '@', segments: segments}}, nil
+	case c == '\'' || c == '"':
+		s, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return &operand{literal: ast.String(s)}, nil
+	case c == '-' || '0' <= c && c <= '9':
+		n, err := p.parseNumber()
+		if err != nil {
+			return nil, err
+		}
+		return &operand{literal: n}, nil
+	case 'a' <= c && c <= 'z':
+		return p.parseNameOperand()
+	}
+	return nil, p.unexpected()
+}
+
+// parseNumber parses a number literal, with the syntax of JSON numbers, except
+// that -0 is allowed.
+func (p *parser) parseNumber() (ast.Value, error) {
+	start := p.pos
+	p.consume("-")
+
+	digits := func() int {
+		n := 0
+		for c := p.peek(); '0' <= c && c <= '9'; c = p.peek() {
+			p.pos++
+			n++
+		}
+		return n
+	}
+
+	intStart := p.pos
+	switch n := digits(); {
+	case n == 0:
+		return nil, p.unexpected()
+	case n > 1 && p.s[intStart] == '0':
+		p.pos = start
+		return nil, p.errorf("invalid number")
+	}
+	if p.consume(".") && digits() == 0 {
+		return nil, p.unexpected()
+	}
+	if c := p.peek(); c == 'e' || c == 'E' {
+		p.pos++
+		if c := p.peek(); c == '+' || c == '-' {
+			p.pos++
+		}
+		if digits() == 0 {
+			return nil, p.unexpected()
+		}
+	}
+	return ast.Number(p.s[start:p.pos]), nil
+}
+
+func (p *parser) parseNameOperand() (*operand, error) {
+	start := p.pos
+	for c := p.peek(); c == '_' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9'; c = p.peek() {
+		p.pos++
+	}
+	name := p.s[start:p.pos]
+
+	if p.peek() != '(' {
+		switch name {
+		case "true":
+			return &operand{literal: ast.Boolean(true)}, nil
+		case "false":
+			return &operand{literal: ast.Boolean(false)}, nil
+		case "null":
+			return &operand{literal: ast.Null{}}, nil
+		}
+		p.pos = start
+		return nil, p.unexpected()
+	}
+
+	fn, ok := functions[name]
+	if !ok {
+		p.pos = start
+		return nil, p.errorf("unknown function %s", name)
+	}
+	p.pos++ // '('
+
+	var args []argument
+	var literals []ast.Value
+	for p.skipBlank(); !p.consume(")"); {
+		if len(args) > 0 {
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+			p.skipBlank()
+		}
+		if len(args) == len(fn.params) {
+			p.pos = start
+			return nil, p.errorf("too many arguments to %s", name)
+		}
+
+		argStart := p.pos
+		arg, lit, err := p.parseArgument(fn.params[len(args)])
+		if err != nil {
+			return nil, err
+		}
+		if arg == nil {
+			p.pos = argStart
+			return nil, p.errorf("argument %d of %s must be of %s", len(args)+1, name, fn.params[len(args)])
+		}
+		args = append(args, arg)
+		literals = append(literals, lit)
+		p.skipBlank()
+	}
+	if len(args) < len(fn.params) {
+		p.pos = start
+		return nil, p.errorf("too few arguments to %s", name)
+	}
+
+	if name == "match" || name == "search" {
+		fn = regexpFunction(name == "match", literals[1])
+	}
+	return &operand{function: &functionExpr{fn: fn, args: args}}, nil
+}
+
+// regexpFunction compiles the pattern of a match or search call once, if it's
+// a literal.
+func regexpFunction(anchored bool, pattern ast.Value) *function {
+	s, ok := pattern.(ast.String)
+	if !ok {
+		if anchored {
+			return functions["match"]
+		}
+		return functions["search"]
+	}
+	re, err := compileIRegexp(string(s), anchored)
+	return &function{
+		params: []exprType{valueType, valueType},
+		result: logicalType,
+		call: func(args []result) result {
+			s, ok := args[0].value.(ast.String)
+			return result{logical: ok && err == nil && re.MatchString(string(s))}
+		},
+	}
+}
+
+// parseArgument parses a function argument and converts it to the declared
+// parameter type (RFC 9535, Section 2.4.3). A nil argument is returned if it
+// can't be converted. The value of literal arguments is returned, too.
+func (p *parser) parseArgument(param exprType) (argument, ast.Value, error) {
+	start := p.pos
+	if c := p.peek(); c != '!' && c != '(' {
+		operand, err := p.parseOperand()
+		if err != nil {
+			return nil, nil, err
+		}
+		end := p.pos
+		p.skipBlank()
+		if c := p.peek(); c == ',' || c == ')' {
+			p.pos = end
+			return operandArgument(operand, param), operand.literal, nil
+		}
+		p.pos = start
+	}
+
+	expr, err := p.parseLogicalOr()
+	if err != nil {
+		return nil, nil, err
+	}
+	if param != logicalType {
+		return nil, nil, nil
+	}
+	return func(root, current ast.Value) result {
+		return result{logical: expr.test(root, current)}
+	}, nil, nil
+}
+
+func operandArgument(operand *operand, param exprType) argument {
+	switch {
+	case operand.literal != nil:
+		if param == valueType {
+			lit := operand.literal
+			return func(ast.Value, ast.Value) result {
+				return result{value: lit}
+			}
+		}
+	case operand.query != nil:
+		q := operand.query
+		switch param {
+		case valueType:
+			if singular(q.segments) {
+				return func(root, current ast.Value) result {
+					return result{value: singularQuery{query: q}.value(root, current)}
+				}
+			}
+		case logicalType:
+			return func(root, current ast.Value) result {
+				return result{logical: len(q.nodes(root, current)) > 0}
+			}
+		case nodesType:
+			return func(root, current ast.Value) result {
+				return result{nodes: q.nodes(root, current)}
+			}
+		}
+	case operand.function != nil:
+		f := operand.function
+		switch {
+		case f.fn.result == param:
+			return f.call
+		case param == logicalType && f.fn.result == nodesType:
+			return func(root, current ast.Value) result {
+				return result{logical: f.test(root, current)}
+			}
+		}
+	}
+	return nil
+}
+
+// comparable converts an operand of a comparison.
+func (p *parser) comparable(operand *operand, start int) (valueExpr, error) {
+	switch {
+	case operand.literal != nil:
+		return literal{v: operand.literal}, nil
+	case operand.query != nil:
+		if singular(operand.query.segments) {
+			return singularQuery{query: operand.query}, nil
+		}
+		p.pos = start
+		return nil, p.errorf("query in comparison must be singular")
+	}
+	if operand.function.fn.result != valueType {
+		p.pos = start
+		return nil, p.errorf("function in comparison must be of ValueType")
+	}
+	return operand.function, nil
+}
+
+// testExpr converts an operand that is used as a test.
+func (p *parser) testExpr(operand *operand, start int) (logicalExpr, error) {
+	switch {
+	case operand.literal != nil:
+		p.pos = start
+		return nil, p.errorf("literal must be compared")
+	case operand.query != nil:
+		return existsExpr{query: operand.query}, nil
+	}
+	if operand.function.fn.result == valueType {
+		p.pos = start
+		return nil, p.errorf("function of ValueType must be compared")
+	}
+	return operand.function, nil
+}
@@ -0,0 +1,358 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Constraint is a parsed version range. The grammar follows the ranges
+// understood by npm and Cargo:
+//
+//	constraint ::= range ( '||' range )*
+//	range      ::= hyphen | simple ( ( ' ' | ',' ) simple )* | ''
+//	hyphen     ::= partial ' - ' partial
+//	simple     ::= primitive | partial | tilde | caret
+//	primitive  ::= ( '<' | '>' | '>=' | '<=' | '=' ) partial
+//	tilde      ::= ( '~' | '~>' ) partial
+//	caret      ::= '^' partial
+//	partial    ::= 'v'? xr ( '.' xr ( '.' xr pre? build? )? )?
+//	xr         ::= 'x' | 'X' | '*' | [0-9]+
+//
+// A version satisfies a constraint if it satisfies every comparator of at
+// least one of its ranges. Versions with a pre-release tag only satisfy a
+// range if one of the range's comparators has a pre-release tag on the same
+// major.minor.patch tuple, so that "^1.2.3" does not match "1.3.0-beta".
+type Constraint struct {
+	ranges [][]comparator
+	raw    string
+}
+
+type comparator struct {
+	op      string
+	version Version
+}
+
+// anyComparator matches every version without a pre-release tag.
+var anyComparator = comparator{op: ">=", version: Version{}}
+
+var rePartial = regexp.MustCompile(`^v?(0|[1-9][0-9]*|[xX*])(?:\.(0|[1-9][0-9]*|[xX*])(?:\.(0|[1-9][0-9]*|[xX*])(?:-([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?(?:\+([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?)?)?$`)
+
+// ParseConstraint parses a version range.
+func ParseConstraint(s string) (Constraint, error) {
+	c := Constraint{raw: s}
+
+	for r := range strings.SplitSeq(s, "||") {
+		cmps, err := parseRange(strings.TrimSpace(r))
+		if err != nil {
+			return Constraint{}, fmt.Errorf("invalid constraint %q: %w", s, err)
+		}
+		c.ranges = append(c.ranges, cmps)
+	}
+
+	return c, nil
+}
+
+// MustParseConstraint is like ParseConstraint but panics if the constraint is invalid.
+func MustParseConstraint(s string) Constraint {
+	c, err := ParseConstraint(s)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// String returns the constraint as it was originally written.
+func (c Constraint) String() string {
+	return c.raw
+}
+
+// Check returns true if v satisfies the constraint.
+func (c Constraint) Check(v Version) bool {
+	for _, r := range c.ranges {
+		if checkRange(r, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkRange(cmps []comparator, v Version) bool {
+	for _, cmp := range cmps {
+		if !cmp.check(v) {
+			return false
+		}
+	}
+
+	if v.PreRelease == "" {
+		return true
+	}
+
+	for _, cmp := range cmps {
+		if cmp.version.PreRelease != "" &&
+			cmp.version.Major == v.Major &&
+			cmp.version.Minor == v.Minor &&
+			cmp.version.Patch == v.Patch {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c comparator) check(v Version) bool {
+	cmp := v.Compare(c.version)
+	switch c.op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	default:
+		return cmp == 0
+	}
+}
+
+func parseRange(s string) ([]comparator, error) {
+	if s == "" {
+		return []comparator{anyComparator}, nil
+	}
+
+	if lo, hi, ok := strings.Cut(s, " - "); ok {
+		return parseHyphen(strings.TrimSpace(lo), strings.TrimSpace(hi))
+	}
+
+	var cmps []comparator
+	for _, tok := range tokenizeRange(s) {
+		cs, err := parseSimple(tok)
+		if err != nil {
+			return nil, err
+		}
+		cmps = append(cmps, cs...)
+	}
+
+	return cmps, nil
+}
+
+// tokenizeRange splits a range into its simple expressions, joining operators
+// to the version that follows them: ">= 1.2.3, < 2" yields [">=1.2.3", "<2"].
+func tokenizeRange(s string) []string {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ' ' || r == '\t' || r == ','
+	})
+
+	var toks []string
+	var pending string
+	for _, f := range fields {
+		if strings.Trim(f, "<>=~^") == "" {
+			pending += f
+			continue
+		}
+		toks = append(toks, pending+f)
+		pending = ""
+	}
+	if pending != "" {
+		toks = append(toks, pending)
+	}
+
+	return toks
+}
+
+// partial is a possibly incomplete version. Missing or wildcard components
+// are -1.
+type partial struct {
+	major, minor, patch int64
+	pre                 string
+}
+
+func parsePartial(s string) (partial, error) {
+	m := rePartial.FindStringSubmatch(s)
+	if m == nil {
+		return partial{}, fmt.Errorf("invalid version %q", s)
+	}
+
+	p := partial{major: -1, minor: -1, patch: -1, pre: m[4]}
+	parts := []*int64{&p.major, &p.minor, &p.patch}
+	for i, x := range m[1:4] {
+		if x == "" || x == "x" || x == "X" || x == "*" {
+			break
+		}
+		n, err := strconv.ParseInt(x, 10, 64)
+		if err != nil {
+			return partial{}, err
+		}
+		*parts[i] = n
+	}
+
+	if p.pre != "" && p.patch < 0 {
+		return partial{}, fmt.Errorf("invalid version %q: pre-release requires a full version", s)
+	}
+
+	return p, nil
+}
+
+func (p partial) floor() Version {
+	return Version{Major: max(p.major, 0), Minor: max(p.minor, 0), Patch: max(p.patch, 0), PreRelease: p.pre}
+}
+
+func (p partial) wildcard() bool {
+	return p.major < 0
+}
+
+// upper returns the exclusive upper bound of the versions matched by the
+// partial, i.e. the next version after the last specified component.
+func (p partial) upper() Version {
+	switch {
+	case p.minor < 0:
+		return Version{Major: p.major + 1, PreRelease: "0"}
+	case p.patch < 0:
+		return Version{Major: p.major, Minor: p.minor + 1, PreRelease: "0"}
+	default:
+		return Version{Major: p.major, Minor: p.minor, Patch: p.patch + 1, PreRelease: "0"}
+	}
+}
+
+func (p partial) complete() bool {
+	return p.patch >= 0
+}
+
+func parseSimple(s string) ([]comparator, error) {
+	op := ""
+	for _, prefix := range []string{">=", "<=", ">", "<", "=", "~", "^"} {
+		if strings.HasPrefix(s, prefix) {
+			op = prefix
+			break
+		}
+	}
+	rest := s[len(op):]
+	if op == "~" {
+		// Accept Ruby-style "~>" as an alias of "~".
+		rest = strings.TrimPrefix(rest, ">")
+	}
+
+	p, err := parsePartial(rest)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op {
+	case "~":
+		return tildeRange(p), nil
+	case "^":
+		return caretRange(p), nil
+	case "", "=":
+		if p.complete() {
+			return []comparator{{op: "=", version: p.floor()}}, nil
+		}
+		if p.wildcard() {
+			return []comparator{anyComparator}, nil
+		}
+		return []comparator{{op: ">=", version: p.floor()}, {op: "<", version: p.upper()}}, nil
+	}
+
+	if p.complete() {
+		return []comparator{{op: op, version: p.floor()}}, nil
+	}
+
+	if p.wildcard() {
+		if op == "<" || op == ">" {
+			// Nothing is less or greater than every version.
+			return []comparator{{op: "<", version: Version{PreRelease: "0"}}}, nil
+		}
+		return []comparator{anyComparator}, nil
+	}
+
+	switch op {
+	case ">":
+		return []comparator{{op: ">=", version: p.upper().withoutPreRelease()}}, nil
+	case ">=":
+		return []comparator{{op: ">=", version: p.floor()}}, nil
+	case "<":
+		return []comparator{{op: "<", version: p.floor().withPreRelease("0")}}, nil
+	default: // "<="
+		return []comparator{{op: "<", version: p.upper()}}, nil
+	}
+}
+
+func tildeRange(p partial) []comparator {
+	if p.wildcard() {
+		return []comparator{anyComparator}
+	}
+	lo := comparator{op: ">=", version: p.floor()}
+	if p.minor < 0 {
+		return []comparator{lo, {op: "<", version: Version{Major: p.major + 1, PreRelease: "0"}}}
+	}
+	return []comparator{lo, {op: "<", version: Version{Major: p.major, Minor: p.minor + 1, PreRelease: "0"}}}
+}
+
+func caretRange(p partial) []comparator {
+	if p.wildcard() {
+		return []comparator{anyComparator}
+	}
+	lo := comparator{op: ">=", version: p.floor()}
+
+	var hi Version
+	switch {
+	case p.major > 0 || p.minor < 0:
+		hi = Version{Major: p.major + 1}
+	case p.minor > 0 || p.patch < 0:
+		hi = Version{Minor: p.minor + 1}
+	default:
+		hi = Version{Patch: p.patch + 1}
+	}
+
+	return []comparator{lo, {op: "<", version: hi.withPreRelease("0")}}
+}
+
+func parseHyphen(lo, hi string) ([]comparator, error) {
+	if lo == "" || hi == "" {
+		return nil, errors.New("hyphen range requires two versions")
+	}
+
+	from, err := parsePartial(lo)
+	if err != nil {
+		return nil, err
+	}
+	to, err := parsePartial(hi)
+	if err != nil {
+		return nil, err
+	}
+
+	var cmps []comparator
+	if !from.wildcard() {
+		cmps = append(cmps, comparator{op: ">=", version: from.floor()})
+	}
+
+	switch {
+	case to.wildcard():
+	case to.complete():
+		cmps = append(cmps, comparator{op: "<=", version: to.floor()})
+	default:
+		cmps = append(cmps, comparator{op: "<", version: to.upper()})
+	}
+
+	if len(cmps) == 0 {
+		cmps = append(cmps, anyComparator)
+	}
+
+	return cmps, nil
+}
+
+func (v Version) withPreRelease(pre string) Version {
+	v.PreRelease = pre
+	return v
+}
+
+func (v Version) withoutPreRelease() Version {
+	v.PreRelease = ""
+	return v
+}
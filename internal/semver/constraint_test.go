@@ -0,0 +1,89 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package semver
+
+import (
+	"testing"
+)
+
+func TestConstraintCheck(t *testing.T) {
+	tests := []struct {
+		constraint string
+		match      []string
+		noMatch    []string
+	}{
+		{"", []string{"0.0.0", "1.2.3", "99.0.0"}, []string{"1.0.0-rc.1"}},
+		{"*", []string{"0.0.0", "1.2.3"}, []string{"1.0.0-rc.1"}},
+		{"1.2.3", []string{"1.2.3", "v1.2.3", "1.2.3+build"}, []string{"1.2.4", "1.2.3-rc.1"}},
+		{"=1.2.3", []string{"1.2.3"}, []string{"1.2.2"}},
+		{"1.2", []string{"1.2.0", "1.2.99"}, []string{"1.3.0", "1.1.9", "1.2.5-beta"}},
+		{"1.x", []string{"1.0.0", "1.99.1"}, []string{"2.0.0", "0.9.0"}},
+		{"1.2.x", []string{"1.2.0", "1.2.9"}, []string{"1.3.0"}},
+		{">1.2.3", []string{"1.2.4", "2.0.0"}, []string{"1.2.3", "1.2.2"}},
+		{">=1.2.3", []string{"1.2.3", "2.0.0"}, []string{"1.2.2"}},
+		{"<1.2.3", []string{"1.2.2", "0.0.1"}, []string{"1.2.3", "1.2.3-rc.1"}},
+		{"<=1.2.3", []string{"1.2.3", "1.0.0"}, []string{"1.2.4"}},
+		{">1.2", []string{"1.3.0"}, []string{"1.2.9"}},
+		{"<1.2", []string{"1.1.9"}, []string{"1.2.0"}},
+		{"<=1.2", []string{"1.2.9"}, []string{"1.3.0"}},
+		{">= 1.2.3 < 2", []string{"1.2.3", "1.9.9"}, []string{"2.0.0", "1.2.2"}},
+		{">=1.2.3, <1.5", []string{"1.4.9"}, []string{"1.5.0"}},
+		{"~1.2.3", []string{"1.2.3", "1.2.9"}, []string{"1.3.0", "1.2.2"}},
+		{"~1.2", []string{"1.2.0", "1.2.9"}, []string{"1.3.0"}},
+		{"~1", []string{"1.0.0", "1.9.9"}, []string{"2.0.0"}},
+		{"~>1.2.3", []string{"1.2.5"}, []string{"1.3.0"}},
+		{"~1.2.3-beta.2", []string{"1.2.3-beta.2", "1.2.3-beta.4", "1.2.3", "1.2.7"}, []string{"1.2.4-beta.2", "1.2.3-beta.1"}},
+		{"^1.2.3", []string{"1.2.3", "1.9.9"}, []string{"2.0.0", "1.2.2", "1.3.0-beta", "2.0.0-rc.1"}},
+		{"^0.2.3", []string{"0.2.3", "0.2.9"}, []string{"0.3.0"}},
+		{"^0.0.3", []string{"0.0.3"}, []string{"0.0.4"}},
+		{"^1.2.x", []string{"1.2.0", "1.9.0"}, []string{"2.0.0"}},
+		{"^0.0.x", []string{"0.0.0", "0.0.9"}, []string{"0.1.0"}},
+		{"^0.x", []string{"0.0.0", "0.9.0"}, []string{"1.0.0"}},
+		{"^1.2.3-beta.2", []string{"1.2.3-beta.4", "1.2.3", "1.9.0"}, []string{"1.2.4-beta.2", "2.0.0"}},
+		{"1.2.3 - 2.3.4", []string{"1.2.3", "2.3.4"}, []string{"1.2.2", "2.3.5"}},
+		{"1.2 - 2.3.4", []string{"1.2.0"}, []string{"1.1.9"}},
+		{"1.2.3 - 2.3", []string{"2.3.9"}, []string{"2.4.0"}},
+		{"1.2.3 - 2", []string{"2.9.9"}, []string{"3.0.0"}},
+		{"^2.3 || >=3.1 <4", []string{"2.3.0", "2.9.0", "3.1.0", "3.9.9"}, []string{"2.2.0", "3.0.0", "4.0.0"}},
+		{"<1.0.0 || >=2.0.0-rc.1", []string{"0.9.0", "2.0.0-rc.2", "2.0.0"}, []string{"1.0.0", "2.0.1-rc.1"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.constraint, func(t *testing.T) {
+			c, err := ParseConstraint(tc.constraint)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, v := range tc.match {
+				if !c.Check(MustParse(v)) {
+					t.Errorf("expected %q to satisfy %q", v, tc.constraint)
+				}
+			}
+			for _, v := range tc.noMatch {
+				if c.Check(MustParse(v)) {
+					t.Errorf("expected %q not to satisfy %q", v, tc.constraint)
+				}
+			}
+		})
+	}
+}
+
+func TestParseConstraintErrors(t *testing.T) {
+	for _, s := range []string{
+		"abc",
+		"1.2.3.4",
+		">=",
+		"01.2.3",
+		"1.2-beta",
+		"1.2.3 -",
+		"^1.2.3 || foo",
+	} {
+		t.Run(s, func(t *testing.T) {
+			if _, err := ParseConstraint(s); err == nil {
+				t.Fatalf("expected error for %q", s)
+			}
+		})
+	}
+}
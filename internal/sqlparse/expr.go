@@ -0,0 +1,989 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package sqlparse
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// functionNames are reserved keywords that are also the names of functions.
+var functionNames = map[string]struct{}{
+	"insert": {}, "left": {}, "mod": {}, "replace": {}, "right": {},
+}
+
+// niladicFunctions are called without parentheses.
+var niladicFunctions = map[string]struct{}{
+	"current_date": {}, "current_time": {}, "current_timestamp": {}, "current_user": {},
+	"localtime": {}, "localtimestamp": {}, "session_user": {},
+}
+
+// intervalUnits are the units of MySQL intervals, and the fields of
+// PostgreSQL intervals.
+var intervalUnits = map[string]struct{}{
+	"microsecond": {}, "second": {}, "minute": {}, "hour": {}, "day": {}, "week": {}, "month": {},
+	"quarter": {}, "year": {}, "second_microsecond": {}, "minute_microsecond": {}, "minute_second": {},
+	"hour_microsecond": {}, "hour_second": {}, "hour_minute": {}, "day_microsecond": {},
+	"day_second": {}, "day_minute": {}, "day_hour": {}, "year_month": {},
+}
+
+func literal(v any) map[string]any {
+	return map[string]any{"type": "literal", "value": v}
+}
+
+func binary(op string, left, right any) map[string]any {
+	return map[string]any{"type": "binary", "operator": op, "left": left, "right": right}
+}
+
+func unary(op string, operand any) map[string]any {
+	return map[string]any{"type": "unary", "operator": op, "operand": operand}
+}
+
+// jsonNumber returns the JSON representation of a numeric literal, which may
+// lack digits before or after the decimal point.
+func jsonNumber(s string) json.Number {
+	if strings.HasPrefix(s, ".") {
+		s = "0" + s
+	}
+	if i := strings.IndexByte(s, '.'); i >= 0 && (i+1 == len(s) || s[i+1] == 'e' || s[i+1] == 'E') {
+		s = s[:i+1] + "0" + s[i+1:]
+	}
+	return json.Number(s)
+}
+
+func (p *parser) parseExprList() ([]any, error) {
+	exprs := []any{}
+	for {
+		expr, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+		if !p.acceptOp(",") {
+			return exprs, nil
+		}
+	}
+}
+
+func (p *parser) parseExpr() (any, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+	return p.parseOr()
+}
+
+func (p *parser) parseOr() (any, error) {
+	left, err := p.parseXor()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword("or") || p.dialect == MySQL && p.acceptOp("||") {
+		right, err := p.parseXor()
+		if err != nil {
+			return nil, err
+		}
+		left = binary("or", left, right)
+	}
+	return left, nil
+}
+
+func (p *parser) parseXor() (any, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.dialect == MySQL && p.acceptKeyword("xor") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = binary("xor", left, right)
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (any, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword("and") || p.dialect == MySQL && p.acceptOp("&&") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = binary("and", left, right)
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (any, error) {
+	if p.acceptKeyword("not") {
+		if err := p.enter(); err != nil {
+			return nil, err
+		}
+		defer p.leave()
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return unary("not", operand), nil
+	}
+	return p.parseIs()
+}
+
+func (p *parser) parseIs() (any, error) {
+	left, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword("is") {
+		op := "is"
+		if p.acceptKeyword("not") {
+			op = "is not"
+		}
+
+		var right any
+		switch {
+		case p.acceptKeyword("null"):
+			right = literal(nil)
+		case p.acceptKeyword("true"):
+			right = literal(true)
+		case p.acceptKeyword("false"):
+			right = literal(false)
+		case p.acceptKeyword("unknown"):
+			right = literal("unknown")
+		case p.acceptKeyword("distinct", "from"):
+			op += " distinct from"
+			if right, err = p.parseComparison(); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, p.unexpected()
+		}
+		left = binary(op, left, right)
+	}
+	return left, nil
+}
+
+var comparisonOps = []string{"=", "<>", "!=", "<", "<=", ">", ">=", "<=>"}
+
+func (p *parser) parseComparison() (any, error) {
+	left, err := p.parseRange()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if t.kind != tokenOp || !contains(comparisonOps, t.text) || t.text == "<=>" && p.dialect != MySQL {
+			return left, nil
+		}
+		p.advance()
+
+		var right any
+		if p.peekKeyword("any") || p.peekKeyword("some") || p.peekKeyword("all") {
+			right, err = p.parseQuantified()
+		} else {
+			right, err = p.parseRange()
+		}
+		if err != nil {
+			return nil, err
+		}
+		left = binary(t.text, left, right)
+	}
+}
+
+func contains(ops []string, op string) bool {
+	for _, x := range ops {
+		if x == op {
+			return true
+		}
+	}
+	return false
+}
+
+// parseQuantified parses the ANY, SOME or ALL operand of a comparison, which is
+// an array (in PostgreSQL) or a subquery.
+func (p *parser) parseQuantified() (any, error) {
+	kind := strings.ToLower(p.advance().text)
+	if kind == "some" {
+		kind = "any"
+	}
+	if err := p.expectOp("("); err != nil {
+		return nil, err
+	}
+
+	node := map[string]any{"type": kind, "expr": nil, "query": nil}
+	var err error
+	if p.startsQuery() {
+		node["query"], err = p.parseQuery()
+	} else if p.dialect == PostgreSQL {
+		node["expr"], err = p.parseExpr()
+	} else {
+		return nil, p.unexpected()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return node, p.expectOp(")")
+}
+
+// parseRange parses BETWEEN, IN and pattern matching predicates.
+func (p *parser) parseRange() (any, error) {
+	left, err := p.parseOther()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		not := false
+		if p.peekKeyword("not") {
+			switch next := p.peekAt(1); {
+			case isKeyword(next, "between"), isKeyword(next, "in"), isKeyword(next, "like"), isKeyword(next, "ilike"),
+				isKeyword(next, "similar"), isKeyword(next, "regexp"), isKeyword(next, "rlike"):
+				p.advance()
+				not = true
+			default:
+				return left, nil
+			}
+		}
+
+		switch {
+		case p.acceptKeyword("between"):
+			low, err := p.parseOther()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expectKeyword("and"); err != nil {
+				return nil, err
+			}
+			high, err := p.parseOther()
+			if err != nil {
+				return nil, err
+			}
+			left = map[string]any{"type": "between", "not": not, "expr": left, "low": low, "high": high}
+		case p.acceptKeyword("in"):
+			if left, err = p.parseIn(left, not); err != nil {
+				return nil, err
+			}
+		case p.peekKeyword("like"), p.peekKeyword("ilike") && p.dialect == PostgreSQL,
+			p.peekKeyword("regexp") && p.dialect == MySQL, p.peekKeyword("rlike") && p.dialect == MySQL:
+			op := strings.ToLower(p.advance().text)
+			if op == "rlike" {
+				op = "regexp"
+			}
+			if left, err = p.parsePattern(op, not, left); err != nil {
+				return nil, err
+			}
+		case p.dialect == PostgreSQL && p.acceptKeyword("similar", "to"):
+			if left, err = p.parsePattern("similar to", not, left); err != nil {
+				return nil, err
+			}
+		default:
+			if not {
+				return nil, p.unexpected()
+			}
+			return left, nil
+		}
+	}
+}
+
+func (p *parser) parseIn(left any, not bool) (any, error) {
+	if err := p.expectOp("("); err != nil {
+		return nil, err
+	}
+
+	node := map[string]any{"type": "in", "not": not, "expr": left, "values": nil, "query": nil}
+	var err error
+	if p.startsQuery() {
+		node["query"], err = p.parseQuery()
+	} else {
+		node["values"], err = p.parseExprList()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return node, p.expectOp(")")
+}
+
+func (p *parser) parsePattern(op string, not bool, left any) (any, error) {
+	if not {
+		op = "not " + op
+	}
+	right, err := p.parseOther()
+	if err != nil {
+		return nil, err
+	}
+	node := binary(op, left, right)
+	if op != "regexp" && op != "not regexp" && p.acceptKeyword("escape") {
+		if node["escape"], err = p.parseOther(); err != nil {
+			return nil, err
+		}
+	}
+	return node, nil
+}
+
+// otherOps are the operators with a precedence between pattern matching and
+// addition, mostly bitwise, string and JSON operators.
+var otherOps = map[Dialect][]string{
+	PostgreSQL: {"||", "->", "->>", "@>", "<@", "~", "~*", "!~", "!~*", "&", "|", "<<", ">>"},
+	MySQL:      {"->", "->>", "&", "|", "<<", ">>"},
+}
+
+func (p *parser) parseOther() (any, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if t.kind != tokenOp || !contains(otherOps[p.dialect], t.text) {
+			return left, nil
+		}
+		p.advance()
+		right, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		left = binary(t.text, left, right)
+	}
+}
+
+func (p *parser) parseAdditive() (any, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekOp("+") || p.peekOp("-") {
+		op := p.advance().text
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = binary(op, left, right)
+	}
+	return left, nil
+}
+
+func (p *parser) parseMultiplicative() (any, error) {
+	left, err := p.parseExponent()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		var op string
+		switch t := p.peek(); {
+		case isOp(t, "*"), isOp(t, "/"), isOp(t, "%"):
+			op = t.text
+		case p.dialect == MySQL && (isKeyword(t, "div") || isKeyword(t, "mod")) && !isOp(p.peekAt(1), "("):
+			op = strings.ToLower(t.text)
+		default:
+			return left, nil
+		}
+		p.advance()
+		right, err := p.parseExponent()
+		if err != nil {
+			return nil, err
+		}
+		left = binary(op, left, right)
+	}
+}
+
+// parseExponent parses PostgreSQL's exponentiation operator, which is the
+// bitwise exclusive or operator in MySQL.
+func (p *parser) parseExponent() (any, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptOp("^") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binary("^", left, right)
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (any, error) {
+	t := p.peek()
+	if !isOp(t, "-") && !isOp(t, "+") && !isOp(t, "~") && !(isOp(t, "!") && p.dialect == MySQL) {
+		return p.parsePostfix()
+	}
+	p.advance()
+
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+	operand, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	// Negative numbers are literals.
+	if e, ok := operand.(map[string]any); ok && t.text == "-" && e["type"] == "literal" {
+		if n, ok := e["value"].(json.Number); ok && !strings.HasPrefix(string(n), "-") {
+			return literal("-" + n), nil
+		}
+	}
+	return unary(t.text, operand), nil
+}
+
+// parsePostfix parses PostgreSQL's typecasts and array subscripts.
+func (p *parser) parsePostfix() (any, error) {
+	expr, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for p.dialect == PostgreSQL {
+		switch {
+		case p.acceptOp("::"):
+			typ, err := p.parseTypeName()
+			if err != nil {
+				return nil, err
+			}
+			expr = map[string]any{"type": "cast", "expr": expr, "as": typ}
+		case p.acceptOp("["):
+			index, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expectOp("]"); err != nil {
+				return nil, err
+			}
+			expr = map[string]any{"type": "subscript", "expr": expr, "index": index}
+		default:
+			return expr, nil
+		}
+	}
+	return expr, nil
+}
+
+func (p *parser) parsePrimary() (any, error) {
+	t := p.peek()
+	switch t.kind {
+	case tokenNumber:
+		p.advance()
+		return literal(jsonNumber(t.text)), nil
+	case tokenString:
+		p.advance()
+		return literal(t.text), nil
+	case tokenParam:
+		p.advance()
+		if p.dialect == MySQL {
+			p.params++
+			return map[string]any{"type": "parameter", "index": p.params}, nil
+		}
+		n, err := strconv.Atoi(t.text[1:])
+		if err != nil || n == 0 {
+			return nil, p.errorf(t.pos, "invalid parameter %s", t.text)
+		}
+		return map[string]any{"type": "parameter", "index": n}, nil
+	case tokenQuotedIdent:
+		return p.parseColumnOrFunction()
+	case tokenOp:
+		if t.text == "(" {
+			return p.parseParenthesized()
+		}
+		return nil, p.unexpected()
+	case tokenEOF:
+		return nil, p.unexpected()
+	}
+
+	next := p.peekAt(1)
+	switch kw := strings.ToLower(t.text); {
+	case kw == "null":
+		p.advance()
+		return literal(nil), nil
+	case kw == "true":
+		p.advance()
+		return literal(true), nil
+	case kw == "false":
+		p.advance()
+		return literal(false), nil
+	case kw == "default":
+		p.advance()
+		return map[string]any{"type": "default"}, nil
+	case kw == "case":
+		return p.parseCase()
+	case kw == "exists":
+		p.advance()
+		if err := p.expectOp("("); err != nil {
+			return nil, err
+		}
+		query, err := p.parseQuery()
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "exists", "query": query}, p.expectOp(")")
+	case kw == "cast" && isOp(next, "("):
+		return p.parseCast()
+	case kw == "convert" && isOp(next, "(") && p.dialect == MySQL:
+		return p.parseConvert()
+	case kw == "extract" && isOp(next, "("):
+		return p.parseExtract()
+	case kw == "interval" && (p.dialect == MySQL || next.kind == tokenString):
+		return p.parseInterval()
+	case kw == "array" && isOp(next, "[") && p.dialect == PostgreSQL:
+		p.advance()
+		p.advance()
+		values := []any{}
+		if !p.peekOp("]") {
+			var err error
+			if values, err = p.parseExprList(); err != nil {
+				return nil, err
+			}
+		}
+		return map[string]any{"type": "array", "values": values}, p.expectOp("]")
+	case kw == "row" && isOp(next, "("):
+		p.advance()
+		p.advance()
+		values, err := p.parseExprList()
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "row", "values": values}, p.expectOp(")")
+	}
+
+	if _, ok := niladicFunctions[strings.ToLower(t.text)]; ok && !isOp(next, "(") {
+		p.advance()
+		return map[string]any{"type": "function", "name": strings.ToLower(t.text), "args": []any{}, "distinct": false}, nil
+	}
+	if _, ok := functionNames[strings.ToLower(t.text)]; ok && isOp(next, "(") {
+		p.advance()
+		return p.parseFunction("", strings.ToLower(t.text))
+	}
+	if !isName(t) {
+		return nil, p.unexpected()
+	}
+	return p.parseColumnOrFunction()
+}
+
+// parseColumnOrFunction parses a column reference, a qualified wildcard like
+// t.*, or a function call.
+func (p *parser) parseColumnOrFunction() (any, error) {
+	start := p.peek().pos
+	parts := []string{}
+	for {
+		name, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, name)
+		if !p.acceptOp(".") {
+			break
+		}
+		if p.acceptOp("*") {
+			return p.star(start, parts)
+		}
+	}
+
+	if p.peekOp("(") {
+		if len(parts) > 2 {
+			return nil, p.errorf(start, "invalid function name %s", strings.Join(parts, "."))
+		}
+		schema, name := "", parts[len(parts)-1]
+		if len(parts) == 2 {
+			schema = parts[0]
+		}
+		// Function names are case-insensitive in MySQL, too.
+		return p.parseFunction(schema, strings.ToLower(name))
+	}
+
+	if len(parts) > 3 {
+		return nil, p.errorf(start, "invalid column reference %s", strings.Join(parts, "."))
+	}
+	for len(parts) < 3 {
+		parts = append([]string{""}, parts...)
+	}
+	p.addColumn(parts[0], parts[1], parts[2])
+	column := map[string]any{"type": "column", "table": nullable(parts[1]), "name": parts[2]}
+	if parts[0] != "" {
+		column["schema"] = parts[0]
+	}
+	return column, nil
+}
+
+// star returns a qualified wildcard, which references all columns of a table.
+func (p *parser) star(start int, parts []string) (any, error) {
+	if len(parts) > 2 {
+		return nil, p.errorf(start, "invalid column reference %s.*", strings.Join(parts, "."))
+	}
+	schema, table := "", parts[len(parts)-1]
+	if len(parts) == 2 {
+		schema = parts[0]
+	}
+	p.addColumn(schema, table, "*")
+	star := map[string]any{"type": "star", "table": table}
+	if schema != "" {
+		star["schema"] = schema
+	}
+	return star, nil
+}
+
+// parseFunction parses the arguments of a function call, and what follows
+// them.
+func (p *parser) parseFunction(schema, name string) (map[string]any, error) {
+	p.advance() // (
+
+	fn := map[string]any{"type": "function", "name": name, "args": []any{}, "distinct": false}
+	if schema != "" {
+		fn["schema"] = schema
+	}
+
+	switch {
+	case p.acceptOp(")"):
+	case p.peekOp("*") && isOp(p.peekAt(1), ")"):
+		p.advance()
+		p.advance()
+		fn["args"] = []any{map[string]any{"type": "star", "table": nil}}
+	default:
+		if p.acceptKeyword("distinct") {
+			fn["distinct"] = true
+		} else {
+			p.acceptKeyword("all")
+		}
+
+		var err error
+		if fn["args"], err = p.parseExprList(); err != nil {
+			return nil, err
+		}
+		if p.peekKeyword("order") {
+			if fn["order_by"], err = p.parseOrderBy(); err != nil {
+				return nil, err
+			}
+		}
+		if p.dialect == MySQL && name == "group_concat" && p.acceptKeyword("separator") {
+			t := p.peek()
+			if t.kind != tokenString {
+				return nil, p.unexpected()
+			}
+			p.advance()
+			fn["separator"] = t.text
+		}
+		if err := p.expectOp(")"); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.dialect == PostgreSQL && p.acceptKeyword("filter") {
+		if err := p.expectOp("("); err != nil {
+			return nil, err
+		}
+		if err := p.expectKeyword("where"); err != nil {
+			return nil, err
+		}
+		filter, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectOp(")"); err != nil {
+			return nil, err
+		}
+		fn["filter"] = filter
+	}
+
+	if p.acceptKeyword("over") {
+		over, err := p.parseWindow()
+		if err != nil {
+			return nil, err
+		}
+		fn["over"] = over
+	}
+
+	return fn, nil
+}
+
+// parseWindow parses the window of a window function call.
+func (p *parser) parseWindow() (any, error) {
+	if isName(p.peek()) {
+		return p.parseName()
+	}
+	if err := p.expectOp("("); err != nil {
+		return nil, err
+	}
+
+	window := map[string]any{"partition_by": []any{}, "order_by": []any{}, "frame": nil}
+	var err error
+	if p.acceptKeyword("partition", "by") {
+		if window["partition_by"], err = p.parseExprList(); err != nil {
+			return nil, err
+		}
+	}
+	if window["order_by"], err = p.parseOrderBy(); err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); isKeyword(t, "rows") || isKeyword(t, "range") || isKeyword(t, "groups") {
+		start := t.pos
+		p.advance()
+		if p.acceptKeyword("between") {
+			if err := p.parseFrameBound(); err != nil {
+				return nil, err
+			}
+			if err := p.expectKeyword("and"); err != nil {
+				return nil, err
+			}
+		}
+		if err := p.parseFrameBound(); err != nil {
+			return nil, err
+		}
+		window["frame"] = strings.ToLower(p.s[start:p.tokens[p.pos-1].end])
+	}
+	return window, p.expectOp(")")
+}
+
+func (p *parser) parseFrameBound() error {
+	switch {
+	case p.acceptKeyword("unbounded", "preceding"), p.acceptKeyword("unbounded", "following"), p.acceptKeyword("current", "row"):
+		return nil
+	}
+	if _, err := p.parseOther(); err != nil {
+		return err
+	}
+	if !p.acceptKeyword("preceding") && !p.acceptKeyword("following") {
+		return p.unexpected()
+	}
+	return nil
+}
+
+// parseParenthesized parses a parenthesized expression, a subquery or a row.
+func (p *parser) parseParenthesized() (any, error) {
+	if p.startsQuery() {
+		p.advance()
+		query, err := p.parseQuery()
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "subquery", "query": query}, p.expectOp(")")
+	}
+
+	p.advance()
+	exprs, err := p.parseExprList()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expectOp(")"); err != nil {
+		return nil, err
+	}
+	if len(exprs) == 1 {
+		return exprs[0], nil
+	}
+	return map[string]any{"type": "row", "values": exprs}, nil
+}
+
+func (p *parser) parseCase() (any, error) {
+	p.advance() // CASE
+
+	node := map[string]any{"type": "case", "operand": nil, "when": []any{}, "else": nil}
+	var err error
+	if !p.peekKeyword("when") {
+		if node["operand"], err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+
+	when := []any{}
+	for p.acceptKeyword("when") {
+		cond, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectKeyword("then"); err != nil {
+			return nil, err
+		}
+		result, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		when = append(when, map[string]any{"condition": cond, "result": result})
+	}
+	if len(when) == 0 {
+		return nil, p.unexpected()
+	}
+	node["when"] = when
+
+	if p.acceptKeyword("else") {
+		if node["else"], err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	return node, p.expectKeyword("end")
+}
+
+func (p *parser) parseCast() (any, error) {
+	p.advance() // CAST
+	p.advance() // (
+
+	expr, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expectKeyword("as"); err != nil {
+		return nil, err
+	}
+	typ, err := p.parseTypeName()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"type": "cast", "expr": expr, "as": typ}, p.expectOp(")")
+}
+
+// parseConvert parses MySQL's CONVERT(expr, type), which is a cast, and
+// CONVERT(expr USING charset).
+func (p *parser) parseConvert() (any, error) {
+	p.advance() // CONVERT
+	p.advance() // (
+
+	expr, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if p.acceptKeyword("using") {
+		charset, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		fn := map[string]any{"type": "function", "name": "convert", "args": []any{expr, literal(strings.ToLower(charset))}, "distinct": false}
+		return fn, p.expectOp(")")
+	}
+	if err := p.expectOp(","); err != nil {
+		return nil, err
+	}
+	typ, err := p.parseTypeName()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"type": "cast", "expr": expr, "as": typ}, p.expectOp(")")
+}
+
+// parseExtract parses EXTRACT(field FROM expr), which is represented as a call
+// with the field as a string.
+func (p *parser) parseExtract() (any, error) {
+	p.advance() // EXTRACT
+	p.advance() // (
+
+	var field string
+	switch t := p.peek(); t.kind {
+	case tokenIdent, tokenString:
+		field = strings.ToLower(p.advance().text)
+	default:
+		return nil, p.unexpected()
+	}
+	if err := p.expectKeyword("from"); err != nil {
+		return nil, err
+	}
+	expr, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	fn := map[string]any{"type": "function", "name": "extract", "args": []any{literal(field), expr}, "distinct": false}
+	return fn, p.expectOp(")")
+}
+
+// parseInterval parses an interval: INTERVAL 'value' [unit] in PostgreSQL, and
+// INTERVAL expr unit in MySQL.
+func (p *parser) parseInterval() (any, error) {
+	p.advance() // INTERVAL
+
+	var value any
+	var err error
+	if p.dialect == PostgreSQL {
+		value = literal(p.advance().text)
+	} else if value, err = p.parseAdditive(); err != nil {
+		return nil, err
+	}
+
+	var unit any
+	if t := p.peek(); t.kind == tokenIdent {
+		if _, ok := intervalUnits[strings.ToLower(t.text)]; ok {
+			p.advance()
+			unit = strings.ToLower(t.text)
+		}
+	}
+	if unit == nil && p.dialect == MySQL {
+		return nil, p.unexpected()
+	}
+	return map[string]any{"type": "interval", "value": value, "unit": unit}, nil
+}
+
+// parseTypeName parses the name of a data type, which is returned in lower
+// case, as in "varchar(255)" or "timestamp with time zone".
+func (p *parser) parseTypeName() (string, error) {
+	t := p.peek()
+	if t.kind != tokenIdent && t.kind != tokenQuotedIdent {
+		return "", p.unexpected()
+	}
+	p.advance()
+
+	words := []string{p.name(t)}
+	if t.kind == tokenIdent {
+		words[0] = strings.ToLower(t.text)
+	}
+	if p.dialect == PostgreSQL && p.acceptOp(".") {
+		name, err := p.parseName()
+		if err != nil {
+			return "", err
+		}
+		words[0] += "." + name
+	}
+
+	switch words[0] {
+	case "double":
+		if p.acceptKeyword("precision") {
+			words = append(words, "precision")
+		}
+	case "character", "char", "bit", "national":
+		if p.acceptKeyword("varying") {
+			words = append(words, "varying")
+		}
+	case "signed", "unsigned":
+		if p.acceptKeyword("integer") {
+			words = append(words, "integer")
+		} else if p.acceptKeyword("int") {
+			words = append(words, "int")
+		}
+	}
+	typ := strings.Join(words, " ")
+
+	if p.acceptOp("(") {
+		args := []string{}
+		for {
+			t := p.peek()
+			if t.kind != tokenNumber {
+				return "", p.unexpected()
+			}
+			p.advance()
+			args = append(args, t.text)
+			if !p.acceptOp(",") {
+				break
+			}
+		}
+		if err := p.expectOp(")"); err != nil {
+			return "", err
+		}
+		typ += "(" + strings.Join(args, ",") + ")"
+	}
+
+	if words[0] == "time" || words[0] == "timestamp" {
+		switch {
+		case p.acceptKeyword("with", "time", "zone"):
+			typ += " with time zone"
+		case p.acceptKeyword("without", "time", "zone"):
+			typ += " without time zone"
+		}
+	}
+
+	for p.dialect == PostgreSQL && p.peekOp("[") && isOp(p.peekAt(1), "]") {
+		p.advance()
+		p.advance()
+		typ += "[]"
+	}
+	return typ, nil
+}
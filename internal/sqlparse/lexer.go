@@ -0,0 +1,335 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package sqlparse
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenQuotedIdent
+	tokenString
+	tokenNumber
+	tokenParam
+	tokenOp
+)
+
+type token struct {
+	kind tokenKind
+	// text is the decoded value of quoted identifiers and strings, the source
+	// text otherwise.
+	text     string
+	pos, end int
+}
+
+// operators are matched longest first.
+var operators = []string{
+	"<=>", "->>", "!~*",
+	"::", "<=", ">=", "<>", "!=", "||", "&&", "->", "<<", ">>", "@>", "<@", "~*", "!~",
+	"+", "-", "*", "/", "%", "=", "<", ">", "(", ")", ",", ".", ";", "&", "|", "^", "~", "!", "[", "]", ":",
+}
+
+type lexer struct {
+	dialect Dialect
+	s       string
+	pos     int
+}
+
+func (l *lexer) errorf(pos int, format string, a ...any) error {
+	return newError(l.s, pos, format, a...)
+}
+
+func (l *lexer) tokens() ([]token, error) {
+	var tokens []token
+	for {
+		t, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+		t.end = l.pos
+		tokens = append(tokens, t)
+		if t.kind == tokenEOF {
+			return tokens, nil
+		}
+	}
+}
+
+func (l *lexer) next() (token, error) {
+	if err := l.skipBlank(); err != nil {
+		return token{}, err
+	}
+
+	start := l.pos
+	if l.pos >= len(l.s) {
+		return token{kind: tokenEOF, pos: start}, nil
+	}
+
+	c := l.s[l.pos]
+	switch {
+	case c == '\'':
+		s, err := l.scanString('\'', l.dialect == MySQL)
+		return token{kind: tokenString, text: s, pos: start}, err
+	case c == '"' && l.dialect == MySQL:
+		s, err := l.scanString('"', true)
+		return token{kind: tokenString, text: s, pos: start}, err
+	case c == '"' && l.dialect == PostgreSQL, c == '`' && l.dialect == MySQL:
+		s, err := l.scanQuotedIdent(c)
+		return token{kind: tokenQuotedIdent, text: s, pos: start}, err
+	case (c == 'e' || c == 'E') && l.dialect == PostgreSQL && strings.HasPrefix(l.s[l.pos+1:], "'"):
+		l.pos++
+		s, err := l.scanString('\'', true)
+		return token{kind: tokenString, text: s, pos: start}, err
+	case c == '$' && l.dialect == PostgreSQL:
+		return l.scanDollar()
+	case c == '?' && l.dialect == MySQL:
+		l.pos++
+		return token{kind: tokenParam, text: "?", pos: start}, nil
+	case isDigit(c) || c == '.' && l.pos+1 < len(l.s) && isDigit(l.s[l.pos+1]):
+		return l.scanNumber()
+	}
+
+	if r, size := utf8.DecodeRuneInString(l.s[l.pos:]); isIdentStart(r) {
+		l.pos += size
+		l.scanIdentRest()
+		return token{kind: tokenIdent, text: l.s[start:l.pos], pos: start}, nil
+	}
+
+	for _, op := range operators {
+		if strings.HasPrefix(l.s[l.pos:], op) {
+			l.pos += len(op)
+			return token{kind: tokenOp, text: op, pos: start}, nil
+		}
+	}
+
+	r, _ := utf8.DecodeRuneInString(l.s[l.pos:])
+	return token{}, l.errorf(start, "unexpected character %q", r)
+}
+
+// skipBlank skips white space and comments. MySQL executes the contents of
+// /*! ... */ comments, so they are rejected rather than ignored.
+func (l *lexer) skipBlank() error {
+	for l.pos < len(l.s) {
+		switch c := l.s[l.pos]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v':
+			l.pos++
+		case strings.HasPrefix(l.s[l.pos:], "--"):
+			// MySQL requires a control or space character after the dashes.
+			if l.dialect == MySQL && l.pos+2 < len(l.s) && l.s[l.pos+2] > ' ' {
+				return nil
+			}
+			l.skipLine()
+		case c == '#' && l.dialect == MySQL:
+			l.skipLine()
+		case strings.HasPrefix(l.s[l.pos:], "/*"):
+			if l.dialect == MySQL && (strings.HasPrefix(l.s[l.pos:], "/*!") || strings.HasPrefix(l.s[l.pos:], "/*M!")) {
+				return l.errorf(l.pos, "executable comments are not supported")
+			}
+			if err := l.skipBlockComment(); err != nil {
+				return err
+			}
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+func (l *lexer) skipLine() {
+	if i := strings.IndexByte(l.s[l.pos:], '\n'); i >= 0 {
+		l.pos += i + 1
+	} else {
+		l.pos = len(l.s)
+	}
+}
+
+// skipBlockComment skips a /* ... */ comment. Those nest in PostgreSQL.
+func (l *lexer) skipBlockComment() error {
+	start := l.pos
+	depth := 0
+	for l.pos < len(l.s) {
+		switch {
+		case strings.HasPrefix(l.s[l.pos:], "/*") && (depth == 0 || l.dialect == PostgreSQL):
+			depth++
+			l.pos += 2
+		case strings.HasPrefix(l.s[l.pos:], "*/"):
+			depth--
+			l.pos += 2
+			if depth == 0 {
+				return nil
+			}
+		default:
+			l.pos++
+		}
+	}
+	return l.errorf(start, "unterminated comment")
+}
+
+// scanString scans a string literal, in which quotes are escaped by doubling
+// them and, if backslash is set, by a backslash.
+func (l *lexer) scanString(quote byte, backslash bool) (string, error) {
+	start := l.pos
+	l.pos++
+
+	var sb strings.Builder
+	for l.pos < len(l.s) {
+		c := l.s[l.pos]
+		switch {
+		case c == quote:
+			if l.pos+1 < len(l.s) && l.s[l.pos+1] == quote {
+				sb.WriteByte(quote)
+				l.pos += 2
+				continue
+			}
+			l.pos++
+			if !utf8.ValidString(sb.String()) {
+				return "", l.errorf(start, "invalid UTF-8 in string")
+			}
+			return sb.String(), nil
+		case c == '\\' && backslash && l.pos+1 < len(l.s):
+			l.pos++
+			sb.WriteString(unescape(l.s[l.pos], l.dialect))
+		default:
+			sb.WriteByte(c)
+		}
+		l.pos++
+	}
+	return "", l.errorf(start, "unterminated string")
+}
+
+func unescape(c byte, d Dialect) string {
+	switch c {
+	case '0':
+		return "\x00"
+	case 'b':
+		return "\b"
+	case 'f':
+		return "\f"
+	case 'n':
+		return "\n"
+	case 'r':
+		return "\r"
+	case 't':
+		return "\t"
+	case 'Z':
+		if d == MySQL {
+			return "\x1a"
+		}
+	case '%', '_':
+		// These are kept escaped in MySQL, so that they can be used in
+		// patterns.
+		if d == MySQL {
+			return "\\" + string(c)
+		}
+	}
+	return string(c)
+}
+
+func (l *lexer) scanQuotedIdent(quote byte) (string, error) {
+	start := l.pos
+	s, err := l.scanString(quote, false)
+	if err != nil {
+		return "", l.errorf(start, "unterminated quoted identifier")
+	}
+	if s == "" {
+		return "", l.errorf(start, "zero-length quoted identifier")
+	}
+	return s, nil
+}
+
+// scanDollar scans a PostgreSQL positional parameter ($1), or a dollar-quoted
+// string ($$...$$ or $tag$...$tag$).
+func (l *lexer) scanDollar() (token, error) {
+	start := l.pos
+	l.pos++
+
+	if l.pos < len(l.s) && isDigit(l.s[l.pos]) {
+		for l.pos < len(l.s) && isDigit(l.s[l.pos]) {
+			l.pos++
+		}
+		return token{kind: tokenParam, text: l.s[start:l.pos], pos: start}, nil
+	}
+
+	if r, size := utf8.DecodeRuneInString(l.s[l.pos:]); isIdentStart(r) {
+		l.pos += size
+		for l.pos < len(l.s) && l.s[l.pos] != '$' {
+			r, size := utf8.DecodeRuneInString(l.s[l.pos:])
+			if !isIdentStart(r) && !isDigit(l.s[l.pos]) {
+				break
+			}
+			l.pos += size
+		}
+	}
+	if l.pos >= len(l.s) || l.s[l.pos] != '$' {
+		return token{}, l.errorf(start, "unexpected character '$'")
+	}
+	l.pos++
+
+	delim := l.s[start:l.pos]
+	end := strings.Index(l.s[l.pos:], delim)
+	if end < 0 {
+		return token{}, l.errorf(start, "unterminated string")
+	}
+	s := l.s[l.pos : l.pos+end]
+	l.pos += end + len(delim)
+	if !utf8.ValidString(s) {
+		return token{}, l.errorf(start, "invalid UTF-8 in string")
+	}
+	return token{kind: tokenString, text: s, pos: start}, nil
+}
+
+func (l *lexer) scanNumber() (token, error) {
+	start := l.pos
+	digits := func() {
+		for l.pos < len(l.s) && isDigit(l.s[l.pos]) {
+			l.pos++
+		}
+	}
+
+	digits()
+	if l.pos < len(l.s) && l.s[l.pos] == '.' {
+		l.pos++
+		digits()
+	}
+	if l.pos < len(l.s) && (l.s[l.pos] == 'e' || l.s[l.pos] == 'E') {
+		l.pos++
+		if l.pos < len(l.s) && (l.s[l.pos] == '+' || l.s[l.pos] == '-') {
+			l.pos++
+		}
+		exp := l.pos
+		digits()
+		if l.pos == exp {
+			return token{}, l.errorf(start, "invalid number")
+		}
+	}
+
+	// Identifiers can't directly follow numbers, as in 0x1F or 1abc.
+	if r, _ := utf8.DecodeRuneInString(l.s[l.pos:]); isIdentStart(r) {
+		return token{}, l.errorf(start, "invalid number")
+	}
+	return token{kind: tokenNumber, text: l.s[start:l.pos], pos: start}, nil
+}
+
+func (l *lexer) scanIdentRest() {
+	for l.pos < len(l.s) {
+		r, size := utf8.DecodeRuneInString(l.s[l.pos:])
+		if !isIdentStart(r) && !isDigit(l.s[l.pos]) && r != '$' {
+			return
+		}
+		l.pos += size
+	}
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || r >= utf8.RuneSelf && r != utf8.RuneError
+}
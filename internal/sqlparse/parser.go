@@ -0,0 +1,1367 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package sqlparse
+
+import (
+	"strings"
+)
+
+// reserved are the keywords that can't be used as unquoted names or aliases.
+var reserved = map[string]struct{}{
+	"all": {}, "and": {}, "any": {}, "as": {}, "asc": {}, "between": {}, "by": {}, "case": {}, "cross": {},
+	"default": {}, "delete": {}, "desc": {}, "distinct": {}, "div": {}, "do": {}, "else": {}, "end": {},
+	"except": {}, "exists": {}, "false": {}, "fetch": {}, "for": {}, "from": {}, "full": {}, "group": {},
+	"having": {}, "ilike": {}, "in": {}, "inner": {}, "insert": {}, "intersect": {}, "into": {}, "is": {},
+	"join": {}, "lateral": {}, "left": {}, "like": {}, "limit": {}, "lock": {}, "mod": {}, "natural": {},
+	"not": {}, "null": {}, "offset": {}, "on": {}, "or": {}, "order": {}, "outer": {}, "regexp": {},
+	"replace": {}, "returning": {}, "right": {}, "rlike": {}, "select": {}, "set": {}, "similar": {},
+	"some": {}, "straight_join": {}, "table": {}, "then": {}, "true": {}, "union": {}, "update": {},
+	"using": {}, "values": {}, "when": {}, "where": {}, "window": {}, "with": {}, "xor": {},
+}
+
+// maxDepth is the maximum nesting depth of expressions, queries and joined
+// tables, which are parsed recursively.
+const maxDepth = 1000
+
+type parser struct {
+	dialect Dialect
+	s       string
+	tokens  []token
+	pos     int
+	depth   int
+
+	// parens is the last run of opening parentheses scanned by startsQuery,
+	// from the first to the token following them, and query whether that
+	// token starts a query.
+	parens struct {
+		start, end int
+		query      bool
+	}
+
+	// params numbers MySQL's positional parameters.
+	params int
+	// ctes holds the names of the common table expressions in scope, which
+	// aren't table references.
+	ctes []map[string]struct{}
+
+	tables     []any
+	columns    []any
+	statements []any
+}
+
+func (p *parser) errorf(pos int, format string, a ...any) error {
+	return newError(p.s, pos, format, a...)
+}
+
+func (p *parser) unexpected() error {
+	t := p.peek()
+	if t.kind == tokenEOF {
+		return p.errorf(t.pos, "unexpected end of statement")
+	}
+	return p.errorf(t.pos, "unexpected %s", p.s[t.pos:t.end])
+}
+
+// enter increments the nesting depth, and returns an error if it exceeds
+// maxDepth. Each successful call must be paired with a call to leave.
+func (p *parser) enter() error {
+	if p.depth == maxDepth {
+		return p.errorf(p.peek().pos, "statement nested too deeply")
+	}
+	p.depth++
+	return nil
+}
+
+func (p *parser) leave() {
+	p.depth--
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) peekAt(n int) token {
+	if p.pos+n < len(p.tokens) {
+		return p.tokens[p.pos+n]
+	}
+	return p.tokens[len(p.tokens)-1]
+}
+
+func (p *parser) advance() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func isKeyword(t token, kw string) bool {
+	return t.kind == tokenIdent && strings.EqualFold(t.text, kw)
+}
+
+func isOp(t token, op string) bool {
+	return t.kind == tokenOp && t.text == op
+}
+
+func (p *parser) peekKeyword(kw string) bool {
+	return isKeyword(p.peek(), kw)
+}
+
+func (p *parser) peekOp(op string) bool {
+	return isOp(p.peek(), op)
+}
+
+// acceptKeyword consumes the keywords if they are next, all of them.
+func (p *parser) acceptKeyword(kws ...string) bool {
+	for i, kw := range kws {
+		if !isKeyword(p.peekAt(i), kw) {
+			return false
+		}
+	}
+	p.pos += len(kws)
+	return true
+}
+
+func (p *parser) expectKeyword(kws ...string) error {
+	for _, kw := range kws {
+		if !p.acceptKeyword(kw) {
+			return p.unexpected()
+		}
+	}
+	return nil
+}
+
+func (p *parser) acceptOp(op string) bool {
+	if p.peekOp(op) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expectOp(op string) error {
+	if !p.acceptOp(op) {
+		return p.unexpected()
+	}
+	return nil
+}
+
+// isName reports whether t can be used as a name.
+func isName(t token) bool {
+	if t.kind == tokenQuotedIdent {
+		return true
+	}
+	if t.kind != tokenIdent {
+		return false
+	}
+	_, ok := reserved[strings.ToLower(t.text)]
+	return !ok
+}
+
+// name returns the name t stands for. Unquoted names are case-insensitive in
+// PostgreSQL, which folds them to lower case.
+func (p *parser) name(t token) string {
+	if t.kind == tokenIdent && p.dialect == PostgreSQL {
+		return strings.ToLower(t.text)
+	}
+	return t.text
+}
+
+func (p *parser) parseName() (string, error) {
+	if !isName(p.peek()) {
+		return "", p.unexpected()
+	}
+	return p.name(p.advance()), nil
+}
+
+func (p *parser) parseNameList() ([]any, error) {
+	if err := p.expectOp("("); err != nil {
+		return nil, err
+	}
+	names := []any{}
+	for {
+		name, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+		if !p.acceptOp(",") {
+			break
+		}
+	}
+	return names, p.expectOp(")")
+}
+
+// parseAlias parses an optional alias, introduced by AS or not.
+func (p *parser) parseAlias() (any, error) {
+	if p.acceptKeyword("as") {
+		if t := p.peek(); t.kind == tokenString && p.dialect == MySQL {
+			p.advance()
+			return t.text, nil
+		}
+		return p.parseName()
+	}
+	if isName(p.peek()) {
+		return p.name(p.advance()), nil
+	}
+	return nil, nil
+}
+
+func nullable(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// addColumn records a reference to a column.
+func (p *parser) addColumn(schema, table, name string) {
+	ref := map[string]any{"table": nullable(table), "name": name}
+	if schema != "" {
+		ref["schema"] = schema
+	}
+	p.columns = append(p.columns, ref)
+}
+
+func (p *parser) isCTE(schema, name string) bool {
+	if schema != "" {
+		return false
+	}
+	for _, scope := range p.ctes {
+		if _, ok := scope[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) parseStatement() (map[string]any, error) {
+	t := p.peek()
+	switch {
+	case isKeyword(t, "with"):
+		return p.parseWith()
+	case isKeyword(t, "select"), isOp(t, "("):
+		return p.parseQuery()
+	case isKeyword(t, "insert"):
+		return p.parseInsert("insert")
+	case isKeyword(t, "replace") && p.dialect == MySQL:
+		return p.parseInsert("replace")
+	case isKeyword(t, "update"):
+		return p.parseUpdate()
+	case isKeyword(t, "delete"):
+		return p.parseDelete()
+	case isKeyword(t, "truncate"):
+		return p.parseTruncate()
+	case isKeyword(t, "drop"):
+		return p.parseDrop()
+	case t.kind == tokenEOF:
+		return nil, p.errorf(t.pos, "empty statement")
+	case t.kind == tokenIdent:
+		return nil, p.errorf(t.pos, "unsupported statement %s", strings.ToUpper(t.text))
+	}
+	return nil, p.unexpected()
+}
+
+// parseWith parses common table expressions, and the statement they belong
+// to.
+func (p *parser) parseWith() (map[string]any, error) {
+	p.advance() // WITH
+	recursive := p.acceptKeyword("recursive")
+
+	scope := map[string]struct{}{}
+	p.ctes = append(p.ctes, scope)
+	defer func() {
+		p.ctes = p.ctes[:len(p.ctes)-1]
+	}()
+
+	ctes := []any{}
+	for {
+		name, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		columns := []any{}
+		if p.peekOp("(") {
+			if columns, err = p.parseNameList(); err != nil {
+				return nil, err
+			}
+		}
+		if err := p.expectKeyword("as"); err != nil {
+			return nil, err
+		}
+		if p.dialect == PostgreSQL && !p.acceptKeyword("materialized") {
+			p.acceptKeyword("not", "materialized")
+		}
+
+		if recursive {
+			scope[name] = struct{}{}
+		}
+		if err := p.expectOp("("); err != nil {
+			return nil, err
+		}
+		var query map[string]any
+		if p.dialect == PostgreSQL && (p.peekKeyword("insert") || p.peekKeyword("update") || p.peekKeyword("delete")) {
+			// Data-modifying statements in WITH are specific to PostgreSQL.
+			query, err = p.parseStatement()
+		} else {
+			query, err = p.parseQuery()
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectOp(")"); err != nil {
+			return nil, err
+		}
+		scope[name] = struct{}{}
+
+		ctes = append(ctes, map[string]any{"name": name, "columns": columns, "query": query})
+		if !p.acceptOp(",") {
+			break
+		}
+	}
+
+	var stmt map[string]any
+	var err error
+	switch t := p.peek(); {
+	case isKeyword(t, "select"), isOp(t, "("):
+		stmt, err = p.parseQuery()
+	case isKeyword(t, "insert"):
+		stmt, err = p.parseInsert("insert")
+	case isKeyword(t, "update"):
+		stmt, err = p.parseUpdate()
+	case isKeyword(t, "delete"):
+		stmt, err = p.parseDelete()
+	default:
+		return nil, p.unexpected()
+	}
+	if err != nil {
+		return nil, err
+	}
+	stmt["with"] = ctes
+	return stmt, nil
+}
+
+// parseQuery parses a SELECT statement, or several combined by set operations,
+// with the ORDER BY, LIMIT and locking clauses that apply to the result.
+func (p *parser) parseQuery() (map[string]any, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	if p.peekKeyword("with") {
+		return p.parseWith()
+	}
+
+	left, err := p.parseQueryTerm()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("union") || p.peekKeyword("except") {
+		op := strings.ToLower(p.advance().text)
+		all := p.acceptKeyword("all")
+		if !all {
+			p.acceptKeyword("distinct")
+		}
+		right, err := p.parseQueryTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = compound(op, all, left, right)
+	}
+
+	return left, p.parseQueryTail(left)
+}
+
+// parseQueryTerm parses an operand of UNION and EXCEPT. INTERSECT binds more
+// tightly.
+func (p *parser) parseQueryTerm() (map[string]any, error) {
+	left, err := p.parseQueryPrimary()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword("intersect") {
+		all := p.acceptKeyword("all")
+		if !all {
+			p.acceptKeyword("distinct")
+		}
+		right, err := p.parseQueryPrimary()
+		if err != nil {
+			return nil, err
+		}
+		left = compound("intersect", all, left, right)
+	}
+	return left, nil
+}
+
+func compound(op string, all bool, left, right map[string]any) map[string]any {
+	return map[string]any{
+		"type":     "select",
+		"with":     []any{},
+		"operator": op,
+		"all":      all,
+		"left":     left,
+		"right":    right,
+		"order_by": []any{},
+		"limit":    nil,
+		"offset":   nil,
+		"lock":     nil,
+	}
+}
+
+func (p *parser) parseQueryPrimary() (map[string]any, error) {
+	if p.acceptOp("(") {
+		q, err := p.parseQuery()
+		if err != nil {
+			return nil, err
+		}
+		return q, p.expectOp(")")
+	}
+	if !p.peekKeyword("select") {
+		return nil, p.unexpected()
+	}
+	return p.parseSelect()
+}
+
+func (p *parser) parseSelect() (map[string]any, error) {
+	p.advance() // SELECT
+
+	stmt := map[string]any{
+		"type":        "select",
+		"with":        []any{},
+		"distinct":    false,
+		"distinct_on": []any{},
+		"columns":     []any{},
+		"from":        []any{},
+		"joins":       []any{},
+		"where":       nil,
+		"group_by":    []any{},
+		"having":      nil,
+		"order_by":    []any{},
+		"limit":       nil,
+		"offset":      nil,
+		"lock":        nil,
+	}
+
+	if p.acceptKeyword("distinct") {
+		stmt["distinct"] = true
+		if p.dialect == PostgreSQL && p.acceptKeyword("on") {
+			if err := p.expectOp("("); err != nil {
+				return nil, err
+			}
+			exprs, err := p.parseExprList()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expectOp(")"); err != nil {
+				return nil, err
+			}
+			stmt["distinct_on"] = exprs
+		}
+	} else {
+		p.acceptKeyword("all")
+	}
+
+	columns, err := p.parseSelectList()
+	if err != nil {
+		return nil, err
+	}
+	stmt["columns"] = columns
+
+	if p.acceptKeyword("from") {
+		from, joins, err := p.parseFromList()
+		if err != nil {
+			return nil, err
+		}
+		stmt["from"], stmt["joins"] = from, joins
+	}
+
+	if stmt["where"], err = p.parseWhere(); err != nil {
+		return nil, err
+	}
+
+	if p.acceptKeyword("group", "by") {
+		if stmt["group_by"], err = p.parseExprList(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.acceptKeyword("having") {
+		if stmt["having"], err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	return stmt, nil
+}
+
+// parseSelectList parses the columns of a SELECT statement, or of a RETURNING
+// clause.
+func (p *parser) parseSelectList() ([]any, error) {
+	columns := []any{}
+	for {
+		var expr any
+		if p.acceptOp("*") {
+			expr = map[string]any{"type": "star", "table": nil}
+			p.addColumn("", "", "*")
+		} else {
+			var err error
+			if expr, err = p.parseExpr(); err != nil {
+				return nil, err
+			}
+		}
+
+		var alias any
+		if e, ok := expr.(map[string]any); !ok || e["type"] != "star" {
+			var err error
+			if alias, err = p.parseAlias(); err != nil {
+				return nil, err
+			}
+		}
+		columns = append(columns, map[string]any{"expr": expr, "alias": alias})
+
+		if !p.acceptOp(",") {
+			return columns, nil
+		}
+	}
+}
+
+func (p *parser) parseWhere() (any, error) {
+	if !p.acceptKeyword("where") {
+		return nil, nil
+	}
+	if p.peekKeyword("current") {
+		return nil, p.errorf(p.peek().pos, "WHERE CURRENT OF is not supported")
+	}
+	return p.parseExpr()
+}
+
+// parseQueryTail parses the clauses that follow the SELECT statements
+// combined in a query.
+func (p *parser) parseQueryTail(stmt map[string]any) error {
+	start := p.peek().pos
+	orderBy, err := p.parseOrderBy()
+	if err != nil {
+		return err
+	}
+	if len(orderBy) > 0 {
+		if len(stmt["order_by"].([]any)) > 0 {
+			return p.errorf(start, "multiple ORDER BY clauses are not allowed")
+		}
+		stmt["order_by"] = orderBy
+	}
+
+	start = p.peek().pos
+	limit, offset, err := p.parseLimit()
+	if err != nil {
+		return err
+	}
+	if limit != nil || offset != nil {
+		if stmt["limit"] != nil || stmt["offset"] != nil {
+			return p.errorf(start, "multiple LIMIT clauses are not allowed")
+		}
+		stmt["limit"], stmt["offset"] = limit, offset
+	}
+
+	lock, err := p.parseLock()
+	if err != nil {
+		return err
+	}
+	if lock != nil {
+		stmt["lock"] = lock
+	}
+	return nil
+}
+
+func (p *parser) parseOrderBy() ([]any, error) {
+	items := []any{}
+	if !p.acceptKeyword("order", "by") {
+		return items, nil
+	}
+	for {
+		expr, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		descending := p.acceptKeyword("desc")
+		if !descending {
+			p.acceptKeyword("asc")
+		}
+		if p.dialect == PostgreSQL && p.acceptKeyword("nulls") {
+			if !p.acceptKeyword("first") && !p.acceptKeyword("last") {
+				return nil, p.unexpected()
+			}
+		}
+		items = append(items, map[string]any{"expr": expr, "descending": descending})
+		if !p.acceptOp(",") {
+			return items, nil
+		}
+	}
+}
+
+// parseLimit parses LIMIT and OFFSET clauses, as well as PostgreSQL's FETCH
+// clause, and MySQL's LIMIT offset, count.
+func (p *parser) parseLimit() (limit, offset any, err error) {
+	for {
+		switch {
+		case limit == nil && p.acceptKeyword("limit"):
+			if p.dialect == PostgreSQL && p.acceptKeyword("all") {
+				continue
+			}
+			if limit, err = p.parseExpr(); err != nil {
+				return nil, nil, err
+			}
+			if p.dialect == MySQL && offset == nil && p.acceptOp(",") {
+				offset = limit
+				if limit, err = p.parseExpr(); err != nil {
+					return nil, nil, err
+				}
+			}
+		case offset == nil && p.acceptKeyword("offset"):
+			if offset, err = p.parseExpr(); err != nil {
+				return nil, nil, err
+			}
+			if p.dialect == PostgreSQL && !p.acceptKeyword("rows") {
+				p.acceptKeyword("row")
+			}
+		case limit == nil && p.dialect == PostgreSQL && p.acceptKeyword("fetch"):
+			if !p.acceptKeyword("first") && !p.acceptKeyword("next") {
+				return nil, nil, p.unexpected()
+			}
+			if !p.peekKeyword("row") && !p.peekKeyword("rows") {
+				if limit, err = p.parseExpr(); err != nil {
+					return nil, nil, err
+				}
+			} else {
+				limit = literal(jsonNumber("1"))
+			}
+			if !p.acceptKeyword("rows") && !p.acceptKeyword("row") {
+				return nil, nil, p.unexpected()
+			}
+			if err := p.expectKeyword("only"); err != nil {
+				return nil, nil, err
+			}
+		default:
+			return limit, offset, nil
+		}
+	}
+}
+
+// parseLock parses a locking clause, like FOR UPDATE.
+func (p *parser) parseLock() (any, error) {
+	var lock string
+	switch {
+	case p.acceptKeyword("for", "update"):
+		lock = "update"
+	case p.acceptKeyword("for", "share"):
+		lock = "share"
+	case p.dialect == PostgreSQL && p.acceptKeyword("for", "no", "key", "update"):
+		lock = "no key update"
+	case p.dialect == PostgreSQL && p.acceptKeyword("for", "key", "share"):
+		lock = "key share"
+	case p.dialect == MySQL && p.acceptKeyword("lock", "in", "share", "mode"):
+		return "share", nil
+	default:
+		return nil, nil
+	}
+
+	if p.acceptKeyword("of") {
+		for {
+			if _, _, _, err := p.parseQualifiedName(); err != nil {
+				return nil, err
+			}
+			if !p.acceptOp(",") {
+				break
+			}
+		}
+	}
+	if !p.acceptKeyword("nowait") {
+		p.acceptKeyword("skip", "locked")
+	}
+	return lock, nil
+}
+
+// parseQualifiedName parses a name with up to two qualifiers.
+func (p *parser) parseQualifiedName() (catalog, schema, name string, err error) {
+	parts := make([]string, 0, 3)
+	for {
+		part, err := p.parseName()
+		if err != nil {
+			return "", "", "", err
+		}
+		parts = append(parts, part)
+		if len(parts) == 3 || !p.peekOp(".") {
+			break
+		}
+		p.advance()
+	}
+	for len(parts) < 3 {
+		parts = append([]string{""}, parts...)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+// parseTableName parses the name of a table, and records the reference.
+func (p *parser) parseTableName() (map[string]any, error) {
+	catalog, schema, name, err := p.parseQualifiedName()
+	if err != nil {
+		return nil, err
+	}
+	table := map[string]any{"type": "table", "schema": nullable(schema), "name": name, "alias": nil}
+	if catalog != "" {
+		table["catalog"] = catalog
+	}
+	return table, nil
+}
+
+// addTable records a reference to a table, unless it refers to a common table
+// expression.
+func (p *parser) addTable(table map[string]any) {
+	schema, _ := table["schema"].(string)
+	if p.isCTE(schema, table["name"].(string)) {
+		return
+	}
+	p.tables = append(p.tables, tableRef(table))
+}
+
+func tableRef(table map[string]any) map[string]any {
+	ref := map[string]any{"schema": table["schema"], "name": table["name"], "alias": table["alias"]}
+	if catalog, ok := table["catalog"]; ok {
+		ref["catalog"] = catalog
+	}
+	return ref
+}
+
+// addStatement records a data-modifying statement, which is either the parsed
+// statement itself or nested in one of its common table expressions.
+func (p *parser) addStatement(stmt map[string]any) {
+	s := map[string]any{"type": stmt["type"]}
+	switch stmt["type"] {
+	case "update", "delete":
+		s["has_where"] = stmt["where"] != nil
+		s["table"] = tableRef(stmt["table"].(map[string]any))
+	case "insert", "replace":
+		s["table"] = tableRef(stmt["table"].(map[string]any))
+	default:
+		tables := stmt["tables"].([]any)
+		refs := make([]any, len(tables))
+		for i, t := range tables {
+			refs[i] = tableRef(t.(map[string]any))
+		}
+		s["tables"] = refs
+	}
+	p.statements = append(p.statements, s)
+}
+
+// parseTable parses the name and alias of a table that is the target of a
+// statement.
+func (p *parser) parseTable(alias bool) (map[string]any, error) {
+	table, err := p.parseTableName()
+	if err != nil {
+		return nil, err
+	}
+	if alias {
+		if table["alias"], err = p.parseAlias(); err != nil {
+			return nil, err
+		}
+	}
+	p.addTable(table)
+	return table, nil
+}
+
+// parseFromList parses the comma separated items of a FROM clause. Joined
+// tables are returned separately.
+func (p *parser) parseFromList() (from, joins []any, err error) {
+	from, joins = []any{}, []any{}
+	for {
+		item, itemJoins, err := p.parseJoinedTable()
+		if err != nil {
+			return nil, nil, err
+		}
+		from = append(from, item)
+		joins = append(joins, itemJoins...)
+		if !p.acceptOp(",") {
+			return from, joins, nil
+		}
+	}
+}
+
+func (p *parser) parseJoinedTable() (any, []any, error) {
+	item, joins, err := p.parseTableFactor()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for {
+		join, err := p.parseJoin()
+		if err != nil {
+			return nil, nil, err
+		}
+		if join == nil {
+			return item, joins, nil
+		}
+		joins = append(joins, join...)
+	}
+}
+
+// parseJoin parses a join, if there is one. The table joined is returned first,
+// followed by any tables joined in parentheses.
+func (p *parser) parseJoin() ([]any, error) {
+	start := p.peek().pos
+	natural := p.acceptKeyword("natural")
+
+	var kind string
+	switch {
+	case p.acceptKeyword("join"), p.acceptKeyword("inner", "join"):
+		kind = "inner"
+	case p.dialect == MySQL && p.acceptKeyword("straight_join"):
+		kind = "inner"
+	case p.acceptKeyword("left", "join"), p.acceptKeyword("left", "outer", "join"):
+		kind = "left"
+	case p.acceptKeyword("right", "join"), p.acceptKeyword("right", "outer", "join"):
+		kind = "right"
+	case p.acceptKeyword("full", "join"), p.acceptKeyword("full", "outer", "join"):
+		kind = "full"
+	case !natural && p.acceptKeyword("cross", "join"):
+		kind = "cross"
+	case natural:
+		return nil, p.unexpected()
+	default:
+		return nil, nil
+	}
+
+	if kind == "full" && p.dialect == MySQL {
+		return nil, p.errorf(start, "FULL JOIN is not supported by MySQL")
+	}
+
+	table, nested, err := p.parseTableFactor()
+	if err != nil {
+		return nil, err
+	}
+	join := map[string]any{"type": kind, "natural": natural, "table": table, "on": nil, "using": []any{}}
+
+	if !natural && kind != "cross" {
+		switch {
+		case p.acceptKeyword("on"):
+			if join["on"], err = p.parseExpr(); err != nil {
+				return nil, err
+			}
+		case p.peekKeyword("using"):
+			p.advance()
+			using, err := p.parseNameList()
+			if err != nil {
+				return nil, err
+			}
+			for _, name := range using {
+				p.addColumn("", "", name.(string))
+			}
+			join["using"] = using
+		case p.dialect == PostgreSQL || kind != "inner":
+			return nil, p.unexpected()
+		}
+	}
+	return append([]any{join}, nested...), nil
+}
+
+// parseTableFactor parses a table, subquery or table function, with its alias,
+// or joined tables in parentheses, which are returned separately.
+func (p *parser) parseTableFactor() (any, []any, error) {
+	lateral := p.acceptKeyword("lateral")
+
+	if p.peekOp("(") {
+		if !p.startsQuery() {
+			if lateral {
+				return nil, nil, p.unexpected()
+			}
+			if err := p.enter(); err != nil {
+				return nil, nil, err
+			}
+			defer p.leave()
+			p.advance()
+			item, joins, err := p.parseJoinedTable()
+			if err != nil {
+				return nil, nil, err
+			}
+			return item, joins, p.expectOp(")")
+		}
+
+		p.advance()
+		query, err := p.parseQuery()
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := p.expectOp(")"); err != nil {
+			return nil, nil, err
+		}
+		alias, columns, err := p.parseTableAlias()
+		if err != nil {
+			return nil, nil, err
+		}
+		return map[string]any{"type": "subquery", "lateral": lateral, "query": query, "alias": alias, "columns": columns}, nil, nil
+	}
+
+	start := p.pos
+	catalog, schema, name, err := p.parseQualifiedName()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if p.peekOp("(") && p.dialect == PostgreSQL && catalog == "" {
+		fn, err := p.parseFunction(schema, name)
+		if err != nil {
+			return nil, nil, err
+		}
+		alias, columns, err := p.parseTableAlias()
+		if err != nil {
+			return nil, nil, err
+		}
+		fn["lateral"], fn["alias"], fn["columns"] = lateral, alias, columns
+		return fn, nil, nil
+	}
+	if lateral {
+		p.pos = start
+		return nil, nil, p.unexpected()
+	}
+
+	table := map[string]any{"type": "table", "schema": nullable(schema), "name": name, "alias": nil}
+	if catalog != "" {
+		table["catalog"] = catalog
+	}
+	alias, columns, err := p.parseTableAlias()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(columns) > 0 {
+		return nil, nil, p.errorf(p.tokens[start].pos, "column aliases are not supported for tables")
+	}
+	table["alias"] = alias
+	p.addTable(table)
+	return table, nil, nil
+}
+
+// startsQuery reports whether the parenthesis that is next starts a query,
+// rather than joined tables. The result is the same for all parentheses of a
+// run, so it is kept for the nested ones, which are scanned only once.
+func (p *parser) startsQuery() bool {
+	if p.pos < p.parens.start || p.pos >= p.parens.end {
+		end := p.pos
+		for isOp(p.tokens[end], "(") {
+			end++
+		}
+		t := p.tokens[end]
+		p.parens.start, p.parens.end = p.pos, end
+		p.parens.query = isKeyword(t, "select") || isKeyword(t, "with")
+	}
+	return p.parens.query
+}
+
+// parseTableAlias parses the alias of a subquery or table function, which may
+// name its columns, too.
+func (p *parser) parseTableAlias() (any, []any, error) {
+	alias, err := p.parseAlias()
+	if err != nil || alias == nil {
+		return nil, []any{}, err
+	}
+	columns := []any{}
+	if p.peekOp("(") {
+		if columns, err = p.parseNameList(); err != nil {
+			return nil, nil, err
+		}
+	}
+	return alias, columns, nil
+}
+
+func (p *parser) parseInsert(kind string) (map[string]any, error) {
+	p.advance() // INSERT or REPLACE
+
+	if p.dialect == MySQL {
+		p.acceptKeyword("ignore")
+		if !p.acceptKeyword("into") && !isName(p.peek()) {
+			return nil, p.unexpected()
+		}
+	} else if err := p.expectKeyword("into"); err != nil {
+		return nil, err
+	}
+
+	table, err := p.parseTableName()
+	if err != nil {
+		return nil, err
+	}
+	if p.dialect == PostgreSQL && p.acceptKeyword("as") {
+		if table["alias"], err = p.parseName(); err != nil {
+			return nil, err
+		}
+	}
+	p.addTable(table)
+
+	stmt := map[string]any{
+		"type":        kind,
+		"with":        []any{},
+		"table":       table,
+		"columns":     []any{},
+		"values":      nil,
+		"query":       nil,
+		"on_conflict": nil,
+		"returning":   []any{},
+	}
+
+	if p.peekOp("(") && !p.startsQuery() {
+		columns, err := p.parseNameList()
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range columns {
+			p.addColumn("", "", name.(string))
+		}
+		stmt["columns"] = columns
+	}
+
+	switch {
+	case p.acceptKeyword("values"), p.dialect == MySQL && p.acceptKeyword("value"):
+		rows := []any{}
+		for {
+			if err := p.expectOp("("); err != nil {
+				return nil, err
+			}
+			row := []any{}
+			if !p.peekOp(")") {
+				if row, err = p.parseExprList(); err != nil {
+					return nil, err
+				}
+			}
+			if err := p.expectOp(")"); err != nil {
+				return nil, err
+			}
+			rows = append(rows, row)
+			if !p.acceptOp(",") {
+				break
+			}
+		}
+		stmt["values"] = rows
+	case p.peekKeyword("select"), p.peekKeyword("with"), p.peekOp("("):
+		if stmt["query"], err = p.parseQuery(); err != nil {
+			return nil, err
+		}
+	case p.dialect == PostgreSQL && p.acceptKeyword("default", "values"):
+		stmt["values"] = []any{[]any{}}
+	case p.dialect == MySQL && p.acceptKeyword("set"):
+		if len(stmt["columns"].([]any)) > 0 {
+			return nil, p.errorf(p.tokens[p.pos-1].pos, "unexpected SET")
+		}
+		set, err := p.parseAssignments()
+		if err != nil {
+			return nil, err
+		}
+		columns, row := make([]any, len(set)), make([]any, len(set))
+		for i, a := range set {
+			a := a.(map[string]any)
+			columns[i], row[i] = a["column"].(map[string]any)["name"], a["value"]
+		}
+		stmt["columns"], stmt["values"] = columns, []any{row}
+	default:
+		return nil, p.unexpected()
+	}
+
+	if stmt["on_conflict"], err = p.parseOnConflict(); err != nil {
+		return nil, err
+	}
+	if stmt["returning"], err = p.parseReturning(); err != nil {
+		return nil, err
+	}
+	p.addStatement(stmt)
+	return stmt, nil
+}
+
+// parseOnConflict parses PostgreSQL's ON CONFLICT and MySQL's ON DUPLICATE KEY
+// UPDATE clauses.
+func (p *parser) parseOnConflict() (any, error) {
+	conflict := map[string]any{"target": []any{}, "constraint": nil, "action": "update", "set": []any{}, "where": nil}
+
+	var err error
+	switch {
+	case p.dialect == MySQL && p.acceptKeyword("on", "duplicate", "key", "update"):
+		if conflict["set"], err = p.parseAssignments(); err != nil {
+			return nil, err
+		}
+		return conflict, nil
+	case p.dialect == PostgreSQL && p.acceptKeyword("on", "conflict"):
+	default:
+		return nil, nil
+	}
+
+	switch {
+	case p.peekOp("("):
+		target, err := p.parseNameList()
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range target {
+			p.addColumn("", "", name.(string))
+		}
+		conflict["target"] = target
+	case p.acceptKeyword("on", "constraint"):
+		if conflict["constraint"], err = p.parseName(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.expectKeyword("do"); err != nil {
+		return nil, err
+	}
+	if p.acceptKeyword("nothing") {
+		conflict["action"] = "nothing"
+		return conflict, nil
+	}
+	if err := p.expectKeyword("update", "set"); err != nil {
+		return nil, err
+	}
+	if conflict["set"], err = p.parseAssignments(); err != nil {
+		return nil, err
+	}
+	if conflict["where"], err = p.parseWhere(); err != nil {
+		return nil, err
+	}
+	return conflict, nil
+}
+
+func (p *parser) parseReturning() ([]any, error) {
+	if p.dialect != PostgreSQL || !p.acceptKeyword("returning") {
+		return []any{}, nil
+	}
+	return p.parseSelectList()
+}
+
+// parseAssignments parses the assignments of columns in SET clauses.
+func (p *parser) parseAssignments() ([]any, error) {
+	set := []any{}
+	for {
+		_, table, name, err := p.parseQualifiedName()
+		if err != nil {
+			return nil, err
+		}
+		p.addColumn("", table, name)
+		if err := p.expectOp("="); err != nil {
+			return nil, err
+		}
+		value, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, map[string]any{
+			"column": map[string]any{"type": "column", "table": nullable(table), "name": name},
+			"value":  value,
+		})
+		if !p.acceptOp(",") {
+			return set, nil
+		}
+	}
+}
+
+func (p *parser) parseUpdate() (map[string]any, error) {
+	p.advance() // UPDATE
+
+	if p.dialect == MySQL {
+		p.acceptKeyword("ignore")
+	}
+	p.acceptKeyword("only")
+
+	table, err := p.parseTable(true)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt := map[string]any{
+		"type":      "update",
+		"with":      []any{},
+		"table":     table,
+		"from":      []any{},
+		"joins":     []any{},
+		"set":       []any{},
+		"where":     nil,
+		"order_by":  []any{},
+		"limit":     nil,
+		"returning": []any{},
+	}
+
+	if p.dialect == MySQL {
+		// MySQL updates several tables at once, joined or listed.
+		var joins, from []any
+		for {
+			join, err := p.parseJoin()
+			if err != nil {
+				return nil, err
+			}
+			if join == nil {
+				break
+			}
+			joins = append(joins, join...)
+		}
+		if p.acceptOp(",") {
+			if from, joins, err = p.appendFromList(joins); err != nil {
+				return nil, err
+			}
+		}
+		if from != nil {
+			stmt["from"] = from
+		}
+		if joins != nil {
+			stmt["joins"] = joins
+		}
+	}
+
+	if err := p.expectKeyword("set"); err != nil {
+		return nil, err
+	}
+	if stmt["set"], err = p.parseAssignments(); err != nil {
+		return nil, err
+	}
+
+	if p.dialect == PostgreSQL && p.acceptKeyword("from") {
+		if stmt["from"], stmt["joins"], err = p.parseFromList(); err != nil {
+			return nil, err
+		}
+	}
+	if stmt["where"], err = p.parseWhere(); err != nil {
+		return nil, err
+	}
+	if err := p.parseOrderLimit(stmt); err != nil {
+		return nil, err
+	}
+	if stmt["returning"], err = p.parseReturning(); err != nil {
+		return nil, err
+	}
+	p.addStatement(stmt)
+	return stmt, nil
+}
+
+// appendFromList parses a FROM list, whose joins follow the given ones.
+func (p *parser) appendFromList(joins []any) ([]any, []any, error) {
+	from, more, err := p.parseFromList()
+	if err != nil {
+		return nil, nil, err
+	}
+	return from, append(joins, more...), nil
+}
+
+// parseOrderLimit parses the ORDER BY and LIMIT clauses MySQL allows in
+// UPDATE and DELETE statements.
+func (p *parser) parseOrderLimit(stmt map[string]any) error {
+	if p.dialect != MySQL {
+		return nil
+	}
+	var err error
+	if stmt["order_by"], err = p.parseOrderBy(); err != nil {
+		return err
+	}
+	if p.acceptKeyword("limit") {
+		stmt["limit"], err = p.parseExpr()
+	}
+	return err
+}
+
+func (p *parser) parseDelete() (map[string]any, error) {
+	p.advance() // DELETE
+
+	if p.dialect == MySQL {
+		p.acceptKeyword("ignore")
+	}
+	if err := p.expectKeyword("from"); err != nil {
+		return nil, err
+	}
+	p.acceptKeyword("only")
+
+	table, err := p.parseTable(true)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt := map[string]any{
+		"type":      "delete",
+		"with":      []any{},
+		"table":     table,
+		"using":     []any{},
+		"joins":     []any{},
+		"where":     nil,
+		"order_by":  []any{},
+		"limit":     nil,
+		"returning": []any{},
+	}
+
+	if p.dialect == PostgreSQL && p.acceptKeyword("using") {
+		if stmt["using"], stmt["joins"], err = p.parseFromList(); err != nil {
+			return nil, err
+		}
+	}
+	if stmt["where"], err = p.parseWhere(); err != nil {
+		return nil, err
+	}
+	if err := p.parseOrderLimit(stmt); err != nil {
+		return nil, err
+	}
+	if stmt["returning"], err = p.parseReturning(); err != nil {
+		return nil, err
+	}
+	p.addStatement(stmt)
+	return stmt, nil
+}
+
+func (p *parser) parseTruncate() (map[string]any, error) {
+	p.advance() // TRUNCATE
+	p.acceptKeyword("table")
+
+	tables, err := p.parseTableNames()
+	if err != nil {
+		return nil, err
+	}
+	if p.dialect == PostgreSQL {
+		if !p.acceptKeyword("restart", "identity") {
+			p.acceptKeyword("continue", "identity")
+		}
+		if !p.acceptKeyword("cascade") {
+			p.acceptKeyword("restrict")
+		}
+	}
+	stmt := map[string]any{"type": "truncate", "tables": tables}
+	p.addStatement(stmt)
+	return stmt, nil
+}
+
+func (p *parser) parseDrop() (map[string]any, error) {
+	p.advance() // DROP
+
+	if p.dialect == MySQL {
+		p.acceptKeyword("temporary")
+	}
+	if !p.acceptKeyword("table") {
+		if t := p.peek(); t.kind == tokenIdent {
+			return nil, p.errorf(t.pos, "unsupported statement DROP %s", strings.ToUpper(t.text))
+		}
+		return nil, p.unexpected()
+	}
+	ifExists := p.acceptKeyword("if", "exists")
+
+	tables, err := p.parseTableNames()
+	if err != nil {
+		return nil, err
+	}
+	if !p.acceptKeyword("cascade") {
+		p.acceptKeyword("restrict")
+	}
+	stmt := map[string]any{"type": "drop", "object": "table", "if_exists": ifExists, "tables": tables}
+	p.addStatement(stmt)
+	return stmt, nil
+}
+
+func (p *parser) parseTableNames() ([]any, error) {
+	tables := []any{}
+	for {
+		table, err := p.parseTable(false)
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+		if !p.acceptOp(",") {
+			return tables, nil
+		}
+	}
+}
@@ -0,0 +1,310 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package sqlparse
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		dialect   Dialect
+		statement string
+		path      string // dot-separated path into the statement
+		exp       string
+	}{
+		// Statement types.
+		{PostgreSQL, `SELECT 1`, "type", `"select"`},
+		{PostgreSQL, `INSERT INTO t VALUES (1)`, "type", `"insert"`},
+		{MySQL, `REPLACE INTO t VALUES (1)`, "type", `"replace"`},
+		{PostgreSQL, `UPDATE t SET a = 1`, "type", `"update"`},
+		{PostgreSQL, `DELETE FROM t`, "type", `"delete"`},
+		{PostgreSQL, `TRUNCATE TABLE a, b`, "tables", `[{"type": "table", "schema": null, "name": "a", "alias": null}, {"type": "table", "schema": null, "name": "b", "alias": null}]`},
+		{MySQL, `DROP TABLE IF EXISTS t`, "", `{"type": "drop", "object": "table", "if_exists": true, "tables": [{"type": "table", "schema": null, "name": "t", "alias": null}], "references": {"tables": [{"schema": null, "name": "t", "alias": null}], "columns": [], "statements": [{"type": "drop", "tables": [{"schema": null, "name": "t", "alias": null}]}]}}`},
+		{PostgreSQL, `SELECT 1;`, "type", `"select"`},
+
+		// DELETE and UPDATE without WHERE.
+		{PostgreSQL, `DELETE FROM users`, "where", `null`},
+		{MySQL, `UPDATE users SET admin = 1`, "where", `null`},
+		{PostgreSQL, `DELETE FROM users WHERE id = $1`, "where", `{"type": "binary", "operator": "=", "left": {"type": "column", "table": null, "name": "id"}, "right": {"type": "parameter", "index": 1}}`},
+		{MySQL, `DELETE FROM users WHERE id = ? AND org = ?`, "where.right.right", `{"type": "parameter", "index": 2}`},
+
+		// Tables, schemas and aliases.
+		{PostgreSQL, `SELECT * FROM public.users AS u`, "from", `[{"type": "table", "schema": "public", "name": "users", "alias": "u"}]`},
+		{MySQL, "SELECT * FROM `db`.`Users` u", "from", `[{"type": "table", "schema": "db", "name": "Users", "alias": "u"}]`},
+		{PostgreSQL, `SELECT * FROM "Public"."Users"`, "references.tables", `[{"schema": "Public", "name": "Users", "alias": null}]`},
+		{PostgreSQL, `SELECT * FROM Public.Users`, "references.tables", `[{"schema": "public", "name": "users", "alias": null}]`},
+		{PostgreSQL, `SELECT * FROM db.public.users`, "from.0.catalog", `"db"`},
+
+		// Columns.
+		{PostgreSQL, `SELECT u.id, u."SSN" AS s FROM users u`, "columns", `[
+			{"expr": {"type": "column", "table": "u", "name": "id"}, "alias": null},
+			{"expr": {"type": "column", "table": "u", "name": "SSN"}, "alias": "s"}
+		]`},
+		{PostgreSQL, `SELECT u.* FROM users u`, "references.columns", `[{"table": "u", "name": "*"}]`},
+		{PostgreSQL, `SELECT * FROM users`, "columns", `[{"expr": {"type": "star", "table": null}, "alias": null}]`},
+		{PostgreSQL, `SELECT count(*) FROM users`, "references.columns", `[]`},
+		{MySQL, "SELECT ssn AS 'x' FROM users", "columns.0.alias", `"x"`},
+		{MySQL, "SELECT SSN FROM users", "references.columns", `[{"table": null, "name": "SSN"}]`},
+		{PostgreSQL, `SELECT a FROM t WHERE b IN (SELECT c FROM s WHERE d = 1)`, "references.columns", `[
+			{"table": null, "name": "a"},
+			{"table": null, "name": "b"},
+			{"table": null, "name": "c"},
+			{"table": null, "name": "d"}
+		]`},
+
+		// Joins.
+		{PostgreSQL, `SELECT 1 FROM a JOIN b ON a.id = b.id LEFT OUTER JOIN c USING (id) CROSS JOIN d`, "joins", `[
+			{"type": "inner", "natural": false, "table": {"type": "table", "schema": null, "name": "b", "alias": null},
+			 "on": {"type": "binary", "operator": "=", "left": {"type": "column", "table": "a", "name": "id"}, "right": {"type": "column", "table": "b", "name": "id"}}, "using": []},
+			{"type": "left", "natural": false, "table": {"type": "table", "schema": null, "name": "c", "alias": null}, "on": null, "using": ["id"]},
+			{"type": "cross", "natural": false, "table": {"type": "table", "schema": null, "name": "d", "alias": null}, "on": null, "using": []}
+		]`},
+		{MySQL, `SELECT 1 FROM a NATURAL JOIN b`, "joins.0.natural", `true`},
+		{PostgreSQL, `SELECT 1 FROM a, (SELECT * FROM b) AS s`, "from.1.type", `"subquery"`},
+		{PostgreSQL, `SELECT 1 FROM a, (SELECT * FROM b) AS s`, "references.tables", `[{"schema": null, "name": "a", "alias": null}, {"schema": null, "name": "b", "alias": null}]`},
+
+		// Clauses.
+		{PostgreSQL, `SELECT a FROM t GROUP BY a HAVING count(*) > 1 ORDER BY a DESC LIMIT 10 OFFSET 5`, "order_by", `[{"expr": {"type": "column", "table": null, "name": "a"}, "descending": true}]`},
+		{PostgreSQL, `SELECT a FROM t LIMIT 10 OFFSET 5`, "offset", `{"type": "literal", "value": 5}`},
+		{MySQL, `SELECT a FROM t LIMIT 5, 10`, "limit", `{"type": "literal", "value": 10}`},
+		{MySQL, `SELECT a FROM t LIMIT 5, 10`, "offset", `{"type": "literal", "value": 5}`},
+		{PostgreSQL, `SELECT a FROM t FETCH FIRST 3 ROWS ONLY`, "limit", `{"type": "literal", "value": 3}`},
+		{PostgreSQL, `SELECT DISTINCT a FROM t`, "distinct", `true`},
+		{PostgreSQL, `SELECT 1 UNION ALL SELECT 2`, "operator", `"union"`},
+		{PostgreSQL, `SELECT 1 UNION ALL SELECT 2`, "all", `true`},
+
+		// WITH.
+		{PostgreSQL, `WITH x AS (SELECT id FROM users) SELECT * FROM x`, "references.tables", `[{"schema": null, "name": "users", "alias": null}]`},
+		{PostgreSQL, `WITH d AS (DELETE FROM users RETURNING id) SELECT * FROM d`, "with.0.query.type", `"delete"`},
+		{PostgreSQL, `WITH d AS (DELETE FROM users RETURNING id) SELECT * FROM d`, "references.statements", `[{"type": "delete", "table": {"schema": null, "name": "users", "alias": null}, "has_where": false}]`},
+		{PostgreSQL, `WITH u AS (UPDATE t SET a = 1 WHERE id = 2 RETURNING *) INSERT INTO log SELECT * FROM u`, "references.statements", `[
+			{"type": "update", "table": {"schema": null, "name": "t", "alias": null}, "has_where": true},
+			{"type": "insert", "table": {"schema": null, "name": "log", "alias": null}}
+		]`},
+		{PostgreSQL, `SELECT * FROM t`, "references.statements", `[]`},
+
+		// INSERT.
+		{PostgreSQL, `INSERT INTO t (a, b) VALUES (1, 'x'), (2, 'y')`, "values", `[
+			[{"type": "literal", "value": 1}, {"type": "literal", "value": "x"}],
+			[{"type": "literal", "value": 2}, {"type": "literal", "value": "y"}]
+		]`},
+		{MySQL, `INSERT INTO t SET a = 1, b = 2`, "columns", `["a", "b"]`},
+		{PostgreSQL, `INSERT INTO t (a) SELECT a FROM s ON CONFLICT (a) DO NOTHING`, "on_conflict.action", `"nothing"`},
+		{MySQL, `INSERT INTO t (a) VALUES (1) ON DUPLICATE KEY UPDATE a = a + 1`, "on_conflict.action", `"update"`},
+
+		// Expressions.
+		{PostgreSQL, `SELECT -1.5, .5, 1e3, 'it''s', E'a\nb', $$x$$, true, null`, "columns", `[
+			{"expr": {"type": "literal", "value": -1.5}, "alias": null},
+			{"expr": {"type": "literal", "value": 0.5}, "alias": null},
+			{"expr": {"type": "literal", "value": 1e3}, "alias": null},
+			{"expr": {"type": "literal", "value": "it's"}, "alias": null},
+			{"expr": {"type": "literal", "value": "a\nb"}, "alias": null},
+			{"expr": {"type": "literal", "value": "x"}, "alias": null},
+			{"expr": {"type": "literal", "value": true}, "alias": null},
+			{"expr": {"type": "literal", "value": null}, "alias": null}
+		]`},
+		{MySQL, `SELECT "a\"b"`, "columns.0.expr.value", `"a\"b"`},
+		{PostgreSQL, `SELECT 1 WHERE a OR b AND NOT c`, "where.operator", `"or"`},
+		{PostgreSQL, `SELECT 1 WHERE a IS NOT NULL`, "where.operator", `"is not"`},
+		{PostgreSQL, `SELECT 1 WHERE a NOT BETWEEN 1 AND 2`, "where.not", `true`},
+		{PostgreSQL, `SELECT 1 WHERE a ILIKE 'x%'`, "where.operator", `"ilike"`},
+		{PostgreSQL, `SELECT x::numeric(10,2)`, "columns.0.expr", `{"type": "cast", "expr": {"type": "column", "table": null, "name": "x"}, "as": "numeric(10,2)"}`},
+		{PostgreSQL, `SELECT CAST(x AS timestamp with time zone)`, "columns.0.expr.as", `"timestamp with time zone"`},
+		{PostgreSQL, `SELECT CASE WHEN a THEN 1 ELSE 2 END`, "columns.0.expr.type", `"case"`},
+		{PostgreSQL, `SELECT 1 WHERE EXISTS (SELECT 1 FROM t)`, "where.type", `"exists"`},
+		{PostgreSQL, `SELECT 1 WHERE a = ANY (SELECT b FROM t)`, "where.right.type", `"any"`},
+
+		// Comments.
+		{PostgreSQL, "SELECT /* a /* nested */ comment */ 1 -- trailing", "type", `"select"`},
+		{MySQL, "SELECT 1 # trailing", "type", `"select"`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.statement, func(t *testing.T) {
+			stmt, err := Parse(tc.dialect, tc.statement)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var exp any
+			if err := json.Unmarshal([]byte(tc.exp), &exp); err != nil {
+				t.Fatal(err)
+			}
+
+			act, ok := get(roundTrip(t, stmt), tc.path)
+			if !ok {
+				t.Fatalf("path %q not found in %v", tc.path, stmt)
+			}
+			if !reflect.DeepEqual(exp, act) {
+				t.Fatalf("expected %v at %q, got %v", exp, tc.path, act)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		dialect   Dialect
+		statement string
+		exp       string
+	}{
+		{PostgreSQL, ``, "sql: line 1, column 1: empty statement"},
+		{PostgreSQL, `CREATE TABLE t (a int)`, "sql: line 1, column 1: unsupported statement CREATE"},
+		{MySQL, `SELECT 1; DROP TABLE users`, "sql: line 1, column 11: multiple statements are not supported"},
+		{MySQL, `SELECT /*! 1 */`, "sql: line 1, column 8: executable comments are not supported"},
+		{MySQL, `SELECT /*M!100000 1 */`, "sql: line 1, column 8: executable comments are not supported"},
+		{PostgreSQL, "SELECT *\nFROM t\nWHERE", "sql: line 3, column 6: unexpected end of statement"},
+		{PostgreSQL, `SELECT 'abc`, "sql: line 1, column 8: unterminated string"},
+		{PostgreSQL, `SELECT "" FROM t`, "sql: line 1, column 8: zero-length quoted identifier"},
+		{PostgreSQL, `SELECT 1 /* comment`, "sql: line 1, column 10: unterminated comment"},
+		{MySQL, `SELECT 0x1F`, "sql: line 1, column 8: invalid number"},
+		{PostgreSQL, `WITH d AS (TRUNCATE users) SELECT 1`, "sql: line 1, column 12: unexpected TRUNCATE"},
+		{PostgreSQL, `DELETE FROM t WHERE CURRENT OF c`, "sql: line 1, column 21: WHERE CURRENT OF is not supported"},
+		{PostgreSQL, `SELECT 1 ORDER BY 1 LIMIT 1 ORDER BY 1`, "sql: line 1, column 29: unexpected ORDER"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.statement, func(t *testing.T) {
+			_, err := Parse(tc.dialect, tc.statement)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if err.Error() != tc.exp {
+				t.Fatalf("expected %q, got %q", tc.exp, err.Error())
+			}
+		})
+	}
+}
+
+// TestParseNesting checks that deeply nested statements are rejected, without
+// rescanning the parentheses at every level.
+func TestParseNesting(t *testing.T) {
+	t.Parallel()
+
+	nest := func(n int, open, inner, close string) string {
+		return strings.Repeat(open, n) + inner + strings.Repeat(close, n)
+	}
+
+	for _, n := range []int{maxDepth / 2, 100_000} {
+		tests := []struct {
+			note      string
+			statement string
+		}{
+			{"expression", "SELECT " + nest(n, "(", "1", ")")},
+			{"subquery", "SELECT " + nest(n, "(", "SELECT 1", ")")},
+			{"in", "SELECT 1 WHERE a IN " + nest(n, "(", "1", ")")},
+			{"query", nest(n, "(", "SELECT 1", ")")},
+			{"joined tables", "SELECT * FROM " + nest(n, "(", "t", ")")},
+			{"not", "SELECT " + nest(n, "NOT ", "true", "")},
+			{"unary", "SELECT " + nest(n, "- ", "1", "")},
+		}
+
+		for _, tc := range tests {
+			t.Run(fmt.Sprintf("%s/%d", tc.note, n), func(t *testing.T) {
+				_, err := Parse(PostgreSQL, tc.statement)
+				if n <= maxDepth {
+					if err != nil {
+						t.Fatal(err)
+					}
+				} else if err == nil || !strings.HasSuffix(err.Error(), "statement nested too deeply") {
+					t.Fatalf("expected nesting error, got %v", err)
+				}
+			})
+		}
+	}
+
+	// A long run of parentheses is scanned once, rather than at every level.
+	statement := "SELECT " + strings.Repeat("(", 4<<20)
+	if _, err := Parse(PostgreSQL, statement); err == nil || !strings.HasSuffix(err.Error(), "statement nested too deeply") {
+		t.Fatalf("expected nesting error, got %v", err)
+	}
+}
+
+// TestDialects checks that quoting is interpreted per dialect: a double quoted
+// identifier in PostgreSQL is a string in MySQL.
+func TestDialects(t *testing.T) {
+	t.Parallel()
+
+	pg, err := Parse(PostgreSQL, `SELECT "ssn" FROM users`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, _ := get(roundTrip(t, pg), "columns.0.expr.type"); act != "column" {
+		t.Fatalf("expected column, got %v", act)
+	}
+
+	my, err := Parse(MySQL, `SELECT "ssn" FROM users`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, _ := get(roundTrip(t, my), "columns.0.expr.type"); act != "literal" {
+		t.Fatalf("expected literal, got %v", act)
+	}
+
+	if _, err := Parse(PostgreSQL, "SELECT `ssn` FROM users"); err == nil {
+		t.Fatal("expected error for backticks in PostgreSQL")
+	}
+}
+
+func TestParseDialect(t *testing.T) {
+	t.Parallel()
+
+	for name, exp := range map[string]Dialect{"postgresql": PostgreSQL, "Postgres": PostgreSQL, "MySQL": MySQL} {
+		if d, ok := ParseDialect(name); !ok || d != exp {
+			t.Errorf("%s: expected %v, got %v", name, exp, d)
+		}
+	}
+	if _, ok := ParseDialect("oracle"); ok {
+		t.Error("expected oracle to be unknown")
+	}
+}
+
+func roundTrip(t *testing.T, v any) any {
+	t.Helper()
+
+	bs, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out any
+	if err := json.Unmarshal(bs, &out); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func get(v any, path string) (any, bool) {
+	if path == "" {
+		return v, true
+	}
+	for _, key := range strings.Split(path, ".") {
+		switch x := v.(type) {
+		case map[string]any:
+			var ok bool
+			if v, ok = x[key]; !ok {
+				return nil, false
+			}
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i >= len(x) {
+				return nil, false
+			}
+			v = x[i]
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
@@ -0,0 +1,1080 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package ast
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/open-policy-agent/opa/internal/astbinary"
+	"github.com/open-policy-agent/opa/v1/util"
+)
+
+// The binary module encoding is a compact serialization of modules meant for
+// caching parsed or compiled modules, e.g. in precompiled bundles. Unlike the
+// JSON representation, it preserves everything the compiler and evaluator
+// depend on, including locations, annotations and generated expressions, and
+// it can be decoded considerably faster than the module source can be parsed.
+//
+// Strings and locations are stored once in tables and referenced by index, so
+// locations shared between nodes remain shared after decoding. If the source
+// the module was parsed from is available on both ends, location text is
+// stored as a length relative to the location offset instead of a copy.
+//
+// The encoding is not stable across OPA versions: decoding fails if the data
+// was written by a different encoding version.
+
+const binaryModuleVersion = 1
+
+func init() {
+	astbinary.Marshal = func(mod any, src []byte) ([]byte, error) {
+		return marshalModuleBinary(mod.(*Module), src)
+	}
+	astbinary.Unmarshal = func(bs []byte, src []byte, file string) (any, error) {
+		return unmarshalModuleBinary(bs, src, file)
+	}
+}
+
+var binaryModuleMagic = []byte("OPAM")
+
+// Tags identifying the type of an encoded value.
+const (
+	binNil byte = iota
+	binNull
+	binFalse
+	binTrue
+	binNumber
+	binString
+	binVar
+	binRef
+	binArray
+	binObject
+	binSet
+	binArrayComprehension
+	binObjectComprehension
+	binSetComprehension
+	binCall
+	binTemplateString
+	binNot
+)
+
+// Tags identifying the type of the terms of an encoded expression.
+const (
+	binExprTerm byte = iota
+	binExprCall
+	binExprSomeDecl
+	binExprEvery
+	binExprNot
+	binExprAnd
+	binExprOr
+)
+
+// Location text modes.
+const (
+	binTextNone byte = iota
+	binTextSource
+	binTextInline
+)
+
+// Kinds of nodes that annotations can be attached to.
+const (
+	binNodeNone byte = iota
+	binNodePackage
+	binNodeImport
+	binNodeRule
+)
+
+// marshalModuleBinary returns the binary encoding of mod. If src is the source
+// that the module was parsed from, location text is encoded as references into
+// it and the same source must be passed to unmarshalModuleBinary.
+func marshalModuleBinary(mod *Module, src []byte) ([]byte, error) {
+	e := &binaryEncoder{
+		src:    src,
+		strs:   map[string]int{},
+		locs:   map[*Location]int{},
+		annots: map[*Annotations]int{},
+	}
+
+	e.module(mod)
+	body := e.buf
+
+	// Annotations are encoded after the module, as the rules refer to them,
+	// but decoded before it so the rules can point at them.
+	e.buf = nil
+	e.uvarint(uint64(len(e.annotList)))
+	for _, a := range e.annotList {
+		e.annotation(a, mod)
+	}
+	annots := e.buf
+
+	if e.err != nil {
+		return nil, e.err
+	}
+
+	e.buf = append(make([]byte, 0, len(body)+len(annots)+len(e.strList)*8), binaryModuleMagic...)
+	e.uvarint(binaryModuleVersion)
+
+	e.uvarint(uint64(len(e.strList)))
+	for _, s := range e.strList {
+		e.uvarint(uint64(len(s)))
+		e.buf = append(e.buf, s...)
+	}
+
+	// Locations only refer to strings already in the table.
+	e.uvarint(uint64(len(e.locList)))
+	for _, loc := range e.locList {
+		e.location(loc)
+	}
+
+	e.buf = append(e.buf, annots...)
+	e.buf = append(e.buf, body...)
+
+	return e.buf, e.err
+}
+
+// unmarshalModuleBinary decodes a module encoded by marshalModuleBinary. The
+// src must be the source the module was encoded with. If file is not empty,
+// it replaces the file of all locations in the module that have one. The rules in the module
+// will have their module pointer set to the module.
+func unmarshalModuleBinary(bs []byte, src []byte, file string) (*Module, error) {
+	if !bytes.HasPrefix(bs, binaryModuleMagic) {
+		return nil, errors.New("binary module: invalid header")
+	}
+
+	d := &binaryDecoder{buf: bs[len(binaryModuleMagic):], src: src, file: file}
+	if v := d.uvarint(); d.err == nil && v != binaryModuleVersion {
+		return nil, fmt.Errorf("binary module: unsupported version %d", v)
+	}
+
+	d.strs = make([]string, d.length())
+	for i := range d.strs {
+		d.strs[i] = string(d.bytes())
+	}
+
+	d.locs = make([]*Location, d.length())
+	for i := range d.locs {
+		d.locs[i] = d.location()
+	}
+
+	d.annots = make([]*Annotations, d.length())
+	d.annotNodes = make([][2]int, len(d.annots))
+	for i := range d.annots {
+		d.annots[i] = d.annotation(i)
+	}
+
+	mod := d.module()
+	if d.err != nil {
+		return nil, d.err
+	}
+	if len(d.buf) != 0 {
+		return nil, errors.New("binary module: trailing data")
+	}
+
+	for i, a := range d.annots {
+		switch kind, idx := d.annotNodes[i][0], d.annotNodes[i][1]; byte(kind) {
+		case binNodePackage:
+			a.node = mod.Package
+		case binNodeImport:
+			if idx < len(mod.Imports) {
+				a.node = mod.Imports[idx]
+			}
+		case binNodeRule:
+			if idx < len(mod.Rules) {
+				a.node = mod.Rules[idx]
+			}
+		}
+	}
+
+	WalkRules(mod, func(rule *Rule) bool {
+		rule.Module = mod
+		return false
+	})
+
+	return mod, nil
+}
+
+type binaryEncoder struct {
+	buf       []byte
+	src       []byte
+	strs      map[string]int
+	strList   []string
+	locs      map[*Location]int
+	locList   []*Location
+	annots    map[*Annotations]int
+	annotList []*Annotations
+	err       error
+}
+
+func (e *binaryEncoder) uvarint(x uint64) {
+	e.buf = binary.AppendUvarint(e.buf, x)
+}
+
+func (e *binaryEncoder) int(x int) {
+	e.buf = binary.AppendVarint(e.buf, int64(x))
+}
+
+func (e *binaryEncoder) byte(b byte) {
+	e.buf = append(e.buf, b)
+}
+
+func (e *binaryEncoder) bool(b bool) {
+	if b {
+		e.byte(1)
+	} else {
+		e.byte(0)
+	}
+}
+
+func (e *binaryEncoder) bytes(bs []byte) {
+	e.uvarint(uint64(len(bs)))
+	e.buf = append(e.buf, bs...)
+}
+
+func (e *binaryEncoder) str(s string) {
+	idx, ok := e.strs[s]
+	if !ok {
+		idx = len(e.strList)
+		e.strs[s] = idx
+		e.strList = append(e.strList, s)
+	}
+	e.uvarint(uint64(idx))
+}
+
+func (e *binaryEncoder) strSlice(ss []string) {
+	e.uvarint(uint64(len(ss)))
+	for _, s := range ss {
+		e.str(s)
+	}
+}
+
+// loc encodes a reference to loc in the location table, 0 being nil.
+func (e *binaryEncoder) loc(loc *Location) {
+	if loc == nil {
+		e.uvarint(0)
+		return
+	}
+	idx, ok := e.locs[loc]
+	if !ok {
+		idx = len(e.locList)
+		e.locs[loc] = idx
+		e.locList = append(e.locList, loc)
+		// Register the file name now, so that the string table is complete
+		// when the location table is written.
+		if _, ok := e.strs[loc.File]; !ok {
+			e.strs[loc.File] = len(e.strList)
+			e.strList = append(e.strList, loc.File)
+		}
+	}
+	e.uvarint(uint64(idx + 1))
+}
+
+func (e *binaryEncoder) location(loc *Location) {
+	e.str(loc.File)
+	e.int(loc.Row)
+	e.int(loc.Col)
+	e.int(loc.Offset)
+
+	e.uvarint(uint64(len(loc.Tabs)))
+	for _, t := range loc.Tabs {
+		e.int(t)
+	}
+
+	switch end := loc.Offset + len(loc.Text); {
+	case len(loc.Text) == 0:
+		e.byte(binTextNone)
+	case loc.Offset >= 0 && end <= len(e.src) && bytes.Equal(e.src[loc.Offset:end], loc.Text):
+		e.byte(binTextSource)
+		e.uvarint(uint64(len(loc.Text)))
+	default:
+		e.byte(binTextInline)
+		e.bytes(loc.Text)
+	}
+}
+
+func (e *binaryEncoder) module(mod *Module) {
+	e.uvarint(uint64(mod.regoVersion))
+
+	e.bool(mod.Package != nil)
+	if mod.Package != nil {
+		e.terms(mod.Package.Path)
+		e.loc(mod.Package.Location)
+	}
+
+	e.uvarint(uint64(len(mod.Imports)))
+	for _, imp := range mod.Imports {
+		e.term(imp.Path)
+		e.str(string(imp.Alias))
+		e.loc(imp.Location)
+	}
+
+	e.annotations(mod.Annotations)
+
+	e.uvarint(uint64(len(mod.Rules)))
+	for _, rule := range mod.Rules {
+		e.rule(rule)
+	}
+
+	e.uvarint(uint64(len(mod.Comments)))
+	for _, c := range mod.Comments {
+		e.bytes(c.Text)
+		e.loc(c.Location)
+	}
+}
+
+func (e *binaryEncoder) annotations(as []*Annotations) {
+	e.uvarint(uint64(len(as)))
+	for _, a := range as {
+		idx, ok := e.annots[a]
+		if !ok {
+			idx = len(e.annotList)
+			e.annots[a] = idx
+			e.annotList = append(e.annotList, a)
+		}
+		e.uvarint(uint64(idx))
+	}
+}
+
+func (e *binaryEncoder) annotation(a *Annotations, mod *Module) {
+	e.str(a.Scope)
+	e.str(a.Title)
+	e.bool(a.Entrypoint)
+	e.str(a.Description)
+	e.strSlice(a.Organizations)
+
+	e.uvarint(uint64(len(a.RelatedResources)))
+	for _, rr := range a.RelatedResources {
+		e.str(rr.Ref.String())
+		e.str(rr.Description)
+	}
+
+	e.uvarint(uint64(len(a.Authors)))
+	for _, author := range a.Authors {
+		e.str(author.Name)
+		e.str(author.Email)
+	}
+
+	e.uvarint(uint64(len(a.Schemas)))
+	for _, s := range a.Schemas {
+		e.terms(s.Path)
+		e.terms(s.Schema)
+		if s.Definition == nil {
+			e.bytes(nil)
+		} else {
+			e.json(*s.Definition)
+		}
+	}
+
+	e.bool(a.Compile != nil)
+	if a.Compile != nil {
+		e.uvarint(uint64(len(a.Compile.Unknowns)))
+		for _, u := range a.Compile.Unknowns {
+			e.terms(u)
+		}
+		e.terms(a.Compile.MaskRule)
+	}
+
+	e.jsonMap(a.Custom)
+	e.jsonMap(a.Labels)
+	e.loc(a.Location)
+	e.loc(a.endLoc)
+
+	kind, idx := binNodeNone, 0
+	switch n := a.node.(type) {
+	case *Package:
+		if n == mod.Package {
+			kind = binNodePackage
+		}
+	case *Import:
+		for i, imp := range mod.Imports {
+			if imp == n {
+				kind, idx = binNodeImport, i
+			}
+		}
+	case *Rule:
+		for i, rule := range mod.Rules {
+			if rule == n {
+				kind, idx = binNodeRule, i
+			}
+		}
+	}
+	e.byte(kind)
+	e.uvarint(uint64(idx))
+}
+
+func (e *binaryEncoder) json(x any) {
+	bs, err := json.Marshal(x)
+	if err != nil && e.err == nil {
+		e.err = err
+	}
+	e.bytes(bs)
+}
+
+func (e *binaryEncoder) jsonMap(m map[string]any) {
+	if m == nil {
+		e.bytes(nil)
+		return
+	}
+	e.json(m)
+}
+
+func (e *binaryEncoder) rule(rule *Rule) {
+	e.bool(rule.Default)
+	e.bool(rule.generatedBody)
+	e.head(rule.Head)
+	e.body(rule.Body)
+	e.loc(rule.Location)
+	e.annotations(rule.Annotations)
+
+	e.bool(rule.Else != nil)
+	if rule.Else != nil {
+		e.rule(rule.Else)
+	}
+}
+
+func (e *binaryEncoder) head(head *Head) {
+	e.str(string(head.Name))
+	e.terms(head.Reference)
+	e.terms(head.Args)
+	e.term(head.Key)
+	e.term(head.Value)
+	e.bool(head.Assign)
+	e.bool(head.generatedValue)
+	e.loc(head.Location)
+}
+
+// terms encodes a slice of terms, distinguishing nil from empty slices.
+func (e *binaryEncoder) terms(ts []*Term) {
+	if ts == nil {
+		e.uvarint(0)
+		return
+	}
+	e.uvarint(uint64(len(ts) + 1))
+	for _, t := range ts {
+		e.term(t)
+	}
+}
+
+// body encodes a body, distinguishing nil from empty bodies.
+func (e *binaryEncoder) body(body Body) {
+	if body == nil {
+		e.uvarint(0)
+		return
+	}
+	e.uvarint(uint64(len(body) + 1))
+	for _, expr := range body {
+		e.expr(expr)
+	}
+}
+
+func (e *binaryEncoder) expr(expr *Expr) {
+	e.bool(expr.Negated)
+	e.bool(expr.Generated)
+	e.bool(expr.fromAssignment)
+	e.uvarint(uint64(expr.Index))
+	e.loc(expr.Location)
+
+	switch ts := expr.Terms.(type) {
+	case *Term:
+		e.byte(binExprTerm)
+		e.term(ts)
+	case []*Term:
+		e.byte(binExprCall)
+		e.terms(ts)
+	case *SomeDecl:
+		e.byte(binExprSomeDecl)
+		e.terms(ts.Symbols)
+		e.loc(ts.Location)
+	case *Every:
+		e.byte(binExprEvery)
+		e.term(ts.Key)
+		e.term(ts.Value)
+		e.term(ts.Domain)
+		e.body(ts.Body)
+		e.loc(ts.Location)
+	case *Not:
+		e.byte(binExprNot)
+		e.not(ts)
+	case *LogicalAnd:
+		e.byte(binExprAnd)
+		e.body(ts.Lhs)
+		e.body(ts.Rhs)
+		e.bool(ts.ExplicitLhs)
+		e.bool(ts.ExplicitRhs)
+		e.loc(ts.Location)
+	case *LogicalOr:
+		e.byte(binExprOr)
+		e.body(ts.Lhs)
+		e.body(ts.Rhs)
+		e.bool(ts.ExplicitLhs)
+		e.bool(ts.ExplicitRhs)
+		e.loc(ts.Location)
+	default:
+		e.fail(fmt.Errorf("binary module: unsupported expression terms type %T", expr.Terms))
+	}
+
+	e.uvarint(uint64(len(expr.With)))
+	for _, w := range expr.With {
+		e.term(w.Target)
+		e.term(w.Value)
+		e.loc(w.Location)
+	}
+}
+
+func (e *binaryEncoder) not(n *Not) {
+	e.body(n.Body)
+	e.bool(n.ExplicitBody)
+	e.loc(n.Location)
+}
+
+func (e *binaryEncoder) term(t *Term) {
+	if t == nil {
+		e.byte(binNil)
+		return
+	}
+	e.value(t.Value)
+	e.loc(t.Location)
+}
+
+func (e *binaryEncoder) value(v Value) {
+	switch v := v.(type) {
+	case Null:
+		e.byte(binNull)
+	case Boolean:
+		if v {
+			e.byte(binTrue)
+		} else {
+			e.byte(binFalse)
+		}
+	case Number:
+		e.byte(binNumber)
+		e.str(string(v))
+	case String:
+		e.byte(binString)
+		e.str(string(v))
+	case Var:
+		e.byte(binVar)
+		e.str(string(v))
+	case Ref:
+		e.byte(binRef)
+		e.terms(v)
+	case *Array:
+		e.byte(binArray)
+		e.uvarint(uint64(v.Len()))
+		v.Foreach(e.term)
+	case Object:
+		e.byte(binObject)
+		e.uvarint(uint64(v.Len()))
+		v.Foreach(func(k, v *Term) {
+			e.term(k)
+			e.term(v)
+		})
+	case Set:
+		e.byte(binSet)
+		e.uvarint(uint64(v.Len()))
+		v.Foreach(e.term)
+	case *ArrayComprehension:
+		e.byte(binArrayComprehension)
+		e.term(v.Term)
+		e.body(v.Body)
+	case *ObjectComprehension:
+		e.byte(binObjectComprehension)
+		e.term(v.Key)
+		e.term(v.Value)
+		e.body(v.Body)
+	case *SetComprehension:
+		e.byte(binSetComprehension)
+		e.term(v.Term)
+		e.body(v.Body)
+	case Call:
+		e.byte(binCall)
+		e.terms(v)
+	case *TemplateString:
+		e.byte(binTemplateString)
+		e.bool(v.MultiLine)
+		e.uvarint(uint64(len(v.Parts)))
+		for _, p := range v.Parts {
+			switch p := p.(type) {
+			case *Term:
+				e.byte(binExprTerm)
+				e.term(p)
+			case *Expr:
+				e.byte(binExprCall)
+				e.expr(p)
+			default:
+				e.fail(fmt.Errorf("binary module: unsupported template string part %T", p))
+			}
+		}
+	case *Not:
+		e.byte(binNot)
+		e.not(v)
+	default:
+		e.fail(fmt.Errorf("binary module: unsupported value type %T", v))
+	}
+}
+
+func (e *binaryEncoder) fail(err error) {
+	if e.err == nil {
+		e.err = err
+	}
+}
+
+var errBinaryTruncated = errors.New("binary module: truncated or corrupt data")
+
+// binaryDecoder decodes the binary module encoding. Errors are sticky: after
+// the first error, all reads return zero values and the error is reported
+// once decoding completes.
+type binaryDecoder struct {
+	buf        []byte
+	src        []byte
+	file       string
+	strs       []string
+	locs       []*Location
+	annots     []*Annotations
+	annotNodes [][2]int
+	err        error
+}
+
+func (d *binaryDecoder) fail(err error) {
+	if d.err == nil {
+		d.err = err
+	}
+	d.buf = nil
+}
+
+func (d *binaryDecoder) uvarint() uint64 {
+	x, n := binary.Uvarint(d.buf)
+	if n <= 0 {
+		d.fail(errBinaryTruncated)
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return x
+}
+
+func (d *binaryDecoder) int() int {
+	x, n := binary.Varint(d.buf)
+	if n <= 0 {
+		d.fail(errBinaryTruncated)
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return int(x)
+}
+
+// length reads a count of encoded items. Every item occupies at least one
+// byte, so larger counts indicate corrupt data.
+func (d *binaryDecoder) length() int {
+	n := d.uvarint()
+	if n > uint64(len(d.buf)) {
+		d.fail(errBinaryTruncated)
+		return 0
+	}
+	return int(n)
+}
+
+// optLength reads a count encoded by terms or body, returning -1 for nil.
+func (d *binaryDecoder) optLength() int {
+	n := d.uvarint()
+	if n == 0 {
+		return -1
+	}
+	if n-1 > uint64(len(d.buf)) {
+		d.fail(errBinaryTruncated)
+		return -1
+	}
+	return int(n - 1)
+}
+
+func (d *binaryDecoder) byte() byte {
+	if len(d.buf) == 0 {
+		d.fail(errBinaryTruncated)
+		return 0
+	}
+	b := d.buf[0]
+	d.buf = d.buf[1:]
+	return b
+}
+
+func (d *binaryDecoder) bool() bool {
+	return d.byte() != 0
+}
+
+func (d *binaryDecoder) bytes() []byte {
+	n := d.length()
+	bs := d.buf[:n:n]
+	d.buf = d.buf[n:]
+	return bs
+}
+
+func (d *binaryDecoder) str() string {
+	idx := d.uvarint()
+	if idx >= uint64(len(d.strs)) {
+		d.fail(errBinaryTruncated)
+		return ""
+	}
+	return d.strs[idx]
+}
+
+func (d *binaryDecoder) strSlice() []string {
+	n := d.length()
+	if n == 0 {
+		return nil
+	}
+	ss := make([]string, n)
+	for i := range ss {
+		ss[i] = d.str()
+	}
+	return ss
+}
+
+func (d *binaryDecoder) loc() *Location {
+	idx := d.uvarint()
+	if idx == 0 {
+		return nil
+	}
+	if idx > uint64(len(d.locs)) {
+		d.fail(errBinaryTruncated)
+		return nil
+	}
+	return d.locs[idx-1]
+}
+
+func (d *binaryDecoder) location() *Location {
+	loc := &Location{
+		File:   d.str(),
+		Row:    d.int(),
+		Col:    d.int(),
+		Offset: d.int(),
+	}
+	if d.file != "" && loc.File != "" {
+		loc.File = d.file
+	}
+
+	if n := d.length(); n > 0 {
+		loc.Tabs = make([]int, n)
+		for i := range loc.Tabs {
+			loc.Tabs[i] = d.int()
+		}
+	}
+
+	switch d.byte() {
+	case binTextNone:
+	case binTextSource:
+		end := uint64(loc.Offset) + d.uvarint()
+		if loc.Offset < 0 || end > uint64(len(d.src)) {
+			d.fail(errors.New("binary module: location text outside of module source"))
+			return loc
+		}
+		loc.Text = d.src[loc.Offset:end:end]
+	case binTextInline:
+		loc.Text = d.bytes()
+	default:
+		d.fail(errBinaryTruncated)
+	}
+
+	return loc
+}
+
+func (d *binaryDecoder) annotation(i int) *Annotations {
+	a := &Annotations{
+		Scope:         d.str(),
+		Title:         d.str(),
+		Entrypoint:    d.bool(),
+		Description:   d.str(),
+		Organizations: d.strSlice(),
+	}
+
+	if n := d.length(); n > 0 {
+		a.RelatedResources = make([]*RelatedResourceAnnotation, n)
+		for j := range a.RelatedResources {
+			ref, err := url.Parse(d.str())
+			if err != nil {
+				d.fail(err)
+				ref = &url.URL{}
+			}
+			a.RelatedResources[j] = &RelatedResourceAnnotation{Ref: *ref, Description: d.str()}
+		}
+	}
+
+	if n := d.length(); n > 0 {
+		a.Authors = make([]*AuthorAnnotation, n)
+		for j := range a.Authors {
+			a.Authors[j] = &AuthorAnnotation{Name: d.str(), Email: d.str()}
+		}
+	}
+
+	if n := d.length(); n > 0 {
+		a.Schemas = make([]*SchemaAnnotation, n)
+		for j := range a.Schemas {
+			s := &SchemaAnnotation{Path: d.terms(), Schema: d.terms()}
+			if bs := d.bytes(); len(bs) > 0 {
+				var def any
+				d.unmarshal(bs, &def)
+				s.Definition = &def
+			}
+			a.Schemas[j] = s
+		}
+	}
+
+	if d.bool() {
+		a.Compile = &CompileAnnotation{}
+		if n := d.length(); n > 0 {
+			a.Compile.Unknowns = make([]Ref, n)
+			for j := range a.Compile.Unknowns {
+				a.Compile.Unknowns[j] = d.terms()
+			}
+		}
+		a.Compile.MaskRule = d.terms()
+	}
+
+	if bs := d.bytes(); len(bs) > 0 {
+		d.unmarshal(bs, &a.Custom)
+	}
+	if bs := d.bytes(); len(bs) > 0 {
+		d.unmarshal(bs, &a.Labels)
+	}
+
+	a.Location = d.loc()
+	a.endLoc = d.loc()
+	d.annotNodes[i] = [2]int{int(d.byte()), int(d.uvarint())}
+
+	return a
+}
+
+func (d *binaryDecoder) unmarshal(bs []byte, x any) {
+	if err := util.UnmarshalJSON(bs, x); err != nil {
+		d.fail(err)
+	}
+}
+
+func (d *binaryDecoder) annotationRefs() []*Annotations {
+	n := d.length()
+	if n == 0 {
+		return nil
+	}
+	as := make([]*Annotations, n)
+	for i := range as {
+		idx := d.uvarint()
+		if idx >= uint64(len(d.annots)) {
+			d.fail(errBinaryTruncated)
+			return nil
+		}
+		as[i] = d.annots[idx]
+	}
+	return as
+}
+
+func (d *binaryDecoder) module() *Module {
+	mod := &Module{regoVersion: RegoVersion(d.uvarint())}
+
+	if d.bool() {
+		mod.Package = &Package{Path: d.terms(), Location: d.loc()}
+	}
+
+	if n := d.length(); n > 0 {
+		mod.Imports = make([]*Import, n)
+		for i := range mod.Imports {
+			mod.Imports[i] = &Import{Path: d.term(), Alias: Var(d.str()), Location: d.loc()}
+		}
+	}
+
+	mod.Annotations = d.annotationRefs()
+
+	if n := d.length(); n > 0 {
+		mod.Rules = make([]*Rule, n)
+		for i := range mod.Rules {
+			mod.Rules[i] = d.rule()
+		}
+	}
+
+	if n := d.length(); n > 0 {
+		mod.Comments = make([]*Comment, n)
+		for i := range mod.Comments {
+			mod.Comments[i] = &Comment{Text: d.bytes(), Location: d.loc()}
+		}
+	}
+
+	return mod
+}
+
+func (d *binaryDecoder) rule() *Rule {
+	rule := &Rule{
+		Default:       d.bool(),
+		generatedBody: d.bool(),
+		Head:          d.head(),
+		Body:          d.body(),
+		Location:      d.loc(),
+		Annotations:   d.annotationRefs(),
+	}
+
+	if d.bool() && d.err == nil {
+		rule.Else = d.rule()
+	}
+
+	return rule
+}
+
+func (d *binaryDecoder) head() *Head {
+	return &Head{
+		Name:           Var(d.str()),
+		Reference:      d.terms(),
+		Args:           d.terms(),
+		Key:            d.term(),
+		Value:          d.term(),
+		Assign:         d.bool(),
+		generatedValue: d.bool(),
+		Location:       d.loc(),
+	}
+}
+
+func (d *binaryDecoder) terms() []*Term {
+	n := d.optLength()
+	if n < 0 {
+		return nil
+	}
+	ts := make([]*Term, n)
+	for i := range ts {
+		ts[i] = d.term()
+	}
+	return ts
+}
+
+func (d *binaryDecoder) body() Body {
+	n := d.optLength()
+	if n < 0 {
+		return nil
+	}
+	body := make(Body, n)
+	for i := range body {
+		body[i] = d.expr()
+	}
+	return body
+}
+
+func (d *binaryDecoder) expr() *Expr {
+	expr := &Expr{
+		Negated:        d.bool(),
+		Generated:      d.bool(),
+		fromAssignment: d.bool(),
+		Index:          int(d.uvarint()),
+		Location:       d.loc(),
+	}
+
+	switch d.byte() {
+	case binExprTerm:
+		expr.Terms = d.term()
+	case binExprCall:
+		expr.Terms = d.terms()
+	case binExprSomeDecl:
+		expr.Terms = &SomeDecl{Symbols: d.terms(), Location: d.loc()}
+	case binExprEvery:
+		expr.Terms = &Every{Key: d.term(), Value: d.term(), Domain: d.term(), Body: d.body(), Location: d.loc()}
+	case binExprNot:
+		expr.Terms = d.not()
+	case binExprAnd:
+		expr.Terms = &LogicalAnd{Lhs: d.body(), Rhs: d.body(), ExplicitLhs: d.bool(), ExplicitRhs: d.bool(), Location: d.loc()}
+	case binExprOr:
+		expr.Terms = &LogicalOr{Lhs: d.body(), Rhs: d.body(), ExplicitLhs: d.bool(), ExplicitRhs: d.bool(), Location: d.loc()}
+	default:
+		d.fail(errBinaryTruncated)
+	}
+
+	if n := d.length(); n > 0 {
+		expr.With = make([]*With, n)
+		for i := range expr.With {
+			expr.With[i] = &With{Target: d.term(), Value: d.term(), Location: d.loc()}
+		}
+	}
+
+	return expr
+}
+
+func (d *binaryDecoder) not() *Not {
+	return &Not{Body: d.body(), ExplicitBody: d.bool(), Location: d.loc()}
+}
+
+func (d *binaryDecoder) term() *Term {
+	tag := d.byte()
+	if tag == binNil || d.err != nil {
+		return nil
+	}
+	v := d.value(tag)
+	return &Term{Value: v, Location: d.loc()}
+}
+
+func (d *binaryDecoder) value(tag byte) Value {
+	switch tag {
+	case binNull:
+		return NullValue
+	case binFalse:
+		return Boolean(false)
+	case binTrue:
+		return Boolean(true)
+	case binNumber:
+		return Number(d.str())
+	case binString:
+		return String(d.str())
+	case binVar:
+		return Var(d.str())
+	case binRef:
+		return Ref(d.terms())
+	case binArray:
+		elems := make([]*Term, d.length())
+		for i := range elems {
+			elems[i] = d.term()
+		}
+		return NewArray(elems...)
+	case binObject:
+		n := d.length()
+		obj := NewObjectWithCapacity(n)
+		for range n {
+			k, v := d.term(), d.term()
+			if d.err == nil {
+				obj.Insert(k, v)
+			}
+		}
+		return obj
+	case binSet:
+		n := d.length()
+		s := NewSetWithCapacity(n)
+		for range n {
+			if t := d.term(); d.err == nil {
+				s.Add(t)
+			}
+		}
+		return s
+	case binArrayComprehension:
+		return &ArrayComprehension{Term: d.term(), Body: d.body()}
+	case binObjectComprehension:
+		return &ObjectComprehension{Key: d.term(), Value: d.term(), Body: d.body()}
+	case binSetComprehension:
+		return &SetComprehension{Term: d.term(), Body: d.body()}
+	case binCall:
+		return Call(d.terms())
+	case binTemplateString:
+		ts := &TemplateString{MultiLine: d.bool()}
+		ts.Parts = make([]Node, d.length())
+		for i := range ts.Parts {
+			switch d.byte() {
+			case binExprTerm:
+				ts.Parts[i] = d.term()
+			case binExprCall:
+				ts.Parts[i] = d.expr()
+			default:
+				d.fail(errBinaryTruncated)
+			}
+		}
+		return ts
+	case binNot:
+		return d.not()
+	}
+
+	d.fail(errBinaryTruncated)
+	return NullValue
+}
@@ -0,0 +1,231 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package ast
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+const binaryTestModule = `# METADATA
+# title: Test
+# description: Module for testing the binary encoding
+# authors:
+# - Jane Doe <jane@example.com>
+# custom:
+#   severity: high
+package test.binary
+
+import data.lib.helpers as h
+import input.user
+
+# a comment
+default allow := false
+
+# METADATA
+# entrypoint: true
+# schemas:
+# - input: {"type": "object"}
+allow if {
+	some role in user.roles
+	role == "admin"
+	not deny
+	every x in [1, 2, 3] { x > 0 }
+}
+
+deny contains msg if {
+	msg := sprintf("denied %v", [user.name])
+	print(msg)
+}
+
+p.q[r] := {"a": [1, 2.5, null, true], "b": {1, 2}} if r := "x"
+
+f(x) := y if {
+	y := x + 1
+} else := 0
+
+comps := [
+	[x | some x in [1, 2]],
+	{x | some x in [1, 2]},
+	{k: v | some k, v in {"a": 1}},
+]
+
+t := $"hello {user.name}!"
+
+w := v if {
+	v := h.value with input.user as {"name": "bob"} with data.lib.helpers.value as 7
+}
+
+empty if count([]) == 0
+`
+
+func TestModuleBinaryRoundTrip(t *testing.T) {
+	popts := ParserOptions{ProcessAnnotation: true, AllFutureKeywords: true}
+	mod := MustParseModuleWithOpts(binaryTestModule, popts)
+	mod.Comments = append(mod.Comments, &Comment{Text: []byte(" extra"), Location: NewLocation([]byte("# extra"), "x.rego", 1, 1)})
+
+	compiler := NewCompiler().WithEnablePrintStatements(true)
+	lib := MustParseModule("package lib.helpers\n\nvalue := 1")
+	compiler.Compile(map[string]*Module{"binary.rego": mod, "lib.rego": lib})
+	if compiler.Failed() {
+		t.Fatal(compiler.Errors)
+	}
+	compiled := compiler.Modules["binary.rego"]
+
+	for note, tc := range map[string]struct {
+		mod *Module
+		src []byte
+	}{
+		"parsed":             {mod: MustParseModuleWithOpts(binaryTestModule, popts), src: []byte(binaryTestModule)},
+		"compiled":           {mod: compiled, src: []byte(binaryTestModule)},
+		"compiled no source": {mod: compiled},
+	} {
+		t.Run(note, func(t *testing.T) {
+			bs, err := marshalModuleBinary(tc.mod, tc.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			act, err := unmarshalModuleBinary(bs, tc.src, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !act.Equal(tc.mod) {
+				t.Fatalf("expected:\n%v\n\ngot:\n%v", tc.mod, act)
+			}
+
+			assertBinaryLocationsEqual(t, tc.mod, act)
+
+			if len(act.Annotations) != len(tc.mod.Annotations) {
+				t.Fatalf("expected %d annotations but got %d", len(tc.mod.Annotations), len(act.Annotations))
+			}
+			for i := range act.Annotations {
+				if act.Annotations[i].Compare(tc.mod.Annotations[i]) != 0 {
+					t.Errorf("expected annotation %v but got %v", tc.mod.Annotations[i], act.Annotations[i])
+				}
+				if act.Annotations[i].GetTargetPath().Compare(tc.mod.Annotations[i].GetTargetPath()) != 0 {
+					t.Errorf("expected annotation target %v but got %v", tc.mod.Annotations[i].GetTargetPath(), act.Annotations[i].GetTargetPath())
+				}
+			}
+
+			WalkRules(act, func(r *Rule) bool {
+				if r.Module != act {
+					t.Errorf("rule %v does not point at its module", r.Head.Ref())
+				}
+				return false
+			})
+		})
+	}
+}
+
+func TestModuleBinaryFile(t *testing.T) {
+	src := "package test\n\np := 1"
+	mod := MustParseModuleWithOpts(src, ParserOptions{})
+	for _, loc := range []*Location{mod.Package.Location, mod.Rules[0].Location} {
+		loc.File = "a.rego"
+	}
+
+	bs, err := marshalModuleBinary(mod, []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	act, err := unmarshalModuleBinary(bs, []byte(src), "/bundle/a.rego")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if act.Package.Location.File != "/bundle/a.rego" || act.Rules[0].Location.File != "/bundle/a.rego" {
+		t.Fatalf("expected file to be replaced but got %v and %v", act.Package.Location, act.Rules[0].Location)
+	}
+}
+
+func TestModuleBinaryErrors(t *testing.T) {
+	mod := MustParseModule("package test\n\np := 1")
+	bs, err := marshalModuleBinary(mod, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string][]byte{
+		"empty":     nil,
+		"magic":     append([]byte("XXXX"), bs[4:]...),
+		"version":   append(append([]byte{}, binaryModuleMagic...), 99),
+		"truncated": bs[:len(bs)/2],
+	}
+
+	for note, data := range tests {
+		t.Run(note, func(t *testing.T) {
+			if _, err := unmarshalModuleBinary(data, nil, ""); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func assertBinaryLocationsEqual(t *testing.T, exp, act *Module) {
+	t.Helper()
+
+	locations := func(m *Module) []string {
+		var locs []string
+		WalkNodes(m, func(n Node) bool {
+			if loc := n.Loc(); loc != nil {
+				locs = append(locs, fmt.Sprintf("%T %s:%d:%d@%d %q", n, loc.File, loc.Row, loc.Col, loc.Offset, loc.Text))
+			}
+			return false
+		})
+		return locs
+	}
+
+	e, a := locations(exp), locations(act)
+	if len(e) != len(a) {
+		t.Fatalf("expected %d locations but got %d", len(e), len(a))
+	}
+	for i := range e {
+		if e[i] != a[i] {
+			t.Fatalf("expected location %s but got %s", e[i], a[i])
+		}
+	}
+}
+
+func BenchmarkModuleBinary(b *testing.B) {
+	var buf bytes.Buffer
+	buf.WriteString("package bench\n\n")
+	for i := range 1000 {
+		fmt.Fprintf(&buf, "r%d if {\n\tsome x in input.items\n\tx.id == %d\n\tstartswith(x.name, \"n%d\")\n}\n\n", i, i, i)
+	}
+	src := buf.Bytes()
+
+	compiler := NewCompiler()
+	compiler.Compile(map[string]*Module{"bench.rego": MustParseModule(string(src))})
+	if compiler.Failed() {
+		b.Fatal(compiler.Errors)
+	}
+
+	bs, err := marshalModuleBinary(compiler.Modules["bench.rego"], src)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("parse", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := ParseModule("bench.rego", string(src)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("unmarshal", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := unmarshalModuleBinary(bs, src, ""); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
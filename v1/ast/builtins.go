@@ -180,6 +180,16 @@ var DefaultBuiltins = [...]*Builtin{
 	YAMLMarshal,
 	YAMLUnmarshal,
 	YAMLIsValid,
+	TOMLUnmarshal,
+	TOMLIsValid,
+	XMLUnmarshal,
+	XMLIsValid,
+	CSVParse,
+	CSVIsValid,
+	HCLUnmarshal,
+	HCLIsValid,
+	INIUnmarshal,
+	INIIsValid,
 	HexEncode,
 	HexDecode,
 
@@ -196,6 +206,8 @@ var DefaultBuiltins = [...]*Builtin{
 	JSONFilter,
 	JSONRemove,
 	JSONPatch,
+	JSONPathQuery,
+	JMESPathQuery,
 
 	// Tokens
 	JWTDecode,
@@ -215,6 +227,10 @@ var DefaultBuiltins = [...]*Builtin{
 	JWTDecodeVerify,
 	JWTEncodeSignRaw,
 	JWTEncodeSign,
+	JWEDecrypt,
+	JWKParse,
+	JWKThumbprint,
+	JWKSSelect,
 
 	// Time
 	NowNanos,
@@ -227,6 +243,10 @@ var DefaultBuiltins = [...]*Builtin{
 	Weekday,
 	AddDate,
 	Diff,
+	CronMatch,
+	NextCron,
+	InWindow,
+	Truncate,
 
 	// Crypto
 	CryptoX509ParseCertificates,
@@ -235,6 +255,11 @@ var DefaultBuiltins = [...]*Builtin{
 	CryptoMd5,
 	CryptoSha1,
 	CryptoSha256,
+	CryptoSha512,
+	CryptoSha3256,
+	CryptoSha3512,
+	CryptoBlake2b256,
+	CryptoBlake2b512,
 	CryptoX509ParseCertificateRequest,
 	CryptoX509ParseRSAPrivateKey,
 	CryptoX509ParseKeyPair,
@@ -244,11 +269,17 @@ var DefaultBuiltins = [...]*Builtin{
 	CryptoHmacSha256,
 	CryptoHmacSha512,
 	CryptoHmacEqual,
+	CryptoVerify,
 
 	// Graphs
 	WalkBuiltin,
 	ReachableBuiltin,
 	ReachablePathsBuiltin,
+	GraphTopologicalSort,
+	GraphHasCycle,
+	GraphCycles,
+	GraphShortestPath,
+	GraphSCC,
 
 	// Sort
 	Sort,
@@ -274,6 +305,10 @@ var DefaultBuiltins = [...]*Builtin{
 	GraphQLIsValid,
 	GraphQLSchemaIsValid,
 
+	// SQL
+	SQLParse,
+	SQLIsValid,
+
 	// JSON Schema
 	JSONSchemaVerify,
 	JSONMatchSchema,
@@ -288,6 +323,7 @@ var DefaultBuiltins = [...]*Builtin{
 
 	// OPA
 	OPARuntime,
+	OPADecisionLogAnnotate,
 
 	// Tracing
 	Trace,
@@ -297,9 +333,14 @@ var DefaultBuiltins = [...]*Builtin{
 	NetCIDRIntersects,
 	NetCIDRContains,
 	NetCIDRContainsMatches,
+	NetCIDRLookup,
 	NetCIDRExpand,
 	NetCIDRMerge,
 	NetLookupIPAddr,
+	NetLookupTXT,
+	NetLookupSRV,
+	NetLookupMX,
+	NetLookupCNAME,
 	NetCIDRIsValid,
 
 	// Glob
@@ -317,6 +358,8 @@ var DefaultBuiltins = [...]*Builtin{
 	// SemVers
 	SemVerIsValid,
 	SemVerCompare,
+	SemVerSatisfies,
+	SemVerMaxSatisfying,
 
 	// Printing
 	Print,
@@ -343,6 +386,11 @@ var IgnoreDuringPartialEval = []*Builtin{
 	HTTPSend,
 	OPARuntime,
 	NetLookupIPAddr,
+	NetLookupTXT,
+	NetLookupSRV,
+	NetLookupMX,
+	NetLookupCNAME,
+	OPADecisionLogAnnotate,
 }
 
 /**
@@ -1719,6 +1767,36 @@ var JSONPatch = &Builtin{
 	CanSkipBctx: true,
 }
 
+var JSONPathQuery = &Builtin{
+	Name: "json.path_query",
+	Description: "Selects the values of a document matched by a JSONPath query, as defined by RFC 9535. " +
+		"For example: `json.path_query({\"a\": [{\"b\": 1}, {\"b\": 2}]}, \"$.a[?@.b > 1]\")` results in `[{\"b\": 2}]`. " +
+		"Compiled queries are cached.",
+	Decl: types.NewFunction(
+		types.Args(
+			types.Named("document", types.A).Description("the document to query"),
+			types.Named("query", types.S).Description("the JSONPath query"),
+		),
+		types.Named("output", types.NewArray(nil, types.A)).Description("the values matched by `query`, in document order"),
+	),
+	Categories: objectCat,
+}
+
+var JMESPathQuery = &Builtin{
+	Name: "json.jmespath_query",
+	Description: "Evaluates a JMESPath expression against a document. " +
+		"For example: `json.jmespath_query({\"a\": [{\"b\": 1}, {\"b\": 2}]}, \"a[*].b\")` results in `[1, 2]`. " +
+		"Compiled expressions are cached.",
+	Decl: types.NewFunction(
+		types.Args(
+			types.Named("document", types.A).Description("the document to query"),
+			types.Named("expression", types.S).Description("the JMESPath expression"),
+		),
+		types.Named("output", types.A).Description("the result of evaluating `expression`, or `null` if it matches nothing"),
+	),
+	Categories: objectCat,
+}
+
 var ObjectSubset = &Builtin{
 	Name: "object.subset",
 	Description: "Determines if an object `sub` is a subset of another object `super`." +
@@ -2148,6 +2226,156 @@ var YAMLIsValid = &Builtin{
 	CanSkipBctx: true,
 }
 
+var TOMLUnmarshal = &Builtin{
+	Name:        "toml.unmarshal",
+	Description: "Deserializes the input TOML string. Offset date-times are returned as RFC 3339 strings, local dates and times as written.",
+	Decl: types.NewFunction(
+		types.Args(
+			types.Named("x", types.S).Description("a TOML string"),
+		),
+		types.Named("y", types.A).Description("the term deserialized from `x`"),
+	),
+	Categories:  catEncoding,
+	CanSkipBctx: true,
+}
+
+// TOMLIsValid verifies the input string is a valid TOML document.
+var TOMLIsValid = &Builtin{
+	Name:        "toml.is_valid",
+	Description: "Verifies the input string is a valid TOML document.",
+	Decl: types.NewFunction(
+		types.Args(
+			types.Named("x", types.S).Description("a TOML string"),
+		),
+		types.Named("result", types.B).Description("`true` if `x` is valid TOML, `false` otherwise"),
+	),
+	Categories:  catEncoding,
+	CanSkipBctx: true,
+}
+
+var XMLUnmarshal = &Builtin{
+	Name:        "xml.unmarshal",
+	Description: "Deserializes the input XML string. The result is an object with the name of the root element as its only key. Elements without attributes and child elements are strings of their text, other elements are objects of their attributes (keys prefixed with `@`), child elements (arrays if repeated) and text (`#text`).",
+	Decl: types.NewFunction(
+		types.Args(
+			types.Named("x", types.S).Description("a XML string"),
+		),
+		types.Named("y", types.A).Description("the term deserialized from `x`"),
+	),
+	Categories:  catEncoding,
+	CanSkipBctx: true,
+}
+
+// XMLIsValid verifies the input string is a valid XML document.
+var XMLIsValid = &Builtin{
+	Name:        "xml.is_valid",
+	Description: "Verifies the input string is a valid XML document.",
+	Decl: types.NewFunction(
+		types.Args(
+			types.Named("x", types.S).Description("a XML string"),
+		),
+		types.Named("result", types.B).Description("`true` if `x` is valid XML, `false` otherwise"),
+	),
+	Categories:  catEncoding,
+	CanSkipBctx: true,
+}
+
+var CSVParse = &Builtin{
+	Name: "csv.parse",
+	Description: "Parses the input CSV string into an array of records. " +
+		"`opts` accepts keys `header` (the first record names the fields of the others, which are returned as objects, default `false`) and `delimiter` (the field separator, default `,`).",
+	Decl: types.NewFunction(
+		types.Args(
+			types.Named("x", types.S).Description("a CSV string"),
+			types.Named("opts", types.NewObject(
+				[]*types.StaticProperty{
+					types.NewStaticProperty("header", types.B),
+					types.NewStaticProperty("delimiter", types.S),
+				},
+				types.NewDynamicProperty(types.S, types.A),
+			)).Description("parsing options"),
+		),
+		types.Named("y", types.NewArray(nil, types.A)).Description("the records of `x`, as arrays of strings, or objects if `header` is `true`"),
+	),
+	Categories:  catEncoding,
+	CanSkipBctx: true,
+}
+
+// CSVIsValid verifies the input string is a valid CSV document.
+var CSVIsValid = &Builtin{
+	Name:        "csv.is_valid",
+	Description: "Verifies the input string is a valid CSV document, given the options of `csv.parse`.",
+	Decl: types.NewFunction(
+		types.Args(
+			types.Named("x", types.S).Description("a CSV string"),
+			types.Named("opts", types.NewObject(
+				[]*types.StaticProperty{
+					types.NewStaticProperty("header", types.B),
+					types.NewStaticProperty("delimiter", types.S),
+				},
+				types.NewDynamicProperty(types.S, types.A),
+			)).Description("parsing options"),
+		),
+		types.Named("result", types.B).Description("`true` if `x` is valid CSV, `false` otherwise"),
+	),
+	Categories:  catEncoding,
+	CanSkipBctx: true,
+}
+
+var HCLUnmarshal = &Builtin{
+	Name:        "hcl.unmarshal",
+	Description: "Deserializes the input HCL string. Blocks are nested by type and labels, with arrays of block bodies innermost. Expressions that need variables or functions are returned as strings of their source.",
+	Decl: types.NewFunction(
+		types.Args(
+			types.Named("x", types.S).Description("a HCL string"),
+		),
+		types.Named("y", types.A).Description("the term deserialized from `x`"),
+	),
+	Categories:  catEncoding,
+	CanSkipBctx: true,
+}
+
+// HCLIsValid verifies the input string is a valid HCL document.
+var HCLIsValid = &Builtin{
+	Name:        "hcl.is_valid",
+	Description: "Verifies the input string is a valid HCL document.",
+	Decl: types.NewFunction(
+		types.Args(
+			types.Named("x", types.S).Description("a HCL string"),
+		),
+		types.Named("result", types.B).Description("`true` if `x` is valid HCL, `false` otherwise"),
+	),
+	Categories:  catEncoding,
+	CanSkipBctx: true,
+}
+
+var INIUnmarshal = &Builtin{
+	Name:        "ini.unmarshal",
+	Description: "Deserializes the input INI string. Sections are objects keyed by their name, keys outside of sections are top-level keys. All values are strings.",
+	Decl: types.NewFunction(
+		types.Args(
+			types.Named("x", types.S).Description("a INI string"),
+		),
+		types.Named("y", types.A).Description("the term deserialized from `x`"),
+	),
+	Categories:  catEncoding,
+	CanSkipBctx: true,
+}
+
+// INIIsValid verifies the input string is a valid INI document.
+var INIIsValid = &Builtin{
+	Name:        "ini.is_valid",
+	Description: "Verifies the input string is a valid INI document.",
+	Decl: types.NewFunction(
+		types.Args(
+			types.Named("x", types.S).Description("a INI string"),
+		),
+		types.Named("result", types.B).Description("`true` if `x` is valid INI, `false` otherwise"),
+	),
+	Categories:  catEncoding,
+	CanSkipBctx: true,
+}
+
 var HexEncode = &Builtin{
 	Name:        "hex.encode",
 	Description: "Serializes the input string using hex-encoding.",
@@ -2435,6 +2663,69 @@ var JWTEncodeSign = &Builtin{
 	CanSkipBctx:      false,
 }
 
+var JWEDecrypt = &Builtin{
+	Name:        "io.jwe.decrypt",
+	Description: "Decrypts a JSON Web Encryption (JWE) token in compact serialization.",
+	Decl: types.NewFunction(
+		types.Args(
+			types.Named("jwe", types.S).Description("JWE token to decrypt"),
+			types.Named("keys", types.S).Description("JSON Web Key (RFC7517) or JSON Web Key Set holding the private key(s) to decrypt the token with"),
+		),
+		types.Named("output", types.NewArray([]types.Type{
+			types.NewObject(nil, types.NewDynamicProperty(types.A, types.A)),
+			types.S,
+		}, nil)).Description("`[header, payload]`, where `header` is the JOSE header object and `payload` is the decrypted plaintext"),
+	),
+	Categories:  tokensCat,
+	CanSkipBctx: true,
+}
+
+var JWKParse = &Builtin{
+	Name:        "io.jwk.parse",
+	Description: "Parses a JSON Web Key, or a PEM encoded key or certificate, into a JSON Web Key object.",
+	Decl: types.NewFunction(
+		types.Args(
+			types.Named("key", types.S).Description("JSON Web Key (RFC7517), or PEM encoded key or certificate"),
+		),
+		types.Named("output", types.NewObject(nil, types.NewDynamicProperty(types.S, types.A))).Description("JSON Web Key"),
+	),
+	Categories:  tokensCat,
+	CanSkipBctx: true,
+}
+
+var JWKThumbprint = &Builtin{
+	Name:        "io.jwk.thumbprint",
+	Description: "Computes the SHA-256 thumbprint (RFC7638) of a JSON Web Key, as used in the `jkt` confirmation of DPoP bound tokens.",
+	Decl: types.NewFunction(
+		types.Args(
+			types.Named("key", types.NewAny(
+				types.S,
+				types.NewObject(nil, types.NewDynamicProperty(types.S, types.A)),
+			)).Description("JSON Web Key (RFC7517), as string or object"),
+		),
+		types.Named("output", types.S).Description("base64url encoded thumbprint of the key"),
+	),
+	Categories:  tokensCat,
+	CanSkipBctx: true,
+}
+
+var JWKSSelect = &Builtin{
+	Name:        "io.jwks.select",
+	Description: "Selects the first key of a JSON Web Key Set matching the given criteria. A key without `alg` matches an algorithm of its key type.",
+	Decl: types.NewFunction(
+		types.Args(
+			types.Named("jwks", types.NewAny(
+				types.S,
+				types.NewObject(nil, types.NewDynamicProperty(types.S, types.A)),
+			)).Description("JSON Web Key Set (RFC7517), as string or object"),
+			types.Named("criteria", types.NewObject(nil, types.NewDynamicProperty(types.S, types.S))).Description("the `kid`, `alg` and `use` the key must match"),
+		),
+		types.Named("output", types.NewObject(nil, types.NewDynamicProperty(types.S, types.A))).Description("matching JSON Web Key, undefined if no key matches"),
+	),
+	Categories:  tokensCat,
+	CanSkipBctx: true,
+}
+
 /**
  * Time
  */
@@ -2584,6 +2875,67 @@ var Diff = &Builtin{
 	CanSkipBctx: true,
 }
 
+var CronMatch = &Builtin{
+	Name:        "time.cron_match",
+	Description: "Returns `true` if the cron expression fires in the minute of the nanoseconds since epoch, on the wall clock of the timezone.",
+	Decl: types.NewFunction(
+		types.Args(
+			types.Named("expr", types.S).Description("cron expression of the form `minute hour day-of-month month day-of-week`, or one of the macros `@yearly`, `@monthly`, `@weekly`, `@daily` and `@hourly`"),
+			types.Named("ns", types.N).Description("nanoseconds since the epoch"),
+			types.Named("tz", types.S).Description("timezone the schedule is evaluated in, e.g. `\"Europe/Berlin\"`; UTC if empty"),
+		),
+		types.Named("result", types.B).Description("`true` if `expr` matches `ns` in `tz`"),
+	),
+	CanSkipBctx: true,
+}
+
+var NextCron = &Builtin{
+	Name: "time.next_cron",
+	Description: "Returns the nanoseconds since epoch of the next minute after `ns` in which the cron expression fires, on the wall clock of the timezone. " +
+		"Wall clock times skipped by a daylight saving time transition never fire, and wall clock times that occur twice fire twice. " +
+		"`undefined` if the schedule doesn't fire within the following eight years.",
+	Decl: types.NewFunction(
+		types.Args(
+			types.Named("expr", types.S).Description("cron expression of the form `minute hour day-of-month month day-of-week`, or one of the macros `@yearly`, `@monthly`, `@weekly`, `@daily` and `@hourly`"),
+			types.Named("ns", types.N).Description("nanoseconds since the epoch"),
+			types.Named("tz", types.S).Description("timezone the schedule is evaluated in, e.g. `\"Europe/Berlin\"`; UTC if empty"),
+		),
+		types.Named("output", types.N).Description("nanoseconds since the epoch of the next activation of `expr`"),
+	),
+	CanSkipBctx: true,
+}
+
+var InWindow = &Builtin{
+	Name: "time.in_window",
+	Description: "Returns `true` if the nanoseconds since epoch fall into a daily or weekly window of wall clock time in the timezone. " +
+		"Windows are given as `\"HH:MM\"` (or `\"HH:MM:SS\"`) for daily windows, or prefixed by a weekday, like `\"Fri 18:00\"`, for weekly windows. " +
+		"The start is inclusive and the end exclusive, so a window whose end equals its start is empty; a window whose end is before its start wraps around midnight, or the end of the week.",
+	Decl: types.NewFunction(
+		types.Args(
+			types.Named("start", types.S).Description("start of the window"),
+			types.Named("end", types.S).Description("end of the window"),
+			types.Named("ns", types.N).Description("nanoseconds since the epoch"),
+			types.Named("tz", types.S).Description("timezone of the window, e.g. `\"Europe/Berlin\"`; UTC if empty"),
+		),
+		types.Named("result", types.B).Description("`true` if `ns` is within the window in `tz`"),
+	),
+	CanSkipBctx: true,
+}
+
+var Truncate = &Builtin{
+	Name:        "time.truncate",
+	Description: "Returns the nanoseconds since epoch of the start of the minute, hour, day, week (starting on Monday), month or year of `ns`, on the wall clock of the timezone.",
+	Decl: types.NewFunction(
+		types.Args(
+			types.Named("ns", types.N).Description("nanoseconds since the epoch"),
+			types.Named("unit", types.S).Description("one of `\"minute\"`, `\"hour\"`, `\"day\"`, `\"week\"`, `\"month\"` and `\"year\"`"),
+			types.Named("tz", types.S).Description("timezone, e.g. `\"Europe/Berlin\"`; UTC if empty"),
+		),
+		types.Named("output", types.N).Description("nanoseconds since the epoch of the start of the `unit` containing `ns`"),
+	),
+	CanSkipBctx: true,
+}
+
 /**
  * Crypto.
  */
@@ -2736,6 +3088,66 @@ var CryptoSha256 = &Builtin{
 	CanSkipBctx: true,
 }
 
+var CryptoSha512 = &Builtin{
+	Name:        "crypto.sha512",
+	Description: "Returns a string representing the input string hashed with the SHA512 function",
+	Decl: types.NewFunction(
+		types.Args(
+			types.Named("x", types.S).Description("input string"),
+		),
+		types.Named("y", types.S).Description("SHA512-hash of `x`"),
+	),
+	CanSkipBctx: true,
+}
+
+var CryptoSha3256 = &Builtin{
+	Name:        "crypto.sha3_256",
+	Description: "Returns a string representing the input string hashed with the SHA3-256 function",
+	Decl: types.NewFunction(
+		types.Args(
+			types.Named("x", types.S).Description("input string"),
+		),
+		types.Named("y", types.S).Description("SHA3-256-hash of `x`"),
+	),
+	CanSkipBctx: true,
+}
+
+var CryptoSha3512 = &Builtin{
+	Name:        "crypto.sha3_512",
+	Description: "Returns a string representing the input string hashed with the SHA3-512 function",
+	Decl: types.NewFunction(
+		types.Args(
+			types.Named("x", types.S).Description("input string"),
+		),
+		types.Named("y", types.S).Description("SHA3-512-hash of `x`"),
+	),
+	CanSkipBctx: true,
+}
+
+var CryptoBlake2b256 = &Builtin{
+	Name:        "crypto.blake2b_256",
+	Description: "Returns a string representing the input string hashed with the BLAKE2b-256 function",
+	Decl: types.NewFunction(
+		types.Args(
+			types.Named("x", types.S).Description("input string"),
+		),
+		types.Named("y", types.S).Description("BLAKE2b-256-hash of `x`"),
+	),
+	CanSkipBctx: true,
+}
+
+var CryptoBlake2b512 = &Builtin{
+	Name:        "crypto.blake2b_512",
+	Description: "Returns a string representing the input string hashed with the BLAKE2b-512 function",
+	Decl: types.NewFunction(
+		types.Args(
+			types.Named("x", types.S).Description("input string"),
+		),
+		types.Named("y", types.S).Description("BLAKE2b-512-hash of `x`"),
+	),
+	CanSkipBctx: true,
+}
+
 var CryptoHmacMd5 = &Builtin{
 	Name:        "crypto.hmac.md5",
 	Description: "Returns a string representing the MD5 HMAC of the input message using the input key.",
@@ -2801,6 +3213,23 @@ var CryptoHmacEqual = &Builtin{
 	CanSkipBctx: true,
 }
 
+var CryptoVerify = &Builtin{
+	Name: "crypto.verify",
+	Description: "Verifies a detached signature of the payload. " +
+		"Supported algorithms are `RS256`, `RS384`, `RS512`, `PS256`, `PS384`, `PS512`, `ES256`, `ES384`, `ES512` and `EdDSA`. " +
+		"ECDSA signatures are accepted either as the concatenation of `r` and `s` or ASN.1 DER encoded.",
+	Decl: types.NewFunction(
+		types.Args(
+			types.Named("alg", types.S).Description("signature algorithm"),
+			types.Named("key", types.S).Description("PEM encoded public key or certificate, JWK or JWKS"),
+			types.Named("payload", types.S).Description("signed payload"),
+			types.Named("signature", types.S).Description("base64 or base64url encoded signature, padding is optional"),
+		),
+		types.Named("result", types.B).Description("`true` if the signature is valid for any of the keys, `false` otherwise"),
+	),
+	CanSkipBctx: true,
+}
+
 /**
  * Graphs.
  */
@@ -2868,6 +3297,80 @@ var ReachablePathsBuiltin = &Builtin{
 	CanSkipBctx: true,
 }
 
+// graphType is the adjacency object representation of directed graphs: each
+// key is a vertex, and its value a set or array of neighboring vertices.
+var graphType = types.NewObject(
+	nil,
+	types.NewDynamicProperty(
+		types.A,
+		types.NewAny(
+			types.SetOfAny,
+			types.NewArray(nil, types.A)),
+	),
+)
+
+var GraphTopologicalSort = &Builtin{
+	Name:        "graph.topological_sort",
+	Description: "Orders the vertices of a directed acyclic graph so that each vertex comes before its neighbors. Where there is a choice, the least vertex comes first. An error is raised if the graph has a cycle.",
+	Decl: types.NewFunction(
+		types.Args(
+			types.Named("graph", graphType).Description("object containing a set or array of neighboring vertices"),
+		),
+		types.Named("output", types.NewArray(nil, types.A)).Description("the vertices of `graph` in topological order"),
+	),
+	CanSkipBctx: true,
+}
+
+var GraphHasCycle = &Builtin{
+	Name:        "graph.has_cycle",
+	Description: "Checks if a directed graph has a cycle.",
+	Decl: types.NewFunction(
+		types.Args(
+			types.Named("graph", graphType).Description("object containing a set or array of neighboring vertices"),
+		),
+		types.Named("output", types.B).Description("`true` if `graph` has a cycle, `false` otherwise"),
+	),
+	CanSkipBctx: true,
+}
+
+var GraphCycles = &Builtin{
+	Name:        "graph.cycles",
+	Description: "Computes the set of elementary cycles of a directed graph. Each cycle is an array of vertices that starts at its least vertex, with an edge from the last vertex back to the first.",
+	Decl: types.NewFunction(
+		types.Args(
+			types.Named("graph", graphType).Description("object containing a set or array of neighboring vertices"),
+		),
+		types.Named("output", types.NewSet(types.NewArray(nil, types.A))).Description("the cycles of `graph`"),
+	),
+	CanSkipBctx: false,
+}
+
+var GraphShortestPath = &Builtin{
+	Name:        "graph.shortest_path",
+	Description: "Computes a path with the fewest edges between two vertices of a directed graph. If there are several, the least one in vertex order is returned. The result is undefined if there is no path.",
+	Decl: types.NewFunction(
+		types.Args(
+			types.Named("graph", graphType).Description("object containing a set or array of neighboring vertices"),
+			types.Named("from", types.A).Description("the first vertex of the path"),
+			types.Named("to", types.A).Description("the last vertex of the path"),
+		),
+		types.Named("output", types.NewArray(nil, types.A)).Description("the vertices of the path, from `from` to `to`"),
+	),
+	CanSkipBctx: true,
+}
+
+var GraphSCC = &Builtin{
+	Name:        "graph.scc",
+	Description: "Computes the strongly connected components of a directed graph: the largest sets of vertices in which every vertex is reachable from every other one.",
+	Decl: types.NewFunction(
+		types.Args(
+			types.Named("graph", graphType).Description("object containing a set or array of neighboring vertices"),
+		),
+		types.Named("output", types.NewSet(types.SetOfAny)).Description("the strongly connected components of `graph`"),
+	),
+	CanSkipBctx: true,
+}
+
 /**
  * Type
  */
@@ -3105,6 +3608,39 @@ var GraphQLSchemaIsValid = &Builtin{
 	CanSkipBctx: false,
 }
 
+/**
+ * SQL
+ */
+
+// SQLParse returns the syntax tree of a SQL statement.
+var SQLParse = &Builtin{
+	Name:        "sql.parse",
+	Description: "Parses a single SQL statement of the given dialect into an object describing its syntax tree: the statement type, the tables with their schemas and aliases, the selected columns, the where clause, the joins, and the data-modifying statements it contains, including those in common table expressions. An error is raised if the statement can't be parsed.",
+	Decl: types.NewFunction(
+		types.Args(
+			types.Named("dialect", types.S).Description("the SQL dialect, `\"postgresql\"` or `\"mysql\"`"),
+			types.Named("statement", types.S).Description("the SQL statement"),
+		),
+		types.Named("output", types.NewObject(nil, types.NewDynamicProperty(types.S, types.A))).Description("the syntax tree of `statement`"),
+	),
+	CanSkipBctx: true,
+}
+
+// SQLIsValid returns true if the input is a SQL statement that sql.parse
+// accepts, and returns false for all other inputs.
+var SQLIsValid = &Builtin{
+	Name:        "sql.is_valid",
+	Description: "Checks that the input is a single SQL statement of the given dialect that `sql.parse` can parse.",
+	Decl: types.NewFunction(
+		types.Args(
+			types.Named("dialect", types.S).Description("the SQL dialect, `\"postgresql\"` or `\"mysql\"`"),
+			types.Named("statement", types.S).Description("the SQL statement"),
+		),
+		types.Named("output", types.B).Description("`true` if `statement` can be parsed. `false` otherwise."),
+	),
+	CanSkipBctx: true,
+}
+
 /**
  * JSON Schema
  */
@@ -3245,6 +3781,22 @@ var OPARuntime = &Builtin{
 	CanSkipBctx:      false,
 }
 
+// Not marked non-deterministic, as the annotated values must not be recorded
+// in the NDBCache, where they would escape the decision log mask. Calls are
+// kept during partial evaluation by IgnoreDuringPartialEval instead.
+var OPADecisionLogAnnotate = &Builtin{
+	Name:        "opa.decision_log.annotate",
+	Description: "Annotates the decision log event of the current decision with the value at the given key, without returning it in the decision. The annotation is only logged if the rule, function or query body that made it succeeds; if the key is annotated by several successful bodies, the last value is logged. The annotations are subject to the decision log mask and drop rules, and ignored when no decision logger is configured.",
+	Decl: types.NewFunction(
+		types.Args(
+			types.Named("key", types.S).Description("key of the annotation"),
+			types.Named("value", types.A).Description("value of the annotation"),
+		),
+		types.Named("result", types.B).Description("always `true`"),
+	),
+	CanSkipBctx: false,
+}
+
 /**
  * Trace
  */
@@ -3353,6 +3905,30 @@ var NetCIDRContainsMatches = &Builtin{
 	CanSkipBctx: true,
 }
 
+var NetCIDRLookup = &Builtin{
+	Name: "net.cidr_lookup",
+	Description: "Looks up the longest CIDR in a table that contains an IP address, and returns it along with its associated value. " +
+		"This function is intended for large tables, which are compiled once and cached by value.",
+	Decl: types.NewFunction(
+		types.Args(
+			types.Named("table", types.NewAny(
+				types.NewObject(nil, types.NewDynamicProperty(types.S, types.A)),
+				types.NewArray(nil, types.S),
+				types.SetOfStr,
+			)).Description("object mapping CIDRs or IPs to values, or array or set of CIDRs or IPs, whose values are their indices or themselves, respectively"),
+			types.Named("ip", types.S).Description("the IP address to look up"),
+		),
+		types.Named("output", types.NewObject(
+			[]*types.StaticProperty{
+				types.NewStaticProperty("prefix", types.S),
+				types.NewStaticProperty("value", types.A),
+			},
+			nil,
+		)).Description("object with the longest matching CIDR in `table` as written, and its value; undefined if there is no match"),
+	),
+	CanSkipBctx: false,
+}
+
 var NetCIDRMerge = &Builtin{
 	Name: "net.cidr_merge",
 	Description: "Merges IP addresses and subnets into the smallest possible list of CIDRs (e.g., `net.cidr_merge([\"192.0.128.0/24\", \"192.0.129.0/24\"])` generates `{\"192.0.128.0/23\"}`." +
@@ -3415,6 +3991,76 @@ var NetLookupIPAddr = &Builtin{
 	CanSkipBctx:      false,
 }
 
+// Marked non-deterministic because DNS resolution results can be non-deterministic.
+var NetLookupTXT = &Builtin{
+	Name:        "net.lookup_txt",
+	Description: "Returns the set of DNS TXT records of the passed-in `name`. The character strings of each record are concatenated.",
+	Decl: types.NewFunction(
+		types.Args(
+			types.Named("name", types.S).Description("domain name to look up"),
+		),
+		types.Named("records", types.SetOfStr).Description("TXT records of `name`"),
+	),
+	Nondeterministic: true,
+	CanSkipBctx:      false,
+}
+
+// Marked non-deterministic because DNS resolution results can be non-deterministic.
+var NetLookupSRV = &Builtin{
+	Name:        "net.lookup_srv",
+	Description: "Returns the set of DNS SRV records of the passed-in `name`, like `_ldap._tcp.example.com`.",
+	Decl: types.NewFunction(
+		types.Args(
+			types.Named("name", types.S).Description("service name to look up"),
+		),
+		types.Named("records", types.NewSet(types.NewObject(
+			[]*types.StaticProperty{
+				types.NewStaticProperty("target", types.S),
+				types.NewStaticProperty("port", types.N),
+				types.NewStaticProperty("priority", types.N),
+				types.NewStaticProperty("weight", types.N),
+			},
+			nil,
+		))).Description("SRV records of `name`, as objects with the `target` host name, `port`, `priority` and `weight`"),
+	),
+	Nondeterministic: true,
+	CanSkipBctx:      false,
+}
+
+// Marked non-deterministic because DNS resolution results can be non-deterministic.
+var NetLookupMX = &Builtin{
+	Name:        "net.lookup_mx",
+	Description: "Returns the set of DNS MX records of the passed-in `name`.",
+	Decl: types.NewFunction(
+		types.Args(
+			types.Named("name", types.S).Description("domain name to look up"),
+		),
+		types.Named("records", types.NewSet(types.NewObject(
+			[]*types.StaticProperty{
+				types.NewStaticProperty("host", types.S),
+				types.NewStaticProperty("pref", types.N),
+			},
+			nil,
+		))).Description("MX records of `name`, as objects with the mail exchange `host` name and its preference `pref`"),
+	),
+	Nondeterministic: true,
+	CanSkipBctx:      false,
+}
+
+// Marked non-deterministic because DNS resolution results can be non-deterministic.
+var NetLookupCNAME = &Builtin{
+	Name:        "net.lookup_cname",
+	Description: "Returns the canonical name of the passed-in `name`, following any chain of CNAME records.",
+	Decl: types.NewFunction(
+		types.Args(
+			types.Named("name", types.S).Description("domain name to look up"),
+		),
+		types.Named("cname", types.S).Description("canonical name of `name`"),
+	),
+	Nondeterministic: true,
+	CanSkipBctx:      false,
+}
+
 /**
  * Semantic Versions
  */
@@ -3444,6 +4090,35 @@ var SemVerCompare = &Builtin{
 	CanSkipBctx: true,
 }
 
+var SemVerSatisfies = &Builtin{
+	Name:        "semver.satisfies",
+	Description: "Checks if a SemVer version satisfies a version range constraint, like `\"^2.3 || >=3.1 <4\"`. See the [range grammar](/docs/policy-reference/builtins/semver#version-ranges).",
+	Decl: types.NewFunction(
+		types.Args(
+			types.Named("vsn", types.S).Description("version string"),
+			types.Named("constraint", types.S).Description("version range constraint"),
+		),
+		types.Named("result", types.B).Description("`true` if `vsn` satisfies `constraint`; `false` otherwise"),
+	),
+	CanSkipBctx: true,
+}
+
+var SemVerMaxSatisfying = &Builtin{
+	Name:        "semver.max_satisfying",
+	Description: "Returns the highest version satisfying a version range constraint. Strings that aren't valid SemVer versions are ignored. See the [range grammar](/docs/policy-reference/builtins/semver#version-ranges).",
+	Decl: types.NewFunction(
+		types.Args(
+			types.Named("versions", types.NewAny(
+				types.NewArray(nil, types.S),
+				types.NewSet(types.S),
+			)).Description("version strings"),
+			types.Named("constraint", types.S).Description("version range constraint"),
+		),
+		types.Named("output", types.S).Description("the highest of `versions` satisfying `constraint`; `undefined` if none does"),
+	),
+	CanSkipBctx: true,
+}
+
 /**
  * Printing
  */
@@ -495,6 +495,12 @@ func (c *Compiler) WithEnablePrintStatements(yes bool) *Compiler {
 	return c
 }
 
+// EnablePrintStatements returns true if print statements are enabled inside of
+// modules compiled by the compiler.
+func (c *Compiler) EnablePrintStatements() bool {
+	return c.enablePrintStatements
+}
+
 // WithPathConflictsCheck enables base-virtual document conflict
 // detection. The compiler will check that rules don't overlap with
 // paths that exist as determined by the provided callable.
@@ -1963,6 +1969,95 @@ func (c *Compiler) checkUnsafeBuiltins() {
 	}
 }
 
+// CheckCapabilities checks the compiled modules with the given names against
+// capabilities that are more restrictive than those of the compiler. An error
+// is returned for each call of a built-in function, use of a language feature
+// and import of a future keyword that the capabilities don't support. Unlike
+// WithCapabilities, this restricts the given modules without restricting the
+// other modules compiled with them.
+func (c *Compiler) CheckCapabilities(capabilities *Capabilities, names ...string) Errors {
+	var errs Errors
+
+	builtins := make(map[string]struct{}, len(capabilities.Builtins))
+	for _, bi := range capabilities.Builtins {
+		builtins[bi.Name] = struct{}{}
+	}
+
+	feature := func(loc *Location, name string, implied ...string) {
+		if !capabilities.ContainsFeature(name) && !slices.ContainsFunc(implied, capabilities.ContainsFeature) {
+			errs = append(errs, NewError(CompileErr, loc, "feature %v is not allowed by capabilities", name))
+		}
+	}
+
+	// Like the parser and compiler, the rego_v1 feature implies the features
+	// and future keywords that are part of v1 Rego.
+	regoV1 := capabilities.ContainsFeature(FeatureRegoV1)
+
+	for _, name := range names {
+		mod, ok := c.Modules[name]
+		if !ok {
+			continue
+		}
+
+		v1 := c.moduleIsRegoV1(mod)
+		if v1 {
+			feature(mod.Package.Loc(), FeatureRegoV1)
+		} else {
+			for _, rule := range mod.Rules {
+				if ref := rule.Head.Reference; len(ref) >= 3 {
+					if len(ref) > len(ref.ConstantPrefix()) {
+						feature(rule.Loc(), FeatureRefHeads, FeatureRegoV1)
+					} else {
+						feature(rule.Loc(), FeatureRefHeadStringPrefixes, FeatureRefHeads, FeatureRegoV1)
+					}
+				}
+			}
+		}
+
+		for _, imp := range c.imports[name] {
+			path := imp.Path.Value.(Ref)
+			switch {
+			case path.Equal(RegoV1CompatibleRef):
+				if !v1 {
+					feature(imp.Loc(), FeatureRegoV1Import, FeatureRegoV1)
+				}
+			case !v1 && len(path) == 3 && path.HasPrefix(futureKeywordsPrefix):
+				kw := string(path[2].Value.(String))
+				if _, implied := futureKeywordsV0[kw]; !(implied && regoV1) && !slices.Contains(capabilities.FutureKeywords, kw) {
+					errs = append(errs, NewError(CompileErr, imp.Loc(), "future keyword %v is not allowed by capabilities", kw))
+				}
+			}
+		}
+
+		WalkExprs(mod, func(x *Expr) bool {
+			if !x.IsCall() {
+				return false
+			}
+
+			operator := x.Operator().String()
+			if _, ok := c.builtins[operator]; !ok {
+				return false
+			}
+
+			switch operator {
+			case InternalPrint.Name:
+				// Calls to print are rewritten by the compiler.
+				operator = Print.Name
+			case InternalTemplateString.Name:
+				feature(x.Loc(), FeatureTemplateStrings)
+			}
+
+			if _, ok := builtins[operator]; !ok {
+				errs = append(errs, NewError(CompileErr, x.Loc(), "built-in function %v is not allowed by capabilities", operator))
+			}
+
+			return false
+		})
+	}
+
+	return errs
+}
+
 func (c *Compiler) checkDeprecatedBuiltins() {
 	checkNeeded := false
 	for _, b := range c.Required.Builtins {
@@ -12732,6 +12732,89 @@ func TestCompilerCapabilitiesExtendedWithCustomBuiltins(t *testing.T) {
 
 }
 
+func TestCompilerCheckCapabilities(t *testing.T) {
+	restricted := CapabilitiesForThisVersion()
+	restricted.Builtins = slices.DeleteFunc(slices.Clone(restricted.Builtins), func(bi *Builtin) bool {
+		return bi.Name == HTTPSend.Name || bi.Name == Print.Name
+	})
+	restricted.Features = slices.DeleteFunc(slices.Clone(restricted.Features), func(f string) bool {
+		return f == FeatureTemplateStrings
+	})
+
+	team := MustParseModuleWithOpts(`package team
+
+import data.lib
+
+p if {
+	lib.q
+	http.send({"method": "get", "url": "http://example.com"})
+	print("p")
+}
+
+s := $"{input.x}"`, ParserOptions{RegoVersion: RegoV1})
+
+	legacy := MustParseModuleWithOpts(`package legacy
+
+import future.keywords.if
+import future.keywords.contains
+
+a.b[c] := 1 if c := "x"
+
+s contains 1`, ParserOptions{RegoVersion: RegoV0})
+
+	lib := MustParseModuleWithOpts(`package lib
+
+q if http.send({"method": "get", "url": "http://example.com"})`, ParserOptions{RegoVersion: RegoV1})
+
+	compiler := NewCompiler().WithEnablePrintStatements(true)
+	compiler.Compile(map[string]*Module{"team.rego": team, "legacy.rego": legacy, "lib.rego": lib})
+	if compiler.Failed() {
+		t.Fatal(compiler.Errors)
+	}
+
+	var act []string
+	for _, err := range compiler.CheckCapabilities(restricted, "team.rego", "legacy.rego", "missing.rego") {
+		act = append(act, fmt.Sprintf("%d:%d: %s", err.Location.Row, err.Location.Col, err.Message))
+	}
+
+	exp := []string{
+		"7:2: built-in function http.send is not allowed by capabilities",
+		"8:2: built-in function print is not allowed by capabilities",
+		"11:6: feature template_strings is not allowed by capabilities",
+	}
+
+	if !slices.Equal(act, exp) {
+		t.Fatalf("expected errors:\n%v\n\ngot:\n%v", strings.Join(exp, "\n"), strings.Join(act, "\n"))
+	}
+
+	if errs := compiler.CheckCapabilities(CapabilitiesForThisVersion(), "team.rego", "legacy.rego", "lib.rego"); len(errs) != 0 {
+		t.Fatalf("expected no errors but got %v", errs)
+	}
+
+	// Without the rego_v1 feature, v1 modules, ref heads and future keywords
+	// are only allowed if the capabilities include them.
+	v0 := CapabilitiesForThisVersion(CapabilitiesRegoVersion(RegoV0))
+	v0.Features = slices.DeleteFunc(slices.Clone(v0.Features), func(f string) bool {
+		return f == FeatureRegoV1 || f == FeatureRefHeads
+	})
+	v0.FutureKeywords = []string{"if"}
+
+	act = act[:0]
+	for _, err := range compiler.CheckCapabilities(v0, "legacy.rego", "lib.rego") {
+		act = append(act, fmt.Sprintf("%d:%d: %s", err.Location.Row, err.Location.Col, err.Message))
+	}
+
+	exp = []string{
+		"6:1: feature rule_head_refs is not allowed by capabilities",
+		"4:1: future keyword contains is not allowed by capabilities",
+		"1:1: feature rego_v1 is not allowed by capabilities",
+	}
+
+	if !slices.Equal(act, exp) {
+		t.Fatalf("expected errors:\n%v\n\ngot:\n%v", strings.Join(exp, "\n"), strings.Join(act, "\n"))
+	}
+}
+
 func TestCompilerWithUnsafeBuiltins(t *testing.T) {
 	// Rego includes a number of built-in functions. In some cases, you may not
 	// want all builtins to be available to a program. This test shows how to
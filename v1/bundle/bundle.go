@@ -18,6 +18,7 @@ import (
 	"path"
 	"path/filepath"
 	"reflect"
+	"slices"
 	"strings"
 	"sync"
 
@@ -71,7 +72,9 @@ type Bundle struct {
 	Patch       Patch
 	Etag        string
 	Raw         []Raw
+	Precompiled []byte // precompiled modules, see Bundle.Precompile
 
+	precompiled     *precompiledState
 	lazyLoadingMode bool
 	sizeLimitBytes  int64
 	manifestProto   bool
@@ -160,6 +163,15 @@ type Manifest struct {
 	// This allows individual files to override the global Rego version specified by RegoVersion.
 	FileRegoVersions map[string]int `json:"file_rego_versions,omitempty"`
 	Metadata         map[string]any `json:"metadata,omitempty"`
+	// Version is the semantic version of the bundle. Other bundles refer to it
+	// when declaring a versioned dependency on this bundle.
+	Version string `json:"version,omitempty"`
+	// Dependencies lists the bundles that must be activated, at a version
+	// satisfying the declared range, before this bundle can be activated.
+	Dependencies []Dependency `json:"dependencies,omitempty"`
+	// Capabilities restricts the built-in functions and features that the
+	// policies of the bundle may use.
+	Capabilities *Capabilities `json:"capabilities,omitempty"`
 
 	compiledFileRegoVersions []fileRegoVersion
 }
@@ -232,6 +244,14 @@ func (m Manifest) Equal(other Manifest) bool {
 		return false
 	}
 
+	if m.Version != other.Version || !slices.Equal(m.Dependencies, other.Dependencies) {
+		return false
+	}
+
+	if !m.Capabilities.Equal(other.Capabilities) {
+		return false
+	}
+
 	return m.equalWasmResolversAndRoots(other)
 }
 
@@ -250,6 +270,12 @@ func (m Manifest) Copy() Manifest {
 	copy(wasmModules, m.WasmResolvers)
 	m.WasmResolvers = wasmModules
 
+	if m.Dependencies != nil {
+		m.Dependencies = slices.Clone(m.Dependencies)
+	}
+
+	m.Capabilities = m.Capabilities.Copy()
+
 	metadata := m.Metadata
 
 	if metadata != nil {
@@ -358,6 +384,14 @@ func (m *Manifest) validateAndInjectDefaults(b Bundle) error {
 		}
 	}
 
+	if err := m.validateDependencies(); err != nil {
+		return err
+	}
+
+	if err := m.Capabilities.Validate(); err != nil {
+		return fmt.Errorf("manifest %w", err)
+	}
+
 	// Validate modules in bundle.
 	for _, module := range b.Modules {
 		found := false
@@ -701,6 +735,8 @@ func (r *Reader) Read() (Bundle, error) {
 
 				bundle.Raw = append(bundle.Raw, Raw{Path: p, Value: bs, module: &mf})
 			}
+		} else if strings.TrimPrefix(path, "/") == PrecompiledFile {
+			bundle.Precompiled = buf.Bytes()
 		} else if filepath.Base(path) == WasmFile {
 			bundle.WasmModules = append(bundle.WasmModules, WasmModuleFile{
 				URL:  f.URL(),
@@ -783,25 +819,39 @@ func (r *Reader) Read() (Bundle, error) {
 	popts := r.ParserOptions()
 	popts.RegoVersion = bundle.RegoVersion(popts.EffectiveRegoVersion())
 
-	g := &errgroup.Group{}
-	r.metrics.Timer(metrics.RegoModuleParse).Start()
-
+	regoVersions := make([]ast.RegoVersion, len(modules))
 	for i, mf := range modules {
-		mpopts := popts
+		regoVersions[i] = popts.RegoVersion
 		if regoVersion, err := bundle.RegoVersionForFile(mf.RelativePath, popts.EffectiveRegoVersion()); err != nil {
 			return *bundle, err
 		} else if regoVersion != ast.RegoUndefined {
 			// We don't expect ast.RegoUndefined here, but don't override
 			// configured rego-version if we do just to be extra protective
-			mpopts.RegoVersion = regoVersion
+			regoVersions[i] = regoVersion
 		}
+	}
 
-		g.Go(func() (err error) {
-			if mf.Parsed, err = ast.ParseModuleWithOpts(mf.Path, util.ByteSliceToString(mf.Raw), mpopts); err == nil {
-				modules[i] = mf
-			}
-			return err
-		})
+	g := &errgroup.Group{}
+	r.metrics.Timer(metrics.RegoModuleParse).Start()
+
+	// Precompiled modules are only used if they were built from the same
+	// sources, with the same rego-versions and for the same OPA; otherwise
+	// the modules are parsed from source.
+	precompiled := len(bundle.Precompiled) > 0 && bundle.Type() == SnapshotBundleType &&
+		r.processAnnotations && r.loadPrecompiled(bundle, modules, popts, regoVersions)
+
+	if !precompiled {
+		for i, mf := range modules {
+			mpopts := popts
+			mpopts.RegoVersion = regoVersions[i]
+
+			g.Go(func() (err error) {
+				if mf.Parsed, err = ast.ParseModuleWithOpts(mf.Path, util.ByteSliceToString(mf.Raw), mpopts); err == nil {
+					modules[i] = mf
+				}
+				return err
+			})
+		}
 	}
 
 	err = g.Wait()
@@ -936,10 +986,22 @@ func Write(w io.Writer, bundle Bundle) error {
 		Write(bundle)
 }
 
+// Compression formats of bundle archives.
+const (
+	CompressionGzip = "gzip"
+	CompressionZstd = "zstd"
+	CompressionNone = "none"
+)
+
+// Compressions contains the list of compression formats supported by the
+// bundle writer. Readers detect the compression of an archive automatically.
+var Compressions = []string{CompressionGzip, CompressionZstd, CompressionNone}
+
 // Writer implements bundle serialization.
 type Writer struct {
 	usePath       bool
 	disableFormat bool
+	compression   string
 	w             io.Writer
 }
 
@@ -964,12 +1026,22 @@ func (w *Writer) DisableFormat(yes bool) *Writer {
 	return w
 }
 
+// WithCompression sets the compression format of the written archive, one of
+// Compressions. Archives are gzip compressed by default.
+func (w *Writer) WithCompression(compression string) *Writer {
+	w.compression = compression
+	return w
+}
+
 // Write writes the bundle to the writer's output stream.
 func (w *Writer) Write(bundle Bundle) error {
 	if err := validateBundleFormat(&bundle); err != nil {
 		return err
 	}
-	tw := archive.NewTarGzWriter(w.w)
+	tw, err := w.newTarWriter()
+	if err != nil {
+		return err
+	}
 
 	if bundle.Type() == SnapshotBundleType {
 		if err := tw.WriteJSONFile("/data.json", bundle.Data); err != nil {
@@ -991,6 +1063,12 @@ func (w *Writer) Write(bundle Bundle) error {
 			return err
 		}
 
+		if len(bundle.Precompiled) != 0 {
+			if err := tw.WriteFile(util.WithPrefix(PrecompiledFile, "/"), bundle.Precompiled); err != nil {
+				return err
+			}
+		}
+
 		if err := writeSignatures(tw, bundle); err != nil {
 			return err
 		}
@@ -1023,7 +1101,19 @@ func (w *Writer) Write(bundle Bundle) error {
 	return tw.Close()
 }
 
-func (w *Writer) writeWasm(tw *archive.TarGzWriter, bundle Bundle) error {
+func (w *Writer) newTarWriter() (*archive.TarWriter, error) {
+	switch w.compression {
+	case "", CompressionGzip:
+		return archive.NewTarGzWriter(w.w), nil
+	case CompressionZstd:
+		return archive.NewTarZstdWriter(w.w)
+	case CompressionNone:
+		return archive.NewTarWriter(w.w), nil
+	}
+	return nil, fmt.Errorf("unsupported compression %q", w.compression)
+}
+
+func (w *Writer) writeWasm(tw *archive.TarWriter, bundle Bundle) error {
 	for _, wm := range bundle.WasmModules {
 		path := wm.URL
 		if w.usePath {
@@ -1042,7 +1132,7 @@ func (w *Writer) writeWasm(tw *archive.TarGzWriter, bundle Bundle) error {
 	return tw.WriteFile(util.WithPrefix(WasmFile, "/"), bundle.Wasm)
 }
 
-func (w *Writer) writePlan(tw *archive.TarGzWriter, bundle Bundle) error {
+func (w *Writer) writePlan(tw *archive.TarWriter, bundle Bundle) error {
 	for _, wm := range bundle.PlanModules {
 		path := wm.URL
 		if w.usePath {
@@ -1057,7 +1147,7 @@ func (w *Writer) writePlan(tw *archive.TarGzWriter, bundle Bundle) error {
 	return nil
 }
 
-func writeSignatures(tw *archive.TarGzWriter, bundle Bundle) error {
+func writeSignatures(tw *archive.TarWriter, bundle Bundle) error {
 	if bundle.Signatures.isEmpty() {
 		return nil
 	}
@@ -1104,6 +1194,14 @@ func hashBundleFiles(hash SignatureHasher, b *Bundle) ([]FileInfo, error) {
 		files = append(files, NewFile(strings.TrimPrefix(planmodule.Path, "/"), hex.EncodeToString(bs), defaultHashingAlg))
 	}
 
+	if len(b.Precompiled) != 0 {
+		bs, err := hash.HashFile(b.Precompiled)
+		if err != nil {
+			return files, err
+		}
+		files = append(files, NewFile(PrecompiledFile, hex.EncodeToString(bs), defaultHashingAlg))
+	}
+
 	// Skip empty manifest — Writer.Write skips it too, so no entry to hash.
 	// Proto manifest is hashed as raw deterministic-marshal bytes (matches
 	// what VerifyBundleFile sees, since IsStructuredDoc is false for /.manifest.pb).
@@ -1336,6 +1336,52 @@ func TestRoundtrip(t *testing.T) {
 	}
 }
 
+func TestRoundtripCompression(t *testing.T) {
+	bundle := Bundle{
+		Data: map[string]any{"foo": "bar"},
+		Modules: []ModuleFile{
+			{
+				URL:    "/foo.rego",
+				Path:   "/foo.rego",
+				Parsed: ast.MustParseModule(`package foo`),
+				Raw:    []byte("package foo\n"),
+			},
+		},
+		Manifest: Manifest{Roots: &[]string{"foo"}, Revision: "quickbrownfaux"},
+	}
+
+	magic := map[string][]byte{
+		CompressionGzip: gzipMagic,
+		CompressionZstd: zstdMagic,
+		CompressionNone: []byte("/data.json"),
+	}
+
+	for _, compression := range Compressions {
+		t.Run(compression, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := NewWriter(&buf).WithCompression(compression).Write(bundle); err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.HasPrefix(buf.Bytes(), magic[compression]) {
+				t.Fatalf("expected archive to start with %q", magic[compression])
+			}
+
+			bundle2, err := NewReader(&buf).Read()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bundle2.Equal(bundle) {
+				t.Fatal("Exp:", bundle, "\n\nGot:", bundle2)
+			}
+		})
+	}
+
+	err := NewWriter(&bytes.Buffer{}).WithCompression("lz4").Write(bundle)
+	if err == nil || err.Error() != `unsupported compression "lz4"` {
+		t.Fatalf("expected unsupported compression error but got %v", err)
+	}
+}
+
 func TestWriterRejectsMixedPlanManifestFormats(t *testing.T) {
 	manifest := Manifest{Roots: &[]string{""}, Revision: "r"}
 
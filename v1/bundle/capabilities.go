@@ -0,0 +1,128 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package bundle
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/util"
+)
+
+// Capabilities restricts the capabilities that the policies of a bundle may
+// use, e.g. to forbid a bundle from calling http.send while other bundles
+// activated by the same OPA may call it. The restrictions are applied to the
+// capabilities of the OPA activating the bundle.
+type Capabilities struct {
+	// Builtins lists the built-in functions that may be called. If nil, all
+	// built-in functions may be called, except those in DenyBuiltins.
+	Builtins *[]string `json:"builtins,omitempty"`
+	// DenyBuiltins lists built-in functions that may not be called.
+	DenyBuiltins []string `json:"deny_builtins,omitempty"`
+	// Features lists the language features, e.g. "rego_v1", that may be used.
+	// If nil, all features may be used.
+	Features *[]string `json:"features,omitempty"`
+}
+
+// Validate returns an error if the restrictions refer to unknown features.
+func (c *Capabilities) Validate() error {
+	if c == nil || c.Features == nil {
+		return nil
+	}
+	for _, f := range *c.Features {
+		if !slices.Contains(ast.Features, f) {
+			return fmt.Errorf("unknown capabilities feature %q", f)
+		}
+	}
+	return nil
+}
+
+// Restrict returns a copy of the capabilities restricted to the built-in
+// functions and features allowed by c. If c is nil, the capabilities are
+// returned unchanged.
+func (c *Capabilities) Restrict(capabilities *ast.Capabilities) *ast.Capabilities {
+	if c == nil {
+		return capabilities
+	}
+
+	restricted := *capabilities
+
+	restricted.Builtins = slices.DeleteFunc(slices.Clone(capabilities.Builtins), func(bi *ast.Builtin) bool {
+		if slices.Contains(c.DenyBuiltins, bi.Name) {
+			return true
+		}
+		return c.Builtins != nil && !slices.Contains(*c.Builtins, bi.Name)
+	})
+
+	if c.Features != nil {
+		restricted.Features = slices.DeleteFunc(slices.Clone(capabilities.Features), func(f string) bool {
+			return !slices.Contains(*c.Features, f)
+		})
+	}
+
+	return &restricted
+}
+
+// Equal returns true if the restrictions are equal.
+func (c *Capabilities) Equal(other *Capabilities) bool {
+	if c == nil || other == nil {
+		return c == other
+	}
+	return equalOptionalStrings(c.Builtins, other.Builtins) &&
+		slices.Equal(c.DenyBuiltins, other.DenyBuiltins) &&
+		equalOptionalStrings(c.Features, other.Features)
+}
+
+// Copy returns a deep copy of the restrictions.
+func (c *Capabilities) Copy() *Capabilities {
+	if c == nil {
+		return nil
+	}
+	cpy := Capabilities{DenyBuiltins: slices.Clone(c.DenyBuiltins)}
+	if c.Builtins != nil {
+		cpy.Builtins = new(slices.Clone(*c.Builtins))
+	}
+	if c.Features != nil {
+		cpy.Features = new(slices.Clone(*c.Features))
+	}
+	return &cpy
+}
+
+func equalOptionalStrings(a, b *[]string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return slices.Equal(*a, *b)
+}
+
+// checkCapabilities checks the compiled modules of the bundles against the
+// capabilities restrictions declared for them, in the manifest of the bundle
+// and in restrictions, keyed by bundle name.
+func checkCapabilities(compiler *ast.Compiler, bundles map[string]*Bundle, restrictions map[string]*Capabilities, legacy bool) error {
+	for _, name := range util.KeysSorted(bundles) {
+		b := bundles[name]
+		if b.Manifest.Capabilities == nil && restrictions[name] == nil {
+			continue
+		}
+
+		capabilities := restrictions[name].Restrict(b.Manifest.Capabilities.Restrict(compiler.Capabilities()))
+
+		var modules []string
+		if legacy {
+			for _, mf := range b.Modules {
+				modules = append(modules, mf.Path)
+			}
+		} else {
+			modules = util.KeysSorted(b.ParsedModules(name))
+		}
+
+		if errs := compiler.CheckCapabilities(capabilities, modules...); len(errs) > 0 {
+			return fmt.Errorf("bundle %s: %w", name, errs)
+		}
+	}
+
+	return nil
+}
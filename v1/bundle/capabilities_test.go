@@ -0,0 +1,178 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package bundle
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/open-policy-agent/opa/internal/file/archive"
+	"github.com/open-policy-agent/opa/internal/storage/mock"
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/metrics"
+	"github.com/open-policy-agent/opa/v1/storage"
+)
+
+func TestCapabilitiesRestrict(t *testing.T) {
+	base := ast.CapabilitiesForThisVersion()
+
+	tests := []struct {
+		note     string
+		c        *Capabilities
+		builtins []string // expected to be present
+		absent   []string // expected to be absent
+		features []string
+	}{
+		{
+			note:     "nil",
+			builtins: []string{"http.send", "count"},
+			features: base.Features,
+		},
+		{
+			note:     "deny",
+			c:        &Capabilities{DenyBuiltins: []string{"http.send", "opa.runtime"}},
+			builtins: []string{"count", "net.lookup_ip_addr"},
+			absent:   []string{"http.send", "opa.runtime"},
+			features: base.Features,
+		},
+		{
+			note:     "allow and deny",
+			c:        &Capabilities{Builtins: &[]string{"count", "http.send"}, DenyBuiltins: []string{"http.send"}},
+			builtins: []string{"count"},
+			absent:   []string{"http.send", "eq"},
+			features: base.Features,
+		},
+		{
+			note:     "features",
+			c:        &Capabilities{Features: &[]string{ast.FeatureRegoV1, "unknown"}},
+			builtins: []string{"count"},
+			features: []string{ast.FeatureRegoV1},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			act := tc.c.Restrict(base)
+			for _, name := range tc.builtins {
+				if !act.ContainsBuiltin(name) {
+					t.Errorf("expected builtin %v", name)
+				}
+			}
+			for _, name := range tc.absent {
+				if act.ContainsBuiltin(name) {
+					t.Errorf("expected no builtin %v", name)
+				}
+			}
+			if !slices.Equal(act.Features, tc.features) {
+				t.Errorf("expected features %v but got %v", tc.features, act.Features)
+			}
+		})
+	}
+
+	if !base.ContainsBuiltin("http.send") || len(base.Features) == 1 {
+		t.Fatal("expected base capabilities to be unchanged")
+	}
+}
+
+func TestReadManifestCapabilities(t *testing.T) {
+	manifest := `{"capabilities": {"deny_builtins": ["http.send"], "features": ["rego_v2"]}}`
+	_, err := NewReader(archive.MustWriteTarGz([][2]string{{"/.manifest", manifest}})).Read()
+	if err == nil || !strings.Contains(err.Error(), `manifest unknown capabilities feature "rego_v2"`) {
+		t.Fatalf("expected unknown feature error but got %v", err)
+	}
+}
+
+func TestActivateWithCapabilities(t *testing.T) {
+	lib := `package lib
+
+resp := http.send({"method": "get", "url": "http://localhost"})
+`
+	team := `package team
+
+import data.lib
+
+p := lib.resp
+
+q if {
+	x := opa.runtime()
+	x.env
+}
+`
+
+	tests := []struct {
+		note         string
+		manifest     *Capabilities
+		restrictions map[string]*Capabilities
+		exp          []string
+	}{
+		{
+			note: "unrestricted",
+		},
+		{
+			note:         "restricted by config",
+			restrictions: map[string]*Capabilities{"team": {DenyBuiltins: []string{"http.send", "opa.runtime"}}},
+			exp:          []string{"bundle team: 1 error occurred: /team.rego:8: rego_compile_error: built-in function opa.runtime is not allowed by capabilities"},
+		},
+		{
+			note:     "restricted by manifest",
+			manifest: &Capabilities{Builtins: &[]string{"eq", "assign"}},
+			exp:      []string{"bundle team: 1 error occurred: /team.rego:8: rego_compile_error: built-in function opa.runtime is not allowed by capabilities"},
+		},
+		{
+			note:         "restricted by both",
+			manifest:     &Capabilities{DenyBuiltins: []string{"count"}},
+			restrictions: map[string]*Capabilities{"team": {Features: &[]string{}}},
+			exp:          []string{"bundle team: 1 error occurred: /team.rego:1: rego_compile_error: feature rego_v1 is not allowed by capabilities"},
+		},
+		{
+			note:         "other bundle",
+			restrictions: map[string]*Capabilities{"lib": {DenyBuiltins: []string{"opa.runtime"}}, "other": {Features: &[]string{}}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			bundles := map[string]*Bundle{
+				"lib": {
+					Manifest: Manifest{Roots: &[]string{"lib"}},
+					Modules:  []ModuleFile{{Path: "/lib.rego", Raw: []byte(lib), Parsed: must(ast.ParseModuleWithOpts("/lib.rego", lib, ast.ParserOptions{}))(t)}},
+				},
+				"team": {
+					Manifest: Manifest{Roots: &[]string{"team"}, Capabilities: tc.manifest},
+					Modules:  []ModuleFile{{Path: "/team.rego", Raw: []byte(team), Parsed: must(ast.ParseModuleWithOpts("/team.rego", team, ast.ParserOptions{}))(t)}},
+				},
+			}
+
+			store := mock.New()
+			txn := storage.NewTransactionOrDie(t.Context(), store, storage.WriteParams)
+			err := Activate(&ActivateOpts{
+				Ctx:          t.Context(),
+				Store:        store,
+				Txn:          txn,
+				Compiler:     ast.NewCompiler(),
+				Metrics:      metrics.NoOp(),
+				Bundles:      bundles,
+				Capabilities: tc.restrictions,
+			})
+
+			if len(tc.exp) == 0 {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			var errs ast.Errors
+			if !errors.As(err, &errs) {
+				t.Fatalf("expected ast errors but got %v", err)
+			}
+			if act := strings.Split(err.Error(), "\n"); !slices.Equal(act, tc.exp) {
+				t.Fatalf("expected:\n%v\n\ngot:\n%v", strings.Join(tc.exp, "\n"), err)
+			}
+		})
+	}
+}
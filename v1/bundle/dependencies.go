@@ -0,0 +1,216 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package bundle
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/open-policy-agent/opa/internal/semver"
+	"github.com/open-policy-agent/opa/v1/util"
+)
+
+// Dependency declares that a bundle requires another bundle to be activated.
+// The dependency is identified by the bundle name used in the OPA
+// configuration. Version is an optional semver range, e.g. "^2.3 || >=3.1 <4",
+// that the version of the dependency must satisfy.
+type Dependency struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+// UnmetDependencyError is returned when a bundle declares a dependency that is
+// not satisfied by the set of activated bundles.
+type UnmetDependencyError struct {
+	Bundle     string // name of the bundle declaring the dependency
+	Dependency string // name of the required bundle
+	Constraint string // version range required, if any
+	Found      string // version of the required bundle, if activated
+	Activated  bool   // whether the required bundle is activated
+}
+
+func (e *UnmetDependencyError) Error() string {
+	if !e.Activated {
+		return fmt.Sprintf("bundle %q requires bundle %q which is not activated", e.Bundle, e.Dependency)
+	}
+	if e.Found == "" {
+		return fmt.Sprintf("bundle %q requires bundle %q at version %q but it has no version", e.Bundle, e.Dependency, e.Constraint)
+	}
+	return fmt.Sprintf("bundle %q requires bundle %q at version %q but found %q", e.Bundle, e.Dependency, e.Constraint, e.Found)
+}
+
+// SemanticVersion returns the semantic version of the bundle described by
+// the manifest. It is the manifest version if set. Otherwise, the revision
+// is used if it is a valid semantic version. The second return value is false
+// if the bundle has no semantic version.
+func (m Manifest) SemanticVersion() (string, bool) {
+	if m.Version != "" {
+		return m.Version, true
+	}
+	if _, err := semver.Parse(m.Revision); err == nil && m.Revision != "" {
+		return m.Revision, true
+	}
+	return "", false
+}
+
+func (m Manifest) validateDependencies() error {
+	if m.Version != "" {
+		if _, err := semver.Parse(m.Version); err != nil {
+			return fmt.Errorf("manifest has invalid version %q: %w", m.Version, err)
+		}
+	}
+
+	seen := make(map[string]struct{}, len(m.Dependencies))
+	for _, dep := range m.Dependencies {
+		if dep.Name == "" {
+			return errors.New("manifest declares a dependency without a name")
+		}
+		if _, ok := seen[dep.Name]; ok {
+			return fmt.Errorf("manifest declares dependency %q more than once", dep.Name)
+		}
+		seen[dep.Name] = struct{}{}
+
+		if dep.Version != "" {
+			if _, err := semver.ParseConstraint(dep.Version); err != nil {
+				return fmt.Errorf("manifest dependency %q: %w", dep.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// CheckDependencies checks the dependencies declared in the manifest of the
+// bundle with the given name against the manifests of the activated bundles,
+// keyed by bundle name. All unmet dependencies are reported as
+// UnmetDependencyError values joined into a single error.
+func CheckDependencies(name string, m Manifest, active map[string]Manifest) error {
+	var errs []error
+
+	for _, dep := range m.Dependencies {
+		other, ok := active[dep.Name]
+		if !ok {
+			errs = append(errs, &UnmetDependencyError{Bundle: name, Dependency: dep.Name, Constraint: dep.Version})
+			continue
+		}
+
+		if dep.Version == "" {
+			continue
+		}
+
+		if !satisfies(other, dep.Version) {
+			found, _ := other.SemanticVersion()
+			errs = append(errs, &UnmetDependencyError{
+				Bundle:     name,
+				Dependency: dep.Name,
+				Constraint: dep.Version,
+				Found:      found,
+				Activated:  true,
+			})
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// CheckDependents checks that activating the bundle with the given name and
+// manifest keeps the dependencies declared by the activated bundles satisfied.
+// It is used to reject an update to a bundle that other bundles depend on.
+func CheckDependents(name string, m Manifest, active map[string]Manifest) error {
+	var errs []error
+
+	for _, other := range util.KeysSorted(active) {
+		if other == name {
+			continue
+		}
+		for _, dep := range active[other].Dependencies {
+			if dep.Name != name || dep.Version == "" {
+				continue
+			}
+			if !satisfies(m, dep.Version) {
+				found, _ := m.SemanticVersion()
+				errs = append(errs, &UnmetDependencyError{
+					Bundle:     other,
+					Dependency: name,
+					Constraint: dep.Version,
+					Found:      found,
+					Activated:  true,
+				})
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func satisfies(m Manifest, constraint string) bool {
+	v, ok := m.SemanticVersion()
+	if !ok {
+		return false
+	}
+
+	version, err := semver.Parse(v)
+	if err != nil {
+		return false
+	}
+
+	c, err := semver.ParseConstraint(constraint)
+	if err != nil {
+		return false
+	}
+
+	return c.Check(version)
+}
+
+// ActivationOrder returns the names of the given bundles ordered so that every
+// bundle comes after the bundles it depends on. Bundles without dependencies
+// between them are ordered by name. Dependencies on bundles outside of the
+// given set are ignored. An error is returned if the dependencies form a cycle.
+func ActivationOrder(manifests map[string]Manifest) ([]string, error) {
+	const (
+		visiting = iota + 1
+		visited
+	)
+
+	state := make(map[string]int, len(manifests))
+	order := make([]string, 0, len(manifests))
+
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("bundle dependency cycle: %s", strings.Join(append(path, name), " -> "))
+		}
+
+		state[name] = visiting
+		deps := make([]string, 0, len(manifests[name].Dependencies))
+		for _, dep := range manifests[name].Dependencies {
+			if _, ok := manifests[dep.Name]; ok {
+				deps = append(deps, dep.Name)
+			}
+		}
+		slices.Sort(deps)
+		for _, dep := range deps {
+			if err := visit(dep, append(path, name)); err != nil {
+				return err
+			}
+		}
+		state[name] = visited
+		order = append(order, name)
+		return nil
+	}
+
+	for _, name := range util.KeysSorted(manifests) {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
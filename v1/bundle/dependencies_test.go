@@ -0,0 +1,190 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package bundle
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/open-policy-agent/opa/internal/file/archive"
+)
+
+func TestReadManifestDependencies(t *testing.T) {
+	tests := []struct {
+		note     string
+		manifest string
+		wantErr  string
+	}{
+		{
+			note:     "valid",
+			manifest: `{"version": "1.4.0", "dependencies": [{"name": "platform", "version": "^2.3 || >=3.1 <4"}, {"name": "shared"}]}`,
+		},
+		{
+			note:     "invalid version",
+			manifest: `{"version": "latest"}`,
+			wantErr:  `manifest has invalid version "latest"`,
+		},
+		{
+			note:     "invalid constraint",
+			manifest: `{"dependencies": [{"name": "platform", "version": "^two"}]}`,
+			wantErr:  `manifest dependency "platform": invalid constraint "^two"`,
+		},
+		{
+			note:     "missing name",
+			manifest: `{"dependencies": [{"version": "^2"}]}`,
+			wantErr:  "manifest declares a dependency without a name",
+		},
+		{
+			note:     "duplicate name",
+			manifest: `{"dependencies": [{"name": "platform"}, {"name": "platform"}]}`,
+			wantErr:  `manifest declares dependency "platform" more than once`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			buf := archive.MustWriteTarGz([][2]string{{"/.manifest", tc.manifest}})
+			b, err := NewReader(buf).Read()
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("expected error containing %q but got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(b.Manifest.Dependencies) != 2 || b.Manifest.Dependencies[0].Version != "^2.3 || >=3.1 <4" {
+				t.Fatalf("unexpected dependencies: %v", b.Manifest.Dependencies)
+			}
+		})
+	}
+}
+
+func TestManifestSemanticVersion(t *testing.T) {
+	tests := []struct {
+		manifest Manifest
+		exp      string
+		ok       bool
+	}{
+		{Manifest{Version: "1.2.3", Revision: "2.0.0"}, "1.2.3", true},
+		{Manifest{Revision: "v2.0.0"}, "v2.0.0", true},
+		{Manifest{Revision: "abc123"}, "", false},
+		{Manifest{}, "", false},
+	}
+
+	for _, tc := range tests {
+		v, ok := tc.manifest.SemanticVersion()
+		if v != tc.exp || ok != tc.ok {
+			t.Errorf("%v: expected (%q, %v) but got (%q, %v)", tc.manifest, tc.exp, tc.ok, v, ok)
+		}
+	}
+}
+
+func TestCheckDependencies(t *testing.T) {
+	active := map[string]Manifest{
+		"platform": {Version: "2.4.1"},
+		"legacy":   {Revision: "abc123"},
+	}
+
+	tests := []struct {
+		note string
+		deps []Dependency
+		exp  []string
+	}{
+		{
+			note: "satisfied",
+			deps: []Dependency{{Name: "platform", Version: "^2.3"}, {Name: "legacy"}},
+		},
+		{
+			note: "not activated",
+			deps: []Dependency{{Name: "missing", Version: "^1"}},
+			exp:  []string{`bundle "team" requires bundle "missing" which is not activated`},
+		},
+		{
+			note: "version mismatch",
+			deps: []Dependency{{Name: "platform", Version: ">=3.0.0"}},
+			exp:  []string{`bundle "team" requires bundle "platform" at version ">=3.0.0" but found "2.4.1"`},
+		},
+		{
+			note: "no version",
+			deps: []Dependency{{Name: "legacy", Version: "^1"}},
+			exp:  []string{`bundle "team" requires bundle "legacy" at version "^1" but it has no version`},
+		},
+		{
+			note: "multiple",
+			deps: []Dependency{{Name: "missing"}, {Name: "platform", Version: "~2.3"}},
+			exp: []string{
+				`bundle "team" requires bundle "missing" which is not activated`,
+				`bundle "team" requires bundle "platform" at version "~2.3" but found "2.4.1"`,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			err := CheckDependencies("team", Manifest{Dependencies: tc.deps}, active)
+			if len(tc.exp) == 0 {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			var unmet *UnmetDependencyError
+			if !errors.As(err, &unmet) {
+				t.Fatalf("expected UnmetDependencyError but got %v", err)
+			}
+			if act := strings.Split(err.Error(), "\n"); !slices.Equal(act, tc.exp) {
+				t.Fatalf("expected errors %v but got %v", tc.exp, act)
+			}
+		})
+	}
+}
+
+func TestCheckDependents(t *testing.T) {
+	active := map[string]Manifest{
+		"platform": {Version: "2.4.1"},
+		"team-a":   {Dependencies: []Dependency{{Name: "platform", Version: "^2"}}},
+		"team-b":   {Dependencies: []Dependency{{Name: "platform"}}},
+	}
+
+	if err := CheckDependents("platform", Manifest{Version: "2.5.0"}, active); err != nil {
+		t.Fatal(err)
+	}
+
+	err := CheckDependents("platform", Manifest{Version: "3.0.0"}, active)
+	exp := `bundle "team-a" requires bundle "platform" at version "^2" but found "3.0.0"`
+	if err == nil || err.Error() != exp {
+		t.Fatalf("expected %q but got %v", exp, err)
+	}
+}
+
+func TestActivationOrder(t *testing.T) {
+	manifests := map[string]Manifest{
+		"team-b":   {Dependencies: []Dependency{{Name: "team-a"}, {Name: "platform"}}},
+		"team-a":   {Dependencies: []Dependency{{Name: "platform"}, {Name: "external"}}},
+		"platform": {},
+		"other":    {},
+	}
+
+	order, err := ActivationOrder(manifests)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []string{"other", "platform", "team-a", "team-b"}
+	if !slices.Equal(order, exp) {
+		t.Fatalf("expected %v but got %v", exp, order)
+	}
+
+	manifests["platform"] = Manifest{Dependencies: []Dependency{{Name: "team-b"}}}
+	_, err = ActivationOrder(manifests)
+	if err == nil || !strings.Contains(err.Error(), "bundle dependency cycle: platform -> team-b -> platform") {
+		t.Fatalf("expected cycle error but got %v", err)
+	}
+}
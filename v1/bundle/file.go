@@ -2,9 +2,11 @@ package bundle
 
 import (
 	"archive/tar"
+	"bufio"
 	"bytes"
 	"cmp"
 	"compress/gzip"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
@@ -14,6 +16,8 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/klauspost/compress/zstd"
+
 	"github.com/open-policy-agent/opa/v1/loader/filter"
 	"github.com/open-policy-agent/opa/v1/util"
 
@@ -22,6 +26,16 @@ import (
 
 const maxSizeLimitBytesErrMsg = "bundle file %s size (%d bytes) exceeds configured size_limit_bytes (%d bytes)"
 
+// Magic bytes used to detect the format of bundle archives. The ustar magic of
+// uncompressed tarballs follows the name, mode, owner, size and other fields of
+// the first header.
+var (
+	gzipMagic      = []byte{0x1f, 0x8b}
+	zstdMagic      = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	tarMagic       = []byte("ustar")
+	tarMagicOffset = 257
+)
+
 // Descriptor contains information about a file and
 // can be used to read the file contents.
 type Descriptor struct {
@@ -277,6 +291,7 @@ type tarballLoader struct {
 	skipDir           map[string]struct{}
 	pathFormat        PathFormat
 	maxSizeLimitBytes int64
+	closer            io.Closer
 }
 
 type file struct {
@@ -296,8 +311,8 @@ func NewTarballLoader(r io.Reader) DirectoryLoader {
 }
 
 // NewTarballLoaderWithBaseURL returns a new DirectoryLoader that reads
-// files out of a gzipped tar archive. The file URLs will be prefixed
-// with the baseURL.
+// files out of a gzip or zstd compressed, or uncompressed, tar archive. The
+// file URLs will be prefixed with the baseURL.
 func NewTarballLoaderWithBaseURL(r io.Reader, baseURL string) DirectoryLoader {
 	l := tarballLoader{
 		baseURL:    strings.TrimSuffix(baseURL, "/"),
@@ -334,15 +349,19 @@ func (t *tarballLoader) WithFollowSymlinks(_ bool) DirectoryLoader {
 // and returns a file Descriptor for the file.
 func (t *tarballLoader) NextFile() (*Descriptor, error) {
 	if t.tr == nil {
-		gr, err := gzip.NewReader(t.r)
+		r, err := t.decompress()
 		if err != nil {
 			return nil, fmt.Errorf("archive read failed: %w", err)
 		}
 
-		t.tr = tar.NewReader(gr)
+		t.tr = tar.NewReader(r)
 	}
 
 	if t.files == nil {
+		if t.closer != nil {
+			defer t.closer.Close()
+		}
+
 		t.files = []file{}
 
 		if t.skipDir == nil {
@@ -424,6 +443,43 @@ func (t *tarballLoader) NextFile() (*Descriptor, error) {
 	return d, nil
 }
 
+// decompress returns a reader for the tarball, detecting its compression by
+// the magic bytes it starts with.
+func (t *tarballLoader) decompress() (io.Reader, error) {
+	br := bufio.NewReader(t.r)
+	header, err := br.Peek(tarMagicOffset + len(tarMagic))
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	switch {
+	case bytes.HasPrefix(header, gzipMagic):
+		return gzip.NewReader(br)
+	case bytes.HasPrefix(header, zstdMagic):
+		// Bound the memory the decoder may allocate for a frame by the size
+		// limit, as the window size is read from the (untrusted) frame header
+		// before any file size is checked.
+		limit := uint64(t.maxSizeLimitBytes)
+		if t.maxSizeLimitBytes <= 0 {
+			limit = DefaultSizeLimitBytes
+		}
+		limit = min(max(limit, zstd.MinWindowSize), zstd.MaxWindowSize)
+		zr, err := zstd.NewReader(br,
+			zstd.WithDecoderConcurrency(1),
+			zstd.WithDecoderMaxWindow(limit),
+			zstd.WithDecoderMaxMemory(limit))
+		if err != nil {
+			return nil, err
+		}
+		t.closer = zr.IOReadCloser()
+		return zr, nil
+	case len(header) > tarMagicOffset && bytes.HasPrefix(header[tarMagicOffset:], tarMagic):
+		return br, nil
+	}
+
+	return nil, errors.New("unsupported format, expected a gzip or zstd compressed or uncompressed tarball")
+}
+
 // Next implements the storage.Iterator interface.
 // It iterates to the next policy or data file in the directory tree
 // and returns a storage.Update for the file.
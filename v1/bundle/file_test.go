@@ -47,6 +47,59 @@ func TestTarballLoader(t *testing.T) {
 	})
 }
 
+func TestTarballLoaderCompression(t *testing.T) {
+	files := make([][2]string, 0, len(archiveFiles))
+	for name, content := range archiveFiles {
+		files = append(files, [2]string{name, content})
+	}
+
+	for _, compression := range []string{CompressionGzip, CompressionZstd, CompressionNone} {
+		t.Run(compression, func(t *testing.T) {
+			var buf bytes.Buffer
+			var tw *archive.TarWriter
+			switch compression {
+			case CompressionGzip:
+				tw = archive.NewTarGzWriter(&buf)
+			case CompressionZstd:
+				var err error
+				if tw, err = archive.NewTarZstdWriter(&buf); err != nil {
+					t.Fatal(err)
+				}
+			case CompressionNone:
+				tw = archive.NewTarWriter(&buf)
+			}
+			for _, f := range files {
+				if err := tw.WriteFile(f[0], []byte(f[1])); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := tw.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			testLoader(t, NewTarballLoaderWithBaseURL(&buf, "/base"), "/base", archiveFiles)
+		})
+	}
+
+	for _, input := range []string{"", "not a tarball", "\x1f\x8bcorrupt"} {
+		_, err := NewTarballLoaderWithBaseURL(strings.NewReader(input), "").NextFile()
+		if err == nil || !strings.HasPrefix(err.Error(), "archive read failed") {
+			t.Fatalf("expected archive read error for %q but got %v", input, err)
+		}
+	}
+}
+
+func TestTarballLoaderZstdWindowLimit(t *testing.T) {
+	// A zstd frame header declaring a 256 MiB window, followed by an empty
+	// last raw block.
+	frame := append(slices.Clone(zstdMagic), 0x00, 0x90, 0x01, 0x00, 0x00)
+
+	_, err := NewTarballLoaderWithBaseURL(bytes.NewReader(frame), "").WithSizeLimitBytes(1 << 20).NextFile()
+	if err == nil || !strings.Contains(err.Error(), "window size exceeded") {
+		t.Fatalf("expected window size error but got %v", err)
+	}
+}
+
 func TestIterator(t *testing.T) {
 
 	files := make([][2]string, 0, len(archiveFiles))
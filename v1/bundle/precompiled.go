@@ -0,0 +1,368 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package bundle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/open-policy-agent/opa/internal/astbinary"
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/util"
+	"github.com/open-policy-agent/opa/v1/version"
+)
+
+// PrecompiledFile is the name of the file containing the precompiled modules
+// of a bundle.
+const PrecompiledFile = ".precompiled"
+
+const precompiledFormatVersion = 1
+
+var precompiledMagic = []byte("OPAP")
+
+// PrecompiledSkipStages are the compiler stages that are skipped when
+// activating a bundle from its precompiled modules. The modules have already
+// been rewritten and checked by these stages when the bundle was built. The
+// remaining stages build the rule tree, dependency graph, type environment and
+// indices, and check the modules against the data and other configuration of
+// the activating OPA.
+//
+// The stages are only skipped if the bundle is the only source of modules of
+// the compiler, i.e. when a single bundle is activated without other bundles,
+// modules from the store or external sources. Otherwise all stages run over the
+// precompiled modules, which still saves parsing them.
+var PrecompiledSkipStages = []ast.StageID{
+	ast.StageResolveRefs,
+	ast.StageRewriteRuleHeadRefs,
+	ast.StageCheckKeywordOverrides,
+	ast.StageCheckDuplicateImports,
+	ast.StageRemoveImports,
+	ast.StageRewriteLocalVars,
+	ast.StageRewriteTemplateStrings,
+	ast.StageCheckVoidCalls,
+	ast.StageRewritePrintCalls,
+	ast.StageRewriteExprTerms,
+	ast.StageRewriteRegoMetadataCalls,
+	ast.StageRewriteComprehensionTerms,
+	ast.StageRewriteRefsInHead,
+	ast.StageRewriteWithValues,
+	ast.StageCheckUndefinedFuncs,
+	ast.StageCheckSafetyRuleHeads,
+	ast.StageCheckSafetyRuleBodies,
+	ast.StageRewriteEquals,
+	ast.StageRewriteDynamicTerms,
+	ast.StageRewriteTestRulesForTracing,
+	ast.StageCheckRecursion,
+	ast.StageCheckDeprecatedBuiltins,
+}
+
+// precompiledHeader describes the precompiled modules. Precompiled modules are
+// only used if they were built by the same OPA version with the same
+// capabilities, from the same module sources, and activated with the same
+// print statement setting.
+type precompiledHeader struct {
+	OPAVersion            string              `json:"opa_version"`
+	Capabilities          string              `json:"capabilities"`
+	EnablePrintStatements bool                `json:"enable_print_statements"`
+	Modules               []precompiledModule `json:"modules"`
+	RewrittenVars         map[string]string   `json:"rewritten_vars,omitempty"`
+}
+
+type precompiledModule struct {
+	Path        string `json:"path"`
+	RegoVersion int    `json:"rego_version"`
+	Digest      string `json:"digest"`
+}
+
+// precompiledState records that the parsed modules of a bundle were loaded
+// from its precompiled modules, and how to parse them from source instead.
+type precompiledState struct {
+	enablePrintStatements bool
+	rewrittenVars         map[ast.Var]ast.Var
+	parserOptions         map[string]ast.ParserOptions // keyed by module path
+}
+
+// Precompile compiles the modules of the bundle and stores the result in
+// b.Precompiled, so that readers can load the compiled modules instead of
+// parsing and compiling the module sources. The modules must compile on their
+// own, without modules from other bundles.
+//
+// Modules are identified by the path they are written to by a Writer that
+// doesn't use module paths. Modules are parsed with the rego-version for their
+// file, defaulting to regoVersion, like the Reader does. Calls to print are
+// kept if enablePrintStatements is true and erased otherwise; the modules are
+// parsed from source again when the bundle is activated by a compiler with the
+// other setting. If capabilities is nil, the capabilities of this OPA version
+// are used; readers ignore the precompiled modules if their capabilities
+// differ.
+func (b *Bundle) Precompile(capabilities *ast.Capabilities, regoVersion ast.RegoVersion, enablePrintStatements bool) error {
+	if capabilities == nil {
+		capabilities = ast.CapabilitiesForThisVersion()
+	}
+
+	digest, err := capabilitiesDigest(capabilities)
+	if err != nil {
+		return err
+	}
+
+	header := precompiledHeader{
+		OPAVersion:            version.Version,
+		Capabilities:          digest,
+		EnablePrintStatements: enablePrintStatements,
+		Modules:               make([]precompiledModule, len(b.Modules)),
+	}
+
+	modules := make(map[string]*ast.Module, len(b.Modules))
+	for i, mf := range b.Modules {
+		path := util.WithPrefix(mf.URL, "/")
+		if _, ok := modules[path]; ok {
+			return fmt.Errorf("duplicate module path: %s", path)
+		}
+
+		v, err := b.RegoVersionForFile(path, b.RegoVersion(regoVersion))
+		if err != nil {
+			return err
+		}
+
+		// Parse the module again as it will be read, so that the locations in
+		// the compiled module are those the reader would produce.
+		popts := ast.ParserOptions{Capabilities: capabilities, RegoVersion: v, ProcessAnnotation: true}
+		if modules[path], err = ast.ParseModuleWithOpts(path, util.ByteSliceToString(mf.Raw), popts); err != nil {
+			return err
+		}
+
+		header.Modules[i] = precompiledModule{Path: path, RegoVersion: v.Int(), Digest: sourceDigest(mf.Raw)}
+	}
+
+	compiler := ast.NewCompiler().
+		WithCapabilities(capabilities).
+		WithEnablePrintStatements(enablePrintStatements)
+
+	if compiler.Compile(modules); compiler.Failed() {
+		return compiler.Errors
+	}
+
+	header.RewrittenVars = make(map[string]string, len(compiler.RewrittenVars))
+	for k, v := range compiler.RewrittenVars {
+		header.RewrittenVars[string(k)] = string(v)
+	}
+
+	bs, err := json.Marshal(header)
+	if err != nil {
+		return err
+	}
+
+	buf := make([]byte, 0, len(precompiledMagic)+binary.MaxVarintLen64+len(bs))
+	buf = append(buf, precompiledMagic...)
+	buf = binary.AppendUvarint(buf, precompiledFormatVersion)
+	buf = binary.AppendUvarint(buf, uint64(len(bs)))
+	buf = append(buf, bs...)
+
+	for i, mf := range b.Modules {
+		enc, err := astbinary.Marshal(compiler.Modules[header.Modules[i].Path], mf.Raw)
+		if err != nil {
+			return err
+		}
+		buf = binary.AppendUvarint(buf, uint64(len(enc)))
+		buf = append(buf, enc...)
+	}
+
+	b.Precompiled = buf
+	return nil
+}
+
+// IsPrecompiled returns true if the parsed modules of the bundle were loaded
+// from its precompiled modules, rather than parsed from source.
+func (b *Bundle) IsPrecompiled() bool {
+	return b.precompiled != nil
+}
+
+// loadPrecompiled sets the parsed modules from the precompiled modules of the
+// bundle. It returns false, leaving the modules untouched, if the precompiled
+// modules can't be used, e.g. because they were built by a different version
+// of OPA or with different capabilities, in which case the modules should be
+// parsed from source. The parser options and rego-versions are those the
+// modules would be parsed with.
+func (r *Reader) loadPrecompiled(b *Bundle, modules []ModuleFile, popts ast.ParserOptions, regoVersions []ast.RegoVersion) bool {
+	header, blobs, err := decodePrecompiled(b.Precompiled)
+	if err != nil || header.OPAVersion != version.Version || len(header.Modules) != len(modules) {
+		return false
+	}
+
+	capabilities := r.capabilities
+	if capabilities == nil {
+		capabilities = ast.CapabilitiesForThisVersion()
+	}
+	if digest, err := capabilitiesDigest(capabilities); err != nil || digest != header.Capabilities {
+		return false
+	}
+
+	index := make(map[string]int, len(header.Modules))
+	for i, m := range header.Modules {
+		index[m.Path] = i
+	}
+
+	parsed := make([]*ast.Module, len(modules))
+	g := &errgroup.Group{}
+
+	for i, mf := range modules {
+		j, ok := index[util.WithPrefix(mf.RelativePath, "/")]
+		if !ok {
+			return false
+		}
+		m := header.Modules[j]
+		if m.RegoVersion != regoVersions[i].Int() || m.Digest != sourceDigest(mf.Raw) {
+			return false
+		}
+
+		g.Go(func() error {
+			x, err := astbinary.Unmarshal(blobs[j], mf.Raw, mf.Path)
+			if err == nil {
+				parsed[i] = x.(*ast.Module)
+			}
+			return err
+		})
+	}
+
+	if g.Wait() != nil {
+		return false
+	}
+
+	for i := range modules {
+		modules[i].Parsed = parsed[i]
+	}
+
+	b.precompiled = &precompiledState{
+		enablePrintStatements: header.EnablePrintStatements,
+		rewrittenVars:         make(map[ast.Var]ast.Var, len(header.RewrittenVars)),
+		parserOptions:         make(map[string]ast.ParserOptions, len(modules)),
+	}
+	for k, v := range header.RewrittenVars {
+		b.precompiled.rewrittenVars[ast.Var(k)] = ast.Var(v)
+	}
+	for i, mf := range modules {
+		mpopts := popts
+		mpopts.RegoVersion = regoVersions[i]
+		b.precompiled.parserOptions[mf.Path] = mpopts
+	}
+
+	return true
+}
+
+func decodePrecompiled(bs []byte) (*precompiledHeader, [][]byte, error) {
+	errInvalid := errors.New("invalid precompiled modules")
+
+	if !bytes.HasPrefix(bs, precompiledMagic) {
+		return nil, nil, errInvalid
+	}
+	bs = bs[len(precompiledMagic):]
+
+	next := func() ([]byte, bool) {
+		n, k := binary.Uvarint(bs)
+		if k <= 0 || n > uint64(len(bs)-k) {
+			return nil, false
+		}
+		x := bs[k : k+int(n)]
+		bs = bs[k+int(n):]
+		return x, true
+	}
+
+	v, k := binary.Uvarint(bs)
+	if k <= 0 || v != precompiledFormatVersion {
+		return nil, nil, errInvalid
+	}
+	bs = bs[k:]
+
+	raw, ok := next()
+	if !ok {
+		return nil, nil, errInvalid
+	}
+
+	var header precompiledHeader
+	if err := json.Unmarshal(raw, &header); err != nil {
+		return nil, nil, err
+	}
+
+	blobs := make([][]byte, len(header.Modules))
+	for i := range blobs {
+		if blobs[i], ok = next(); !ok {
+			return nil, nil, errInvalid
+		}
+	}
+
+	return &header, blobs, nil
+}
+
+// isPrecompiledActivation returns true if the bundle is the only source of
+// modules for the compiler, and its modules were loaded from its precompiled
+// modules, so that compilation can skip the PrecompiledSkipStages.
+func isPrecompiledActivation(compiler *ast.Compiler, bundles map[string]*Bundle, extraModules map[string]*ast.Module, legacy bool) bool {
+	if legacy || len(bundles) != 1 || len(compiler.Modules) != 0 || len(extraModules) != 0 {
+		return false
+	}
+	for _, b := range bundles {
+		return b.IsPrecompiled()
+	}
+	return false
+}
+
+// reparsePrecompiled parses the modules of the bundles that were loaded from
+// precompiled modules built with a different print statement setting than the
+// compiler's from source, as their calls to print have already been kept or
+// erased. The bundles are no longer considered precompiled afterwards.
+func reparsePrecompiled(compiler *ast.Compiler, bundles map[string]*Bundle) error {
+	for _, b := range bundles {
+		if b.precompiled == nil || b.precompiled.enablePrintStatements == compiler.EnablePrintStatements() {
+			continue
+		}
+
+		for i, mf := range b.Modules {
+			popts, ok := b.precompiled.parserOptions[mf.Path]
+			if !ok {
+				return fmt.Errorf("no parser options for precompiled module: %s", mf.Path)
+			}
+			parsed, err := ast.ParseModuleWithOpts(mf.Path, util.ByteSliceToString(mf.Raw), popts)
+			if err != nil {
+				return err
+			}
+			b.Modules[i].Parsed = parsed
+		}
+
+		b.precompiled = nil
+	}
+	return nil
+}
+
+// addPrecompiledRewrittenVars adds the variables rewritten when precompiling
+// the bundles to the compiler, so that they can be reported by their original
+// names.
+func addPrecompiledRewrittenVars(compiler *ast.Compiler, bundles map[string]*Bundle) {
+	for _, b := range bundles {
+		if b.precompiled != nil {
+			maps.Copy(compiler.RewrittenVars, b.precompiled.rewrittenVars)
+		}
+	}
+}
+
+func capabilitiesDigest(c *ast.Capabilities) (string, error) {
+	bs, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return sourceDigest(bs), nil
+}
+
+func sourceDigest(bs []byte) string {
+	sum := sha256.Sum256(bs)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,382 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package bundle
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/open-policy-agent/opa/internal/file/archive"
+	"github.com/open-policy-agent/opa/internal/storage/mock"
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/metrics"
+	"github.com/open-policy-agent/opa/v1/storage"
+	"github.com/open-policy-agent/opa/v1/storage/inmem"
+)
+
+var precompiledTestFiles = [][2]string{
+	{"/.manifest", `{"roots": ["authz", "lib"], "rego_version": 1, "file_rego_versions": {"/legacy.rego": 0}}`},
+	{"/data.json", `{"lib": {"admins": ["alice"]}}`},
+	{"/authz/authz.rego", `# METADATA
+# title: Authz
+package authz
+
+import data.lib.admins
+
+# METADATA
+# entrypoint: true
+allow if {
+	some name in admins
+	name == input.user
+	print("allowed", name)
+}
+
+users := {u | some u in input.users; u != ""}
+`},
+	{"/legacy.rego", `package lib
+
+default deny = false
+
+deny {
+	input.user == "mallory"
+}
+`},
+}
+
+func TestPrecompiledBundleRoundTrip(t *testing.T) {
+	src := must(NewReader(archive.MustWriteTarGz(precompiledTestFiles)).WithProcessAnnotations(true).Read())(t)
+	if err := src.Precompile(nil, ast.RegoV1, true); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := NewWriter(&buf).Write(src); err != nil {
+		t.Fatal(err)
+	}
+
+	b := must(NewReader(&buf).WithProcessAnnotations(true).Read())(t)
+	if !b.IsPrecompiled() {
+		t.Fatal("expected bundle to be precompiled")
+	}
+
+	regular := must(NewReader(archive.MustWriteTarGz(precompiledTestFiles)).WithProcessAnnotations(true).Read())(t)
+	if regular.IsPrecompiled() {
+		t.Fatal("expected bundle not to be precompiled")
+	}
+
+	exp := activateTestBundle(t, &regular)
+	act := activateTestBundle(t, &b)
+
+	if len(exp.Modules) != len(act.Modules) {
+		t.Fatalf("expected %d modules but got %d", len(exp.Modules), len(act.Modules))
+	}
+	for name, mod := range exp.Modules {
+		if !mod.Equal(act.Modules[name]) {
+			t.Errorf("module %s: expected:\n%v\n\ngot:\n%v", name, mod, act.Modules[name])
+		}
+		if act.Modules[name].Package.Location.File != mod.Package.Location.File {
+			t.Errorf("module %s: expected file %q but got %q", name, mod.Package.Location.File, act.Modules[name].Package.Location.File)
+		}
+	}
+
+	for _, ref := range []string{"data.authz.allow", "data.authz.users", "data.lib.deny"} {
+		r := ast.MustParseRef(ref)
+		if e, a := exp.RuleTree.Find(r), act.RuleTree.Find(r); e == nil || a == nil || len(e.Values) != len(a.Values) {
+			t.Errorf("expected rule tree node %v to match", ref)
+		}
+		if e, a := exp.TypeEnv.GetByRef(r), act.TypeEnv.GetByRef(r); fmt.Sprint(e) != fmt.Sprint(a) {
+			t.Errorf("expected type %v for %v but got %v", e, ref, a)
+		}
+	}
+
+	if len(act.RewrittenVars) == 0 {
+		t.Fatal("expected rewritten vars to be set")
+	}
+	for k, v := range exp.RewrittenVars {
+		if act.RewrittenVars[k] != v {
+			t.Errorf("expected rewritten var %v to be %v but got %v", k, v, act.RewrittenVars[k])
+		}
+	}
+}
+
+func TestPrecompiledBundleFallback(t *testing.T) {
+	tests := []struct {
+		note   string
+		modify func(*Reader, *Bundle)
+	}{
+		{
+			note: "modified source",
+			modify: func(_ *Reader, b *Bundle) {
+				b.Modules[0].Raw = append(b.Modules[0].Raw, []byte("\nextra := 1\n")...)
+			},
+		},
+		{
+			note: "capabilities",
+			modify: func(r *Reader, _ *Bundle) {
+				caps := ast.CapabilitiesForThisVersion()
+				caps.Builtins = caps.Builtins[:len(caps.Builtins)-1]
+				r.WithCapabilities(caps)
+			},
+		},
+		{
+			note: "rego version",
+			modify: func(_ *Reader, b *Bundle) {
+				b.Manifest.FileRegoVersions = nil
+			},
+		},
+		{
+			note: "opa version",
+			modify: func(_ *Reader, b *Bundle) {
+				b.Precompiled = bytes.Replace(b.Precompiled, []byte(`"opa_version":"`), []byte(`"opa_version":"0`), 1)
+			},
+		},
+		{
+			note: "corrupt",
+			modify: func(_ *Reader, b *Bundle) {
+				b.Precompiled = b.Precompiled[:len(b.Precompiled)-10]
+			},
+		},
+		{
+			note: "annotations not processed",
+			modify: func(r *Reader, _ *Bundle) {
+				r.WithProcessAnnotations(false)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			files := precompiledTestFiles
+			if tc.note == "rego version" {
+				// Parse legacy.rego as v0 when precompiling, but as v1 when reading
+				// back in, by dropping the per-file override below.
+				files = [][2]string{precompiledTestFiles[0], precompiledTestFiles[1], precompiledTestFiles[2]}
+				files = append(files, [2]string{"/legacy.rego", "package lib\n\ndeny := false\n"})
+			}
+
+			b := must(NewReader(archive.MustWriteTarGz(files)).WithProcessAnnotations(true).Read())(t)
+			if err := b.Precompile(nil, ast.RegoV1, true); err != nil {
+				t.Fatal(err)
+			}
+
+			r := NewCustomReader(nil).WithProcessAnnotations(true)
+			tc.modify(r, &b)
+
+			var buf bytes.Buffer
+			if err := NewWriter(&buf).Write(b); err != nil {
+				t.Fatal(err)
+			}
+			r.loader = NewTarballLoaderWithBaseURL(&buf, "")
+
+			act := must(r.Read())(t)
+			if act.IsPrecompiled() {
+				t.Fatal("expected bundle not to be precompiled")
+			}
+			for _, mf := range act.Modules {
+				if mf.Parsed == nil {
+					t.Fatalf("expected module %v to be parsed", mf.Path)
+				}
+			}
+			activateTestBundle(t, &act)
+		})
+	}
+}
+
+func TestPrecompiledBundlePrintStatements(t *testing.T) {
+	for _, built := range []bool{true, false} {
+		for _, enabled := range []bool{true, false} {
+			t.Run(fmt.Sprintf("built=%v/enabled=%v", built, enabled), func(t *testing.T) {
+				src := must(NewReader(archive.MustWriteTarGz(precompiledTestFiles)).WithProcessAnnotations(true).Read())(t)
+				if err := src.Precompile(nil, ast.RegoV1, built); err != nil {
+					t.Fatal(err)
+				}
+
+				var buf bytes.Buffer
+				if err := NewWriter(&buf).Write(src); err != nil {
+					t.Fatal(err)
+				}
+
+				b := must(NewReader(&buf).WithProcessAnnotations(true).Read())(t)
+
+				store := mock.New()
+				txn := storage.NewTransactionOrDie(t.Context(), store, storage.WriteParams)
+				compiler := ast.NewCompiler().WithEnablePrintStatements(enabled)
+
+				err := Activate(&ActivateOpts{
+					Ctx:      t.Context(),
+					Store:    store,
+					Txn:      txn,
+					Compiler: compiler,
+					Metrics:  metrics.NoOp(),
+					Bundles:  map[string]*Bundle{"test": &b},
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if b.IsPrecompiled() != (built == enabled) {
+					t.Errorf("expected precompiled %v but got %v", built == enabled, b.IsPrecompiled())
+				}
+
+				mod := compiler.Modules["test/authz/authz.rego"]
+				if mod == nil {
+					t.Fatalf("expected module in %v", compiler.Modules)
+				}
+				if act := strings.Contains(mod.String(), "internal.print"); act != enabled {
+					t.Errorf("expected print calls %v but got:\n%v", enabled, mod)
+				}
+			})
+		}
+	}
+}
+
+func TestPrecompiledBundleSigned(t *testing.T) {
+	b := must(NewReader(archive.MustWriteTarGz(precompiledTestFiles)).WithProcessAnnotations(true).Read())(t)
+	if err := b.Precompile(nil, ast.RegoV1, true); err != nil {
+		t.Fatal(err)
+	}
+
+	sc := NewSigningConfig("secret", "HS256", "")
+	if err := b.GenerateSignature(sc, "foo", false); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := NewWriter(&buf).Write(b); err != nil {
+		t.Fatal(err)
+	}
+
+	tampered := bytes.Clone(buf.Bytes())
+
+	vc := NewVerificationConfig(map[string]*KeyConfig{"foo": {Key: "secret", Algorithm: "HS256"}}, "foo", "", nil)
+	act := must(NewReader(&buf).WithProcessAnnotations(true).WithBundleVerificationConfig(vc).Read())(t)
+	if !act.IsPrecompiled() {
+		t.Fatal("expected bundle to be precompiled")
+	}
+
+	// Replace the precompiled modules with those of another bundle.
+	other := must(NewReader(archive.MustWriteTarGz(precompiledTestFiles[:3])).WithProcessAnnotations(true).Read())(t)
+	if err := other.Precompile(nil, ast.RegoV1, true); err != nil {
+		t.Fatal(err)
+	}
+	files := readTarGzFiles(t, tampered)
+	for i := range files {
+		if files[i][0] == "/"+PrecompiledFile {
+			files[i][1] = string(other.Precompiled)
+		}
+	}
+
+	_, err := NewReader(archive.MustWriteTarGz(files)).WithBundleVerificationConfig(vc).Read()
+	if err == nil || !strings.Contains(err.Error(), PrecompiledFile) {
+		t.Fatalf("expected verification error for %s but got %v", PrecompiledFile, err)
+	}
+}
+
+func TestPrecompileErrors(t *testing.T) {
+	files := [][2]string{{"/x.rego", "package x\n\np := y"}}
+	b := must(NewReader(archive.MustWriteTarGz(files)).Read())(t)
+	if err := b.Precompile(nil, ast.RegoV1, true); err == nil || !strings.Contains(err.Error(), "var y is unsafe") {
+		t.Fatalf("expected compile error but got %v", err)
+	}
+}
+
+func activateTestBundle(t testing.TB, b *Bundle) *ast.Compiler {
+	t.Helper()
+
+	store := mock.New()
+	txn := storage.NewTransactionOrDie(t.Context(), store, storage.WriteParams)
+	compiler := ast.NewCompiler().WithEnablePrintStatements(true)
+
+	err := Activate(&ActivateOpts{
+		Ctx:      t.Context(),
+		Store:    store,
+		Txn:      txn,
+		Compiler: compiler,
+		Metrics:  metrics.NoOp(),
+		Bundles:  map[string]*Bundle{"test": b},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return compiler
+}
+
+func readTarGzFiles(t testing.TB, bs []byte) [][2]string {
+	t.Helper()
+
+	loader := NewTarballLoaderWithBaseURL(bytes.NewReader(bs), "")
+	var files [][2]string
+	for {
+		f, err := loader.NextFile()
+		if err != nil {
+			break
+		}
+		var buf bytes.Buffer
+		if _, err := buf.ReadFrom(f.reader); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, [2]string{f.Path(), buf.String()})
+	}
+	return files
+}
+
+// BenchmarkActivatePrecompiled compares the time and memory spent reading and
+// activating a bundle with and without precompiled modules.
+func BenchmarkActivatePrecompiled(b *testing.B) {
+	for _, n := range []int{100, 1000} {
+		var sb strings.Builder
+		sb.WriteString("package bench\n\n")
+		for i := range n {
+			fmt.Fprintf(&sb, "r%d if {\n\tsome x in input.items\n\tx.id == %d\n\tstartswith(x.name, \"n%d\")\n\tcount([y | some y in x.tags; y != \"\"]) > 0\n}\n\n", i, i, i)
+		}
+		files := [][2]string{{"/bench.rego", sb.String()}}
+
+		bundle := must(NewReader(archive.MustWriteTarGz(files)).Read())(b)
+		if err := bundle.Precompile(nil, ast.RegoV1, true); err != nil {
+			b.Fatal(err)
+		}
+
+		var buf bytes.Buffer
+		if err := NewWriter(&buf).Write(bundle); err != nil {
+			b.Fatal(err)
+		}
+		regular := archive.MustWriteTarGz(files).Bytes()
+		precompiled := buf.Bytes()
+
+		for _, tc := range []struct {
+			name string
+			bs   []byte
+		}{{"regular", regular}, {"precompiled", precompiled}} {
+			name, bs := tc.name, tc.bs
+			b.Run(fmt.Sprintf("%s/%d", name, n), func(b *testing.B) {
+				b.ReportAllocs()
+				for b.Loop() {
+					bundle := must(NewReader(bytes.NewReader(bs)).WithProcessAnnotations(true).Read())(b)
+					if bundle.IsPrecompiled() != (name == "precompiled") {
+						b.Fatal("unexpected precompiled state")
+					}
+
+					store := inmem.New()
+					txn := storage.NewTransactionOrDie(b.Context(), store, storage.WriteParams)
+					err := Activate(&ActivateOpts{
+						Ctx:      b.Context(),
+						Store:    store,
+						Txn:      txn,
+						Compiler: ast.NewCompiler(),
+						Metrics:  metrics.NoOp(),
+						Bundles:  map[string]*Bundle{"bench": &bundle},
+					})
+					if err != nil {
+						b.Fatal(err)
+					}
+					store.Abort(b.Context(), txn)
+				}
+			})
+		}
+	}
+}
@@ -57,6 +57,29 @@ func ManifestToProto(m *Manifest) (*pb.Manifest, error) {
 		}
 		out.Metadata = s
 	}
+	if m.Version != "" {
+		out.Version = new(m.Version)
+	}
+	if len(m.Dependencies) > 0 {
+		out.Dependencies = make([]*pb.Dependency, len(m.Dependencies))
+		for i, d := range m.Dependencies {
+			out.Dependencies[i] = &pb.Dependency{
+				Name:    new(d.Name),
+				Version: new(d.Version),
+			}
+		}
+	}
+	if c := m.Capabilities; c != nil {
+		out.Capabilities = &pb.Capabilities{DenyBuiltins: c.DenyBuiltins}
+		if c.Builtins != nil {
+			out.Capabilities.Builtins = *c.Builtins
+			out.Capabilities.BuiltinsSet = new(true)
+		}
+		if c.Features != nil {
+			out.Capabilities.Features = *c.Features
+			out.Capabilities.FeaturesSet = new(true)
+		}
+	}
 	return out, nil
 }
 
@@ -238,6 +261,25 @@ func ManifestFromProto(m *pb.Manifest) (*Manifest, error) {
 	if m.Metadata != nil {
 		out.Metadata = m.Metadata.AsMap()
 	}
+	out.Version = m.GetVersion()
+	if len(m.Dependencies) > 0 {
+		out.Dependencies = make([]Dependency, len(m.Dependencies))
+		for i, d := range m.Dependencies {
+			out.Dependencies[i] = Dependency{
+				Name:    d.GetName(),
+				Version: d.GetVersion(),
+			}
+		}
+	}
+	if c := m.GetCapabilities(); c != nil {
+		out.Capabilities = &Capabilities{DenyBuiltins: c.GetDenyBuiltins()}
+		if c.GetBuiltinsSet() {
+			out.Capabilities.Builtins = new(append([]string{}, c.GetBuiltins()...))
+		}
+		if c.GetFeaturesSet() {
+			out.Capabilities.Features = new(append([]string{}, c.GetFeatures()...))
+		}
+	}
 	return out, nil
 }
 
@@ -61,6 +61,9 @@ func TestManifestProtoRoundTrip(t *testing.T) {
 		RegoVersion:      &regoV1,
 		FileRegoVersions: map[string]int{"a.rego": 1},
 		Metadata:         map[string]any{"k": "v", "n": float64(7)},
+		Version:          "1.2.3",
+		Dependencies:     []Dependency{{Name: "platform", Version: "^2.3"}, {Name: "shared"}},
+		Capabilities:     &Capabilities{Builtins: &[]string{}, DenyBuiltins: []string{"http.send"}},
 	}
 
 	pbManifest, err := ManifestToProto(m)
@@ -83,6 +86,9 @@ func TestManifestProtoRoundTrip(t *testing.T) {
 	if !m.Equal(*got) {
 		t.Fatal("manifest semantic equality failed after round trip")
 	}
+	if got.Capabilities.Builtins == nil || got.Capabilities.Features != nil {
+		t.Fatalf("capabilities round-trip lost nil-vs-empty: %+v", got.Capabilities)
+	}
 	if !maps.Equal(m.FileRegoVersions, got.FileRegoVersions) {
 		t.Fatalf("file rego versions: want %v, got %v", m.FileRegoVersions, got.FileRegoVersions)
 	}
@@ -364,6 +364,7 @@ type ActivateOpts struct {
 	AuthorizationDecisionRef ast.Ref
 	ParserOptions            ast.ParserOptions
 	Plugin                   string
+	Capabilities             map[string]*Capabilities // Optional, capabilities restrictions keyed by bundle name
 
 	legacy bool
 }
@@ -532,7 +533,7 @@ func activateBundles(opts *ActivateOpts) error {
 	maps.Copy(remainingAndExtra, remaining)
 	maps.Copy(remainingAndExtra, opts.ExtraModules)
 
-	err = compileModules(opts.Compiler, opts.Metrics, snapshotBundles, remainingAndExtra, opts.legacy, opts.AuthorizationDecisionRef, opts.ExternalSources)
+	err = compileModules(opts.Compiler, opts.Metrics, snapshotBundles, remainingAndExtra, opts.legacy, opts.AuthorizationDecisionRef, opts.ExternalSources, opts.Capabilities)
 	if err != nil {
 		return err
 	}
@@ -959,7 +960,7 @@ func writeData(ctx context.Context, store storage.Store, txn storage.Transaction
 	return nil
 }
 
-func compileModules(compiler *ast.Compiler, m metrics.Metrics, bundles map[string]*Bundle, extraModules map[string]*ast.Module, legacy bool, authorizationDecisionRef ast.Ref, externalSources *util.HasherMap[ast.Ref, ast.ExternalRuleSource]) error {
+func compileModules(compiler *ast.Compiler, m metrics.Metrics, bundles map[string]*Bundle, extraModules map[string]*ast.Module, legacy bool, authorizationDecisionRef ast.Ref, externalSources *util.HasherMap[ast.Ref, ast.ExternalRuleSource], restrictions map[string]*Capabilities) error {
 	m.Timer(metrics.RegoModuleCompile).Start()
 	defer m.Timer(metrics.RegoModuleCompile).Stop()
 
@@ -979,6 +980,10 @@ func compileModules(compiler *ast.Compiler, m metrics.Metrics, bundles map[strin
 	// preserve any modules passed in from the store
 	maps.Copy(modules, extraModules)
 
+	if err := reparsePrecompiled(compiler, bundles); err != nil {
+		return err
+	}
+
 	// include all the new bundle modules
 	for bundleName, b := range bundles {
 		if legacy {
@@ -990,10 +995,22 @@ func compileModules(compiler *ast.Compiler, m metrics.Metrics, bundles map[strin
 		}
 	}
 
+	// Modules loaded from a precompiled bundle have already been rewritten and
+	// checked, so only the remaining stages need to run if nothing else is
+	// compiled with them.
+	if externalSources == nil && isPrecompiledActivation(compiler, bundles, extraModules, legacy) {
+		compiler = compiler.WithSkipStages(PrecompiledSkipStages...)
+	}
+	addPrecompiledRewrittenVars(compiler, bundles)
+
 	if compiler.Compile(modules); compiler.Failed() {
 		return compiler.Errors
 	}
 
+	if err := checkCapabilities(compiler, bundles, restrictions, legacy); err != nil {
+		return err
+	}
+
 	if authorizationDecisionRef.Equal(ast.InternedEmptyRefValue) {
 		return nil
 	}
@@ -44,7 +44,13 @@ type Manifest struct {
 	Metadata *structpb.Struct `protobuf:"bytes,6,opt,name=metadata" json:"metadata,omitempty"`
 	// True if `bundle.Manifest.Roots` was non-nil. `repeated string` can't
 	// distinguish nil (default to [""]) from explicit-empty (owns no paths).
-	RootsSet      *bool `protobuf:"varint,7,opt,name=roots_set,json=rootsSet" json:"roots_set,omitempty"`
+	RootsSet *bool `protobuf:"varint,7,opt,name=roots_set,json=rootsSet" json:"roots_set,omitempty"`
+	// Semantic version of the bundle.
+	Version *string `protobuf:"bytes,8,opt,name=version" json:"version,omitempty"`
+	// Bundles this bundle depends on.
+	Dependencies []*Dependency `protobuf:"bytes,9,rep,name=dependencies" json:"dependencies,omitempty"`
+	// Restrictions on the capabilities the bundle's policies may use.
+	Capabilities  *Capabilities `protobuf:"bytes,10,opt,name=capabilities" json:"capabilities,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -128,6 +134,165 @@ func (x *Manifest) GetRootsSet() bool {
 	return false
 }
 
+func (x *Manifest) GetVersion() string {
+	if x != nil && x.Version != nil {
+		return *x.Version
+	}
+	return ""
+}
+
+func (x *Manifest) GetDependencies() []*Dependency {
+	if x != nil {
+		return x.Dependencies
+	}
+	return nil
+}
+
+func (x *Manifest) GetCapabilities() *Capabilities {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
+// Capabilities mirrors `bundle.Capabilities` in v1/bundle/capabilities.go.
+type Capabilities struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Built-in functions that may be called. See `builtins_set`.
+	Builtins []string `protobuf:"bytes,1,rep,name=builtins" json:"builtins,omitempty"`
+	// Built-in functions that may not be called.
+	DenyBuiltins []string `protobuf:"bytes,2,rep,name=deny_builtins,json=denyBuiltins" json:"deny_builtins,omitempty"`
+	// Language features that may be used. See `features_set`.
+	Features []string `protobuf:"bytes,3,rep,name=features" json:"features,omitempty"`
+	// True if `bundle.Capabilities.Builtins` was non-nil, i.e. only the
+	// listed built-in functions may be called.
+	BuiltinsSet *bool `protobuf:"varint,4,opt,name=builtins_set,json=builtinsSet" json:"builtins_set,omitempty"`
+	// True if `bundle.Capabilities.Features` was non-nil.
+	FeaturesSet   *bool `protobuf:"varint,5,opt,name=features_set,json=featuresSet" json:"features_set,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Capabilities) Reset() {
+	*x = Capabilities{}
+	mi := &file_v1_bundle_manifest_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Capabilities) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Capabilities) ProtoMessage() {}
+
+func (x *Capabilities) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_bundle_manifest_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Capabilities.ProtoReflect.Descriptor instead.
+func (*Capabilities) Descriptor() ([]byte, []int) {
+	return file_v1_bundle_manifest_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Capabilities) GetBuiltins() []string {
+	if x != nil {
+		return x.Builtins
+	}
+	return nil
+}
+
+func (x *Capabilities) GetDenyBuiltins() []string {
+	if x != nil {
+		return x.DenyBuiltins
+	}
+	return nil
+}
+
+func (x *Capabilities) GetFeatures() []string {
+	if x != nil {
+		return x.Features
+	}
+	return nil
+}
+
+func (x *Capabilities) GetBuiltinsSet() bool {
+	if x != nil && x.BuiltinsSet != nil {
+		return *x.BuiltinsSet
+	}
+	return false
+}
+
+func (x *Capabilities) GetFeaturesSet() bool {
+	if x != nil && x.FeaturesSet != nil {
+		return *x.FeaturesSet
+	}
+	return false
+}
+
+// Dependency mirrors `bundle.Dependency` in v1/bundle/dependencies.go.
+type Dependency struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Name of the required bundle.
+	Name *string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	// Semver range the required bundle's version must satisfy.
+	Version       *string `protobuf:"bytes,2,opt,name=version" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Dependency) Reset() {
+	*x = Dependency{}
+	mi := &file_v1_bundle_manifest_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Dependency) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Dependency) ProtoMessage() {}
+
+func (x *Dependency) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_bundle_manifest_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Dependency.ProtoReflect.Descriptor instead.
+func (*Dependency) Descriptor() ([]byte, []int) {
+	return file_v1_bundle_manifest_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Dependency) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *Dependency) GetVersion() string {
+	if x != nil && x.Version != nil {
+		return *x.Version
+	}
+	return ""
+}
+
 // WasmResolver mirrors `bundle.WasmResolver` in v1/bundle/bundle.go.
 type WasmResolver struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -143,7 +308,7 @@ type WasmResolver struct {
 
 func (x *WasmResolver) Reset() {
 	*x = WasmResolver{}
-	mi := &file_v1_bundle_manifest_proto_msgTypes[1]
+	mi := &file_v1_bundle_manifest_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -155,7 +320,7 @@ func (x *WasmResolver) String() string {
 func (*WasmResolver) ProtoMessage() {}
 
 func (x *WasmResolver) ProtoReflect() protoreflect.Message {
-	mi := &file_v1_bundle_manifest_proto_msgTypes[1]
+	mi := &file_v1_bundle_manifest_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -168,7 +333,7 @@ func (x *WasmResolver) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WasmResolver.ProtoReflect.Descriptor instead.
 func (*WasmResolver) Descriptor() ([]byte, []int) {
-	return file_v1_bundle_manifest_proto_rawDescGZIP(), []int{1}
+	return file_v1_bundle_manifest_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *WasmResolver) GetEntrypoint() string {
@@ -215,7 +380,7 @@ type Annotations struct {
 
 func (x *Annotations) Reset() {
 	*x = Annotations{}
-	mi := &file_v1_bundle_manifest_proto_msgTypes[2]
+	mi := &file_v1_bundle_manifest_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -227,7 +392,7 @@ func (x *Annotations) String() string {
 func (*Annotations) ProtoMessage() {}
 
 func (x *Annotations) ProtoReflect() protoreflect.Message {
-	mi := &file_v1_bundle_manifest_proto_msgTypes[2]
+	mi := &file_v1_bundle_manifest_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -240,7 +405,7 @@ func (x *Annotations) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Annotations.ProtoReflect.Descriptor instead.
 func (*Annotations) Descriptor() ([]byte, []int) {
-	return file_v1_bundle_manifest_proto_rawDescGZIP(), []int{2}
+	return file_v1_bundle_manifest_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *Annotations) GetScope() string {
@@ -346,7 +511,7 @@ type SchemaAnnotation struct {
 
 func (x *SchemaAnnotation) Reset() {
 	*x = SchemaAnnotation{}
-	mi := &file_v1_bundle_manifest_proto_msgTypes[3]
+	mi := &file_v1_bundle_manifest_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -358,7 +523,7 @@ func (x *SchemaAnnotation) String() string {
 func (*SchemaAnnotation) ProtoMessage() {}
 
 func (x *SchemaAnnotation) ProtoReflect() protoreflect.Message {
-	mi := &file_v1_bundle_manifest_proto_msgTypes[3]
+	mi := &file_v1_bundle_manifest_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -371,7 +536,7 @@ func (x *SchemaAnnotation) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SchemaAnnotation.ProtoReflect.Descriptor instead.
 func (*SchemaAnnotation) Descriptor() ([]byte, []int) {
-	return file_v1_bundle_manifest_proto_rawDescGZIP(), []int{3}
+	return file_v1_bundle_manifest_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *SchemaAnnotation) GetPath() string {
@@ -407,7 +572,7 @@ type CompileAnnotation struct {
 
 func (x *CompileAnnotation) Reset() {
 	*x = CompileAnnotation{}
-	mi := &file_v1_bundle_manifest_proto_msgTypes[4]
+	mi := &file_v1_bundle_manifest_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -419,7 +584,7 @@ func (x *CompileAnnotation) String() string {
 func (*CompileAnnotation) ProtoMessage() {}
 
 func (x *CompileAnnotation) ProtoReflect() protoreflect.Message {
-	mi := &file_v1_bundle_manifest_proto_msgTypes[4]
+	mi := &file_v1_bundle_manifest_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -432,7 +597,7 @@ func (x *CompileAnnotation) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CompileAnnotation.ProtoReflect.Descriptor instead.
 func (*CompileAnnotation) Descriptor() ([]byte, []int) {
-	return file_v1_bundle_manifest_proto_rawDescGZIP(), []int{4}
+	return file_v1_bundle_manifest_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *CompileAnnotation) GetUnknowns() []string {
@@ -460,7 +625,7 @@ type AuthorAnnotation struct {
 
 func (x *AuthorAnnotation) Reset() {
 	*x = AuthorAnnotation{}
-	mi := &file_v1_bundle_manifest_proto_msgTypes[5]
+	mi := &file_v1_bundle_manifest_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -472,7 +637,7 @@ func (x *AuthorAnnotation) String() string {
 func (*AuthorAnnotation) ProtoMessage() {}
 
 func (x *AuthorAnnotation) ProtoReflect() protoreflect.Message {
-	mi := &file_v1_bundle_manifest_proto_msgTypes[5]
+	mi := &file_v1_bundle_manifest_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -485,7 +650,7 @@ func (x *AuthorAnnotation) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AuthorAnnotation.ProtoReflect.Descriptor instead.
 func (*AuthorAnnotation) Descriptor() ([]byte, []int) {
-	return file_v1_bundle_manifest_proto_rawDescGZIP(), []int{5}
+	return file_v1_bundle_manifest_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *AuthorAnnotation) GetName() string {
@@ -514,7 +679,7 @@ type RelatedResourceAnnotation struct {
 
 func (x *RelatedResourceAnnotation) Reset() {
 	*x = RelatedResourceAnnotation{}
-	mi := &file_v1_bundle_manifest_proto_msgTypes[6]
+	mi := &file_v1_bundle_manifest_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -526,7 +691,7 @@ func (x *RelatedResourceAnnotation) String() string {
 func (*RelatedResourceAnnotation) ProtoMessage() {}
 
 func (x *RelatedResourceAnnotation) ProtoReflect() protoreflect.Message {
-	mi := &file_v1_bundle_manifest_proto_msgTypes[6]
+	mi := &file_v1_bundle_manifest_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -539,7 +704,7 @@ func (x *RelatedResourceAnnotation) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RelatedResourceAnnotation.ProtoReflect.Descriptor instead.
 func (*RelatedResourceAnnotation) Descriptor() ([]byte, []int) {
-	return file_v1_bundle_manifest_proto_rawDescGZIP(), []int{6}
+	return file_v1_bundle_manifest_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *RelatedResourceAnnotation) GetRef() string {
@@ -573,7 +738,7 @@ type Location struct {
 
 func (x *Location) Reset() {
 	*x = Location{}
-	mi := &file_v1_bundle_manifest_proto_msgTypes[7]
+	mi := &file_v1_bundle_manifest_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -585,7 +750,7 @@ func (x *Location) String() string {
 func (*Location) ProtoMessage() {}
 
 func (x *Location) ProtoReflect() protoreflect.Message {
-	mi := &file_v1_bundle_manifest_proto_msgTypes[7]
+	mi := &file_v1_bundle_manifest_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -598,7 +763,7 @@ func (x *Location) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Location.ProtoReflect.Descriptor instead.
 func (*Location) Descriptor() ([]byte, []int) {
-	return file_v1_bundle_manifest_proto_rawDescGZIP(), []int{7}
+	return file_v1_bundle_manifest_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *Location) GetFile() string {
@@ -626,7 +791,7 @@ var File_v1_bundle_manifest_proto protoreflect.FileDescriptor
 
 const file_v1_bundle_manifest_proto_rawDesc = "" +
 	"\n" +
-	"\x18v1/bundle/manifest.proto\x12\ropa.bundle.v1\x1a\x1cgoogle/protobuf/struct.proto\"\x84\x03\n" +
+	"\x18v1/bundle/manifest.proto\x12\ropa.bundle.v1\x1a\x1cgoogle/protobuf/struct.proto\"\x9e\x04\n" +
 	"\bManifest\x12\x1a\n" +
 	"\brevision\x18\x01 \x01(\tR\brevision\x12\x14\n" +
 	"\x05roots\x18\x02 \x03(\tR\x05roots\x12/\n" +
@@ -634,10 +799,24 @@ const file_v1_bundle_manifest_proto_rawDesc = "" +
 	"\frego_version\x18\x04 \x01(\x05R\vregoVersion\x12[\n" +
 	"\x12file_rego_versions\x18\x05 \x03(\v2-.opa.bundle.v1.Manifest.FileRegoVersionsEntryR\x10fileRegoVersions\x123\n" +
 	"\bmetadata\x18\x06 \x01(\v2\x17.google.protobuf.StructR\bmetadata\x12\x1b\n" +
-	"\troots_set\x18\a \x01(\bR\brootsSet\x1aC\n" +
+	"\troots_set\x18\a \x01(\bR\brootsSet\x12\x18\n" +
+	"\aversion\x18\b \x01(\tR\aversion\x12=\n" +
+	"\fdependencies\x18\t \x03(\v2\x19.opa.bundle.v1.DependencyR\fdependencies\x12?\n" +
+	"\fcapabilities\x18\n" +
+	" \x01(\v2\x1b.opa.bundle.v1.CapabilitiesR\fcapabilities\x1aC\n" +
 	"\x15FileRegoVersionsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\"\x84\x01\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\"\xb1\x01\n" +
+	"\fCapabilities\x12\x1a\n" +
+	"\bbuiltins\x18\x01 \x03(\tR\bbuiltins\x12#\n" +
+	"\rdeny_builtins\x18\x02 \x03(\tR\fdenyBuiltins\x12\x1a\n" +
+	"\bfeatures\x18\x03 \x03(\tR\bfeatures\x12!\n" +
+	"\fbuiltins_set\x18\x04 \x01(\bR\vbuiltinsSet\x12!\n" +
+	"\ffeatures_set\x18\x05 \x01(\bR\vfeaturesSet\":\n" +
+	"\n" +
+	"Dependency\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion\"\x84\x01\n" +
 	"\fWasmResolver\x12\x1e\n" +
 	"\n" +
 	"entrypoint\x18\x01 \x01(\tR\n" +
@@ -692,38 +871,42 @@ func file_v1_bundle_manifest_proto_rawDescGZIP() []byte {
 	return file_v1_bundle_manifest_proto_rawDescData
 }
 
-var file_v1_bundle_manifest_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_v1_bundle_manifest_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
 var file_v1_bundle_manifest_proto_goTypes = []any{
 	(*Manifest)(nil),                  // 0: opa.bundle.v1.Manifest
-	(*WasmResolver)(nil),              // 1: opa.bundle.v1.WasmResolver
-	(*Annotations)(nil),               // 2: opa.bundle.v1.Annotations
-	(*SchemaAnnotation)(nil),          // 3: opa.bundle.v1.SchemaAnnotation
-	(*CompileAnnotation)(nil),         // 4: opa.bundle.v1.CompileAnnotation
-	(*AuthorAnnotation)(nil),          // 5: opa.bundle.v1.AuthorAnnotation
-	(*RelatedResourceAnnotation)(nil), // 6: opa.bundle.v1.RelatedResourceAnnotation
-	(*Location)(nil),                  // 7: opa.bundle.v1.Location
-	nil,                               // 8: opa.bundle.v1.Manifest.FileRegoVersionsEntry
-	(*structpb.Struct)(nil),           // 9: google.protobuf.Struct
-	(*structpb.Value)(nil),            // 10: google.protobuf.Value
+	(*Capabilities)(nil),              // 1: opa.bundle.v1.Capabilities
+	(*Dependency)(nil),                // 2: opa.bundle.v1.Dependency
+	(*WasmResolver)(nil),              // 3: opa.bundle.v1.WasmResolver
+	(*Annotations)(nil),               // 4: opa.bundle.v1.Annotations
+	(*SchemaAnnotation)(nil),          // 5: opa.bundle.v1.SchemaAnnotation
+	(*CompileAnnotation)(nil),         // 6: opa.bundle.v1.CompileAnnotation
+	(*AuthorAnnotation)(nil),          // 7: opa.bundle.v1.AuthorAnnotation
+	(*RelatedResourceAnnotation)(nil), // 8: opa.bundle.v1.RelatedResourceAnnotation
+	(*Location)(nil),                  // 9: opa.bundle.v1.Location
+	nil,                               // 10: opa.bundle.v1.Manifest.FileRegoVersionsEntry
+	(*structpb.Struct)(nil),           // 11: google.protobuf.Struct
+	(*structpb.Value)(nil),            // 12: google.protobuf.Value
 }
 var file_v1_bundle_manifest_proto_depIdxs = []int32{
-	1,  // 0: opa.bundle.v1.Manifest.wasm:type_name -> opa.bundle.v1.WasmResolver
-	8,  // 1: opa.bundle.v1.Manifest.file_rego_versions:type_name -> opa.bundle.v1.Manifest.FileRegoVersionsEntry
-	9,  // 2: opa.bundle.v1.Manifest.metadata:type_name -> google.protobuf.Struct
-	2,  // 3: opa.bundle.v1.WasmResolver.annotations:type_name -> opa.bundle.v1.Annotations
-	6,  // 4: opa.bundle.v1.Annotations.related_resources:type_name -> opa.bundle.v1.RelatedResourceAnnotation
-	5,  // 5: opa.bundle.v1.Annotations.authors:type_name -> opa.bundle.v1.AuthorAnnotation
-	3,  // 6: opa.bundle.v1.Annotations.schemas:type_name -> opa.bundle.v1.SchemaAnnotation
-	4,  // 7: opa.bundle.v1.Annotations.compile:type_name -> opa.bundle.v1.CompileAnnotation
-	9,  // 8: opa.bundle.v1.Annotations.custom:type_name -> google.protobuf.Struct
-	9,  // 9: opa.bundle.v1.Annotations.labels:type_name -> google.protobuf.Struct
-	7,  // 10: opa.bundle.v1.Annotations.location:type_name -> opa.bundle.v1.Location
-	10, // 11: opa.bundle.v1.SchemaAnnotation.definition:type_name -> google.protobuf.Value
-	12, // [12:12] is the sub-list for method output_type
-	12, // [12:12] is the sub-list for method input_type
-	12, // [12:12] is the sub-list for extension type_name
-	12, // [12:12] is the sub-list for extension extendee
-	0,  // [0:12] is the sub-list for field type_name
+	3,  // 0: opa.bundle.v1.Manifest.wasm:type_name -> opa.bundle.v1.WasmResolver
+	10, // 1: opa.bundle.v1.Manifest.file_rego_versions:type_name -> opa.bundle.v1.Manifest.FileRegoVersionsEntry
+	11, // 2: opa.bundle.v1.Manifest.metadata:type_name -> google.protobuf.Struct
+	2,  // 3: opa.bundle.v1.Manifest.dependencies:type_name -> opa.bundle.v1.Dependency
+	1,  // 4: opa.bundle.v1.Manifest.capabilities:type_name -> opa.bundle.v1.Capabilities
+	4,  // 5: opa.bundle.v1.WasmResolver.annotations:type_name -> opa.bundle.v1.Annotations
+	8,  // 6: opa.bundle.v1.Annotations.related_resources:type_name -> opa.bundle.v1.RelatedResourceAnnotation
+	7,  // 7: opa.bundle.v1.Annotations.authors:type_name -> opa.bundle.v1.AuthorAnnotation
+	5,  // 8: opa.bundle.v1.Annotations.schemas:type_name -> opa.bundle.v1.SchemaAnnotation
+	6,  // 9: opa.bundle.v1.Annotations.compile:type_name -> opa.bundle.v1.CompileAnnotation
+	11, // 10: opa.bundle.v1.Annotations.custom:type_name -> google.protobuf.Struct
+	11, // 11: opa.bundle.v1.Annotations.labels:type_name -> google.protobuf.Struct
+	9,  // 12: opa.bundle.v1.Annotations.location:type_name -> opa.bundle.v1.Location
+	12, // 13: opa.bundle.v1.SchemaAnnotation.definition:type_name -> google.protobuf.Value
+	14, // [14:14] is the sub-list for method output_type
+	14, // [14:14] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
 }
 
 func init() { file_v1_bundle_manifest_proto_init() }
@@ -737,7 +920,7 @@ func file_v1_bundle_manifest_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_v1_bundle_manifest_proto_rawDesc), len(file_v1_bundle_manifest_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   9,
+			NumMessages:   11,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
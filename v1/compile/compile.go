@@ -94,6 +94,8 @@ type Compiler struct {
 	bsc                          *bundle.SigningConfig      // represents the key configuration used to generate a signed bundle
 	keyID                        string                     // represents the name of the default key used to verify a signed bundle
 	enableBundleLazyLoadingMode  bool                       // bundle lazy loading mode
+	precompile                   bool                       // whether to include precompiled modules in the output bundle
+	compression                  string                     // compression format of the output bundle archive
 	metadata                     *map[string]any            // represents additional data included in .manifest file
 	fsys                         fs.FS                      // file system to use when loading paths
 	ns                           string
@@ -250,6 +252,24 @@ func (c *Compiler) WithBundleLazyLoadingMode(mode bool) *Compiler {
 	return c
 }
 
+// WithPrecompile sets whether to include precompiled modules in the output
+// bundle. Precompiled modules are only used by OPAs of the same version and
+// with the same capabilities as the compiler; other OPAs compile the modules
+// from source. Calls to print are kept in the precompiled modules if print
+// statements are enabled (see WithEnablePrintStatements), and the modules are
+// compiled from source by OPAs with the other setting.
+func (c *Compiler) WithPrecompile(yes bool) *Compiler {
+	c.precompile = yes
+	return c
+}
+
+// WithCompression sets the compression format of the output bundle archive,
+// one of bundle.Compressions. The archive is gzip compressed by default.
+func (c *Compiler) WithCompression(compression string) *Compiler {
+	c.compression = compression
+	return c
+}
+
 // WithCapabilities sets the capabilities to use while checking policies.
 func (c *Compiler) WithCapabilities(capabilities *ast.Capabilities) *Compiler {
 	c.capabilities = capabilities
@@ -456,6 +476,12 @@ func (c *Compiler) Build(ctx context.Context) error {
 		return err
 	}
 
+	if c.precompile {
+		if err := c.bundle.Precompile(c.capabilities, c.regoVersion, c.enablePrintStatements); err != nil {
+			return err
+		}
+	}
+
 	if c.bsc != nil {
 		if err := c.bundle.GenerateSignature(c.bsc, c.keyID, false); err != nil {
 			return err
@@ -466,7 +492,7 @@ func (c *Compiler) Build(ctx context.Context) error {
 		return nil
 	}
 
-	return bundle.NewWriter(*c.output).Write(*c.bundle)
+	return bundle.NewWriter(*c.output).WithCompression(c.compression).Write(*c.bundle)
 }
 
 func (c *Compiler) init() error {
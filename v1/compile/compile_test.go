@@ -1232,6 +1232,50 @@ func TestCompilerOptimizationL1(t *testing.T) {
 	}
 }
 
+func TestCompilerOptimizationKeepsDecisionLogAnnotations(t *testing.T) {
+
+	files := map[string]string{
+		"test.rego": `
+			package test
+
+			default p := false
+			p if {
+				opa.decision_log.annotate("role", data.role)
+				input.x = data.foo
+			}`,
+		"data.json": `
+			{"foo": 1, "role": "admin"}`,
+	}
+
+	test.WithTestFS(files, true, func(root string, fsys fs.FS) {
+
+		compiler := New().
+			WithRegoVersion(ast.RegoV1).
+			WithFS(fsys).
+			WithPaths(root).
+			WithOptimizationLevel(1).
+			WithEntrypoints("test/p")
+
+		if err := compiler.Build(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(compiler.bundle.Modules) != 1 {
+			t.Fatalf("expected 1 module but got: %v", compiler.bundle.Modules)
+		}
+
+		exp := ast.MustParseExpr(`opa.decision_log.annotate("role", "admin")`)
+		var found bool
+		ast.WalkExprs(compiler.bundle.Modules[0].Parsed, func(x *ast.Expr) bool {
+			found = found || x.Equal(exp)
+			return found
+		})
+		if !found {
+			t.Fatalf("expected optimized module to contain %v, got:\n\n%v", exp, compiler.bundle.Modules[0].Parsed)
+		}
+	})
+}
+
 func TestCompilerOptimizationL2(t *testing.T) {
 
 	files := map[string]string{
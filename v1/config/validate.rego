@@ -95,13 +95,22 @@ _core_specs := [
 	}},
 	{"pattern": ["decision_logs"], "keys": {
 		"plugin", "service", "partition_name", "reporting", "request_context",
-		"mask_decision", "drop_decision", "console", "resource", "nd_builtin_cache",
+		"mask_decision", "drop_decision", "sample_decision", "console", "otlp", "aggregation", "recent_decisions", "sinks",
+		"resource", "nd_builtin_cache",
 	}},
-	{"pattern": ["decision_logs", "reporting"], "keys": {
-		"buffer_type", "buffer_size_limit_bytes", "buffer_size_limit_events",
-		"upload_size_limit_bytes", "min_delay_seconds", "max_delay_seconds",
-		"max_decisions_per_second", "trigger",
+	{"pattern": ["decision_logs", "recent_decisions"], "keys": {"size"}},
+	{"pattern": ["decision_logs", "aggregation"], "keys": _decision_logs_aggregation_keys},
+	{"pattern": ["decision_logs", "otlp"], "keys": {
+		"type", "address", "service_name", "encryption", "allow_insecure_tls",
+		"tls_cert_file", "tls_private_key_file", "tls_ca_cert_file",
 	}},
+	{"pattern": ["decision_logs", "reporting"], "keys": _decision_logs_reporting_keys},
+	{"pattern": ["decision_logs", "sinks", "*"], "keys": {
+		"service", "plugin", "console", "file", "resource", "reporting",
+		"mask_decision", "drop_decision", "sample_decision", "aggregation", "paths", "route_decision",
+	}},
+	{"pattern": ["decision_logs", "sinks", "*", "aggregation"], "keys": _decision_logs_aggregation_keys},
+	{"pattern": ["decision_logs", "sinks", "*", "reporting"], "keys": _decision_logs_reporting_keys},
 	{"pattern": ["decision_logs", "request_context"], "keys": {"http"}},
 	{"pattern": ["decision_logs", "request_context", "http"], "keys": {"headers"}},
 	{"pattern": ["status"], "keys": {
@@ -163,3 +172,14 @@ _core_specs := [
 ]
 
 _polling_keys := {"min_delay_seconds", "max_delay_seconds", "long_polling_timeout_seconds"}
+
+_decision_logs_reporting_keys := {
+	"buffer_type", "buffer_size_limit_bytes", "buffer_size_limit_events",
+	"buffer_directory", "buffer_segment_size_bytes", "buffer_fsync",
+	"upload_size_limit_bytes", "min_delay_seconds", "max_delay_seconds",
+	"max_decisions_per_second", "trigger", "encoding",
+}
+
+_decision_logs_aggregation_keys := {
+	"interval_seconds", "paths", "result_projection", "input_fields", "latency_buckets_ms",
+}
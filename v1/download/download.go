@@ -362,6 +362,8 @@ func (d *Downloader) download(ctx context.Context, m metrics.Metrics) (*download
 			if d.logger.GetLevel() >= logging.Debug {
 				expectedBundleContentType := []string{
 					"application/gzip",
+					"application/zstd",
+					"application/x-tar",
 					"application/octet-stream",
 					"application/vnd.openpolicyagent.bundles",
 				}
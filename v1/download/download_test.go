@@ -1112,7 +1112,7 @@ func TestWarnOnNonBundleContentType(t *testing.T) {
 	d.Stop(ctx)
 
 	expectLogged := "Content-Type response header set to text/html. " +
-		"Expected one of [application/gzip application/octet-stream application/vnd.openpolicyagent.bundles]. " +
+		"Expected one of [application/gzip application/zstd application/x-tar application/octet-stream application/vnd.openpolicyagent.bundles]. " +
 		"Possibly not a bundle being downloaded."
 	var found bool
 	for _, entry := range logger.Entries() {
@@ -0,0 +1,421 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package download
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/rand"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/compile"
+	"github.com/open-policy-agent/opa/v1/logging"
+	"github.com/open-policy-agent/opa/v1/metrics"
+	"github.com/open-policy-agent/opa/v1/plugins"
+	"github.com/open-policy-agent/opa/v1/util"
+)
+
+const (
+	// defaultGitRef is the ref checked out when none is configured. It
+	// resolves to the default branch of the remote repository.
+	defaultGitRef = "HEAD"
+
+	gitRemoteName = "origin"
+)
+
+// GitConfig represents the configuration for building bundles from a git
+// repository.
+type GitConfig struct {
+	URL        string `json:"url"`                   // remote URL or local path of the repository
+	Ref        string `json:"ref,omitempty"`         // branch, tag or commit SHA to check out
+	Path       string `json:"path,omitempty"`        // subdirectory of the repository to build the bundle from
+	BundleMode bool   `json:"bundle_mode,omitempty"` // load the subdirectory as a bundle, like `opa build --bundle`
+}
+
+// ValidateAndInjectDefaults checks for configuration errors and ensures all
+// values are set on the GitConfig object.
+func (c *GitConfig) ValidateAndInjectDefaults() error {
+	if c.URL == "" {
+		return errors.New("git source missing 'url'")
+	}
+
+	if c.Ref == "" {
+		c.Ref = defaultGitRef
+	}
+
+	if c.Path != "" {
+		clean := filepath.ToSlash(filepath.Clean(c.Path))
+		if filepath.IsAbs(c.Path) || clean == ".." || strings.HasPrefix(clean, "../") {
+			return fmt.Errorf("git source 'path' must be relative to the repository root: %q", c.Path)
+		}
+		c.Path = clean
+	}
+
+	return nil
+}
+
+// GitDownloader implements bundle downloading from a git repository. After
+// starting, the downloader fetches the configured repository, resolves the
+// configured ref to a commit and, whenever the commit changes, builds a bundle
+// from the configured subdirectory. The commit SHA is used as both the bundle
+// revision and the etag.
+type GitDownloader struct {
+	config           Config                              // downloader configuration for tuning polling and other downloader behaviour
+	git              GitConfig                           // repository, ref and subdirectory to build bundles from
+	localPath        string                              // path of the local clone
+	localPathIsTemp  bool                                // whether localPath is a temporary directory, created on the first fetch and removed when stopping
+	stop             chan chan struct{}                  // used to signal plugin to stop running
+	f                func(context.Context, Update) error // callback function invoked when download updates occur
+	etag             string                              // commit SHA of the last bundle built
+	persist          bool
+	wg               sync.WaitGroup
+	mtx              sync.Mutex // serializes git operations on the local clone
+	logger           logging.Logger
+	stopOnce         sync.Once
+	bundleParserOpts ast.ParserOptions
+}
+
+// NewGit returns a new GitDownloader that can be started. The repository is
+// cloned into localPath. If localPath is empty, a temporary directory is
+// created on the first fetch and removed when the downloader is stopped.
+func NewGit(config Config, git GitConfig, localPath string) *GitDownloader {
+	return &GitDownloader{
+		config:          config,
+		git:             git,
+		localPath:       localPath,
+		localPathIsTemp: localPath == "",
+		stop:            make(chan chan struct{}),
+		logger:          logging.Get(),
+	}
+}
+
+// WithCallback registers a function f to be called when download updates occur.
+func (d *GitDownloader) WithCallback(f func(context.Context, Update) error) *GitDownloader {
+	d.f = f
+	return d
+}
+
+// WithLogger sets the logger used by the downloader.
+func (d *GitDownloader) WithLogger(logger logging.Logger) *GitDownloader {
+	d.logger = logger
+	return d
+}
+
+// WithLogAttrs sets an optional set of key/value pair attributes to include in
+// log messages emitted by the downloader.
+func (d *GitDownloader) WithLogAttrs(attrs map[string]any) *GitDownloader {
+	d.logger = d.logger.WithFields(attrs)
+	return d
+}
+
+// WithBundlePersistence specifies if the built bundle will eventually be persisted to disk.
+func (d *GitDownloader) WithBundlePersistence(persist bool) *GitDownloader {
+	d.persist = persist
+	return d
+}
+
+// WithBundleParserOpts specifies the parser options to use when building bundles.
+func (d *GitDownloader) WithBundleParserOpts(opts ast.ParserOptions) *GitDownloader {
+	d.bundleParserOpts = opts
+	return d
+}
+
+// ClearCache is deprecated. Use SetCache instead.
+func (d *GitDownloader) ClearCache() {
+	d.etag = ""
+}
+
+// SetCache sets the commit SHA of the currently activated bundle.
+func (d *GitDownloader) SetCache(etag string) {
+	d.etag = etag
+}
+
+// Trigger can be used to control when the downloader attempts to fetch
+// the repository in manual triggering mode.
+func (d *GitDownloader) Trigger(ctx context.Context) error {
+	done := make(chan error)
+
+	go func() {
+		err := d.oneShot(ctx)
+		if err != nil {
+			d.logger.Error("Git - Bundle build failed: %v.", err)
+			if ctx.Err() == nil {
+				done <- err
+			}
+		}
+		close(done)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Start tells the GitDownloader to begin polling the repository.
+func (d *GitDownloader) Start(ctx context.Context) {
+	if *d.config.Trigger == plugins.TriggerPeriodic {
+		go d.doStart(ctx)
+	}
+}
+
+// Stop tells the GitDownloader to stop polling the repository.
+func (d *GitDownloader) Stop(context.Context) {
+	d.stopOnce.Do(func() {
+		if *d.config.Trigger == plugins.TriggerPeriodic {
+			done := make(chan struct{})
+			d.stop <- done
+			<-done
+		}
+
+		d.mtx.Lock()
+		defer d.mtx.Unlock()
+		if d.localPathIsTemp && d.localPath != "" {
+			if err := os.RemoveAll(d.localPath); err != nil {
+				d.logger.Error("Git - Failed to remove temporary clone %q: %v.", d.localPath, err)
+			}
+		}
+	})
+}
+
+func (d *GitDownloader) doStart(context.Context) {
+	// We'll revisit context passing/usage later.
+	ctx, cancel := context.WithCancel(context.Background())
+
+	d.wg.Add(1)
+	go d.loop(ctx)
+
+	done := <-d.stop // blocks until there's something to read
+	cancel()
+	d.wg.Wait()
+	close(done)
+}
+
+func (d *GitDownloader) loop(ctx context.Context) {
+	defer d.wg.Done()
+
+	var retry int
+
+	for {
+
+		var delay time.Duration
+
+		err := d.oneShot(ctx)
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err != nil {
+			delay = util.DefaultBackoff(float64(minRetryDelay), float64(*d.config.Polling.parsedMaxDelaySeconds), retry)
+		} else {
+			min := float64(*d.config.Polling.parsedMinDelaySeconds)
+			max := float64(*d.config.Polling.parsedMaxDelaySeconds)
+			delay = time.Duration(((max - min) * rand.Float64()) + min)
+		}
+
+		d.logger.Debug("Git - Waiting %v before next fetch/retry.", delay)
+
+		timer, timerCancel := util.TimerWithCancel(delay)
+		select {
+		case <-timer.C:
+			if err != nil {
+				retry++
+			} else {
+				retry = 0
+			}
+		case <-ctx.Done():
+			timerCancel() // explicitly cancel the timer.
+			return
+		}
+	}
+}
+
+func (d *GitDownloader) oneShot(ctx context.Context) error {
+	m := metrics.New()
+	resp, err := d.download(ctx, m)
+	if err != nil {
+		d.etag = ""
+
+		if d.f != nil {
+			err = errors.Join(err, d.f(ctx, Update{ETag: "", Bundle: nil, Error: err, Metrics: m, Raw: nil}))
+		}
+		return err
+	}
+
+	d.etag = resp.etag
+
+	if d.f != nil {
+		if err := d.f(ctx, Update{ETag: resp.etag, Bundle: resp.b, Error: nil, Metrics: m, Raw: resp.raw, Size: resp.size}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *GitDownloader) download(ctx context.Context, m metrics.Metrics) (*downloaderResponse, error) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	d.logger.Debug("Git - Fetch starting.")
+
+	m.Timer(metrics.BundleRequest).Start()
+	sha, err := d.fetch(ctx)
+	m.Timer(metrics.BundleRequest).Stop()
+	if err != nil {
+		return nil, err
+	}
+
+	if sha == d.etag {
+		d.logger.Debug("Git - Commit %v already activated.", sha)
+		return &downloaderResponse{etag: sha}, nil
+	}
+
+	if _, err := d.runGit(ctx, "checkout", "--quiet", "--force", "--detach", sha); err != nil {
+		return nil, err
+	}
+	if _, err := d.runGit(ctx, "clean", "--quiet", "-ffdx"); err != nil {
+		return nil, err
+	}
+
+	m.Timer(metrics.RegoLoadBundles).Start()
+	defer m.Timer(metrics.RegoLoadBundles).Stop()
+
+	root := d.localPath
+	if d.git.Path != "" {
+		root = filepath.Join(d.localPath, filepath.FromSlash(d.git.Path))
+	}
+
+	if _, err := os.Stat(root); err != nil {
+		return nil, fmt.Errorf("path %q not found at commit %v", d.git.Path, sha)
+	}
+
+	var buf bytes.Buffer
+	compiler := compile.New().
+		WithFS(os.DirFS(root)).
+		WithPaths(".").
+		WithAsBundle(d.git.BundleMode).
+		WithFilter(gitLoaderFilter(d.git.BundleMode)).
+		WithRevision(sha).
+		WithRegoVersion(d.bundleParserOpts.RegoVersion).
+		WithRegoAnnotationEntrypoints(d.bundleParserOpts.ProcessAnnotation).
+		WithCapabilities(d.bundleParserOpts.Capabilities)
+
+	if d.persist {
+		compiler = compiler.WithOutput(&buf)
+	}
+
+	if err := compiler.Build(ctx); err != nil {
+		return nil, fmt.Errorf("failed to build bundle at commit %v: %w", sha, err)
+	}
+
+	b := compiler.Bundle()
+	b.Manifest.SetRegoVersion(d.bundleParserOpts.RegoVersion)
+
+	d.logger.Debug("Git - Built bundle at commit %v.", sha)
+
+	return &downloaderResponse{
+		b:    b,
+		raw:  &buf,
+		etag: sha,
+		size: buf.Len(),
+	}, nil
+}
+
+// fetch brings the local clone up to date with the remote repository and
+// returns the commit SHA the configured ref resolves to.
+func (d *GitDownloader) fetch(ctx context.Context) (string, error) {
+	if d.localPath == "" {
+		localPath, err := os.MkdirTemp("", "opa-git-*")
+		if err != nil {
+			return "", fmt.Errorf("failed to create temporary clone directory: %w", err)
+		}
+		d.localPath = localPath
+	}
+
+	if _, err := os.Stat(filepath.Join(d.localPath, ".git")); errors.Is(err, fs.ErrNotExist) {
+		if err := os.MkdirAll(d.localPath, 0o755); err != nil {
+			return "", err
+		}
+		if _, err := d.runGit(ctx, "init", "--quiet"); err != nil {
+			return "", err
+		}
+		if _, err := d.runGit(ctx, "remote", "add", gitRemoteName, d.git.URL); err != nil {
+			return "", err
+		}
+	} else if err != nil {
+		return "", err
+	} else if _, err := d.runGit(ctx, "remote", "set-url", gitRemoteName, d.git.URL); err != nil {
+		return "", err
+	}
+
+	_, err := d.runGit(ctx, "fetch", "--quiet", "--force", "--prune", "--no-recurse-submodules", gitRemoteName,
+		"+HEAD:refs/remotes/"+gitRemoteName+"/HEAD",
+		"+refs/heads/*:refs/remotes/"+gitRemoteName+"/*",
+		"+refs/tags/*:refs/tags/*")
+	if err != nil {
+		return "", err
+	}
+
+	candidates := []string{
+		"refs/remotes/" + gitRemoteName + "/" + d.git.Ref,
+		"refs/tags/" + d.git.Ref,
+		d.git.Ref,
+	}
+
+	for _, c := range candidates {
+		sha, err := d.runGit(ctx, "rev-parse", "--quiet", "--verify", c+"^{commit}")
+		if err == nil {
+			return sha, nil
+		}
+	}
+
+	return "", fmt.Errorf("git ref %q not found in %v", d.git.Ref, d.git.URL)
+}
+
+func (d *GitDownloader) runGit(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = d.localPath
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			return "", fmt.Errorf("git %v: %w", args[0], err)
+		}
+		return "", fmt.Errorf("git %v: %w: %v", args[0], err, msg)
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// gitLoaderFilter mirrors the filter applied by `opa build`, additionally
+// skipping the repository metadata directory.
+func gitLoaderFilter(bundleMode bool) func(string, fs.FileInfo, int) bool {
+	return func(abspath string, info fs.FileInfo, _ int) bool {
+		if info.IsDir() && info.Name() == ".git" {
+			return true
+		}
+		if !bundleMode && !info.IsDir() && strings.HasSuffix(abspath, ".tar.gz") {
+			return true
+		}
+		return false
+	}
+}
@@ -0,0 +1,318 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package download
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/plugins"
+)
+
+func TestGitConfigValidation(t *testing.T) {
+	tests := []struct {
+		note    string
+		config  GitConfig
+		wantErr bool
+		expRef  string
+		expPath string
+	}{
+		{
+			note:    "missing url",
+			config:  GitConfig{Ref: "main"},
+			wantErr: true,
+		},
+		{
+			note:   "default ref",
+			config: GitConfig{URL: "https://example.com/policies.git"},
+			expRef: defaultGitRef,
+		},
+		{
+			note:    "path cleaned",
+			config:  GitConfig{URL: "/tmp/policies.git", Ref: "v1.0.0", Path: "./policies/authz/"},
+			expRef:  "v1.0.0",
+			expPath: "policies/authz",
+		},
+		{
+			note:    "path outside repository",
+			config:  GitConfig{URL: "/tmp/policies.git", Path: "../other"},
+			wantErr: true,
+		},
+		{
+			note:    "absolute path",
+			config:  GitConfig{URL: "/tmp/policies.git", Path: "/etc"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			err := tc.config.ValidateAndInjectDefaults()
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.config.Ref != tc.expRef {
+				t.Fatalf("expected ref %q but got %q", tc.expRef, tc.config.Ref)
+			}
+			if tc.config.Path != tc.expPath {
+				t.Fatalf("expected path %q but got %q", tc.expPath, tc.config.Path)
+			}
+		})
+	}
+}
+
+func TestGitDownloader(t *testing.T) {
+	repo := newTestGitRepo(t)
+	first := repo.commit(map[string]string{
+		"policies/authz/authz.rego": "package authz\n\nallow if input.admin\n",
+		"policies/authz/data.json":  `{"roles": ["admin"]}`,
+		"README.md":                 "not part of the bundle",
+	})
+	repo.git("tag", "v1")
+
+	ctx := context.Background()
+
+	config := Config{}
+	if err := config.ValidateAndInjectDefaults(); err != nil {
+		t.Fatal(err)
+	}
+	manual := plugins.TriggerManual
+	config.Trigger = &manual
+
+	gitConfig := GitConfig{URL: repo.remote, Ref: "main", Path: "policies"}
+	if err := gitConfig.ValidateAndInjectDefaults(); err != nil {
+		t.Fatal(err)
+	}
+
+	var updates []Update
+	d := NewGit(config, gitConfig, "").
+		WithBundleParserOpts(ast.ParserOptions{RegoVersion: ast.RegoV1}).
+		WithBundlePersistence(true).
+		WithCallback(func(_ context.Context, u Update) error {
+			updates = append(updates, u)
+			return nil
+		})
+	defer d.Stop(ctx)
+
+	if err := d.Trigger(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	u := updates[len(updates)-1]
+	if u.Bundle == nil {
+		t.Fatal("expected bundle")
+	}
+	if u.ETag != first || u.Bundle.Manifest.Revision != first {
+		t.Fatalf("expected etag and revision %v but got %v and %v", first, u.ETag, u.Bundle.Manifest.Revision)
+	}
+	if len(u.Bundle.Modules) != 1 || u.Bundle.Modules[0].Path != "authz/authz.rego" {
+		t.Fatalf("unexpected modules: %v", u.Bundle.Modules)
+	}
+	if u.Size == 0 || u.Raw == nil {
+		t.Fatal("expected raw bundle for persistence")
+	}
+
+	// No new commits: the bundle is not rebuilt.
+	if err := d.Trigger(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if u := updates[len(updates)-1]; u.Bundle != nil || u.ETag != first {
+		t.Fatalf("expected unchanged update, got %+v", u)
+	}
+
+	second := repo.commit(map[string]string{
+		"policies/authz/authz.rego": "package authz\n\nallow if input.root\n",
+	})
+
+	if err := d.Trigger(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if u := updates[len(updates)-1]; u.Bundle == nil || u.Bundle.Manifest.Revision != second {
+		t.Fatalf("expected bundle at %v, got %+v", second, u)
+	}
+
+	// Pin to a tag.
+	d.git.Ref = "v1"
+	if err := d.Trigger(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if u := updates[len(updates)-1]; u.Bundle == nil || u.Bundle.Manifest.Revision != first {
+		t.Fatalf("expected bundle at %v, got %+v", first, u)
+	}
+
+	// Pin to a commit.
+	d.git.Ref = second
+	if err := d.Trigger(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if u := updates[len(updates)-1]; u.Bundle == nil || u.Bundle.Manifest.Revision != second {
+		t.Fatalf("expected bundle at %v, got %+v", second, u)
+	}
+}
+
+func TestGitDownloaderErrors(t *testing.T) {
+	repo := newTestGitRepo(t)
+	repo.commit(map[string]string{
+		"authz.rego": "package authz\n\nallow if {\n",
+	})
+
+	ctx := context.Background()
+
+	config := Config{}
+	if err := config.ValidateAndInjectDefaults(); err != nil {
+		t.Fatal(err)
+	}
+	manual := plugins.TriggerManual
+	config.Trigger = &manual
+
+	tests := []struct {
+		note   string
+		config GitConfig
+		expErr string
+	}{
+		{
+			note:   "unknown ref",
+			config: GitConfig{URL: repo.remote, Ref: "does-not-exist"},
+			expErr: `git ref "does-not-exist" not found`,
+		},
+		{
+			note:   "missing path",
+			config: GitConfig{URL: repo.remote, Ref: "main", Path: "missing"},
+			expErr: `path "missing" not found`,
+		},
+		{
+			note:   "parse error",
+			config: GitConfig{URL: repo.remote, Ref: "main"},
+			expErr: "failed to build bundle",
+		},
+		{
+			note:   "unknown repository",
+			config: GitConfig{URL: filepath.Join(t.TempDir(), "missing.git")},
+			expErr: "git fetch",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			var update Update
+			d := NewGit(config, tc.config, t.TempDir()).
+				WithCallback(func(_ context.Context, u Update) error {
+					update = u
+					return nil
+				})
+
+			err := d.Trigger(ctx)
+			if err == nil || !strings.Contains(err.Error(), tc.expErr) {
+				t.Fatalf("expected error containing %q but got %v", tc.expErr, err)
+			}
+			if update.Error == nil {
+				t.Fatal("expected error to be passed to callback")
+			}
+		})
+	}
+}
+
+func TestGitDownloaderTempDirError(t *testing.T) {
+	// The temporary directory can't be created inside a regular file.
+	tmp := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(tmp, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TMPDIR", tmp)
+
+	ctx := context.Background()
+
+	config := Config{}
+	if err := config.ValidateAndInjectDefaults(); err != nil {
+		t.Fatal(err)
+	}
+	manual := plugins.TriggerManual
+	config.Trigger = &manual
+
+	var update Update
+	d := NewGit(config, GitConfig{URL: "https://example.com/policies.git", Ref: "main"}, "").
+		WithCallback(func(_ context.Context, u Update) error {
+			update = u
+			return nil
+		})
+	defer d.Stop(ctx)
+
+	err := d.Trigger(ctx)
+	if err == nil || !strings.Contains(err.Error(), "failed to create temporary clone directory") {
+		t.Fatalf("expected temporary directory error but got %v", err)
+	}
+	if update.Error == nil {
+		t.Fatal("expected error to be passed to callback")
+	}
+}
+
+type testGitRepo struct {
+	t      *testing.T
+	remote string
+	work   string
+}
+
+func newTestGitRepo(t *testing.T) *testGitRepo {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	root := t.TempDir()
+	r := &testGitRepo{
+		t:      t,
+		remote: filepath.Join(root, "remote.git"),
+		work:   filepath.Join(root, "work"),
+	}
+
+	runGit(t, root, "init", "--quiet", "--bare", "--initial-branch=main", r.remote)
+	runGit(t, root, "init", "--quiet", "--initial-branch=main", r.work)
+	r.git("remote", "add", "origin", r.remote)
+	return r
+}
+
+func (r *testGitRepo) git(args ...string) string {
+	return runGit(r.t, r.work, args...)
+}
+
+func (r *testGitRepo) commit(files map[string]string) string {
+	r.t.Helper()
+	for name, content := range files {
+		path := filepath.Join(r.work, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			r.t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			r.t.Fatal(err)
+		}
+	}
+	r.git("add", "-A")
+	r.git("-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "update")
+	r.git("push", "--quiet", "origin", "main", "--tags")
+	return r.git("rev-parse", "HEAD")
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v: %s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
@@ -9,11 +9,14 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"math/rand"
 	"net/http"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -24,6 +27,7 @@ import (
 	"oras.land/oras-go/v2/registry"
 	"oras.land/oras-go/v2/registry/remote/auth"
 
+	"github.com/open-policy-agent/opa/internal/file/archive"
 	"github.com/open-policy-agent/opa/v1/ast"
 	"github.com/open-policy-agent/opa/v1/bundle"
 	"github.com/open-policy-agent/opa/v1/metrics"
@@ -32,6 +36,21 @@ import (
 	"github.com/open-policy-agent/opa/v1/util"
 )
 
+// bundleLayerMediaTypes are the media types of OCI layers containing bundle
+// tarballs. The compression of a tarball is detected when reading it.
+var bundleLayerMediaTypes = []string{
+	ocispec.MediaTypeImageLayerGzip,
+	ocispec.MediaTypeImageLayerZstd,
+	ocispec.MediaTypeImageLayer,
+}
+
+// The layout annotation of OCI manifests is set to chunked for bundles split
+// into several layers, each containing some of the files of the bundle.
+const (
+	bundleLayoutAnnotation = "org.openpolicyagent.bundle.layout"
+	bundleLayoutChunked    = "chunked"
+)
+
 // NewOCI returns a new Downloader that can be started.
 func NewOCI(config Config, client rest.Client, path, storePath string) *OCIDownloader {
 	localStoreIsTemp := false
@@ -271,18 +290,26 @@ func (d *OCIDownloader) download(ctx context.Context, m metrics.Metrics) (*downl
 		return nil, err
 	}
 
-	tarballDescriptor := ocispec.Descriptor{}
+	var layers []ocispec.Descriptor
 	for _, descriptor := range manifest.Layers {
-		if descriptor.MediaType == "application/vnd.oci.image.layer.v1.tar+gzip" {
-			tarballDescriptor = descriptor
-			break
+		if slices.Contains(bundleLayerMediaTypes, descriptor.MediaType) {
+			layers = append(layers, descriptor)
 		}
 	}
-	if tarballDescriptor.MediaType == "" {
+	if len(layers) == 0 {
 		return nil, errors.New("no tarball descriptor found in the layers")
 	}
-	etag := tarballDescriptor.Digest.Hex()
-	bundleFilePath := filepath.Join(d.localStorePath, "blobs", "sha256", etag)
+
+	// Bundles split into chunks are identified by the manifest, as any of their
+	// layers may change. Unchanged chunks are already in the local store, so
+	// pulling the manifest only fetched the changed ones.
+	etag := layers[0].Digest.Hex()
+	if manifest.Annotations[bundleLayoutAnnotation] == bundleLayoutChunked {
+		etag = desc.Digest.Hex()
+	} else {
+		layers = layers[:1]
+	}
+
 	// if the downloader etag sha is the same with digest of the tarball it was already loaded
 	if d.etag == etag {
 		return &downloaderResponse{
@@ -292,14 +319,22 @@ func (d *OCIDownloader) download(ctx context.Context, m metrics.Metrics) (*downl
 			longPoll: false,
 		}, nil
 	}
-	fileReader, err := os.Open(bundleFilePath)
-	if err != nil {
-		return nil, err
-	}
-	defer fileReader.Close()
 
 	cnt := &count{}
-	r := io.TeeReader(fileReader, cnt)
+	var r io.Reader
+	var chunks *mergedChunks
+	if len(layers) == 1 {
+		fileReader, err := os.Open(d.blobPath(layers[0]))
+		if err != nil {
+			return nil, err
+		}
+		defer fileReader.Close()
+		r = io.TeeReader(fileReader, cnt)
+	} else {
+		chunks = d.mergeChunks(layers, cnt)
+		defer chunks.Close()
+		r = chunks
+	}
 	tee := io.TeeReader(r, &buf)
 
 	loader := bundle.NewTarballLoaderWithBaseURL(tee, d.localStorePath)
@@ -310,6 +345,10 @@ func (d *OCIDownloader) download(ctx context.Context, m metrics.Metrics) (*downl
 		WithRegoVersion(d.bundleParserOpts.RegoVersion).
 		WithProcessAnnotations(d.bundleParserOpts.ProcessAnnotation)
 	bundleInfo, err := reader.Read()
+	if chunks != nil {
+		// Wait for the chunks to be merged, so that all of them are counted.
+		chunks.Close()
+	}
 	if err != nil {
 		return &downloaderResponse{}, fmt.Errorf("unexpected error %w", err)
 	}
@@ -325,6 +364,146 @@ func (d *OCIDownloader) download(ctx context.Context, m metrics.Metrics) (*downl
 	}, nil
 }
 
+func (d *OCIDownloader) blobPath(desc ocispec.Descriptor) string {
+	return filepath.Join(d.localStorePath, "blobs", desc.Digest.Algorithm().String(), desc.Digest.Hex())
+}
+
+// mergedChunks is a gzip compressed tarball with the files of the chunks of a
+// bundle, which is written while it is read.
+type mergedChunks struct {
+	*io.PipeReader
+	done chan struct{}
+}
+
+// Close stops merging the chunks, and waits until the merging has stopped.
+func (c *mergedChunks) Close() error {
+	err := c.PipeReader.Close()
+	<-c.done
+	return err
+}
+
+// mergeChunks returns a tarball with the files of the chunks of a bundle, so
+// that the bundle can be read and persisted like any other. Each file must be
+// contained in a single chunk, except data.json files, which may be split into
+// objects with disjoint keys in several chunks. The tarball is streamed and
+// compressed like a single layer bundle, so that neither the merged bundle nor
+// its raw copy for persistence is held in memory uncompressed; only data.json
+// files are held until all chunks are read, as any of them may be split.
+func (d *OCIDownloader) mergeChunks(layers []ocispec.Descriptor, cnt io.Writer) *mergedChunks {
+	pr, pw := io.Pipe()
+	chunks := &mergedChunks{PipeReader: pr, done: make(chan struct{})}
+
+	go func() {
+		defer close(chunks.done)
+
+		tw := archive.NewTarGzWriter(pw)
+		seen := map[string]string{}
+		data := map[string][]dataPart{}
+		for _, layer := range layers {
+			if err := d.mergeChunk(tw, layer, cnt, seen, data); err != nil {
+				pw.CloseWithError(fmt.Errorf("chunk %v: %w", layer.Digest, err))
+				return
+			}
+		}
+		if err := mergeDataParts(tw, data); err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+		pw.CloseWithError(tw.Close())
+	}()
+
+	return chunks
+}
+
+// dataPart is the part of a data.json file contained in a chunk.
+type dataPart struct {
+	chunk string
+	raw   []byte
+}
+
+func (d *OCIDownloader) mergeChunk(tw *archive.TarWriter, layer ocispec.Descriptor, cnt io.Writer, seen map[string]string, data map[string][]dataPart) error {
+	f, err := os.Open(d.blobPath(layer))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	limit := int64(bundle.DefaultSizeLimitBytes)
+	if d.sizeLimitBytes != nil {
+		limit = *d.sizeLimitBytes
+	}
+	loader := bundle.NewTarballLoaderWithBaseURL(io.TeeReader(f, cnt), "").WithSizeLimitBytes(limit)
+
+	for {
+		file, err := loader.NextFile()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		var bs bytes.Buffer
+		if _, err := file.Read(&bs, limit+1); err != nil && err != io.EOF {
+			return err
+		}
+
+		if path.Base(file.Path()) == "data.json" {
+			data[file.Path()] = append(data[file.Path()], dataPart{chunk: layer.Digest.String(), raw: bs.Bytes()})
+			continue
+		}
+
+		if other, ok := seen[file.Path()]; ok {
+			return fmt.Errorf("file %v is also contained in chunk %v", file.Path(), other)
+		}
+		seen[file.Path()] = layer.Digest.String()
+
+		if err := tw.WriteFile(file.Path(), bs.Bytes()); err != nil {
+			return err
+		}
+	}
+}
+
+// mergeDataParts writes the data.json files of the chunks, merging the ones
+// split into several chunks. The parts of a split file must be objects with
+// disjoint keys, so that the merged file is the original one, and its hash in
+// the bundle signatures still matches.
+func mergeDataParts(tw *archive.TarWriter, data map[string][]dataPart) error {
+	for _, name := range slices.Sorted(maps.Keys(data)) {
+		parts := data[name]
+		if len(parts) == 1 {
+			if err := tw.WriteFile(name, parts[0].raw); err != nil {
+				return err
+			}
+			continue
+		}
+
+		merged := map[string]any{}
+		chunks := map[string]string{}
+		for _, part := range parts {
+			var obj map[string]any
+			if err := util.UnmarshalJSON(part.raw, &obj); err != nil {
+				return fmt.Errorf("chunk %v: file %v split into several chunks must be an object: %w", part.chunk, name, err)
+			}
+			for k, v := range obj {
+				if other, ok := chunks[k]; ok {
+					return fmt.Errorf("chunk %v: key %q of file %v is also contained in chunk %v", part.chunk, k, name, other)
+				}
+				chunks[k] = part.chunk
+				merged[k] = v
+			}
+		}
+
+		bs, err := json.Marshal(merged)
+		if err != nil {
+			return err
+		}
+		if err := tw.WriteFile(name, bs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (d *OCIDownloader) pull(ctx context.Context, ref string) (*ocispec.Descriptor, error) {
 	lookup := d.client.AuthPluginLookup()
 
@@ -4,15 +4,26 @@
 package download
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"path"
 	"reflect"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+
+	"github.com/open-policy-agent/opa/internal/file/archive"
 	"github.com/open-policy-agent/opa/v1/ast"
 	"github.com/open-policy-agent/opa/v1/bundle"
 
@@ -464,3 +475,182 @@ func (*mockAuthPlugin) Prepare(r *http.Request) error {
 	r.Header.Set("Authorization", "Bearer secret")
 	return nil
 }
+
+func TestOCIChunkedBundle(t *testing.T) {
+	ctx := t.Context()
+
+	blobs := map[digest.Digest][]byte{}
+	fetched := map[digest.Digest]int{}
+	var manifest []byte
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var bs []byte
+		mediaType := "application/octet-stream"
+		switch ref := path.Base(r.URL.Path); {
+		case strings.HasPrefix(r.URL.Path, "/v2/org/repo/manifests/"):
+			bs, mediaType = manifest, ocispec.MediaTypeImageManifest
+			if ref != "latest" && digest.FromBytes(manifest).String() != ref {
+				bs = nil
+			}
+		case strings.HasPrefix(r.URL.Path, "/v2/org/repo/blobs/"):
+			bs = blobs[digest.Digest(ref)]
+			if r.Method == http.MethodGet {
+				fetched[digest.Digest(ref)]++
+			}
+		}
+		if bs == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", mediaType)
+		w.Header().Set("Content-Length", strconv.Itoa(len(bs)))
+		w.Header().Set("Docker-Content-Digest", digest.FromBytes(bs).String())
+		if r.Method == http.MethodGet {
+			w.Write(bs)
+		}
+	}))
+	defer server.Close()
+
+	layer := func(mediaType string, files ...[2]string) ocispec.Descriptor {
+		var buf bytes.Buffer
+		var tw *archive.TarWriter
+		switch mediaType {
+		case ocispec.MediaTypeImageLayerGzip:
+			tw = archive.NewTarGzWriter(&buf)
+		case ocispec.MediaTypeImageLayerZstd:
+			var err error
+			if tw, err = archive.NewTarZstdWriter(&buf); err != nil {
+				t.Fatal(err)
+			}
+		default:
+			tw = archive.NewTarWriter(&buf)
+		}
+		for _, f := range files {
+			if err := tw.WriteFile(f[0], []byte(f[1])); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := tw.Close(); err != nil {
+			t.Fatal(err)
+		}
+		desc := content.NewDescriptorFromBytes(mediaType, buf.Bytes())
+		blobs[desc.Digest] = buf.Bytes()
+		return desc
+	}
+
+	config := []byte("{}")
+	configDesc := content.NewDescriptorFromBytes(ocispec.MediaTypeImageConfig, config)
+	blobs[configDesc.Digest] = config
+
+	push := func(chunked bool, layers ...ocispec.Descriptor) {
+		m := ocispec.Manifest{
+			Versioned: specs.Versioned{SchemaVersion: 2},
+			MediaType: ocispec.MediaTypeImageManifest,
+			Config:    configDesc,
+			Layers:    layers,
+		}
+		if chunked {
+			m.Annotations = map[string]string{bundleLayoutAnnotation: bundleLayoutChunked}
+		}
+		var err error
+		if manifest, err = json.Marshal(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	client, err := rest.New(fmt.Appendf(nil, `{"url": %q, "type": "oci"}`, server.URL), map[string]*keys.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var update Update
+	d := NewOCI(Config{}, client, "ghcr.io/org/repo:latest", t.TempDir()).WithCallback(func(_ context.Context, u Update) error {
+		update = u
+		return nil
+	})
+
+	policy := layer(ocispec.MediaTypeImageLayerZstd,
+		[2]string{"/.manifest", `{"roots": ["a", "b", "test"]}`},
+		[2]string{"/test/policy.rego", "package test\n\np := data.b.x"})
+	a := layer(ocispec.MediaTypeImageLayerGzip, [2]string{"/a/data.json", `{"x": 1}`})
+	b1 := layer(ocispec.MediaTypeImageLayer, [2]string{"/b/data.json", `{"x": 1}`})
+	b2 := layer(ocispec.MediaTypeImageLayer, [2]string{"/b/data.json", `{"x": 2}`})
+
+	push(true, policy, a, b1)
+	if err := d.oneShot(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	exp := map[string]any{"a": map[string]any{"x": json.Number("1")}, "b": map[string]any{"x": json.Number("1")}}
+	if update.Bundle == nil || !reflect.DeepEqual(update.Bundle.Data, exp) || len(update.Bundle.Modules) != 1 {
+		t.Fatalf("expected bundle with data %v and one module but got %+v", exp, update.Bundle)
+	}
+	if update.ETag != digest.FromBytes(manifest).Hex() {
+		t.Fatalf("expected manifest digest as etag but got %v", update.ETag)
+	}
+
+	// Only the changed chunk is downloaded again.
+	push(true, policy, a, b2)
+	if err := d.oneShot(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, desc := range []ocispec.Descriptor{policy, a, b1, b2} {
+		if fetched[desc.Digest] != 1 {
+			t.Fatalf("expected chunk %v to be fetched once but got %d", desc.Digest, fetched[desc.Digest])
+		}
+	}
+
+	// The raw bundle is a single gzip compressed tarball that can be persisted
+	// and read again.
+	if raw, ok := update.Raw.(*bytes.Buffer); !ok || !bytes.HasPrefix(raw.Bytes(), []byte{0x1f, 0x8b}) {
+		t.Fatalf("expected gzip compressed raw bundle but got %T", update.Raw)
+	}
+	persisted, err := bundle.NewReader(update.Raw).Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp["b"] = map[string]any{"x": json.Number("2")}
+	for _, b := range []*bundle.Bundle{update.Bundle, &persisted} {
+		if !reflect.DeepEqual(b.Data, exp) {
+			t.Fatalf("expected data %v but got %v", exp, b.Data)
+		}
+	}
+
+	// Without the chunked layout, only the first bundle layer is read.
+	push(false, a, b2)
+	if err := d.oneShot(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if exp := map[string]any{"a": map[string]any{"x": json.Number("1")}}; !reflect.DeepEqual(update.Bundle.Data, exp) {
+		t.Fatalf("expected data %v but got %v", exp, update.Bundle.Data)
+	}
+
+	// Data files can be split into objects with disjoint keys.
+	push(true, policy, a, layer(ocispec.MediaTypeImageLayerZstd, [2]string{"/a/data.json", `{"y": {"z": 2}}`}), b2)
+	if err := d.oneShot(ctx); err != nil {
+		t.Fatal(err)
+	}
+	exp["a"] = map[string]any{"x": json.Number("1"), "y": map[string]any{"z": json.Number("2")}}
+	if !reflect.DeepEqual(update.Bundle.Data, exp) {
+		t.Fatalf("expected data %v but got %v", exp, update.Bundle.Data)
+	}
+
+	push(true, policy, a, layer(ocispec.MediaTypeImageLayerGzip, [2]string{"/a/data.json", `{"x": 3}`}))
+	err = d.oneShot(ctx)
+	if err == nil || !strings.Contains(err.Error(), `key "x" of file /a/data.json is also contained in chunk `+a.Digest.String()) {
+		t.Fatalf("expected duplicate key error but got %v", err)
+	}
+
+	push(true, policy, a, layer(ocispec.MediaTypeImageLayerGzip, [2]string{"/a/data.json", `[3]`}))
+	err = d.oneShot(ctx)
+	if err == nil || !strings.Contains(err.Error(), "file /a/data.json split into several chunks must be an object") {
+		t.Fatalf("expected split file error but got %v", err)
+	}
+
+	push(true, policy, a, layer(ocispec.MediaTypeImageLayerGzip, [2]string{"/test/policy.rego", "package test"}))
+	err = d.oneShot(ctx)
+	if err == nil || !strings.Contains(err.Error(), "file /test/policy.rego is also contained in chunk "+policy.Digest.String()) {
+		t.Fatalf("expected duplicate file error but got %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package keys
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -17,12 +18,24 @@ var supportedAlgos = map[string]struct{}{
 	"RS256": {}, "RS384": {}, "RS512": {},
 }
 
+// encryptionAlgos maps the supported encryption algorithms to their key sizes
+// in bytes.
+var encryptionAlgos = map[string]int{
+	"A128GCM": 16, "A192GCM": 24, "A256GCM": 32,
+}
+
 // IsSupportedAlgorithm true if provided alg is supported
 func IsSupportedAlgorithm(alg string) bool {
 	_, ok := supportedAlgos[alg]
 	return ok
 }
 
+// IsEncryptionAlgorithm true if provided alg is a supported encryption algorithm
+func IsEncryptionAlgorithm(alg string) bool {
+	_, ok := encryptionAlgos[alg]
+	return ok
+}
+
 // Config holds the keys used to sign or verify bundles and tokens
 type Config struct {
 	Key        string `json:"key"`
@@ -48,6 +61,13 @@ func (k *Config) validateAndInjectDefaults(id string) error {
 		k.Algorithm = defaultSigningAlgorithm
 	}
 
+	if IsEncryptionAlgorithm(k.Algorithm) {
+		if _, err := k.EncryptionKey(); err != nil {
+			return fmt.Errorf("invalid keys configuration for key ID %v: %w", id, err)
+		}
+		return nil
+	}
+
 	if !IsSupportedAlgorithm(k.Algorithm) {
 		return fmt.Errorf("unsupported algorithm '%v'", k.Algorithm)
 	}
@@ -55,6 +75,24 @@ func (k *Config) validateAndInjectDefaults(id string) error {
 	return nil
 }
 
+// EncryptionKey returns the key of an encryption algorithm, which is base64
+// encoded in the key field.
+func (k *Config) EncryptionKey() ([]byte, error) {
+	size, ok := encryptionAlgos[k.Algorithm]
+	if !ok {
+		return nil, fmt.Errorf("unsupported encryption algorithm '%v'", k.Algorithm)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(k.Key)
+	if err != nil {
+		return nil, fmt.Errorf("key must be base64 encoded: %w", err)
+	}
+	if len(key) != size {
+		return nil, fmt.Errorf("key must be %d bytes for algorithm '%v', got %d", size, k.Algorithm, len(key))
+	}
+	return key, nil
+}
+
 // NewKeyConfig return a new Config
 func NewKeyConfig(key, alg, scope string) (*Config, error) {
 	var pubKey string
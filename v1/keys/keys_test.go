@@ -59,6 +59,21 @@ func TestParseKeysConfig(t *testing.T) {
 			nil,
 			true, errors.New("json: cannot unmarshal array into Go value of type"),
 		},
+		"valid_config_encryption_key": {
+			`{"audit": {"algorithm": "A128GCM", "key": "MDEyMzQ1Njc4OWFiY2RlZg=="}}`,
+			map[string]*Config{"audit": {Key: "MDEyMzQ1Njc4OWFiY2RlZg==", Algorithm: "A128GCM"}},
+			false, nil,
+		},
+		"invalid_config_encryption_key_size": {
+			`{"audit": {"algorithm": "A256GCM", "key": "MDEyMzQ1Njc4OWFiY2RlZg=="}}`,
+			nil,
+			true, errors.New("invalid keys configuration for key ID audit: key must be 32 bytes for algorithm 'A256GCM', got 16"),
+		},
+		"invalid_config_encryption_key_encoding": {
+			`{"audit": {"algorithm": "A128GCM", "key": "not base64"}}`,
+			nil,
+			true, errors.New("invalid keys configuration for key ID audit: key must be base64 encoded"),
+		},
 	}
 
 	for name, tc := range tests {
@@ -0,0 +1,60 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package loader
+
+import (
+	"encoding/json"
+
+	"github.com/open-policy-agent/opa/internal/dataformat"
+	"github.com/open-policy-agent/opa/v1/loader/extension"
+	"github.com/open-policy-agent/opa/v1/util"
+)
+
+// dataFormats maps the file extensions of configuration formats, that can be
+// loaded as data in addition to JSON and YAML, to their handlers.
+var dataFormats = map[string]extension.Handler{
+	".toml": formatHandler(dataformat.UnmarshalTOML),
+	".xml":  formatHandler(dataformat.UnmarshalXML),
+	".csv": formatHandler(func(bs []byte) (any, error) {
+		return dataformat.ParseCSV(bs, dataformat.CSVOptions{Header: true})
+	}),
+	".hcl":    formatHandler(dataformat.UnmarshalHCL),
+	".tf":     formatHandler(dataformat.UnmarshalHCL),
+	".tfvars": formatHandler(dataformat.UnmarshalHCL),
+	".ini":    formatHandler(dataformat.UnmarshalINI),
+}
+
+// RegisterDataFormats registers the TOML (.toml), XML (.xml), CSV (.csv), HCL
+// (.hcl, .tf and .tfvars) and INI (.ini) formats as loader extensions, so that
+// files with these extensions are loaded as data. Files with these extensions
+// are ignored otherwise. Extensions that already have a handler registered are
+// left untouched.
+// EXPERIMENTAL: Please don't rely on this functionality, it may go
+// away or change in the future.
+func RegisterDataFormats() {
+	for ext, handler := range dataFormats {
+		if extension.FindExtension(ext) == nil {
+			extension.RegisterExtension(ext, handler)
+		}
+	}
+}
+
+func formatHandler(unmarshal func([]byte) (any, error)) extension.Handler {
+	return func(bs []byte, x any) error {
+		v, err := unmarshal(bs)
+		if err != nil {
+			return err
+		}
+		if p, ok := x.(*any); ok {
+			*p = v
+			return nil
+		}
+		bs, err = json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		return util.UnmarshalJSON(bs, x)
+	}
+}
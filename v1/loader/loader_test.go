@@ -514,6 +514,99 @@ func TestLoadExtensionFail(t *testing.T) {
 	})
 }
 
+func registerDataFormats(t *testing.T) {
+	t.Helper()
+	RegisterDataFormats()
+	t.Cleanup(func() {
+		for ext := range dataFormats {
+			extension.RegisterExtension(ext, nil)
+		}
+	})
+}
+
+func TestLoadFormatsNotRegistered(t *testing.T) {
+	files := map[string]string{
+		"/data.json": `{"a": 1}`,
+		"/main.tf":   `provider "aws" {}`,
+		"/west.tf":   `provider "aws" {}`,
+	}
+
+	test.WithTempFS(files, func(rootDir string) {
+		loaded, err := NewFileLoader().All([]string{rootDir})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expected := parseJSON(files["/data.json"])
+
+		if !reflect.DeepEqual(loaded.Documents, expected) {
+			t.Fatalf("Expected %v but got: %v", expected, loaded.Documents)
+		}
+	})
+}
+
+func TestLoadFormats(t *testing.T) {
+	registerDataFormats(t)
+
+	files := map[string]string{
+		"/a/app.toml": `
+[server]
+port = 8080
+`,
+		"/b/pom.xml": `<project><version>1.0</version></project>`,
+		"/c/users.csv": `name,role
+alice,admin
+`,
+		"/d/main.tf": `
+resource "aws_s3_bucket" "logs" {
+  bucket = var.name
+}
+`,
+		"/e/config.ini": `
+[core]
+editor = vim
+`,
+	}
+
+	test.WithTempFS(files, func(rootDir string) {
+		loaded, err := NewFileLoader().All([]string{rootDir})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expected := parseJSON(`{
+			"a": {"server": {"port": 8080}},
+			"b": {"project": {"version": "1.0"}},
+			"c": [{"name": "alice", "role": "admin"}],
+			"d": {"resource": {"aws_s3_bucket": {"logs": [{"bucket": "${var.name}"}]}}},
+			"e": {"core": {"editor": "vim"}}
+		}`)
+
+		if !reflect.DeepEqual(loaded.Documents, expected) {
+			t.Fatalf("Expected %v but got: %v", expected, loaded.Documents)
+		}
+	})
+}
+
+func TestLoadFormatsFail(t *testing.T) {
+	registerDataFormats(t)
+
+	files := map[string]string{
+		"/foo.toml": `a b = 1`,
+	}
+
+	test.WithTempFS(files, func(rootDir string) {
+		_, err := NewFileLoader().All([]string{filepath.Join(rootDir, "foo.toml")})
+		if err == nil {
+			t.Fatal("Expected error")
+		}
+
+		if !strings.Contains(err.Error(), "foo.toml: toml: line 1, column 3: expected character =") {
+			t.Fatal(err)
+		}
+	})
+}
+
 func TestLoadDirRecursive(t *testing.T) {
 	files := map[string]string{
 		"/a/data1.json": `{"a": [1,2,3]}`,
@@ -152,6 +152,8 @@ type Source struct {
 	Signing        *bundle.VerificationConfig `json:"signing"`
 	Persist        bool                       `json:"persist"`
 	SizeLimitBytes int64                      `json:"size_limit_bytes"`
+	Git            *download.GitConfig        `json:"git,omitempty"`
+	Capabilities   *bundle.Capabilities       `json:"capabilities,omitempty"`
 }
 
 // IsMultiBundle returns whether or not the config is the newer multi-bundle
@@ -172,7 +174,16 @@ func (c *Config) validateAndInjectDefaults(services []string, keys map[string]*k
 			source.Resource = path.Join(defaultBundlePathPrefix, name)
 		}
 
-		if source.Signing != nil {
+		if source.Git != nil {
+			// Bundles built from git repositories carry no signatures, so
+			// verification cannot be configured for them.
+			if source.Signing != nil {
+				return fmt.Errorf("invalid configuration for bundle %q: signing is not supported for git sources", name)
+			}
+			if err := source.Git.ValidateAndInjectDefaults(); err != nil {
+				return fmt.Errorf("invalid configuration for bundle %q: %w", name, err)
+			}
+		} else if source.Signing != nil {
 			err := source.Signing.ValidateAndInjectDefaults(keys)
 			if err != nil {
 				return fmt.Errorf("invalid configuration for bundle %q: %s", name, err.Error())
@@ -181,11 +192,14 @@ func (c *Config) validateAndInjectDefaults(services []string, keys map[string]*k
 			source.Signing = bundle.NewVerificationConfig(keys, "", "", nil)
 		}
 
-		if strings.HasPrefix(source.Resource, "file://") {
+		switch {
+		case source.Git != nil:
+			// The repository URL takes the place of a service.
+		case strings.HasPrefix(source.Resource, "file://"):
 			if _, err := url.Parse(source.Resource); err != nil {
 				return fmt.Errorf("invalid URL for bundle %q: %v", name, err)
 			}
-		} else {
+		default:
 			svc, err := c.getServiceFromList(source.Service, services)
 			if err != nil {
 				return fmt.Errorf("invalid configuration for bundle %q: %s", name, err.Error())
@@ -207,6 +221,10 @@ func (c *Config) validateAndInjectDefaults(services []string, keys map[string]*k
 		if source.SizeLimitBytes <= 0 {
 			source.SizeLimitBytes = bundle.DefaultSizeLimitBytes
 		}
+
+		if err := source.Capabilities.Validate(); err != nil {
+			return fmt.Errorf("invalid configuration for bundle %q: %w", name, err)
+		}
 	}
 
 	return nil
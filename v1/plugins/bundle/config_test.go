@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/open-policy-agent/opa/v1/plugins"
@@ -377,6 +378,92 @@ func TestParseBundlesConfigSimpleFileURL(t *testing.T) {
 
 }
 
+func TestParseBundlesConfigGit(t *testing.T) {
+	tests := []struct {
+		note    string
+		config  string
+		keys    map[string]*keys.Config
+		wantErr string
+		expRef  string
+	}{
+		{
+			note:   "no service required",
+			config: `{"authz": {"git": {"url": "https://example.com/policies.git"}}}`,
+			expRef: "HEAD",
+		},
+		{
+			note:   "ref and path",
+			config: `{"authz": {"git": {"url": "/srv/policies.git", "ref": "v1.2.0", "path": "authz"}}}`,
+			expRef: "v1.2.0",
+		},
+		{
+			note:   "keys do not imply signing",
+			config: `{"authz": {"git": {"url": "/srv/policies.git"}}}`,
+			keys:   map[string]*keys.Config{"foo": {Key: "secret"}},
+			expRef: "HEAD",
+		},
+		{
+			note:    "missing url",
+			config:  `{"authz": {"git": {"ref": "main"}}}`,
+			wantErr: "git source missing 'url'",
+		},
+		{
+			note:    "signing not supported",
+			config:  `{"authz": {"git": {"url": "/srv/policies.git"}, "signing": {"keyid": "foo"}}}`,
+			keys:    map[string]*keys.Config{"foo": {Key: "secret"}},
+			wantErr: "signing is not supported for git sources",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			trigger := plugins.DefaultTriggerMode
+			c, err := NewConfigBuilder().WithBytes([]byte(tc.config)).WithKeyConfigs(tc.keys).WithTriggerMode(&trigger).Parse()
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("expected error containing %q but got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			src := c.Bundles["authz"]
+			if src.Git.Ref != tc.expRef {
+				t.Fatalf("expected ref %q but got %q", tc.expRef, src.Git.Ref)
+			}
+			if src.Service != "" {
+				t.Fatalf("expected no service but got %q", src.Service)
+			}
+			if src.Signing != nil {
+				t.Fatalf("expected no signing config but got %+v", src.Signing)
+			}
+		})
+	}
+}
+
+func TestParseBundlesConfigCapabilities(t *testing.T) {
+	config := []byte(`{"team": {"resource": "/bundles/team.tar.gz", "capabilities": {"deny_builtins": ["http.send"], "features": ["rego_v1"]}}}`)
+
+	c, err := ParseBundlesConfig(config, []string{"s1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := &bundle.Capabilities{DenyBuiltins: []string{"http.send"}, Features: &[]string{"rego_v1"}}
+	if !c.Bundles["team"].Capabilities.Equal(exp) {
+		t.Fatalf("expected capabilities %+v but got %+v", exp, c.Bundles["team"].Capabilities)
+	}
+
+	config = []byte(`{"team": {"resource": "/bundles/team.tar.gz", "capabilities": {"features": ["rego_v2"]}}}`)
+
+	_, err = ParseBundlesConfig(config, []string{"s1"})
+	if err == nil || !strings.Contains(err.Error(), `invalid configuration for bundle "team": unknown capabilities feature "rego_v2"`) {
+		t.Fatalf("expected unknown feature error but got %v", err)
+	}
+}
+
 func TestConfigIsMultiBundle(t *testing.T) {
 	tests := []struct {
 		conf     Config
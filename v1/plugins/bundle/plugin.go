@@ -16,6 +16,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"runtime"
+	"slices"
 	"strings"
 	"sync"
 
@@ -29,6 +30,7 @@ import (
 	"github.com/open-policy-agent/opa/v1/metrics"
 	"github.com/open-policy-agent/opa/v1/plugins"
 	"github.com/open-policy-agent/opa/v1/storage"
+	"github.com/open-policy-agent/opa/v1/util"
 )
 
 // maxActivationRetry represents the maximum number of attempts
@@ -61,6 +63,8 @@ type Plugin struct {
 	manager           *plugins.Manager                 // plugin manager for storage and service clients
 	status            map[string]*Status               // current status for each bundle
 	etags             map[string]string                // etag on last successful activation
+	manifests         map[string]bundle.Manifest       // manifest on last successful activation
+	pending           map[string]download.Update       // bundles waiting for their dependencies to be activated
 	listeners         map[any]func(Status)             // listeners to send status updates to
 	bulkListeners     map[any]func(map[string]*Status) // listeners to send aggregated status updates to
 	downloaders       map[string]Loader
@@ -87,6 +91,8 @@ func New(parsedConfig *Config, manager *plugins.Manager) *Plugin {
 		status:      initialStatus,
 		downloaders: make(map[string]Loader),
 		etags:       make(map[string]string),
+		manifests:   make(map[string]bundle.Manifest),
+		pending:     make(map[string]download.Update),
 		ready:       false,
 		logger:      manager.Logger(),
 	}
@@ -196,9 +202,19 @@ func (p *Plugin) Reconfigure(ctx context.Context, config any) {
 			delete(p.downloaders, name)
 			delete(p.status, name)
 			delete(p.etags, name)
+			delete(p.manifests, name)
+			delete(p.pending, name)
 		}
 	}
 
+	// Bundles depending on the removed ones must not stay active without
+	// their dependencies, so they are deactivated too.
+	dependents := p.removeDependents(deletedBundles, updatedBundles)
+	deactivated := maps.Clone(deletedBundles)
+	for _, name := range dependents {
+		deactivated[name] = struct{}{}
+	}
+
 	// Deactivate the bundles that were removed
 	params := storage.WriteParams
 	params.Context = storage.NewContext() // TODO(sr): metrics?
@@ -207,7 +223,7 @@ func (p *Plugin) Reconfigure(ctx context.Context, config any) {
 			Ctx:           ctx,
 			Store:         p.manager.Store,
 			Txn:           txn,
-			BundleNames:   deletedBundles,
+			BundleNames:   deactivated,
 			ParserOptions: p.manager.ParserOptions(),
 		}
 		err := bundle.Deactivate(opts)
@@ -223,6 +239,10 @@ func (p *Plugin) Reconfigure(ctx context.Context, config any) {
 		panic(errors.New("Unable deactivate bundle: " + err.Error()))
 	}
 
+	if len(dependents) > 0 {
+		p.notifyListeners(dependents)
+	}
+
 	readyNow := p.ready
 
 	bundles := p.getBundlesCpy()
@@ -405,14 +425,23 @@ func (p *Plugin) loadAndActivateBundlesFromDisk(ctx context.Context) {
 		return
 	}
 
+	order, err := p.activationOrder(persistedBundles)
+	if err != nil {
+		p.logger.Error("Failed to order persisted bundles by their dependencies: %v", err)
+	}
+
 	for range maxActivationRetry {
 
 		numActivatedBundles := 0
-		for name, b := range persistedBundles {
+		for _, name := range order {
+			b := persistedBundles[name]
 			p.status[name].Metrics = metrics.New()
 			p.status[name].Type = b.Type()
 
-			err := p.activate(ctx, name, b, isMultiBundle)
+			err := p.checkDependencies(name, b)
+			if err == nil {
+				err = p.activate(ctx, name, b, isMultiBundle)
+			}
 			if err != nil {
 				p.log(name).Error("Bundle activation failed: %v", err)
 				p.status[name].SetError(err)
@@ -421,6 +450,7 @@ func (p *Plugin) loadAndActivateBundlesFromDisk(ctx context.Context) {
 
 			p.status[name].SetError(nil)
 			p.status[name].SetActivateSuccess(b.Manifest.Revision)
+			p.setManifest(name, b.Manifest)
 
 			p.checkPluginReadiness()
 
@@ -447,6 +477,21 @@ func (p *Plugin) newDownloader(name string, source *Source, bundles map[string]*
 	}
 
 	conf := source.Config
+	if source.Git != nil {
+		gitPath := ""
+		if cfg := p.manager.GetConfig(); cfg.PersistenceDirectory != nil {
+			gitPath = filepath.Join(*cfg.PersistenceDirectory, "git", getNormalizedBundleName(name))
+		}
+		return download.NewGit(conf, *source.Git, gitPath).
+			WithLogger(p.manager.Logger()).
+			WithLogAttrs(map[string]any{"name": name, "plugin": Name}).
+			WithCallback(func(ctx context.Context, u download.Update) error {
+				return p.oneShot(ctx, name, u)
+			}).
+			WithBundlePersistence(p.persistBundle(name, bundles)).
+			WithBundleParserOpts(p.manager.ParserOptions())
+	}
+
 	client := p.manager.Client(source.Service)
 	path := source.Resource
 	callback := func(ctx context.Context, u download.Update) error {
@@ -481,8 +526,23 @@ func (p *Plugin) oneShot(ctx context.Context, name string, u download.Update) er
 
 	err := p.process(ctx, name, u)
 
-	for _, listener := range p.listeners {
-		listener(*p.status[name])
+	updated := []string{name}
+	if err == nil {
+		updated = append(updated, p.activatePending(ctx)...)
+	}
+
+	p.notifyListeners(updated)
+
+	return err
+}
+
+// notifyListeners sends the status of the updated bundles to the listeners,
+// and the status of all bundles to the bulk listeners.
+func (p *Plugin) notifyListeners(updated []string) {
+	for _, name := range updated {
+		for _, listener := range p.listeners {
+			listener(*p.status[name])
+		}
 	}
 
 	for _, listener := range p.bulkListeners {
@@ -497,8 +557,6 @@ func (p *Plugin) oneShot(ctx context.Context, name string, u download.Update) er
 		}
 		listener(statusCpy)
 	}
-
-	return err
 }
 
 func (p *Plugin) process(ctx context.Context, name string, u download.Update) error {
@@ -533,6 +591,27 @@ func (p *Plugin) process(ctx context.Context, name string, u download.Update) er
 		isMultiBundle := p.config.IsMultiBundle()
 		p.cfgMtx.RUnlock()
 
+		// A newer bundle supersedes one still waiting for its dependencies.
+		delete(p.pending, name)
+
+		if err := p.checkDependencies(name, u.Bundle); err != nil {
+			if unmet := (*bundle.UnmetDependencyError)(nil); errors.As(err, &unmet) && unmet.Bundle == name {
+				p.log(name).Warn("Bundle activation delayed: %v", err)
+				if p.pending == nil {
+					p.pending = make(map[string]download.Update)
+				}
+				p.pending[name] = u
+			} else {
+				p.log(name).Error("Bundle activation failed: %v", err)
+			}
+			p.status[name].SetError(err)
+			if !p.stopped {
+				etag := p.etags[name]
+				p.downloaders[name].SetCache(etag)
+			}
+			return err
+		}
+
 		if err := p.activate(ctx, name, u.Bundle, isMultiBundle); err != nil {
 			p.log(name).Error("Bundle activation failed: %v", err)
 			p.status[name].SetError(err)
@@ -563,6 +642,10 @@ func (p *Plugin) process(ctx context.Context, name string, u download.Update) er
 		p.status[name].SetActivateSuccess(u.Bundle.Manifest.Revision)
 		p.status[name].SetBundleSize(u.Size)
 
+		if u.Bundle.Type() == bundle.SnapshotBundleType {
+			p.setManifest(name, u.Bundle.Manifest)
+		}
+
 		if u.ETag != "" {
 			p.log(name).Info("Bundle loaded and activated successfully. Etag updated to %v.", u.ETag)
 		} else {
@@ -587,6 +670,130 @@ func (p *Plugin) process(ctx context.Context, name string, u download.Update) er
 	return nil
 }
 
+// checkDependencies returns an error if the dependencies declared by the
+// bundle are not satisfied by the activated bundles, or if activating the
+// bundle would leave the dependencies of activated bundles unsatisfied.
+// Dependencies on bundles that are not configured are never satisfied. Delta
+// bundles do not carry a complete manifest and are not checked.
+func (p *Plugin) checkDependencies(name string, b *bundle.Bundle) error {
+	if b.Type() == bundle.DeltaBundleType {
+		return nil
+	}
+
+	bundles := p.getBundlesCpy()
+	for _, dep := range b.Manifest.Dependencies {
+		if _, ok := bundles[dep.Name]; !ok {
+			return fmt.Errorf("bundle %q requires bundle %q which is not configured", name, dep.Name)
+		}
+	}
+
+	if err := bundle.CheckDependencies(name, b.Manifest, p.manifests); err != nil {
+		return err
+	}
+
+	return bundle.CheckDependents(name, b.Manifest, p.manifests)
+}
+
+// removeDependents forgets the activated bundles that depend, directly or not,
+// on the removed bundles, and returns their names so that they are deactivated
+// along with them. Their status reports the missing dependency, and their
+// etags are reset, so that they are downloaded and checked again. Bundles
+// whose configuration changed are restarted anyway and are not returned.
+func (p *Plugin) removeDependents(removed map[string]struct{}, updated map[string]*Source) []string {
+	var dependents []string
+
+	missing := slices.Collect(maps.Keys(removed))
+	for len(missing) > 0 {
+		dep := missing[0]
+		missing = missing[1:]
+
+		for _, name := range util.KeysSorted(p.manifests) {
+			if _, ok := updated[name]; ok {
+				continue
+			}
+			if !slices.ContainsFunc(p.manifests[name].Dependencies, func(d bundle.Dependency) bool { return d.Name == dep }) {
+				continue
+			}
+
+			err := &bundle.UnmetDependencyError{Bundle: name, Dependency: dep}
+			p.log(name).Error("Bundle deactivated: %v", err)
+			delete(p.manifests, name)
+			p.etags[name] = ""
+			if dl, ok := p.downloaders[name]; ok {
+				dl.SetCache("")
+			}
+			if status, ok := p.status[name]; ok {
+				status.ActiveRevision = ""
+				status.SetError(err)
+			}
+
+			dependents = append(dependents, name)
+			missing = append(missing, name)
+		}
+	}
+
+	return dependents
+}
+
+// setManifest records the manifest of the activated bundle for checking the
+// dependencies of bundles activated later.
+func (p *Plugin) setManifest(name string, m bundle.Manifest) {
+	if p.manifests == nil {
+		p.manifests = make(map[string]bundle.Manifest)
+	}
+	p.manifests[name] = m.Copy()
+}
+
+// activatePending activates the bundles whose activation was delayed until
+// their dependencies were activated, in dependency order. It returns the
+// names of the bundles it attempted to activate.
+func (p *Plugin) activatePending(ctx context.Context) []string {
+	var attempted []string
+
+	for progress := true; progress && len(p.pending) > 0; {
+		progress = false
+
+		bundles := make(map[string]*bundle.Bundle, len(p.pending))
+		for name, u := range p.pending {
+			bundles[name] = u.Bundle
+		}
+		order, _ := p.activationOrder(bundles)
+
+		for _, name := range order {
+			u := p.pending[name]
+			if bundle.CheckDependencies(name, u.Bundle.Manifest, p.manifests) != nil {
+				continue
+			}
+
+			p.log(name).Info("Dependencies satisfied. Activating delayed bundle.")
+			delete(p.pending, name)
+			attempted = append(attempted, name)
+			if err := p.process(ctx, name, u); err == nil {
+				progress = true
+			}
+		}
+	}
+
+	return attempted
+}
+
+// activationOrder returns the names of the given bundles ordered so that
+// dependencies are activated first. If the dependencies form a cycle, the
+// names are returned in lexical order along with the error.
+func (*Plugin) activationOrder(bundles map[string]*bundle.Bundle) ([]string, error) {
+	manifests := make(map[string]bundle.Manifest, len(bundles))
+	for name, b := range bundles {
+		manifests[name] = b.Manifest
+	}
+
+	order, err := bundle.ActivationOrder(manifests)
+	if err != nil {
+		return util.KeysSorted(manifests), err
+	}
+
+	return order, nil
+}
+
 func (p *Plugin) checkPluginReadiness() {
 	if !p.ready {
 		readyNow := true // optimistically
@@ -610,6 +817,13 @@ func (p *Plugin) activate(ctx context.Context, name string, b *bundle.Bundle, is
 	params := storage.WriteParams
 	params.Context = storage.NewContext().WithMetrics(p.status[name].Metrics)
 
+	var capabilities map[string]*bundle.Capabilities
+	p.cfgMtx.RLock()
+	if src, ok := p.config.Bundles[name]; ok && src.Capabilities != nil {
+		capabilities = map[string]*bundle.Capabilities{name: src.Capabilities}
+	}
+	p.cfgMtx.RUnlock()
+
 	err := storage.Txn(ctx, p.manager.Store, params, func(txn storage.Transaction) error {
 		p.log(name).Debug("Opened storage transaction (%v).", txn.ID())
 		defer p.log(name).Debug("Closing storage transaction (%v).", txn.ID())
@@ -656,6 +870,7 @@ func (p *Plugin) activate(ctx context.Context, name string, b *bundle.Bundle, is
 			Bundles:         map[string]*bundle.Bundle{name: b},
 			ExternalSources: p.manager.GetExternalSources(),
 			ParserOptions:   p.manager.ParserOptions(),
+			Capabilities:    capabilities,
 		}
 
 		if p.manager.Info != nil {
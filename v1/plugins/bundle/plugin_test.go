@@ -13,6 +13,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"os/exec"
 	"path"
 	"path/filepath"
 	"reflect"
@@ -5411,6 +5412,76 @@ func TestPluginUsingFileLoader(t *testing.T) {
 	}
 }
 
+func TestPluginUsingGitLoader(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	root := t.TempDir()
+	remote := filepath.Join(root, "remote.git")
+	work := filepath.Join(root, "work")
+
+	git := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+
+	git(root, "init", "--quiet", "--bare", "--initial-branch=main", remote)
+	git(root, "init", "--quiet", "--initial-branch=main", work)
+	if err := os.MkdirAll(filepath.Join(work, "policy"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(work, "policy", "test.rego"), []byte("package test\n\np := 7\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	git(work, "add", "-A")
+	git(work, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "init")
+	git(work, "push", "--quiet", remote, "main")
+	sha := git(work, "rev-parse", "HEAD")
+
+	mgr := getTestManager()
+
+	p := New(&Config{Bundles: map[string]*Source{
+		"test": {
+			SizeLimitBytes: 1e5,
+			Git:            &download.GitConfig{URL: remote, Ref: "main", Path: "policy"},
+		},
+	}}, mgr)
+
+	if err := p.config.validateAndInjectDefaults(nil, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	ch := make(chan Status)
+
+	p.Register("test", func(s Status) {
+		ch <- s
+	})
+
+	if err := p.Start(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	defer p.Stop(t.Context())
+
+	s := <-ch
+
+	if s.LastSuccessfulActivation.IsZero() {
+		t.Fatalf("expected successful activation, got errors: %v", s.Errors)
+	}
+
+	if s.ActiveRevision != sha {
+		t.Fatalf("expected revision %v but got %v", sha, s.ActiveRevision)
+	}
+}
+
 func TestPluginUsingFileLoaderV1Compatible(t *testing.T) {
 	t.Parallel()
 
@@ -7800,3 +7871,256 @@ func ensurePluginState(t *testing.T, p *Plugin, state plugins.State) {
 		t.Fatalf("Unexpected status state found in plugin manager for %s:\n\n\tFound:%+v\n\n\tExpected: %s", Name, status.State, state)
 	}
 }
+
+func TestPluginBundleDependencies(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	manager := getTestManager()
+	defer manager.Stop(ctx)
+
+	plugin := New(&Config{Bundles: map[string]*Source{
+		"platform": {Service: "s1"},
+		"team":     {Service: "s1"},
+	}}, manager)
+	for _, name := range []string{"platform", "team"} {
+		plugin.status[name] = &Status{Name: name, Metrics: metrics.New()}
+		plugin.downloaders[name] = download.New(download.Config{}, plugin.manager.Client(""), name)
+	}
+
+	var activated []string
+	plugin.Register("test", func(s Status) {
+		if len(s.Errors) == 0 && !s.LastSuccessfulActivation.IsZero() {
+			activated = append(activated, s.Name+"@"+s.ActiveRevision)
+		}
+	})
+
+	newBundle := func(pkg, version string, deps ...bundle.Dependency) *bundle.Bundle {
+		module := "package " + pkg + "\n\np := 1"
+		b := bundle.Bundle{
+			Manifest: bundle.Manifest{
+				Revision:     pkg + "-" + version,
+				Version:      version,
+				Roots:        &[]string{pkg},
+				Dependencies: deps,
+			},
+			Data: map[string]any{},
+			Modules: []bundle.ModuleFile{
+				{
+					Path:   "/" + pkg + ".rego",
+					Parsed: ast.MustParseModule(module),
+					Raw:    []byte(module),
+				},
+			},
+		}
+		return &b
+	}
+
+	// The team bundle arrives before the platform bundle it depends on, so its
+	// activation is delayed.
+	team := newBundle("team", "1.0.0", bundle.Dependency{Name: "platform", Version: "^2.1"})
+	err := plugin.oneShot(ctx, "team", download.Update{Bundle: team, Metrics: metrics.New()})
+	if err == nil || !strings.Contains(err.Error(), `requires bundle "platform" which is not activated`) {
+		t.Fatalf("expected unmet dependency error but got %v", err)
+	}
+	if _, ok := plugin.pending["team"]; !ok {
+		t.Fatal("expected team bundle to be pending")
+	}
+
+	// A platform bundle at a version that does not satisfy the constraint
+	// keeps the team bundle pending.
+	if err := plugin.oneShot(ctx, "platform", download.Update{Bundle: newBundle("platform", "2.0.0"), Metrics: metrics.New()}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := plugin.pending["team"]; !ok {
+		t.Fatal("expected team bundle to be pending")
+	}
+
+	// Activating a satisfying platform bundle activates the team bundle.
+	if err := plugin.oneShot(ctx, "platform", download.Update{Bundle: newBundle("platform", "2.3.0"), Metrics: metrics.New()}); err != nil {
+		t.Fatal(err)
+	}
+	if len(plugin.pending) != 0 {
+		t.Fatalf("expected no pending bundles but got %v", plugin.pending)
+	}
+
+	exp := []string{"platform@platform-2.0.0", "platform@platform-2.3.0", "team@team-1.0.0"}
+	if !slices.Equal(activated, exp) {
+		t.Fatalf("expected activations %v but got %v", exp, activated)
+	}
+
+	ensurePluginState(t, plugin, plugins.StateOK)
+
+	// Upgrading the platform bundle past the range required by the active
+	// team bundle is rejected.
+	err = plugin.oneShot(ctx, "platform", download.Update{Bundle: newBundle("platform", "3.0.0"), Metrics: metrics.New()})
+	if err == nil || !strings.Contains(err.Error(), `bundle "team" requires bundle "platform" at version "^2.1" but found "3.0.0"`) {
+		t.Fatalf("expected unmet dependency error but got %v", err)
+	}
+	if _, ok := plugin.pending["platform"]; ok {
+		t.Fatal("expected platform bundle to be rejected, not delayed")
+	}
+	if rev := plugin.status["platform"].ActiveRevision; rev != "platform-2.3.0" {
+		t.Fatalf("expected platform revision to remain platform-2.3.0 but got %v", rev)
+	}
+
+	// Dependencies on bundles that are not configured are rejected.
+	err = plugin.oneShot(ctx, "team", download.Update{Bundle: newBundle("team", "1.1.0", bundle.Dependency{Name: "other"}), Metrics: metrics.New()})
+	if err == nil || !strings.Contains(err.Error(), `requires bundle "other" which is not configured`) {
+		t.Fatalf("expected unconfigured dependency error but got %v", err)
+	}
+	if _, ok := plugin.pending["team"]; ok {
+		t.Fatal("expected team bundle to be rejected, not delayed")
+	}
+}
+
+func TestPluginBundleDependenciesRemoved(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	manager := getTestManager()
+	defer manager.Stop(ctx)
+
+	newConfig := func(names ...string) *Config {
+		config := &Config{Bundles: map[string]*Source{}}
+		for _, name := range names {
+			source := &Source{Service: "s1", Config: download.Config{Trigger: pointTo(plugins.TriggerManual)}}
+			if err := source.ValidateAndInjectDefaults(); err != nil {
+				t.Fatal(err)
+			}
+			config.Bundles[name] = source
+		}
+		return config
+	}
+
+	config := newConfig("platform", "team", "addon", "other")
+	plugin := New(config, manager)
+	for name, source := range config.Bundles {
+		plugin.status[name] = &Status{Name: name, Metrics: metrics.New()}
+		plugin.downloaders[name] = download.New(source.Config, plugin.manager.Client(""), name)
+	}
+
+	var notified []string
+	plugin.Register("test", func(s Status) {
+		notified = append(notified, s.Name+": "+s.Message)
+	})
+
+	newBundle := func(pkg string, deps ...bundle.Dependency) *bundle.Bundle {
+		module := "package " + pkg + "\n\np := 1"
+		return &bundle.Bundle{
+			Manifest: bundle.Manifest{
+				Revision:     pkg,
+				Roots:        &[]string{pkg},
+				Dependencies: deps,
+			},
+			Data: map[string]any{},
+			Modules: []bundle.ModuleFile{
+				{
+					Path:   "/" + pkg + ".rego",
+					Parsed: ast.MustParseModule(module),
+					Raw:    []byte(module),
+				},
+			},
+		}
+	}
+
+	for _, u := range []struct {
+		name string
+		b    *bundle.Bundle
+	}{
+		{"platform", newBundle("platform")},
+		{"team", newBundle("team", bundle.Dependency{Name: "platform"})},
+		{"addon", newBundle("addon", bundle.Dependency{Name: "team"})},
+		{"other", newBundle("other")},
+	} {
+		if err := plugin.oneShot(ctx, u.name, download.Update{Bundle: u.b, Metrics: metrics.New()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	notified = nil
+
+	// Removing the platform bundle deactivates the bundles depending on it,
+	// directly or not.
+	plugin.Reconfigure(ctx, newConfig("team", "addon", "other"))
+
+	txn := storage.NewTransactionOrDie(ctx, manager.Store)
+	ids, err := manager.Store.ListPolicies(ctx, txn)
+	manager.Store.Abort(ctx, txn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := []string{"other/other.rego"}; !slices.Equal(ids, exp) {
+		t.Fatalf("expected policies %v but got %v", exp, ids)
+	}
+
+	if exp := []string{"other"}; !slices.Equal(util.KeysSorted(plugin.manifests), exp) {
+		t.Fatalf("expected manifests of %v but got %v", exp, util.KeysSorted(plugin.manifests))
+	}
+
+	exp := []string{
+		`team: bundle "team" requires bundle "platform" which is not activated`,
+		`addon: bundle "addon" requires bundle "team" which is not activated`,
+	}
+	if !slices.Equal(notified, exp) {
+		t.Fatalf("expected status updates %v but got %v", exp, notified)
+	}
+	if rev := plugin.status["team"].ActiveRevision; rev != "" {
+		t.Fatalf("expected no active team revision but got %v", rev)
+	}
+	if etag := plugin.etags["team"]; etag != "" {
+		t.Fatalf("expected team etag to be reset but got %v", etag)
+	}
+}
+
+func TestPluginBundleCapabilities(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	manager := getTestManager()
+	defer manager.Stop(ctx)
+
+	plugin := New(&Config{Bundles: map[string]*Source{
+		"platform": {Service: "s1"},
+		"team":     {Service: "s1", Capabilities: &bundle.Capabilities{DenyBuiltins: []string{"http.send", "opa.runtime"}}},
+	}}, manager)
+	for _, name := range []string{"platform", "team"} {
+		plugin.status[name] = &Status{Name: name, Metrics: metrics.New()}
+		plugin.downloaders[name] = download.New(download.Config{}, plugin.manager.Client(""), name)
+	}
+
+	newBundle := func(pkg, module string) *bundle.Bundle {
+		b := bundle.Bundle{
+			Manifest: bundle.Manifest{Revision: pkg, Roots: &[]string{pkg}},
+			Data:     map[string]any{},
+			Modules: []bundle.ModuleFile{
+				{
+					Path:   "/" + pkg + ".rego",
+					Parsed: ast.MustParseModule(module),
+					Raw:    []byte(module),
+				},
+			},
+		}
+		return &b
+	}
+
+	// The platform bundle is not restricted.
+	platform := newBundle("platform", "package platform\n\nenv := opa.runtime().env")
+	if err := plugin.oneShot(ctx, "platform", download.Update{Bundle: platform, Metrics: metrics.New()}); err != nil {
+		t.Fatal(err)
+	}
+
+	team := newBundle("team", "package team\n\nenv := opa.runtime().env")
+	err := plugin.oneShot(ctx, "team", download.Update{Bundle: team, Metrics: metrics.New()})
+	if err == nil || !strings.Contains(err.Error(), "bundle team: 1 error occurred: 3:8: rego_compile_error: built-in function opa.runtime is not allowed by capabilities") {
+		t.Fatalf("expected capabilities error but got %v", err)
+	}
+	if plugin.status["team"].ActiveRevision != "" {
+		t.Fatal("expected team bundle not to be activated")
+	}
+
+	team = newBundle("team", "package team\n\np := data.platform.env")
+	if err := plugin.oneShot(ctx, "team", download.Update{Bundle: team, Metrics: metrics.New()}); err != nil {
+		t.Fatal(err)
+	}
+	ensurePluginState(t, plugin, plugins.StateOK)
+}
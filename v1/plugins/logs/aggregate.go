@@ -0,0 +1,380 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package logs
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/open-policy-agent/opa/internal/uuid"
+	"github.com/open-policy-agent/opa/v1/metrics"
+	"github.com/open-policy-agent/opa/v1/server"
+	"github.com/open-policy-agent/opa/v1/storage"
+	"github.com/open-policy-agent/opa/v1/util"
+)
+
+const (
+	defaultAggregationIntervalSeconds = int64(60)
+	logAggregatedCounterName          = "decision_logs_aggregated"
+)
+
+// defaultLatencyBucketsMs are the upper bounds of the latency histogram
+// buckets, in milliseconds.
+var defaultLatencyBucketsMs = []float64{1, 2.5, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
+// AggregationConfig represents the configuration of decision aggregation.
+// Instead of logging an event per decision, the decisions whose path matches
+// one of the paths, or all decisions if there are none, are counted per
+// interval, keyed by path, (projected) result and the selected input fields.
+type AggregationConfig struct {
+	IntervalSeconds  *int64    `json:"interval_seconds,omitempty"`
+	Paths            []string  `json:"paths,omitempty"`
+	ResultProjection *string   `json:"result_projection,omitempty"`
+	InputFields      []string  `json:"input_fields,omitempty"`
+	LatencyBucketsMs []float64 `json:"latency_buckets_ms,omitempty"`
+	paths            []string
+	resultProjection storage.Path
+	inputFields      []storage.Path
+}
+
+func (c *AggregationConfig) validateAndInjectDefaults() error {
+	interval := defaultAggregationIntervalSeconds
+	if c.IntervalSeconds != nil {
+		if *c.IntervalSeconds <= 0 {
+			return errors.New("invalid decision_log config, 'aggregation.interval_seconds' must be higher than 0")
+		}
+		interval = *c.IntervalSeconds
+	}
+	c.IntervalSeconds = &interval
+
+	c.paths = make([]string, 0, len(c.Paths))
+	for _, path := range c.Paths {
+		c.paths = append(c.paths, strings.Trim(path, "/"))
+	}
+
+	if c.ResultProjection != nil {
+		path, ok := storage.ParsePathEscaped(*c.ResultProjection)
+		if !ok {
+			return fmt.Errorf("invalid decision_log config, invalid 'aggregation.result_projection' %q", *c.ResultProjection)
+		}
+		c.resultProjection = path
+	}
+
+	c.inputFields = make([]storage.Path, 0, len(c.InputFields))
+	for _, field := range c.InputFields {
+		path, ok := storage.ParsePathEscaped(field)
+		if !ok {
+			return fmt.Errorf("invalid decision_log config, invalid 'aggregation.input_fields' %q", field)
+		}
+		c.inputFields = append(c.inputFields, path)
+	}
+
+	if c.LatencyBucketsMs == nil {
+		c.LatencyBucketsMs = defaultLatencyBucketsMs
+	} else if !slices.IsSorted(c.LatencyBucketsMs) {
+		return errors.New("invalid decision_log config, 'aggregation.latency_buckets_ms' must be sorted")
+	}
+
+	return nil
+}
+
+// AggregateV1 represents the statistics of the decisions aggregated into an
+// event. The path and (projected) result of the decisions are those of the
+// event.
+type AggregateV1 struct {
+	Start   time.Time          `json:"start"`
+	End     time.Time          `json:"end"`
+	Count   uint64             `json:"count"`
+	Errors  uint64             `json:"errors,omitempty"`
+	Input   map[string]any     `json:"input,omitempty"`
+	Latency LatencyHistogramV1 `json:"latency"`
+}
+
+// LatencyHistogramV1 represents a histogram of decision latencies. Counts
+// holds the number of decisions per bucket, where the last bucket holds those
+// slower than the last bound.
+type LatencyHistogramV1 struct {
+	BoundsMs []float64 `json:"bounds_ms"`
+	Counts   []uint64  `json:"counts"`
+	SumMs    float64   `json:"sum_ms"`
+	MinMs    float64   `json:"min_ms"`
+	MaxMs    float64   `json:"max_ms"`
+}
+
+func (h *LatencyHistogramV1) observe(ms float64, first bool) {
+	i, _ := slices.BinarySearch(h.BoundsMs, ms)
+	h.Counts[i]++
+	h.SumMs += ms
+	if first || ms < h.MinMs {
+		h.MinMs = ms
+	}
+	if first || ms > h.MaxMs {
+		h.MaxMs = ms
+	}
+}
+
+// aggregator aggregates the decisions of the current interval.
+type aggregator struct {
+	mtx     sync.Mutex
+	config  *AggregationConfig
+	start   time.Time
+	entries map[string]*aggregateEntry
+	keys    []string // in order of insertion, so that flushed events are ordered
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+type aggregateEntry struct {
+	path      string
+	result    *any
+	aggregate AggregateV1
+}
+
+func newAggregator(config *AggregationConfig) *aggregator {
+	return &aggregator{
+		config:  config,
+		start:   time.Now(),
+		entries: map[string]*aggregateEntry{},
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+}
+
+// matches returns true if decisions with the path are aggregated.
+func (a *aggregator) matches(path string) bool {
+	if len(a.config.paths) == 0 {
+		return true
+	}
+	path = strings.Trim(path, "/")
+	for _, prefix := range a.config.paths {
+		if prefix == "" || path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// Add aggregates the decision.
+func (a *aggregator) Add(decision *server.Info) error {
+	var result *any
+	if decision.Results != nil {
+		if a.config.resultProjection == nil {
+			result = decision.Results
+		} else if v, ok := lookup(*decision.Results, a.config.resultProjection); ok {
+			result = &v
+		}
+	}
+	if result != nil {
+		// Round trip, so that equal results of different types get the same key.
+		v := *result
+		if err := util.RoundTrip(&v); err != nil {
+			return err
+		}
+		result = &v
+	}
+
+	var input map[string]any
+	if decision.Input != nil && len(a.config.inputFields) > 0 {
+		x := *decision.Input
+		if err := util.RoundTrip(&x); err != nil {
+			return err
+		}
+		input = make(map[string]any, len(a.config.inputFields))
+		for i, field := range a.config.inputFields {
+			if v, ok := lookup(x, field); ok {
+				input[a.config.InputFields[i]] = v
+			}
+		}
+	}
+
+	key := string(util.MustMarshalJSON([]any{decision.Path, result, input}))
+	latency := decisionLatencyMs(decision.Metrics)
+
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	entry, ok := a.entries[key]
+	if !ok {
+		entry = &aggregateEntry{
+			path:   decision.Path,
+			result: result,
+			aggregate: AggregateV1{
+				Input: input,
+				Latency: LatencyHistogramV1{
+					BoundsMs: a.config.LatencyBucketsMs,
+					Counts:   make([]uint64, len(a.config.LatencyBucketsMs)+1),
+				},
+			},
+		}
+		a.entries[key] = entry
+		a.keys = append(a.keys, key)
+	}
+
+	entry.aggregate.Latency.observe(latency, entry.aggregate.Count == 0)
+	entry.aggregate.Count++
+	if decision.Error != nil {
+		entry.aggregate.Errors++
+	}
+
+	return nil
+}
+
+// Flush returns an event for each aggregate of the current interval, which
+// ends at now, and starts the next interval.
+func (a *aggregator) Flush(labels map[string]string, now time.Time) []EventV1 {
+	a.mtx.Lock()
+	entries, keys, start := a.entries, a.keys, a.start
+	a.entries, a.keys, a.start = map[string]*aggregateEntry{}, nil, now
+	a.mtx.Unlock()
+
+	events := make([]EventV1, 0, len(keys))
+	for _, key := range keys {
+		entry := entries[key]
+		aggregate := entry.aggregate
+		aggregate.Start = start
+		aggregate.End = now
+
+		decisionID, err := uuid.New(rand.Reader)
+		if err != nil {
+			decisionID = strconv.FormatInt(now.UnixNano(), 10) + "-" + strconv.Itoa(len(events))
+		}
+
+		events = append(events, EventV1{
+			Labels:     labels,
+			DecisionID: decisionID,
+			Path:       entry.path,
+			Result:     entry.result,
+			Timestamp:  now,
+			Aggregate:  &aggregate,
+		})
+	}
+	return events
+}
+
+// decisionLatencyMs returns the latency of the decision, which is the time
+// spent handling the request if the decision was made by the server, and the
+// time spent evaluating the query otherwise.
+func decisionLatencyMs(m metrics.Metrics) float64 {
+	if m == nil {
+		return 0
+	}
+	all := m.All()
+	for _, name := range []string{metrics.ServerHandler, metrics.RegoQueryEval} {
+		if ns, ok := all["timer_"+name+"_ns"].(int64); ok {
+			return float64(ns) / float64(time.Millisecond)
+		}
+	}
+	return 0
+}
+
+// lookup returns the value at the path in the JSON document.
+func lookup(x any, path storage.Path) (any, bool) {
+	for _, key := range path {
+		switch v := x.(type) {
+		case map[string]any:
+			var ok bool
+			if x, ok = v[key]; !ok {
+				return nil, false
+			}
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			x = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return x, true
+}
+
+// aggregateLoop flushes the aggregates at the end of each interval, and when
+// the aggregator is stopped.
+func (p *Plugin) aggregateLoop(a *aggregator) {
+	defer close(a.stopped)
+
+	ticker := time.NewTicker(time.Duration(*a.config.IntervalSeconds) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			p.flushAggregates(context.Background(), a, now)
+		case <-a.done:
+			p.flushAggregates(context.Background(), a, time.Now())
+			return
+		}
+	}
+}
+
+func (p *Plugin) flushAggregates(ctx context.Context, a *aggregator, now time.Time) {
+	for _, event := range a.Flush(p.manager.Labels(), now) {
+		if err := p.emit(ctx, event); err != nil {
+			p.logger.Error("Failed to log aggregated decisions: %v.", err)
+		}
+	}
+}
+
+// startAggregation starts aggregating decisions, if configured.
+func (p *Plugin) startAggregation() {
+	if p.config.Aggregation == nil {
+		return
+	}
+	a := newAggregator(p.config.Aggregation)
+	go p.aggregateLoop(a)
+
+	p.aggregatorMtx.Lock()
+	p.aggregator = a
+	p.aggregatorMtx.Unlock()
+}
+
+// stopAggregation stops aggregating decisions, and waits for the aggregates of
+// the current interval to be flushed.
+func (p *Plugin) stopAggregation() {
+	p.aggregatorMtx.Lock()
+	a := p.aggregator
+	p.aggregator = nil
+	p.aggregatorMtx.Unlock()
+
+	if a == nil {
+		return
+	}
+	close(a.done)
+	<-a.stopped
+}
+
+// aggregatorFor returns the aggregator of decisions with the path, or nil if
+// they are not aggregated.
+func (p *Plugin) aggregatorFor(path string) *aggregator {
+	p.aggregatorMtx.Lock()
+	a := p.aggregator
+	p.aggregatorMtx.Unlock()
+
+	if a == nil || !a.matches(path) {
+		return nil
+	}
+	return a
+}
+
+// aggregate aggregates the decision with the masked input and result of its
+// event.
+func (p *Plugin) aggregate(a *aggregator, decision *server.Info, event *EventV1) {
+	masked := *decision
+	masked.Input, masked.Results = event.Input, event.Result
+
+	if err := a.Add(&masked); err != nil {
+		p.logger.Error("Failed to aggregate decision: %v.", err)
+	} else {
+		p.incrMetric(logAggregatedCounterName)
+	}
+}
@@ -0,0 +1,348 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package logs
+
+import (
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/open-policy-agent/opa/v1/metrics"
+	"github.com/open-policy-agent/opa/v1/plugins"
+	"github.com/open-policy-agent/opa/v1/server"
+	"github.com/open-policy-agent/opa/v1/storage"
+	"github.com/open-policy-agent/opa/v1/storage/inmem"
+	"github.com/open-policy-agent/opa/v1/util"
+)
+
+func TestParseConfigAggregation(t *testing.T) {
+	tests := []struct {
+		note  string
+		raw   string
+		check func(*testing.T, *Config)
+		err   string
+	}{
+		{
+			note: "defaults",
+			raw:  `{"console": true, "aggregation": {}}`,
+			check: func(t *testing.T, c *Config) {
+				a := c.Aggregation
+				if *a.IntervalSeconds != defaultAggregationIntervalSeconds {
+					t.Fatalf("expected default interval, got %v", *a.IntervalSeconds)
+				}
+				if !slices.Equal(a.LatencyBucketsMs, defaultLatencyBucketsMs) {
+					t.Fatalf("expected default buckets, got %v", a.LatencyBucketsMs)
+				}
+				if a.resultProjection != nil || len(a.inputFields) != 0 || len(a.paths) != 0 {
+					t.Fatalf("unexpected aggregation config: %+v", a)
+				}
+			},
+		},
+		{
+			note: "projection and input fields",
+			raw: `{"console": true, "aggregation": {
+				"interval_seconds": 10,
+				"paths": ["/features/"],
+				"result_projection": "/allow",
+				"input_fields": ["/user/tenant", "/method"],
+				"latency_buckets_ms": [1, 10, 100]
+			}}`,
+			check: func(t *testing.T, c *Config) {
+				a := c.Aggregation
+				if *a.IntervalSeconds != 10 {
+					t.Fatalf("expected interval 10, got %v", *a.IntervalSeconds)
+				}
+				if exp := []string{"features"}; !slices.Equal(a.paths, exp) {
+					t.Fatalf("expected paths %v, got %v", exp, a.paths)
+				}
+				if act := a.resultProjection.String(); act != "/allow" {
+					t.Fatalf("unexpected result projection %v", act)
+				}
+				if len(a.inputFields) != 2 || a.inputFields[0].String() != "/user/tenant" {
+					t.Fatalf("unexpected input fields %v", a.inputFields)
+				}
+			},
+		},
+		{
+			note: "sink aggregation",
+			raw:  `{"sinks": {"stats": {"console": true, "aggregation": {"input_fields": ["/method"]}}}}`,
+			check: func(t *testing.T, c *Config) {
+				a := c.Sinks["stats"].config.Aggregation
+				if a == nil || len(a.inputFields) != 1 {
+					t.Fatalf("expected sink aggregation, got %+v", a)
+				}
+			},
+		},
+		{
+			note: "invalid interval",
+			raw:  `{"console": true, "aggregation": {"interval_seconds": 0}}`,
+			err:  "'aggregation.interval_seconds' must be higher than 0",
+		},
+		{
+			note: "invalid result projection",
+			raw:  `{"console": true, "aggregation": {"result_projection": "/a/%zz"}}`,
+			err:  `invalid 'aggregation.result_projection' "/a/%zz"`,
+		},
+		{
+			note: "unsorted buckets",
+			raw:  `{"console": true, "aggregation": {"latency_buckets_ms": [10, 1]}}`,
+			err:  "'aggregation.latency_buckets_ms' must be sorted",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			c, err := ParseConfig([]byte(tc.raw), nil, nil)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("expected error %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			tc.check(t, c)
+		})
+	}
+}
+
+// testLatency returns metrics with a server handler timer of the latency.
+type testLatency struct {
+	metrics.Metrics
+	latency time.Duration
+}
+
+func (m testLatency) All() map[string]any {
+	return map[string]any{"timer_" + metrics.ServerHandler + "_ns": m.latency.Nanoseconds()}
+}
+
+func aggregateDecision(path string, input any, result any, latency time.Duration) *server.Info {
+	return &server.Info{
+		Path:    path,
+		Input:   &input,
+		Results: &result,
+		Metrics: testLatency{Metrics: metrics.New(), latency: latency},
+	}
+}
+
+func TestAggregator(t *testing.T) {
+	config, err := ParseConfig([]byte(`{"console": true, "aggregation": {
+		"result_projection": "/allow",
+		"input_fields": ["/user/tenant", "/missing"],
+		"latency_buckets_ms": [1, 10]
+	}}`), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := newAggregator(config.Aggregation)
+	start := a.start
+
+	decisions := []*server.Info{
+		aggregateDecision("authz/allow", map[string]any{"user": map[string]any{"tenant": "a"}}, map[string]any{"allow": true, "reason": "x"}, 500*time.Microsecond),
+		aggregateDecision("authz/allow", map[string]any{"user": map[string]any{"tenant": "a"}}, map[string]any{"allow": true, "reason": "y"}, 5*time.Millisecond),
+		aggregateDecision("authz/allow", map[string]any{"user": map[string]any{"tenant": "a"}}, map[string]any{"allow": true}, 20*time.Millisecond),
+		aggregateDecision("authz/allow", map[string]any{"user": map[string]any{"tenant": "b"}}, map[string]any{"allow": false}, 2*time.Millisecond),
+	}
+	decisions[3].Error = errors.New("boom")
+
+	for _, d := range decisions {
+		if err := a.Add(d); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	end := start.Add(time.Minute)
+	events := a.Flush(map[string]string{"id": "test"}, end)
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+
+	exp := []AggregateV1{
+		{
+			Start: start,
+			End:   end,
+			Count: 3,
+			Input: map[string]any{"/user/tenant": "a"},
+			Latency: LatencyHistogramV1{
+				BoundsMs: []float64{1, 10},
+				Counts:   []uint64{1, 1, 1},
+				SumMs:    25.5,
+				MinMs:    0.5,
+				MaxMs:    20,
+			},
+		},
+		{
+			Start:  start,
+			End:    end,
+			Count:  1,
+			Errors: 1,
+			Input:  map[string]any{"/user/tenant": "b"},
+			Latency: LatencyHistogramV1{
+				BoundsMs: []float64{1, 10},
+				Counts:   []uint64{0, 1, 0},
+				SumMs:    2,
+				MinMs:    2,
+				MaxMs:    2,
+			},
+		},
+	}
+
+	for i, event := range events {
+		if event.Path != "authz/allow" || event.DecisionID == "" || !event.Timestamp.Equal(end) || event.Labels["id"] != "test" {
+			t.Fatalf("unexpected event %d: %+v", i, event)
+		}
+		if exp, act := i == 0, *event.Result; act != exp {
+			t.Fatalf("expected projected result %v for event %d, got %v", exp, i, act)
+		}
+		if !reflect.DeepEqual(*event.Aggregate, exp[i]) {
+			t.Fatalf("expected aggregate %d:\n%+v\ngot:\n%+v", i, exp[i], *event.Aggregate)
+		}
+	}
+
+	if events := a.Flush(nil, end.Add(time.Minute)); len(events) != 0 {
+		t.Fatalf("expected no events after flush, got %v", events)
+	}
+	if !a.start.Equal(end.Add(time.Minute)) {
+		t.Fatalf("expected next interval to start at %v, got %v", end.Add(time.Minute), a.start)
+	}
+}
+
+func TestPluginAggregation(t *testing.T) {
+	ctx := t.Context()
+
+	ts := &testEventServer{}
+	srv := httptest.NewServer(ts)
+	defer srv.Close()
+
+	manager, err := plugins.New(fmt.Appendf(nil, `{"services": {"s0": {"url": %q}}}`, srv.URL), "test", inmem.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw := `{"aggregation": {"paths": ["features"], "input_fields": ["/tenant"]}}`
+	config, err := parseManualConfig(raw, manager.Services())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := New(config, manager)
+	if err := p.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, path := range []string{"features/enabled", "features/enabled", "authz/allow"} {
+		decision := aggregateDecision(path, map[string]any{"tenant": "a", "user": "alice"}, true, time.Millisecond)
+		decision.DecisionID = fmt.Sprint(i)
+		if err := p.Log(ctx, decision); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Reconfiguring flushes the aggregates of the current interval.
+	p.Reconfigure(ctx, config)
+
+	if err := p.Trigger(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	events := ts.uploaded()
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %v", events)
+	}
+	if events[0]["decision_id"] != "2" || events[0]["aggregate"] != nil {
+		t.Fatalf("expected decision 2 to be uploaded, got %v", events[0])
+	}
+
+	aggregate := events[1]["aggregate"]
+	if events[1]["path"] != "features/enabled" || events[1]["result"] != true || events[1]["input"] != nil {
+		t.Fatalf("unexpected aggregated event %v", events[1])
+	}
+	var act AggregateV1
+	if err := util.Unmarshal(util.MustMarshalJSON(aggregate), &act); err != nil {
+		t.Fatal(err)
+	}
+	if act.Count != 2 || !reflect.DeepEqual(act.Input, map[string]any{"/tenant": "a"}) || act.Latency.SumMs != 2 {
+		t.Fatalf("unexpected aggregate %+v", act)
+	}
+
+	p.Stop(ctx)
+}
+
+func TestPluginAggregationMask(t *testing.T) {
+	ctx := t.Context()
+
+	ts := &testEventServer{}
+	srv := httptest.NewServer(ts)
+	defer srv.Close()
+
+	store := inmem.New()
+	policy := `package system.log
+
+mask contains "/input/user"
+
+mask contains {"op": "upsert", "path": "/input/tenant", "value": "***"}
+`
+	err := storage.Txn(ctx, store, storage.WriteParams, func(txn storage.Transaction) error {
+		return store.UpsertPolicy(ctx, txn, "test.rego", []byte(policy))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manager, err := plugins.New(fmt.Appendf(nil, `{"services": {"s0": {"url": %q}}}`, srv.URL), "test", store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	raw := `{"aggregation": {"input_fields": ["/tenant", "/user", "/action"]}}`
+	config, err := parseManualConfig(raw, manager.Services())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := New(config, manager)
+	if err := p.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, user := range []string{"alice", "bob"} {
+		decision := aggregateDecision("authz/allow", map[string]any{"tenant": "a", "user": user, "action": "read"}, true, time.Millisecond)
+		decision.DecisionID = fmt.Sprint(i)
+		if err := p.Log(ctx, decision); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p.Reconfigure(ctx, config)
+
+	if err := p.Trigger(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// The masked fields are not used to group the decisions, and not logged.
+	events := ts.uploaded()
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %v", events)
+	}
+	var act AggregateV1
+	if err := util.Unmarshal(util.MustMarshalJSON(events[0]["aggregate"]), &act); err != nil {
+		t.Fatal(err)
+	}
+	if exp := map[string]any{"/tenant": "***", "/action": "read"}; act.Count != 2 || !reflect.DeepEqual(act.Input, exp) {
+		t.Fatalf("expected 2 decisions with input %v, got %+v", exp, act)
+	}
+
+	p.Stop(ctx)
+}
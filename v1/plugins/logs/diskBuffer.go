@@ -0,0 +1,720 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package logs
+
+import (
+	"cmp"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/open-policy-agent/opa/v1/logging"
+	"github.com/open-policy-agent/opa/v1/metrics"
+	"github.com/open-policy-agent/opa/v1/plugins"
+	"github.com/open-policy-agent/opa/v1/plugins/rest"
+	"github.com/open-policy-agent/opa/v1/util"
+	"golang.org/x/time/rate"
+)
+
+const (
+	diskSegmentExt        = ".log"
+	diskRecordHeaderSize  = 8 // uint32 length and CRC-32 checksum of the record
+	diskFsyncPeriodicTick = time.Second
+)
+
+// diskSegment is a file of the disk buffer. Segments are append-only files of
+// records, each holding a JSON encoded event. Only the newest segment, the
+// active segment, is written to. Segments are removed once all of their events
+// are uploaded, or when they are dropped to stay within the buffer size limit.
+type diskSegment struct {
+	id     uint64
+	path   string
+	size   int64
+	events int
+	f      *os.File // open while the segment is active
+}
+
+// diskBuffer stores events in segment files on disk, so that events that are
+// not yet uploaded survive restarts and outlast long outages of the log
+// service. Events are encoded into gzip compressed JSON arrays when uploaded,
+// like the in-memory buffers do. A segment is only removed once all of its
+// chunks are uploaded. When an upload fails part way, the events of the chunks
+// already uploaded are removed from the segment, so that only the others are
+// uploaded again. Events may still be uploaded more than once if OPA stops
+// before the segment is removed.
+type diskBuffer struct {
+	mtx         sync.Mutex // guards the segments and the active segment
+	uploadMtx   sync.Mutex // serializes uploads and guards the encoder
+	dir         string
+	limit       int64 // limit on the size of all segments, 0 for unlimited
+	segmentSize int64
+	fsync       string
+	loaded      bool
+	segments    []*diskSegment // oldest first, the last one may be active
+	active      *diskSegment
+	nextID      uint64
+	usage       int64
+	pending     int64 // bytes written since the last upload
+	dirty       bool  // the active segment has writes that are not synced
+	enc         *chunkEncoder
+	format      *eventFormat
+	uploadLimit int64
+	limiter     *rate.Limiter
+	metrics     metrics.Metrics
+	logger      logging.Logger
+	client      rest.Client
+	uploadPath  string
+	mode        plugins.TriggerMode
+	upload      chan struct{}
+	stop        chan chan struct{}
+}
+
+func newDiskBuffer(dir string, bufferSizeLimitBytes int64, segmentSizeBytes int64, fsync string, uploadSizeLimitBytes int64, client rest.Client, uploadPath string, mode plugins.TriggerMode) *diskBuffer {
+	if bufferSizeLimitBytes > 0 {
+		segmentSizeBytes = min(segmentSizeBytes, bufferSizeLimitBytes)
+	}
+
+	b := &diskBuffer{
+		dir:         dir,
+		limit:       bufferSizeLimitBytes,
+		segmentSize: segmentSizeBytes,
+		fsync:       fsync,
+		nextID:      1,
+		enc:         newChunkEncoder(uploadSizeLimitBytes),
+		format:      jsonEventFormat,
+		uploadLimit: uploadSizeLimitBytes,
+		client:      client,
+		uploadPath:  uploadPath,
+		mode:        mode,
+	}
+
+	if b.mode == plugins.TriggerImmediate || b.fsync == diskBufferFsyncPeriodic {
+		b.upload = make(chan struct{}, 1)
+		b.stop = make(chan chan struct{})
+		go b.loop()
+	}
+
+	return b
+}
+
+func (b *diskBuffer) WithLimiter(maxDecisionsPerSecond *float64) *diskBuffer {
+	if maxDecisionsPerSecond != nil {
+		b.limiter = rate.NewLimiter(rate.Limit(*maxDecisionsPerSecond), int(math.Max(1, *maxDecisionsPerSecond)))
+	}
+	return b
+}
+
+// WithFormat sets the encoding of the uploaded events. Events are stored on
+// disk as JSON regardless, and encoded when they are uploaded.
+func (b *diskBuffer) WithFormat(format *eventFormat) *diskBuffer {
+	b.format = format
+	b.enc = b.enc.WithFormat(format)
+	return b
+}
+
+func (b *diskBuffer) WithMetrics(m metrics.Metrics) {
+	b.metrics = m
+	b.enc = b.enc.WithMetrics(m)
+}
+
+func (b *diskBuffer) WithLogger(l logging.Logger) *diskBuffer {
+	b.logger = l
+	b.enc = b.enc.WithLogger(l)
+	return b
+}
+
+func (*diskBuffer) Name() string {
+	return diskBufferType
+}
+
+func (b *diskBuffer) incrMetric(name string) {
+	if b.metrics != nil {
+		b.metrics.Counter(name).Incr()
+	}
+}
+
+func (b *diskBuffer) updateUsage(delta int64) {
+	b.usage += delta
+	if b.metrics != nil {
+		b.metrics.Histogram(logBufferDiskBytesHistogramName).Update(b.usage)
+	}
+}
+
+// Push writes the event to the active segment.
+func (b *diskBuffer) Push(event *EventV1) {
+	if b.limiter != nil && !b.limiter.Allow() {
+		b.incrMetric(logRateLimitExDropCounterName)
+		if b.logger != nil {
+			b.logger.Error("Decision log dropped as rate limit exceeded. Reduce reporting interval or increase rate limit.")
+		}
+		return
+	}
+
+	eventBytes, err := json.Marshal(&event)
+	if err != nil {
+		if b.logger != nil {
+			b.logger.Error("Decision log dropped due to error serializing event to JSON with decision ID %v", event.DecisionID)
+		}
+		return
+	}
+
+	b.mtx.Lock()
+	err = b.write(eventBytes)
+	ready := b.mode == plugins.TriggerImmediate && b.pending >= b.uploadLimit
+	b.mtx.Unlock()
+
+	if err != nil {
+		b.incrMetric(logBufferDiskWriteFailureCounterName)
+		if b.logger != nil {
+			b.logger.Error("Decision log dropped due to error writing to the disk buffer with decision ID %v: %v", event.DecisionID, err)
+		}
+		return
+	}
+
+	if ready {
+		select {
+		case b.upload <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// write appends a record to the active segment, dropping the oldest segments
+// if the buffer size limit would be exceeded.
+func (b *diskBuffer) write(bs []byte) error {
+	if err := b.load(); err != nil {
+		return err
+	}
+
+	size := int64(len(bs)) + diskRecordHeaderSize
+	if b.limit > 0 {
+		if size > b.limit {
+			b.dropped(1)
+			return nil
+		}
+		for b.usage+size > b.limit && len(b.segments) > 0 {
+			b.drop(b.segments[0])
+		}
+	}
+
+	if b.active == nil {
+		if err := b.rotate(); err != nil {
+			return err
+		}
+	}
+
+	seg := b.active
+	if _, err := seg.f.Write(diskRecord(bs)); err != nil {
+		// The segment may end with a partial record now, which is truncated
+		// when the segment is read. Continue in a new segment.
+		b.seal()
+		return err
+	}
+
+	seg.size += size
+	seg.events++
+	b.pending += size
+	b.updateUsage(size)
+
+	b.dirty = true
+	if b.fsync == diskBufferFsyncAlways {
+		b.sync()
+	}
+
+	if seg.size >= b.segmentSize {
+		b.seal()
+	}
+
+	return nil
+}
+
+// diskRecord returns the record holding the encoded event.
+func diskRecord(bs []byte) []byte {
+	record := make([]byte, len(bs)+diskRecordHeaderSize)
+	binary.BigEndian.PutUint32(record[0:4], uint32(len(bs)))
+	binary.BigEndian.PutUint32(record[4:8], crc32.ChecksumIEEE(bs))
+	copy(record[diskRecordHeaderSize:], bs)
+	return record
+}
+
+// rotate creates a new active segment.
+func (b *diskBuffer) rotate() error {
+	id := b.nextID
+	path := filepath.Join(b.dir, fmt.Sprintf("%020d%s", id, diskSegmentExt))
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+
+	b.nextID++
+	b.active = &diskSegment{id: id, path: path, f: f}
+	b.segments = append(b.segments, b.active)
+	return nil
+}
+
+// seal closes the active segment. Subsequent writes create a new segment.
+func (b *diskBuffer) seal() {
+	if b.active == nil {
+		return
+	}
+	b.sync()
+	if err := b.active.f.Close(); err != nil && b.logger != nil {
+		b.logger.Error("Failed to close disk buffer segment %v: %v", b.active.path, err)
+	}
+	b.active.f = nil
+	b.active = nil
+}
+
+func (b *diskBuffer) sync() {
+	if b.active == nil || !b.dirty {
+		return
+	}
+	b.dirty = false
+	if b.fsync == diskBufferFsyncNever {
+		return
+	}
+	if err := b.active.f.Sync(); err != nil {
+		b.incrMetric(logBufferDiskWriteFailureCounterName)
+		if b.logger != nil {
+			b.logger.Error("Failed to sync disk buffer segment %v: %v", b.active.path, err)
+		}
+	}
+}
+
+// drop removes a segment to make space for new events.
+func (b *diskBuffer) drop(seg *diskSegment) {
+	if b.remove(seg) {
+		b.dropped(seg.events)
+	}
+}
+
+// dropped records events dropped to stay within the buffer size limit, which
+// is the only limit of the disk buffer.
+func (b *diskBuffer) dropped(n int) {
+	if b.metrics != nil {
+		b.metrics.Counter(logBufferSizeLimitExDropCounterName).Add(uint64(n))
+	}
+	if b.logger != nil {
+		b.logger.Error("Dropped %v events from disk buffer. Reduce reporting interval or increase buffer size.", n)
+	}
+}
+
+// remove deletes a segment from disk, if it has not been removed already.
+func (b *diskBuffer) remove(seg *diskSegment) bool {
+	i := slices.Index(b.segments, seg)
+	if i < 0 {
+		return false
+	}
+	if seg == b.active {
+		b.seal()
+	}
+	if err := os.Remove(seg.path); err != nil && !errors.Is(err, fs.ErrNotExist) && b.logger != nil {
+		b.logger.Error("Failed to remove disk buffer segment %v: %v", seg.path, err)
+	}
+	b.segments = slices.Delete(b.segments, i, i+1)
+	b.updateUsage(-seg.size)
+	return true
+}
+
+// load reads the segments left on disk by a previous process, so that their
+// events are uploaded.
+func (b *diskBuffer) load() error {
+	if b.loaded {
+		return nil
+	}
+
+	if err := os.MkdirAll(b.dir, 0o700); err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(b.dir)
+	if err != nil {
+		return err
+	}
+
+	var events int
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() && strings.HasSuffix(name, diskSegmentExt+".tmp") {
+			// left over by a segment being trimmed when OPA stopped
+			if err := os.Remove(filepath.Join(b.dir, name)); err != nil {
+				return err
+			}
+			continue
+		}
+		if entry.IsDir() || !strings.HasSuffix(name, diskSegmentExt) {
+			continue
+		}
+		id, err := strconv.ParseUint(strings.TrimSuffix(name, diskSegmentExt), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		path := filepath.Join(b.dir, name)
+		records, size, err := readDiskSegment(path)
+		if err != nil {
+			return err
+		}
+		if info, err := entry.Info(); err == nil && info.Size() > size {
+			if b.logger != nil {
+				b.logger.Warn("Truncating incomplete disk buffer segment %v at %d bytes.", path, size)
+			}
+			if err := os.Truncate(path, size); err != nil {
+				return err
+			}
+		}
+		if len(records) == 0 {
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+			continue
+		}
+
+		b.segments = append(b.segments, &diskSegment{id: id, path: path, size: size, events: len(records)})
+		b.nextID = max(b.nextID, id+1)
+		b.updateUsage(size)
+		events += len(records)
+	}
+
+	slices.SortFunc(b.segments, func(x, y *diskSegment) int {
+		return cmp.Compare(x.id, y.id)
+	})
+
+	b.loaded = true
+
+	if events > 0 && b.logger != nil {
+		b.logger.Info("Loaded %d decision log events from disk buffer.", events)
+	}
+
+	for b.limit > 0 && b.usage > b.limit && len(b.segments) > 0 {
+		b.drop(b.segments[0])
+	}
+
+	return nil
+}
+
+// readDiskSegment returns the records of a segment, and the size of the
+// segment up to the first incomplete or corrupt record, if any.
+func readDiskSegment(path string) ([][]byte, int64, error) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var records [][]byte
+	var offset int
+	for len(bs)-offset >= diskRecordHeaderSize {
+		n := int(binary.BigEndian.Uint32(bs[offset : offset+4]))
+		sum := binary.BigEndian.Uint32(bs[offset+4 : offset+8])
+		start := offset + diskRecordHeaderSize
+		if n > len(bs)-start || crc32.ChecksumIEEE(bs[start:start+n]) != sum {
+			break
+		}
+		records = append(records, bs[start:start+n])
+		offset = start + n
+	}
+
+	return records, int64(offset), nil
+}
+
+// diskEvent decodes events read from disk. EventV1.Error cannot be decoded, so
+// it is kept as the raw JSON value, which is encoded as is when uploaded.
+type diskEvent struct {
+	EventV1
+	Error json.RawMessage `json:"error,omitempty"`
+}
+
+type rawEventError json.RawMessage
+
+func (e rawEventError) Error() string {
+	return string(e)
+}
+
+func (e rawEventError) MarshalJSON() ([]byte, error) {
+	return e, nil
+}
+
+func decodeDiskEvent(bs []byte) (*EventV1, error) {
+	var e diskEvent
+	if err := util.UnmarshalJSON(bs, &e); err != nil {
+		return nil, err
+	}
+	if e.Error != nil {
+		e.EventV1.Error = rawEventError(e.Error)
+	}
+	return &e.EventV1, nil
+}
+
+// Upload seals the active segment, and uploads and removes the segments one
+// after another, oldest first. Events pushed during the upload are written to
+// a new segment.
+func (b *diskBuffer) Upload(ctx context.Context) error {
+	b.uploadMtx.Lock()
+	defer b.uploadMtx.Unlock()
+
+	b.mtx.Lock()
+	if err := b.load(); err != nil {
+		b.mtx.Unlock()
+		return err
+	}
+	b.seal()
+	b.pending = 0
+	segments := slices.Clone(b.segments)
+	b.mtx.Unlock()
+
+	if len(segments) == 0 {
+		return &bufferEmpty{}
+	}
+
+	for _, seg := range segments {
+		if err := b.uploadSegment(ctx, seg); err != nil {
+			return err
+		}
+
+		b.mtx.Lock()
+		b.remove(seg)
+		b.mtx.Unlock()
+	}
+
+	return nil
+}
+
+func (b *diskBuffer) uploadSegment(ctx context.Context, seg *diskSegment) error {
+	records, _, err := readDiskSegment(seg.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		// dropped since the upload started
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	// The decision IDs of the records locate the events of the uploaded
+	// chunks in the segment if the upload fails.
+	ids := make([]string, len(records))
+
+	var chunks [][]byte
+	for i, bs := range records {
+		event, err := decodeDiskEvent(bs)
+		if err != nil {
+			b.incrMetric(logEncodingFailureCounterName)
+			if b.logger != nil {
+				b.logger.Error("Dropping event from disk buffer due to decoding failure: %v", err)
+			}
+			continue
+		}
+		ids[i] = event.DecisionID
+
+		if !b.format.isJSON() {
+			if bs, err = b.format.marshal(event); err != nil {
+				b.incrMetric(logEncodingFailureCounterName)
+				if b.logger != nil {
+					b.logger.Error("Dropping event due to encoding failure with decision ID: %v", event.DecisionID)
+				}
+				continue
+			}
+		}
+
+		result, err := b.enc.Encode(*event, bs)
+		if err != nil {
+			b.incrMetric(logEncodingFailureCounterName)
+			if b.logger != nil {
+				b.logger.Error("Dropping event due to encoding failure with decision ID: %v", event.DecisionID)
+			}
+			continue
+		}
+		chunks = append(chunks, result...)
+	}
+
+	result, err := b.enc.Flush()
+	if err != nil {
+		return err
+	}
+	chunks = append(chunks, result...)
+
+	for i, chunk := range chunks {
+		if err := uploadChunk(ctx, b.client, b.uploadPath, chunk, b.format); err != nil {
+			if i > 0 {
+				if n := b.uploadedRecords(chunks[i-1], ids); n > 0 {
+					if err := b.trim(seg, records, n); err != nil && b.logger != nil {
+						b.logger.Error("Failed to remove uploaded events from disk buffer segment %v: %v", seg.path, err)
+					}
+				}
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// uploadedRecords returns the number of records at the start of a segment
+// whose events are in the given uploaded chunk or the ones before it. The
+// chunks hold the events in the order of the records, so the last event of the
+// chunk is the last uploaded one. Zero is returned if it cannot be located.
+func (b *diskBuffer) uploadedRecords(chunk []byte, ids []string) int {
+	events, err := newChunkDecoder(chunk).WithFormat(b.format).decode()
+	if err != nil || len(events) == 0 {
+		return 0
+	}
+
+	last := events[len(events)-1].DecisionID
+	if last == "" {
+		return 0
+	}
+	return slices.Index(ids, last) + 1
+}
+
+// trim removes the first n records of a segment, so that their events are not
+// uploaded again. The remaining records are written to a new file, which
+// replaces the segment.
+func (b *diskBuffer) trim(seg *diskSegment, records [][]byte, n int) error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if !slices.Contains(b.segments, seg) {
+		// dropped since the upload started
+		return nil
+	}
+	if n >= len(records) {
+		b.remove(seg)
+		return nil
+	}
+
+	var size int64
+	tmp := seg.path + ".tmp"
+	err := func() error {
+		f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		for _, bs := range records[n:] {
+			k, err := f.Write(diskRecord(bs))
+			if err != nil {
+				return err
+			}
+			size += int64(k)
+		}
+		if b.fsync != diskBufferFsyncNever {
+			if err := f.Sync(); err != nil {
+				return err
+			}
+		}
+		return f.Close()
+	}()
+	if err == nil {
+		err = os.Rename(tmp, seg.path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+
+	b.updateUsage(size - seg.size)
+	seg.size = size
+	seg.events = len(records) - n
+	return nil
+}
+
+// Flush removes all segments and returns their events.
+func (b *diskBuffer) Flush() []*EventV1 {
+	b.uploadMtx.Lock()
+	defer b.uploadMtx.Unlock()
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if err := b.load(); err != nil {
+		if b.logger != nil {
+			b.logger.Error("Failed to load disk buffer: %v", err)
+		}
+		return nil
+	}
+	b.seal()
+
+	var events []*EventV1
+	for _, seg := range slices.Clone(b.segments) {
+		records, _, err := readDiskSegment(seg.path)
+		if err != nil && b.logger != nil {
+			b.logger.Error("Failed to read disk buffer segment %v: %v", seg.path, err)
+		}
+
+		for _, bs := range records {
+			event, err := decodeDiskEvent(bs)
+			if err != nil {
+				b.incrMetric(logEncodingFailureCounterName)
+				if b.logger != nil {
+					b.logger.Error("Dropping event from disk buffer due to decoding failure: %v", err)
+				}
+				continue
+			}
+			events = append(events, event)
+		}
+
+		b.remove(seg)
+	}
+
+	return events
+}
+
+// Stop stops the background loop and closes the active segment. The buffer
+// can still be uploaded or flushed afterwards.
+func (b *diskBuffer) Stop(ctx context.Context) {
+	if b.stop != nil {
+		done := make(chan struct{})
+		select {
+		case b.stop <- done:
+			<-done
+		case <-ctx.Done():
+		}
+		b.stop = nil
+	}
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.seal()
+}
+
+// loop uploads events as soon as a chunk worth of events is written in
+// immediate mode, and syncs the active segment with the periodic fsync policy.
+func (b *diskBuffer) loop() {
+	ctx := context.Background()
+
+	var tick <-chan time.Time
+	if b.fsync == diskBufferFsyncPeriodic {
+		ticker := time.NewTicker(diskFsyncPeriodicTick)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-tick:
+			b.mtx.Lock()
+			b.sync()
+			b.mtx.Unlock()
+		case <-b.upload:
+			if err := b.Upload(ctx); err != nil && !errors.Is(err, &bufferEmpty{}) && b.logger != nil {
+				b.logger.Error("Failed to upload decision logs, events have been buffered and will be retried. Error: %v", err)
+			}
+		case done := <-b.stop:
+			done <- struct{}{}
+			return
+		}
+	}
+}
@@ -0,0 +1,516 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package logs
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/open-policy-agent/opa/v1/logging"
+	"github.com/open-policy-agent/opa/v1/metrics"
+	"github.com/open-policy-agent/opa/v1/plugins"
+	"github.com/open-policy-agent/opa/v1/plugins/rest"
+	"github.com/open-policy-agent/opa/v1/server/types"
+	"github.com/open-policy-agent/opa/v1/util/test"
+)
+
+// testLogServer collects the decision IDs of uploaded events, failing uploads
+// while fail is set, and the failAt-th upload if set.
+type testLogServer struct {
+	mtx      sync.Mutex
+	ids      []string
+	fail     bool
+	failAt   int
+	requests int
+}
+
+func (s *testLogServer) handle(t *testing.T) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mtx.Lock()
+		defer s.mtx.Unlock()
+		s.requests++
+		if s.fail || s.requests == s.failAt {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		// EventV1 cannot decode events with errors, so only decode the IDs.
+		gr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		var events []struct {
+			DecisionID string `json:"decision_id"`
+		}
+		if err := json.NewDecoder(gr).Decode(&events); err != nil {
+			t.Error(err)
+			return
+		}
+		for _, e := range events {
+			s.ids = append(s.ids, e.DecisionID)
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func (s *testLogServer) uploaded() []string {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return slices.Clone(s.ids)
+}
+
+func segmentFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*"+diskSegmentExt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func TestDiskBuffer_Upload(t *testing.T) {
+	t.Parallel()
+
+	uploadPath := "/v1/test"
+	srv := &testLogServer{}
+	client, ts := setupTestServer(t, uploadPath, srv.handle(t))
+	defer ts.Close()
+
+	dir := t.TempDir()
+	// Each test event takes ~200 bytes, so segments hold a few events each.
+	b := newDiskBuffer(dir, 0, 512, diskBufferFsyncAlways, 196, client, uploadPath, plugins.TriggerPeriodic).WithLogger(logging.NewNoOpLogger())
+	b.WithMetrics(metrics.New())
+
+	var exp []string
+	for i := range 10 {
+		id := strconv.Itoa(i)
+		exp = append(exp, id)
+		b.Push(newTestEvent(t, id, false))
+	}
+
+	if n := len(segmentFiles(t, dir)); n < 2 {
+		t.Fatalf("expected multiple segments, got %d", n)
+	}
+
+	if err := b.Upload(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+
+	if act := srv.uploaded(); !slices.Equal(act, exp) {
+		t.Fatalf("expected events %v to be uploaded, got %v", exp, act)
+	}
+	if files := segmentFiles(t, dir); len(files) != 0 {
+		t.Fatalf("expected segments to be removed, got %v", files)
+	}
+	if b.usage != 0 {
+		t.Fatalf("expected no usage, got %d", b.usage)
+	}
+
+	if err := b.Upload(t.Context()); !errors.Is(err, &bufferEmpty{}) {
+		t.Fatalf("expected buffer empty error, got %v", err)
+	}
+}
+
+func TestDiskBuffer_UploadFailure(t *testing.T) {
+	t.Parallel()
+
+	uploadPath := "/v1/test"
+	srv := &testLogServer{fail: true}
+	client, ts := setupTestServer(t, uploadPath, srv.handle(t))
+	defer ts.Close()
+
+	dir := t.TempDir()
+	b := newDiskBuffer(dir, 0, defaultBufferSegmentSizeBytes, diskBufferFsyncAlways, defaultUploadSizeLimitBytes, client, uploadPath, plugins.TriggerPeriodic).WithLogger(logging.NewNoOpLogger())
+
+	b.Push(newTestEvent(t, "1", false))
+
+	err := b.Upload(t.Context())
+	if err == nil || err.Error() != "log upload failed, server replied with HTTP 500 Internal Server Error" {
+		t.Fatalf("expected upload error, got %v", err)
+	}
+	if n := len(segmentFiles(t, dir)); n != 1 {
+		t.Fatalf("expected segment to be kept, got %d segments", n)
+	}
+
+	// events pushed after a failed upload are uploaded after the buffered ones
+	b.Push(newTestEvent(t, "2", false))
+
+	srv.mtx.Lock()
+	srv.fail = false
+	srv.mtx.Unlock()
+
+	if err := b.Upload(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := srv.uploaded(), []string{"1", "2"}; !slices.Equal(act, exp) {
+		t.Fatalf("expected events %v to be uploaded, got %v", exp, act)
+	}
+}
+
+func TestDiskBuffer_UploadPartialFailure(t *testing.T) {
+	t.Parallel()
+
+	uploadPath := "/v1/test"
+	srv := &testLogServer{failAt: 4}
+	client, ts := setupTestServer(t, uploadPath, srv.handle(t))
+	defer ts.Close()
+
+	dir := t.TempDir()
+	// A single segment is uploaded in chunks of a few events each.
+	b := newDiskBuffer(dir, 0, defaultBufferSegmentSizeBytes, diskBufferFsyncAlways, 196, client, uploadPath, plugins.TriggerPeriodic).WithLogger(logging.NewNoOpLogger())
+
+	var exp []string
+	for i := range 10 {
+		id := strconv.Itoa(i)
+		exp = append(exp, id)
+		b.Push(newTestEvent(t, id, false))
+	}
+	usage := b.usage
+
+	if err := b.Upload(t.Context()); err == nil {
+		t.Fatal("expected upload error")
+	}
+	uploaded := srv.uploaded()
+	if len(uploaded) == 0 || len(uploaded) == len(exp) {
+		t.Fatalf("expected some events to be uploaded, got %v", uploaded)
+	}
+
+	// The uploaded events are removed from the segment.
+	if n := len(segmentFiles(t, dir)); n != 1 {
+		t.Fatalf("expected segment to be kept, got %d segments", n)
+	}
+	if b.usage >= usage {
+		t.Fatalf("expected usage to shrink from %d, got %d", usage, b.usage)
+	}
+
+	if err := b.Upload(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	if act := srv.uploaded(); !slices.Equal(act, exp) {
+		t.Fatalf("expected events %v to be uploaded once, got %v", exp, act)
+	}
+	if b.usage != 0 {
+		t.Fatalf("expected no usage, got %d", b.usage)
+	}
+}
+
+func TestDiskBuffer_Restart(t *testing.T) {
+	t.Parallel()
+
+	uploadPath := "/v1/test"
+	srv := &testLogServer{}
+	client, ts := setupTestServer(t, uploadPath, srv.handle(t))
+	defer ts.Close()
+
+	dir := t.TempDir()
+	b := newDiskBuffer(dir, 0, 512, diskBufferFsyncPeriodic, defaultUploadSizeLimitBytes, client, uploadPath, plugins.TriggerPeriodic)
+	for i := range 5 {
+		b.Push(newTestEvent(t, strconv.Itoa(i), true))
+	}
+	event := newTestEvent(t, "5", false)
+	event.Error = types.NewErrorV1(types.CodeInternal, "eval failed")
+	b.Push(event)
+	b.Stop(t.Context())
+
+	// A write was torn by the previous process.
+	files := segmentFiles(t, dir)
+	last := files[len(files)-1]
+	f, err := os.OpenFile(last, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte{0, 0, 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	m := metrics.New()
+	b = newDiskBuffer(dir, 0, 512, diskBufferFsyncPeriodic, defaultUploadSizeLimitBytes, client, uploadPath, plugins.TriggerPeriodic).WithLogger(logging.NewNoOpLogger())
+	b.WithMetrics(m)
+	defer b.Stop(t.Context())
+
+	b.Push(newTestEvent(t, "6", false))
+
+	events := b.Flush()
+	var ids []string
+	for _, e := range events {
+		ids = append(ids, e.DecisionID)
+	}
+	if exp := []string{"0", "1", "2", "3", "4", "5", "6"}; !slices.Equal(ids, exp) {
+		t.Fatalf("expected events %v, got %v", exp, ids)
+	}
+	if events[0].NDBuiltinCache == nil {
+		t.Fatal("expected ND builtin cache to be restored")
+	}
+	if bs, err := events[5].Error.(rawEventError).MarshalJSON(); err != nil || string(bs) != `{"code":"internal_error","message":"eval failed"}` {
+		t.Fatalf("expected error to be restored, got %s", bs)
+	}
+
+	// Flushed events are removed from disk, so they can be pushed again.
+	if files := segmentFiles(t, dir); len(files) != 0 {
+		t.Fatalf("expected segments to be removed, got %v", files)
+	}
+	for _, e := range events {
+		b.Push(e)
+	}
+	if err := b.Upload(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(srv.uploaded()); n != len(events) {
+		t.Fatalf("expected %d events to be uploaded, got %d", len(events), n)
+	}
+}
+
+func TestDiskBuffer_SizeLimit(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	m := metrics.New()
+	event := newTestEvent(t, "0", false)
+	size := int64(len(mustMarshalEvent(t, event))) + diskRecordHeaderSize
+
+	// Room for two and a half events, in segments of a single event.
+	b := newDiskBuffer(dir, size*5/2, 1, diskBufferFsyncNever, defaultUploadSizeLimitBytes, rest.Client{}, "", plugins.TriggerManual).WithLogger(logging.NewNoOpLogger())
+	b.WithMetrics(m)
+
+	for i := range 4 {
+		b.Push(newTestEvent(t, strconv.Itoa(i), false))
+	}
+
+	if n := len(segmentFiles(t, dir)); n != 2 {
+		t.Fatalf("expected 2 segments, got %d", n)
+	}
+	if b.usage > b.limit {
+		t.Fatalf("expected usage %d to be within limit %d", b.usage, b.limit)
+	}
+	if n := m.Counter(logBufferSizeLimitExDropCounterName).Value(); n != uint64(2) {
+		t.Fatalf("expected 2 dropped events, got %v", n)
+	}
+
+	// Events larger than the limit are dropped.
+	large := newTestEvent(t, "large", false)
+	var input any = map[string]any{"data": string(make([]byte, size*3))}
+	large.Input = &input
+	b.Push(large)
+
+	if n := m.Counter(logBufferSizeLimitExDropCounterName).Value(); n != uint64(3) {
+		t.Fatalf("expected 3 dropped events, got %v", n)
+	}
+	// Each dropped event is only counted once.
+	if n := m.Counter(logBufferEventDropCounterName).Value(); n != uint64(0) {
+		t.Fatalf("expected no events dropped for the event limit, got %v", n)
+	}
+
+	// Lowering the limit drops the oldest segments on load.
+	b.Stop(t.Context())
+	b = newDiskBuffer(dir, size, 1, diskBufferFsyncNever, defaultUploadSizeLimitBytes, rest.Client{}, "", plugins.TriggerManual).WithLogger(logging.NewNoOpLogger())
+	events := b.Flush()
+	if len(events) != 1 || events[0].DecisionID != "3" {
+		t.Fatalf("expected only the newest event, got %v", events)
+	}
+}
+
+func TestDiskBuffer_ImmediateMode(t *testing.T) {
+	t.Parallel()
+
+	uploadPath := "/v1/test"
+	srv := &testLogServer{}
+	client, ts := setupTestServer(t, uploadPath, srv.handle(t))
+	defer ts.Close()
+
+	dir := t.TempDir()
+	b := newDiskBuffer(dir, 0, defaultBufferSegmentSizeBytes, diskBufferFsyncAlways, 1024, client, uploadPath, plugins.TriggerImmediate).WithLogger(logging.NewNoOpLogger())
+	defer b.Stop(t.Context())
+
+	for i := range 10 {
+		b.Push(newTestEvent(t, strconv.Itoa(i), false))
+	}
+
+	test.EventuallyOrFatal(t, 5*time.Second, func() bool { return len(srv.uploaded()) >= 5 })
+}
+
+func TestParseConfigDiskBuffer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		note   string
+		config string
+		err    string
+		check  func(*testing.T, *Config)
+	}{
+		{
+			note:   "defaults",
+			config: `{"reporting": {"buffer_type": "disk"}}`,
+			check: func(t *testing.T, c *Config) {
+				if c.Reporting.BufferFsync != diskBufferFsyncAlways || *c.Reporting.BufferSegmentSize != defaultBufferSegmentSizeBytes || c.Reporting.BufferDirectory != "" {
+					t.Fatalf("unexpected defaults: %+v", c.Reporting)
+				}
+			},
+		},
+		{
+			note:   "options",
+			config: `{"reporting": {"buffer_type": "disk", "buffer_directory": "/var/opa/logs", "buffer_size_limit_bytes": 1048576, "buffer_segment_size_bytes": 1024, "buffer_fsync": "periodic"}}`,
+			check: func(t *testing.T, c *Config) {
+				if c.Reporting.BufferFsync != diskBufferFsyncPeriodic || *c.Reporting.BufferSegmentSize != 1024 || c.Reporting.BufferDirectory != "/var/opa/logs" || *c.Reporting.BufferSizeLimitBytes != 1048576 {
+					t.Fatalf("unexpected config: %+v", c.Reporting)
+				}
+			},
+		},
+		{
+			note:   "invalid fsync",
+			config: `{"reporting": {"buffer_type": "disk", "buffer_fsync": "sometimes"}}`,
+			err:    `invalid decision_log config, 'buffer_fsync' must be "always", "periodic" or "never"`,
+		},
+		{
+			note:   "invalid segment size",
+			config: `{"reporting": {"buffer_type": "disk", "buffer_segment_size_bytes": 0}}`,
+			err:    "invalid decision_log config, 'buffer_segment_size_bytes' must be higher than 0",
+		},
+		{
+			note:   "event limit",
+			config: `{"reporting": {"buffer_type": "disk", "buffer_size_limit_events": 10}}`,
+			err:    "invalid decision_log config, 'buffer_size_limit_events' isn't supported for the disk buffer type",
+		},
+		{
+			note:   "disk options for size buffer",
+			config: `{"reporting": {"buffer_directory": "/var/opa/logs"}}`,
+			err:    "invalid decision_log config, 'buffer_directory', 'buffer_segment_size_bytes' and 'buffer_fsync' are only supported for the disk buffer type",
+		},
+		{
+			note:   "invalid type",
+			config: `{"reporting": {"buffer_type": "file"}}`,
+			err:    `invalid buffer type "file", expected "event", "size" or "disk"`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			c, err := ParseConfig([]byte(tc.config), []string{"s0"}, nil)
+			if tc.err != "" {
+				if err == nil || err.Error() != tc.err {
+					t.Fatalf("expected error %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			tc.check(t, c)
+		})
+	}
+}
+
+func TestPluginDiskBufferDirectory(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	manager, err := plugins.New([]byte(`{"services": {"s0": {"url": "http://localhost"}}, "persistence_directory": "`+filepath.ToSlash(dir)+`"}`), "test", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := ParseConfig([]byte(`{"reporting": {"buffer_type": "disk"}}`), manager.Services(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := New(config, manager)
+	defer p.b.Stop(t.Context())
+
+	b, ok := p.b.(*diskBuffer)
+	if !ok {
+		t.Fatalf("expected disk buffer, got %T", p.b)
+	}
+	if exp := filepath.Join(dir, defaultBufferDirectory); b.dir != exp {
+		t.Fatalf("expected directory %v, got %v", exp, b.dir)
+	}
+}
+
+func TestPluginDiskBufferReconfigure(t *testing.T) {
+	t.Parallel()
+
+	srv := &testLogServer{fail: true}
+	ts := httptest.NewServer(http.HandlerFunc(srv.handle(t)))
+	defer ts.Close()
+
+	dir := t.TempDir()
+	manager, err := plugins.New([]byte(`{"services": {"s0": {"url": "`+ts.URL+`"}}, "persistence_directory": "`+filepath.ToSlash(dir)+`"}`), "test", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parse := func(raw string) *Config {
+		t.Helper()
+		config, err := parseManualConfig(raw, manager.Services())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return config
+	}
+
+	p := New(parse(`{"reporting": {"buffer_type": "disk"}}`), manager)
+	defer func() { p.b.Stop(t.Context()) }()
+
+	p.b.Push(newTestEvent(t, "1", false))
+	p.b.Push(newTestEvent(t, "2", false))
+	files := segmentFiles(t, filepath.Join(dir, defaultBufferDirectory))
+	if len(files) != 1 {
+		t.Fatalf("expected 1 segment, got %v", files)
+	}
+
+	// The segment is kept open, so that a new segment can't reuse its file.
+	f, err := os.Open(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	before, err := f.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The segment stays on disk while the buffer is reconfigured.
+	p.reconfigure(t.Context(), parse(`{"reporting": {"buffer_type": "disk", "upload_size_limit_bytes": 65536}}`))
+
+	if _, ok := p.b.(*diskBuffer); !ok {
+		t.Fatalf("expected disk buffer, got %T", p.b)
+	}
+	after, err := os.Stat(files[0])
+	if err != nil || !os.SameFile(before, after) {
+		t.Fatalf("expected segment %v to be kept, got %v", files[0], err)
+	}
+
+	srv.mtx.Lock()
+	srv.fail = false
+	srv.mtx.Unlock()
+
+	if err := p.b.Upload(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := srv.uploaded(), []string{"1", "2"}; !slices.Equal(act, exp) {
+		t.Fatalf("expected events %v to be uploaded, got %v", exp, act)
+	}
+}
+
+func mustMarshalEvent(t *testing.T, event *EventV1) []byte {
+	t.Helper()
+	bs, err := json.Marshal(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bs
+}
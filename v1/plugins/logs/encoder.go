@@ -7,7 +7,7 @@ package logs
 import (
 	"bytes"
 	"compress/gzip"
-	"encoding/json"
+	"io"
 	"math"
 
 	"github.com/open-policy-agent/opa/v1/logging"
@@ -41,6 +41,7 @@ type chunkEncoder struct {
 	eventsWritten int64
 	buf           *bytes.Buffer
 	w             *gzip.Writer
+	format        *eventFormat
 	metrics       metrics.Metrics
 	logger        logging.Logger
 	// lastDroppedNDSize is a known size of an individual event that would require the ND cache to be dropped
@@ -64,6 +65,7 @@ func newChunkEncoder(limit int64) *chunkEncoder {
 		limit:                              limit,
 		uncompressedLimit:                  limit,
 		threshold:                          int(float64(limit) * encCompressedLimitThreshold),
+		format:                             jsonEventFormat,
 		uncompressedLimitScaleUpExponent:   0,
 		uncompressedLimitScaleDownExponent: 0,
 	}
@@ -81,6 +83,13 @@ func (enc *chunkEncoder) WithUncompressedLimit(uncompressedLimit int64, uncompre
 	return enc
 }
 
+// WithFormat sets the encoding of the events in the chunks, which defaults to
+// a JSON array.
+func (enc *chunkEncoder) WithFormat(format *eventFormat) *chunkEncoder {
+	enc.format = format
+	return enc
+}
+
 func (enc *chunkEncoder) WithMetrics(m metrics.Metrics) *chunkEncoder {
 	enc.metrics = m
 	return enc
@@ -104,7 +113,27 @@ func (enc *chunkEncoder) scaleUp() {
 // A chunk is returned when it reaches the uncompressed limit, the uncompressed limit is adjusted if the buffer was underutilized or exceeded.
 // An event is only dropped if it exceeds the limit after being compressed with or without dropping the Non-deterministic Cache (NDBuiltinCache).
 // An event stays in the buffer until either a new event reaches the uncompressed limit or by calling Flush.
+// Formats holding a single event per chunk return the event in a chunk of its own right away.
 func (enc *chunkEncoder) Encode(event EventV1, eventBytes []byte) ([][]byte, error) {
+	if !enc.format.single {
+		return enc.encode(event, eventBytes)
+	}
+
+	// an event left in the chunk after dropping its ND cache is returned first
+	var results [][]byte
+	chunk, err := enc.reset()
+	if err != nil {
+		return nil, err
+	}
+	if chunk != nil {
+		results = append(results, chunk)
+	}
+
+	r, err := enc.encode(event, eventBytes)
+	return append(results, r...), err
+}
+
+func (enc *chunkEncoder) encode(event EventV1, eventBytes []byte) ([][]byte, error) {
 	// the incoming event is too big without dropping the ND cache
 	if enc.lastDroppedNDSize != 0 && int64(len(eventBytes)) >= enc.lastDroppedNDSize {
 		if event.NDBuiltinCache == nil {
@@ -124,13 +153,13 @@ func (enc *chunkEncoder) Encode(event EventV1, eventBytes []byte) ([][]byte, err
 		enc.incrMetric(logNDBDropCounterName)
 
 		var err error
-		eventBytes, err = json.Marshal(&event)
+		eventBytes, err = enc.format.marshal(&event)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	if int64(len(eventBytes)+enc.bytesWritten+1) < enc.uncompressedLimit {
+	if !enc.format.single && int64(len(eventBytes)+enc.bytesWritten+1) < enc.uncompressedLimit {
 		return nil, enc.appendEvent(eventBytes)
 	}
 
@@ -188,7 +217,7 @@ func (enc *chunkEncoder) Encode(event EventV1, eventBytes []byte) ([][]byte, err
 		// re-encode the event with the ND cache removed
 		event.NDBuiltinCache = nil
 
-		eventBytes, err = json.Marshal(&event)
+		eventBytes, err = enc.format.marshal(&event)
 		if err != nil {
 			return nil, err
 		}
@@ -278,7 +307,7 @@ func (enc *chunkEncoder) Encode(event EventV1, eventBytes []byte) ([][]byte, err
 
 	// 2) Scale Down: If the current chunk size exceeds the compressed limit, decrease the uncompressed limit and re-encode the
 	// decisions in the last chunk.
-	events, err := newChunkDecoder(result).decode()
+	events, err := newChunkDecoder(result).WithFormat(enc.format).decode()
 	if err != nil {
 		return nil, err
 	}
@@ -326,7 +355,7 @@ func (enc *chunkEncoder) scaleDown(events []EventV1) ([][]byte, error) {
 	// split the events into multiple chunks
 	var result [][]byte
 	for i := range events {
-		eventBytes, err := json.Marshal(&events[i])
+		eventBytes, err := enc.format.marshal(&events[i])
 		if err != nil {
 			return nil, err
 		}
@@ -360,21 +389,13 @@ func (enc *chunkEncoder) appendEvent(event []byte) error {
 		return nil
 	}
 
-	if enc.bytesWritten == 0 {
-		n, err := enc.w.Write([]byte(`[`))
-		if err != nil {
-			return err
-		}
-		enc.bytesWritten += n
-	} else {
-		n, err := enc.w.Write([]byte(`,`))
-		if err != nil {
-			return err
-		}
-		enc.bytesWritten += n
+	n, err := enc.w.Write(enc.format.frame(enc.bytesWritten == 0))
+	if err != nil {
+		return err
 	}
+	enc.bytesWritten += n
 
-	n, err := enc.w.Write(event)
+	n, err = enc.w.Write(event)
 	if err != nil {
 		return err
 	}
@@ -385,7 +406,7 @@ func (enc *chunkEncoder) appendEvent(event []byte) error {
 }
 
 func (enc *chunkEncoder) writeClose() error {
-	if _, err := enc.w.Write([]byte(`]`)); err != nil {
+	if _, err := enc.w.Write(enc.format.suffix); err != nil {
 		return err
 	}
 	return enc.w.Close()
@@ -414,7 +435,7 @@ func (enc *chunkEncoder) Flush() ([][]byte, error) {
 		if len(r) < int(enc.limit) {
 			return append(result, r), nil
 		}
-		events, err := newChunkDecoder(r).decode()
+		events, err := newChunkDecoder(r).WithFormat(enc.format).decode()
 		if err != nil {
 			return nil, err
 		}
@@ -463,23 +484,37 @@ func (enc *chunkEncoder) updateMetric(name string, value int64) {
 
 // chunkDecoder decodes the encoded chunks and outputs the log events
 type chunkDecoder struct {
-	raw []byte
+	raw    []byte
+	format *eventFormat
 }
 
 func newChunkDecoder(raw []byte) *chunkDecoder {
 	return &chunkDecoder{
-		raw: raw,
+		raw:    raw,
+		format: jsonEventFormat,
 	}
 }
 
+// WithFormat sets the encoding of the events in the chunk, which defaults to
+// a JSON array.
+func (dec *chunkDecoder) WithFormat(format *eventFormat) *chunkDecoder {
+	dec.format = format
+	return dec
+}
+
 func (dec *chunkDecoder) decode() ([]EventV1, error) {
 	gr, err := gzip.NewReader(bytes.NewReader(dec.raw))
 	if err != nil {
 		return nil, err
 	}
 
-	var events []EventV1
-	if err := json.NewDecoder(gr).Decode(&events); err != nil {
+	bs, err := io.ReadAll(gr)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := dec.format.unmarshal(bs)
+	if err != nil {
 		return nil, err
 	}
 
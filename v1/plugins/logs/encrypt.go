@@ -0,0 +1,244 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package logs
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/open-policy-agent/opa/v1/keys"
+	"github.com/open-policy-agent/opa/v1/util"
+)
+
+// Fields of the envelope replacing the values encrypted by mask rules.
+const (
+	envelopeKeyID      = "kid"
+	envelopeAlgorithm  = "alg"
+	envelopeNonce      = "iv"
+	envelopeCiphertext = "ciphertext"
+)
+
+func withKey(kid string, keys map[string]*keys.Config) maskRuleOption {
+	return func(r *maskRule) error {
+		if kid == "" {
+			return nil
+		}
+		if r.OP != maskOPEncrypt {
+			return fmt.Errorf("mask key is not supported with op: %s", r.OP)
+		}
+
+		kc, ok := keys[kid]
+		if !ok {
+			return fmt.Errorf("mask key not found: %s", kid)
+		}
+		aead, err := newAEAD(kc)
+		if err != nil {
+			return fmt.Errorf("mask key %s: %w", kid, err)
+		}
+
+		r.Key = kid
+		r.algorithm = kc.Algorithm
+		r.aead = aead
+		return nil
+	}
+}
+
+func newAEAD(kc *keys.Config) (cipher.AEAD, error) {
+	key, err := kc.EncryptionKey()
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encrypt returns the envelope of the JSON encoded value. The path of the
+// rule is authenticated with the value, so that the envelope cannot be moved
+// to another path of the event.
+func (r maskRule) encrypt(value any) (any, error) {
+	plaintext, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, r.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	ciphertext := r.aead.Seal(nil, nonce, plaintext, []byte(r.String()))
+
+	return map[string]any{
+		envelopeKeyID:      r.Key,
+		envelopeAlgorithm:  r.algorithm,
+		envelopeNonce:      base64.StdEncoding.EncodeToString(nonce),
+		envelopeCiphertext: base64.StdEncoding.EncodeToString(ciphertext),
+	}, nil
+}
+
+func (r maskRule) encryptValue(p []string, node any) error {
+	parent, key, err := walkParent(p, node)
+	if err != nil {
+		return err
+	}
+	return replaceChild(parent, key, r.encrypt)
+}
+
+// walkParent returns the parent of the node at the path, and the key of the
+// node in its parent.
+func walkParent(p []string, node any) (any, string, error) {
+	for i := range len(p) - 1 {
+		switch v := node.(type) {
+		case map[string]any:
+			child, ok := v[p[i]]
+			if !ok {
+				return nil, "", errMaskInvalidObject
+			}
+			node = child
+		case []any:
+			index, err := strconv.Atoi(p[i])
+			if err != nil || index < 0 || index >= len(v) {
+				return nil, "", errMaskInvalidObject
+			}
+			node = v[index]
+		default:
+			return nil, "", errMaskInvalidObject
+		}
+	}
+	return node, p[len(p)-1], nil
+}
+
+func replaceChild(parent any, key string, f func(any) (any, error)) error {
+	switch v := parent.(type) {
+	case map[string]any:
+		child, ok := v[key]
+		if !ok {
+			return errMaskInvalidObject
+		}
+		replaced, err := f(child)
+		if err != nil {
+			return err
+		}
+		v[key] = replaced
+	case []any:
+		index, err := strconv.Atoi(key)
+		if err != nil || index < 0 || index >= len(v) {
+			return errMaskInvalidObject
+		}
+		replaced, err := f(v[index])
+		if err != nil {
+			return err
+		}
+		v[index] = replaced
+	default:
+		return errMaskInvalidObject
+	}
+	return nil
+}
+
+// DecryptEvent decrypts the values of a decision log event that were
+// encrypted by mask rules, with the keys of the keys configuration. The event
+// is the JSON representation of an EventV1, as uploaded by the plugin.
+// Decrypted paths are removed from the "encrypted" field of the event, which
+// is removed once all paths are decrypted.
+func DecryptEvent(event map[string]any, keys map[string]*keys.Config) error {
+	pointers, ok := event["encrypted"].([]any)
+	if !ok {
+		return nil
+	}
+
+	var errs []error
+	remaining := make([]any, 0, len(pointers))
+	for _, x := range pointers {
+		pointer, ok := x.(string)
+		if !ok || !strings.HasPrefix(pointer, "/") {
+			errs = append(errs, fmt.Errorf("invalid encrypted path: %v", x))
+			remaining = append(remaining, x)
+			continue
+		}
+
+		decrypt := func(envelope any) (any, error) {
+			return decryptEnvelope(envelope, pointer, keys)
+		}
+		parent, key, err := walkParent(strings.Split(pointer[1:], "/"), event)
+		if err == nil {
+			err = replaceChild(parent, key, decrypt)
+		}
+		if err != nil {
+			if errors.Is(err, errMaskInvalidObject) {
+				err = errors.New("path not found")
+			}
+			errs = append(errs, fmt.Errorf("%v: %w", pointer, err))
+			remaining = append(remaining, x)
+		}
+	}
+
+	if len(remaining) == 0 {
+		delete(event, "encrypted")
+	} else {
+		event["encrypted"] = slices.Clip(remaining)
+	}
+	return errors.Join(errs...)
+}
+
+func decryptEnvelope(envelope any, pointer string, keys map[string]*keys.Config) (any, error) {
+	obj, ok := envelope.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("unexpected envelope type %T", envelope)
+	}
+	fields := map[string]string{}
+	for _, field := range []string{envelopeKeyID, envelopeAlgorithm, envelopeNonce, envelopeCiphertext} {
+		s, ok := obj[field].(string)
+		if !ok {
+			return nil, fmt.Errorf("envelope field %q missing", field)
+		}
+		fields[field] = s
+	}
+
+	kc, ok := keys[fields[envelopeKeyID]]
+	if !ok {
+		return nil, fmt.Errorf("key not found: %s", fields[envelopeKeyID])
+	}
+	if kc.Algorithm != fields[envelopeAlgorithm] {
+		return nil, fmt.Errorf("key %s algorithm %s does not match envelope algorithm %s", fields[envelopeKeyID], kc.Algorithm, fields[envelopeAlgorithm])
+	}
+	aead, err := newAEAD(kc)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := base64.StdEncoding.DecodeString(fields[envelopeNonce])
+	if err != nil {
+		return nil, fmt.Errorf("invalid envelope iv: %w", err)
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, errors.New("invalid envelope iv size")
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(fields[envelopeCiphertext])
+	if err != nil {
+		return nil, fmt.Errorf("invalid envelope ciphertext: %w", err)
+	}
+
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(pointer))
+	if err != nil {
+		return nil, err
+	}
+
+	var value any
+	if err := util.UnmarshalJSON(plaintext, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
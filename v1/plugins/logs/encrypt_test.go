@@ -0,0 +1,226 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package logs
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/open-policy-agent/opa/v1/keys"
+	"github.com/open-policy-agent/opa/v1/plugins"
+	"github.com/open-policy-agent/opa/v1/server"
+	"github.com/open-policy-agent/opa/v1/storage"
+	"github.com/open-policy-agent/opa/v1/storage/inmem"
+	"github.com/open-policy-agent/opa/v1/util"
+)
+
+const testEncryptionKey = "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=" // 32 bytes
+
+func testEncryptionKeys(t *testing.T) map[string]*keys.Config {
+	t.Helper()
+	kcs, err := keys.ParseKeysConfig(util.MustMarshalJSON(map[string]any{
+		"logs":   map[string]any{"algorithm": "A256GCM", "key": testEncryptionKey},
+		"other":  map[string]any{"algorithm": "A128GCM", "key": base64.StdEncoding.EncodeToString([]byte("0123456789abcdef"))},
+		"verify": map[string]any{"algorithm": "HS256", "key": "secret"},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return kcs
+}
+
+func TestNewMaskRuleSetEncrypt(t *testing.T) {
+	kcs := testEncryptionKeys(t)
+
+	tests := []struct {
+		note string
+		rule map[string]any
+		err  string
+	}{
+		{
+			note: "valid",
+			rule: map[string]any{"op": "encrypt", "path": "/input/password", "key": "logs"},
+		},
+		{
+			note: "missing key",
+			rule: map[string]any{"op": "encrypt", "path": "/input/password"},
+			err:  "mask op encrypt requires a key",
+		},
+		{
+			note: "unknown key",
+			rule: map[string]any{"op": "encrypt", "path": "/input/password", "key": "missing"},
+			err:  "mask key not found: missing",
+		},
+		{
+			note: "signing key",
+			rule: map[string]any{"op": "encrypt", "path": "/input/password", "key": "verify"},
+			err:  "mask key verify: unsupported encryption algorithm 'HS256'",
+		},
+		{
+			note: "key with other op",
+			rule: map[string]any{"op": "upsert", "path": "/input/password", "key": "logs"},
+			err:  "mask key is not supported with op: upsert",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			_, err := newMaskRuleSet([]any{tc.rule}, func(*maskRule, error) {}, kcs)
+			if tc.err == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tc.err {
+				t.Fatalf("expected error %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestMaskEncryptDecrypt(t *testing.T) {
+	kcs := testEncryptionKeys(t)
+
+	rs, err := newMaskRuleSet([]any{
+		map[string]any{"op": "encrypt", "path": "/input/user/ssn", "key": "logs"},
+		map[string]any{"op": "encrypt", "path": "/input/cards/1", "key": "other"},
+		map[string]any{"op": "encrypt", "path": "/result", "key": "logs"},
+		map[string]any{"op": "encrypt", "path": "/input/missing", "key": "logs"},
+	}, func(r *maskRule, err error) { t.Errorf("rule %v: %v", r, err) }, kcs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var input any = map[string]any{
+		"user":  map[string]any{"name": "alice", "ssn": "123-45-6789"},
+		"cards": []any{"1111", "2222"},
+	}
+	var result any = map[string]any{"allow": true, "limit": json.Number("10")}
+	original := util.MustMarshalJSON(input)
+
+	event := &EventV1{Input: &input, Result: &result}
+	rs.Mask(event)
+
+	if exp := []string{"/input/user/ssn", "/input/cards/1", "/result"}; !slices.Equal(event.Encrypted, exp) {
+		t.Fatalf("expected encrypted %v, got %v", exp, event.Encrypted)
+	}
+	if act := util.MustMarshalJSON(input); string(act) != string(original) {
+		t.Fatalf("expected the decision input not to be modified, got %s", act)
+	}
+
+	bs := util.MustMarshalJSON(event)
+	for _, secret := range []string{"123-45-6789", "2222", "limit"} {
+		if strings.Contains(string(bs), secret) {
+			t.Fatalf("expected %q to be encrypted, got %s", secret, bs)
+		}
+	}
+
+	var decoded map[string]any
+	if err := util.UnmarshalJSON(bs, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	// Envelopes cannot be decrypted at another path of the event.
+	moved := map[string]any{}
+	if err := util.UnmarshalJSON(bs, &moved); err != nil {
+		t.Fatal(err)
+	}
+	moved["result"] = moved["input"].(map[string]any)["user"].(map[string]any)["ssn"]
+	if err := DecryptEvent(moved, kcs); err == nil || !strings.Contains(err.Error(), "/result: cipher: message authentication failed") {
+		t.Fatalf("expected authentication error, got %v", err)
+	}
+	if exp := []any{"/result"}; !reflect.DeepEqual(moved["encrypted"], exp) {
+		t.Fatalf("expected %v to remain encrypted, got %v", exp, moved["encrypted"])
+	}
+
+	// Without the key, the values stay encrypted.
+	partial := map[string]any{}
+	if err := util.UnmarshalJSON(bs, &partial); err != nil {
+		t.Fatal(err)
+	}
+	if err := DecryptEvent(partial, map[string]*keys.Config{"logs": kcs["logs"]}); err == nil || !strings.Contains(err.Error(), "/input/cards/1: key not found: other") {
+		t.Fatalf("expected key not found error, got %v", err)
+	}
+
+	if err := DecryptEvent(decoded, kcs); err != nil {
+		t.Fatal(err)
+	}
+	if decoded["encrypted"] != nil {
+		t.Fatalf("expected all paths to be decrypted, got %v", decoded["encrypted"])
+	}
+	if exp := map[string]any{"user": map[string]any{"name": "alice", "ssn": "123-45-6789"}, "cards": []any{"1111", "2222"}}; !reflect.DeepEqual(decoded["input"], exp) {
+		t.Fatalf("expected input %v, got %v", exp, decoded["input"])
+	}
+	if exp := map[string]any{"allow": true, "limit": json.Number("10")}; !reflect.DeepEqual(decoded["result"], exp) {
+		t.Fatalf("expected result %v, got %v", exp, decoded["result"])
+	}
+}
+
+func TestPluginMaskEncrypt(t *testing.T) {
+	ctx := t.Context()
+
+	store := inmem.New()
+	policy := `package system.log
+
+mask contains {"op": "encrypt", "path": "/input/password", "key": "logs"}
+`
+	err := storage.Txn(ctx, store, storage.WriteParams, func(txn storage.Transaction) error {
+		return store.UpsertPolicy(ctx, txn, "test.rego", []byte(policy))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manager, err := plugins.New([]byte(`{"keys": {"logs": {"algorithm": "A256GCM", "key": "`+testEncryptionKey+`"}}}`), "test", store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := ParseConfig([]byte(`{"recent_decisions": {"size": 1}}`), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := New(config, manager)
+	if err := p.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer p.Stop(ctx)
+
+	var input any = map[string]any{"user": "alice", "password": "secret"}
+	if err := p.Log(ctx, &server.Info{DecisionID: "0", Input: &input}); err != nil {
+		t.Fatal(err)
+	}
+	if exp := map[string]any{"user": "alice", "password": "secret"}; !reflect.DeepEqual(input, exp) {
+		t.Fatalf("expected the decision input not to be modified, got %v", input)
+	}
+
+	events, err := p.RecentDecisions(server.DecisionsQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var event map[string]any
+	if err := util.UnmarshalJSON(util.MustMarshalJSON(events[0]), &event); err != nil {
+		t.Fatal(err)
+	}
+	if event["input"].(map[string]any)["password"] == "secret" {
+		t.Fatalf("expected password to be encrypted, got %v", event)
+	}
+	if err := DecryptEvent(event, manager.PublicKeys()); err != nil {
+		t.Fatal(err)
+	}
+	if exp := map[string]any{"user": "alice", "password": "secret"}; !reflect.DeepEqual(event["input"], exp) || event["encrypted"] != nil {
+		t.Fatalf("expected decrypted event, got %v", event)
+	}
+}
@@ -6,7 +6,6 @@ package logs
 
 import (
 	"context"
-	"encoding/json"
 	"math"
 	"sync"
 
@@ -28,6 +27,7 @@ type eventBuffer struct {
 	buffer     chan *bufferItem // buffer stores JSON encoded EventV1 data
 	uploadLock sync.Mutex
 	enc        *chunkEncoder // enc adds events into a gzip compressed JSON array (chunk)
+	format     *eventFormat
 	limiter    *rate.Limiter
 	metrics    metrics.Metrics
 	logger     logging.Logger
@@ -43,6 +43,7 @@ func newEventBuffer(bufferSizeLimitEvents int64, uploadSizeLimitBytes int64, cli
 	b := &eventBuffer{
 		buffer:     make(chan *bufferItem, bufferSizeLimitEvents),
 		enc:        newChunkEncoder(uploadSizeLimitBytes),
+		format:     jsonEventFormat,
 		mode:       mode,
 		client:     client,
 		uploadPath: uploadPath,
@@ -63,6 +64,13 @@ func (b *eventBuffer) WithLimiter(maxDecisionsPerSecond *float64) *eventBuffer {
 	return b
 }
 
+// WithFormat sets the encoding of the uploaded events.
+func (b *eventBuffer) WithFormat(format *eventFormat) *eventBuffer {
+	b.format = format
+	b.enc = b.enc.WithFormat(format)
+	return b
+}
+
 func (b *eventBuffer) WithMetrics(m metrics.Metrics) {
 	b.metrics = m
 	b.enc = b.enc.WithMetrics(m)
@@ -97,7 +105,7 @@ func (b *eventBuffer) Flush() []*EventV1 {
 	}
 
 	for _, r := range result {
-		decodedEvents, err := newChunkDecoder(r).decode()
+		decodedEvents, err := newChunkDecoder(r).WithFormat(b.format).decode()
 		if err != nil {
 			b.incrMetric(logEncodingFailureCounterName)
 			if b.logger != nil {
@@ -121,7 +129,7 @@ func (b *eventBuffer) Flush() []*EventV1 {
 		if event.EventV1 != nil {
 			events = append(events, event.EventV1)
 		} else if event.chunk != nil {
-			decodedEvents, err := newChunkDecoder(event.chunk).decode()
+			decodedEvents, err := newChunkDecoder(event.chunk).WithFormat(b.format).decode()
 			if err != nil {
 				b.incrMetric(logEncodingFailureCounterName)
 				if b.logger != nil {
@@ -177,7 +185,7 @@ func (b *eventBuffer) processBufferItem(item *bufferItem) [][]byte {
 
 	var result [][]byte
 	event := item.EventV1
-	eventBytes, err := json.Marshal(&event)
+	eventBytes, err := b.format.marshal(event)
 	if err != nil {
 		b.incrMetric(logEncodingFailureCounterName)
 		if b.logger != nil {
@@ -290,7 +298,7 @@ func (b *eventBuffer) Upload(ctx context.Context) error {
 func (b *eventBuffer) uploadChunks(ctx context.Context, result [][]byte, client rest.Client, uploadPath string) error {
 	var finalErr error
 	for _, chunk := range result {
-		err := uploadChunk(ctx, client, uploadPath, chunk)
+		err := uploadChunk(ctx, client, uploadPath, chunk, b.format)
 
 		// if an upload failed, requeue the chunk
 		if err != nil {
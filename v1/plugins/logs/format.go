@@ -0,0 +1,198 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package logs
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/open-policy-agent/opa/v1/plugins/logs/v1pb"
+)
+
+const (
+	encodingJSON             = "json"
+	encodingCloudEvents      = "cloudevents"
+	encodingCloudEventsBatch = "cloudevents-batch"
+	encodingProtobuf         = "protobuf"
+
+	cloudEventsSpecVersion = "1.0"
+	cloudEventsType        = "org.openpolicyagent.decision_log.v1"
+)
+
+// eventFormat describes how decision events are encoded into the chunks
+// uploaded to the remote service. The encoded events of a chunk are framed by
+// prefix, separator and suffix before the chunk is compressed.
+type eventFormat struct {
+	contentType string
+	prefix      []byte
+	separator   []byte
+	suffix      []byte
+	// single is set if a chunk holds exactly one event.
+	single    bool
+	marshal   func(*EventV1) ([]byte, error)
+	unmarshal func([]byte) ([]EventV1, error)
+}
+
+var (
+	jsonEventFormat = &eventFormat{
+		contentType: "application/json",
+		prefix:      []byte(`[`),
+		separator:   []byte(`,`),
+		suffix:      []byte(`]`),
+		marshal:     marshalJSONEvent,
+		unmarshal:   unmarshalJSONEvents,
+	}
+
+	cloudEventsFormat = &eventFormat{
+		contentType: "application/cloudevents+json",
+		single:      true,
+		marshal:     marshalCloudEvent,
+		unmarshal:   unmarshalCloudEvent,
+	}
+
+	cloudEventsBatchFormat = &eventFormat{
+		contentType: "application/cloudevents-batch+json",
+		prefix:      []byte(`[`),
+		separator:   []byte(`,`),
+		suffix:      []byte(`]`),
+		marshal:     marshalCloudEvent,
+		unmarshal:   unmarshalCloudEvents,
+	}
+
+	protobufEventFormat = &eventFormat{
+		contentType: "application/x-protobuf",
+		marshal:     marshalProtoEvent,
+		unmarshal:   unmarshalProtoEvents,
+	}
+
+	eventFormats = map[string]*eventFormat{
+		encodingJSON:             jsonEventFormat,
+		encodingCloudEvents:      cloudEventsFormat,
+		encodingCloudEventsBatch: cloudEventsBatchFormat,
+		encodingProtobuf:         protobufEventFormat,
+	}
+)
+
+func marshalJSONEvent(event *EventV1) ([]byte, error) {
+	return json.Marshal(event)
+}
+
+func unmarshalJSONEvents(bs []byte) ([]EventV1, error) {
+	var events []EventV1
+	if err := json.Unmarshal(bs, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// cloudEvent is a decision event in the structured content mode of the
+// CloudEvents JSON format, see https://github.com/cloudevents/spec.
+type cloudEvent struct {
+	SpecVersion     string    `json:"specversion"`
+	ID              string    `json:"id"`
+	Source          string    `json:"source"`
+	Type            string    `json:"type"`
+	Subject         string    `json:"subject,omitempty"`
+	Time            time.Time `json:"time"`
+	DataContentType string    `json:"datacontenttype"`
+	Data            *EventV1  `json:"data"`
+}
+
+func marshalCloudEvent(event *EventV1) ([]byte, error) {
+	return json.Marshal(cloudEvent{
+		SpecVersion:     cloudEventsSpecVersion,
+		ID:              event.DecisionID,
+		Source:          cloudEventSource(event),
+		Type:            cloudEventsType,
+		Subject:         event.Path,
+		Time:            event.Timestamp,
+		DataContentType: "application/json",
+		Data:            event,
+	})
+}
+
+// cloudEventSource identifies the OPA instance that made the decision.
+func cloudEventSource(event *EventV1) string {
+	if id := event.Labels["id"]; id != "" {
+		return "/opa/" + url.PathEscape(id)
+	}
+	return "/opa"
+}
+
+func unmarshalCloudEvent(bs []byte) ([]EventV1, error) {
+	var ce cloudEvent
+	if err := json.Unmarshal(bs, &ce); err != nil {
+		return nil, err
+	}
+	if ce.Data == nil {
+		return nil, errors.New("cloud event without data")
+	}
+	return []EventV1{*ce.Data}, nil
+}
+
+func unmarshalCloudEvents(bs []byte) ([]EventV1, error) {
+	var ces []cloudEvent
+	if err := json.Unmarshal(bs, &ces); err != nil {
+		return nil, err
+	}
+	events := make([]EventV1, 0, len(ces))
+	for _, ce := range ces {
+		if ce.Data == nil {
+			return nil, errors.New("cloud event without data")
+		}
+		events = append(events, *ce.Data)
+	}
+	return events, nil
+}
+
+// marshalProtoEvent encodes the event as an element of the repeated events
+// field of an EventBatch, so that the concatenated events of a chunk form a
+// valid EventBatch message.
+func marshalProtoEvent(event *EventV1) ([]byte, error) {
+	msg, err := EventToProto(event)
+	if err != nil {
+		return nil, err
+	}
+	bs, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	out := protowire.AppendTag(nil, 1, protowire.BytesType)
+	return protowire.AppendBytes(out, bs), nil
+}
+
+func unmarshalProtoEvents(bs []byte) ([]EventV1, error) {
+	var batch pb.EventBatch
+	if err := proto.Unmarshal(bs, &batch); err != nil {
+		return nil, err
+	}
+	events := make([]EventV1, 0, len(batch.GetEvents()))
+	for _, msg := range batch.GetEvents() {
+		event, err := EventFromProto(msg)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, *event)
+	}
+	return events, nil
+}
+
+// isJSON reports whether the format's encoded events are the plain JSON
+// encoding of EventV1.
+func (f *eventFormat) isJSON() bool {
+	return f == jsonEventFormat
+}
+
+func (f *eventFormat) frame(first bool) []byte {
+	if first {
+		return f.prefix
+	}
+	return f.separator
+}
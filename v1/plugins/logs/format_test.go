@@ -0,0 +1,341 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package logs
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/open-policy-agent/opa/v1/plugins"
+	pb "github.com/open-policy-agent/opa/v1/plugins/logs/v1pb"
+	"github.com/open-policy-agent/opa/v1/topdown"
+)
+
+func TestEventFormats(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		encoding string
+		chunks   int
+	}{
+		{encoding: encodingJSON, chunks: 1},
+		{encoding: encodingCloudEvents, chunks: 3},
+		{encoding: encodingCloudEventsBatch, chunks: 1},
+		{encoding: encodingProtobuf, chunks: 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.encoding, func(t *testing.T) {
+			t.Parallel()
+
+			format := eventFormats[tc.encoding]
+			enc := newChunkEncoder(10000).WithFormat(format)
+
+			var chunks [][]byte
+			for i := range 3 {
+				event := newTestEvent(t, fmt.Sprint(i), true)
+				bs, err := format.marshal(event)
+				if err != nil {
+					t.Fatal(err)
+				}
+				result, err := enc.Encode(*event, bs)
+				if err != nil {
+					t.Fatal(err)
+				}
+				chunks = append(chunks, result...)
+			}
+
+			result, err := enc.Flush()
+			if err != nil {
+				t.Fatal(err)
+			}
+			chunks = append(chunks, result...)
+
+			if len(chunks) != tc.chunks {
+				t.Fatalf("expected %d chunks, got %d", tc.chunks, len(chunks))
+			}
+
+			var ids []string
+			for _, chunk := range chunks {
+				events, err := newChunkDecoder(chunk).WithFormat(format).decode()
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, event := range events {
+					if event.NDBuiltinCache == nil || event.Labels["id"] != "test-instance-id" {
+						t.Fatalf("unexpected event: %+v", event)
+					}
+					ids = append(ids, event.DecisionID)
+				}
+			}
+
+			if exp := []string{"0", "1", "2"}; !reflect.DeepEqual(ids, exp) {
+				t.Fatalf("expected decision IDs %v, got %v", exp, ids)
+			}
+		})
+	}
+}
+
+func TestEventFormatSizeLimit(t *testing.T) {
+	t.Parallel()
+
+	// the upload size limit applies to the encoded events, the ND cache is
+	// dropped from an event that doesn't fit otherwise
+	for _, encoding := range []string{encodingCloudEvents, encodingProtobuf} {
+		t.Run(encoding, func(t *testing.T) {
+			t.Parallel()
+
+			format := eventFormats[encoding]
+			enc := newChunkEncoder(1000).WithFormat(format)
+
+			// hex encoded random bytes don't compress below the limit
+			random := make([]byte, 4096)
+			if _, err := rand.Read(random); err != nil {
+				t.Fatal(err)
+			}
+			var ndbCache any = map[string]any{"rand.intn": hex.EncodeToString(random)}
+			event := *newTestEvent(t, "abc", false)
+			event.NDBuiltinCache = &ndbCache
+
+			bs, err := format.marshal(&event)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			chunks, err := enc.Encode(event, bs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := enc.Flush()
+			if err != nil {
+				t.Fatal(err)
+			}
+			chunks = append(chunks, result...)
+
+			if len(chunks) != 1 {
+				t.Fatalf("expected 1 chunk, got %d", len(chunks))
+			}
+			if len(chunks[0]) > 1000 {
+				t.Fatalf("expected chunk within the limit, got %d bytes", len(chunks[0]))
+			}
+
+			events, err := newChunkDecoder(chunks[0]).WithFormat(format).decode()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(events) != 1 || events[0].DecisionID != "abc" || events[0].NDBuiltinCache != nil {
+				t.Fatalf("unexpected events: %+v", events)
+			}
+		})
+	}
+}
+
+func TestCloudEventsEncoding(t *testing.T) {
+	t.Parallel()
+
+	event := newTestEvent(t, "abc", false)
+	bs, err := cloudEventsFormat.marshal(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ce map[string]any
+	if err := json.Unmarshal(bs, &ce); err != nil {
+		t.Fatal(err)
+	}
+
+	for k, exp := range map[string]any{
+		"specversion":     "1.0",
+		"id":              "abc",
+		"source":          "/opa/test-instance-id",
+		"type":            "org.openpolicyagent.decision_log.v1",
+		"subject":         "foo/bar",
+		"time":            "2018-01-01T12:00:00.123456Z",
+		"datacontenttype": "application/json",
+	} {
+		if ce[k] != exp {
+			t.Errorf("expected %v to be %v, got %v", k, exp, ce[k])
+		}
+	}
+
+	data, ok := ce["data"].(map[string]any)
+	if !ok || data["decision_id"] != "abc" || data["path"] != "foo/bar" {
+		t.Fatalf("unexpected data: %v", ce["data"])
+	}
+}
+
+func TestEventProtoRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	event := newTestEvent(t, "abc", true)
+	var mapped any = map[string]any{"allowed": false}
+	var annotations any = map[string]any{"risk": json.Number("9007199254740993")}
+	event.BatchDecisionID = "batch"
+	event.TraceID = "trace"
+	event.SpanID = "span"
+	event.Bundles = map[string]BundleInfoV1{"authz": {Revision: "r1"}}
+	event.Query = "data.foo.bar"
+	event.MappedResult = &mapped
+	event.Annotations = &annotations
+	event.IntermediateResults = map[string]any{"data.foo.x": true}
+	event.Erased = []string{"/input/password"}
+	event.Masked = []string{"/input/ssn"}
+	event.Error = &topdown.Error{Code: topdown.BuiltinErr, Message: "boom"}
+	event.Metrics = map[string]any{"timer_server_handler_ns": json.Number("1234")}
+	event.RequestID = 42
+	event.RuleLabels = []map[string]any{{"team": "a"}}
+	event.RequestContext = &RequestContext{HTTPRequest: &HTTPRequestContext{Headers: map[string][]string{"X-Foo": {"a", "b"}}}}
+	event.Custom = map[string]any{"region": "eu"}
+	event.SampleRate = 0.5
+	event.Aggregate = &AggregateV1{
+		Start:  event.Timestamp,
+		End:    event.Timestamp.Add(time.Minute),
+		Count:  3,
+		Errors: 1,
+		Input:  map[string]any{"method": map[string]any{"GET": json.Number("3")}},
+		Latency: LatencyHistogramV1{
+			BoundsMs: []float64{1, 10},
+			Counts:   []uint64{1, 2, 0},
+			SumMs:    5.5,
+			MinMs:    0.5,
+			MaxMs:    3,
+		},
+	}
+
+	msg, err := EventToProto(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bs, err := proto.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded pb.Event
+	if err := proto.Unmarshal(bs, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	result, err := EventFromProto(&decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp, err := json.Marshal(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	act, err := json.Marshal(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(exp, act) {
+		t.Fatalf("expected:\n\n%s\n\ngot:\n\n%s", exp, act)
+	}
+}
+
+func TestEventFormatUpload(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		encoding    string
+		contentType string
+		decode      func(*testing.T, []byte) []string
+	}{
+		{
+			encoding:    encodingCloudEventsBatch,
+			contentType: "application/cloudevents-batch+json",
+			decode: func(t *testing.T, bs []byte) []string {
+				var ces []cloudEvent
+				if err := json.Unmarshal(bs, &ces); err != nil {
+					t.Fatal(err)
+				}
+				var ids []string
+				for _, ce := range ces {
+					ids = append(ids, ce.ID)
+				}
+				return ids
+			},
+		},
+		{
+			encoding:    encodingProtobuf,
+			contentType: "application/x-protobuf",
+			decode: func(t *testing.T, bs []byte) []string {
+				var batch pb.EventBatch
+				if err := proto.Unmarshal(bs, &batch); err != nil {
+					t.Fatal(err)
+				}
+				var ids []string
+				for _, event := range batch.GetEvents() {
+					ids = append(ids, event.GetDecisionId())
+				}
+				return ids
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.encoding, func(t *testing.T) {
+			t.Parallel()
+
+			var ids []string
+			client, ts := setupTestServer(t, "/logs", func(w http.ResponseWriter, r *http.Request) {
+				if ct := r.Header.Get("Content-Type"); ct != tc.contentType {
+					t.Errorf("expected content type %q, got %q", tc.contentType, ct)
+				}
+				gr, err := gzip.NewReader(r.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				bs, err := io.ReadAll(gr)
+				if err != nil {
+					t.Fatal(err)
+				}
+				ids = append(ids, tc.decode(t, bs)...)
+				w.WriteHeader(http.StatusOK)
+			})
+			defer ts.Close()
+
+			b := newSizeBuffer(1000000, 10000, client, "/logs", plugins.TriggerPeriodic).WithFormat(eventFormats[tc.encoding])
+			b.Push(newTestEvent(t, "1", false))
+			b.Push(newTestEvent(t, "2", false))
+
+			if err := b.Upload(t.Context()); err != nil {
+				t.Fatal(err)
+			}
+
+			if exp := []string{"1", "2"}; !reflect.DeepEqual(ids, exp) {
+				t.Fatalf("expected decision IDs %v, got %v", exp, ids)
+			}
+		})
+	}
+}
+
+func TestEncodingConfig(t *testing.T) {
+	t.Parallel()
+
+	c, err := ParseConfig([]byte(`{}`), []string{"s0"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Reporting.Encoding != encodingJSON {
+		t.Fatalf("expected default encoding %q, got %q", encodingJSON, c.Reporting.Encoding)
+	}
+
+	_, err = ParseConfig([]byte(`{"reporting": {"encoding": "avro"}}`), []string{"s0"}, nil)
+	exp := `invalid decision_log config, 'encoding' must be "json", "cloudevents", "cloudevents-batch" or "protobuf"`
+	if err == nil || err.Error() != exp {
+		t.Fatalf("expected error %q, got %v", exp, err)
+	}
+}
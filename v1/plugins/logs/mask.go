@@ -5,6 +5,7 @@
 package logs
 
 import (
+	"crypto/cipher"
 	"errors"
 	"fmt"
 	"net/url"
@@ -13,17 +14,20 @@ import (
 	"strings"
 
 	"github.com/open-policy-agent/opa/internal/deepcopy"
+	"github.com/open-policy-agent/opa/v1/keys"
 )
 
 type maskOP string
 
 const (
-	maskOPRemove maskOP = "remove"
-	maskOPUpsert maskOP = "upsert"
-
-	partInput    = "input"
-	partResult   = "result"
-	partNDBCache = "nd_builtin_cache"
+	maskOPRemove  maskOP = "remove"
+	maskOPUpsert  maskOP = "upsert"
+	maskOPEncrypt maskOP = "encrypt"
+
+	partInput       = "input"
+	partResult      = "result"
+	partNDBCache    = "nd_builtin_cache"
+	partAnnotations = "annotations"
 )
 
 var errMaskInvalidObject = errors.New("mask upsert invalid object")
@@ -32,15 +36,20 @@ type maskRule struct {
 	OP                maskOP `json:"op"`
 	Path              string `json:"path"`
 	Value             any    `json:"value"`
+	Key               string `json:"key"`
 	escapedParts      []string
 	modifyFullObj     bool
 	failUndefinedPath bool
+	algorithm         string
+	aead              cipher.AEAD
 }
 
 type maskRuleSet struct {
-	OnRuleError  func(*maskRule, error)
-	Rules        []*maskRule
-	resultCopied bool
+	OnRuleError       func(*maskRule, error)
+	Rules             []*maskRule
+	resultCopied      bool
+	inputCopied       bool
+	annotationsCopied bool
 }
 
 func (r maskRule) String() string {
@@ -64,7 +73,7 @@ func newMaskRule(path string, opts ...maskRuleOption) (*maskRule, error) {
 	parts := strings.Split(path[1:], "/")
 
 	switch parts[0] {
-	case partInput, partResult, partNDBCache: // OK
+	case partInput, partResult, partNDBCache, partAnnotations: // OK
 	default:
 		return nil, fmt.Errorf("mask prefix not allowed: %v", parts[0])
 	}
@@ -97,13 +106,17 @@ func newMaskRule(path string, opts ...maskRuleOption) (*maskRule, error) {
 			return nil, err
 		}
 	}
+
+	if r.OP == maskOPEncrypt && r.aead == nil {
+		return nil, errors.New("mask op encrypt requires a key")
+	}
 	return r, nil
 }
 
 func withOP(op maskOP) maskRuleOption {
 	return func(r *maskRule) error {
 		switch op {
-		case maskOPRemove, maskOPUpsert:
+		case maskOPRemove, maskOPUpsert, maskOPEncrypt:
 			r.OP = op
 			return nil
 		}
@@ -126,8 +139,8 @@ func withFailUndefinedPath() maskRuleOption {
 }
 
 func (r maskRule) Mask(event *EventV1) error {
-	var maskObj *any     // pointer to event Input|Result|NDBCache object
-	var maskObjPtr **any // pointer to the event Input|Result|NDBCache pointer itself
+	var maskObj *any     // pointer to event Input|Result|NDBCache|Annotations object
+	var maskObjPtr **any // pointer to the event Input|Result|NDBCache|Annotations pointer itself
 
 	switch p := r.escapedParts[0]; p {
 	case partInput:
@@ -157,6 +170,15 @@ func (r maskRule) Mask(event *EventV1) error {
 		}
 		maskObj = event.NDBuiltinCache
 		maskObjPtr = &event.NDBuiltinCache
+	case partAnnotations:
+		if event.Annotations == nil {
+			if r.failUndefinedPath {
+				return errMaskInvalidObject
+			}
+			return nil
+		}
+		maskObj = event.Annotations
+		maskObjPtr = &event.Annotations
 	default:
 		return fmt.Errorf("illegal path value: %s", p)
 	}
@@ -195,6 +217,21 @@ func (r maskRule) Mask(event *EventV1) error {
 		}
 
 		event.Masked = append(event.Masked, r.String())
+	case maskOPEncrypt:
+		if r.modifyFullObj {
+			envelope, err := r.encrypt(*maskObj)
+			if err != nil {
+				return err
+			}
+			*maskObjPtr = &envelope
+		} else if err := r.encryptValue(r.escapedParts[1:], *maskObj); err != nil {
+			if err == errMaskInvalidObject && !r.failUndefinedPath {
+				return nil
+			}
+			return err
+		}
+
+		event.Encrypted = append(event.Encrypted, r.String())
 	default:
 		return fmt.Errorf("illegal mask op value: %s", r.OP)
 	}
@@ -333,7 +370,7 @@ func (maskRule) mkdirp(node any, path []string, value any) error {
 	return nil
 }
 
-func newMaskRuleSet(rv any, onRuleError func(*maskRule, error)) (*maskRuleSet, error) {
+func newMaskRuleSet(rv any, onRuleError func(*maskRule, error), keys map[string]*keys.Config) (*maskRuleSet, error) {
 	mRuleSet := &maskRuleSet{
 		OnRuleError: onRuleError,
 	}
@@ -371,8 +408,14 @@ func newMaskRuleSet(rv any, onRuleError func(*maskRule, error)) (*maskRuleSet, e
 
 			rule.Value = v["value"]
 
+			key, set := getString(v, "key")
+			if set && key == "" {
+				return nil, fmt.Errorf("invalid \"key\" value: %v %[1]T", v["key"])
+			}
+			rule.Key = key
+
 			// use unmarshalled values to create new Mask Rule
-			rule, err := newMaskRule(rule.Path, withOP(rule.OP), withValue(rule.Value))
+			rule, err := newMaskRule(rule.Path, withOP(rule.OP), withValue(rule.Value), withKey(rule.Key, keys))
 			// TODO add withFailUndefinedPath() option based on
 			//   A) new syntax in user defined mask rule
 			//   B) passed in/global configuration option
@@ -401,6 +444,18 @@ func (rs maskRuleSet) Mask(event *EventV1) {
 			event.Result = &resultCopy
 			rs.resultCopied = true
 		}
+		// likewise, the input is shared with the decision log sinks,
+		// which apply their own mask rules
+		if mRule.escapedParts[0] == partInput && event.Input != nil && !rs.inputCopied {
+			inputCopy := deepcopy.DeepCopy(*event.Input)
+			event.Input = &inputCopy
+			rs.inputCopied = true
+		}
+		if mRule.escapedParts[0] == partAnnotations && event.Annotations != nil && !rs.annotationsCopied {
+			annotationsCopy := deepcopy.DeepCopy(*event.Annotations)
+			event.Annotations = &annotationsCopy
+			rs.annotationsCopied = true
+		}
 		err := mRule.Mask(event)
 		if err != nil {
 			rs.OnRuleError(mRule, err)
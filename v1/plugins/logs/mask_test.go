@@ -227,6 +227,34 @@ func TestMaskRuleMask(t *testing.T) {
 			event: `{"result": "foo"}`,
 			exp:   `{"masked": ["/result"], "result": "upserted"}`,
 		},
+		{
+			note: "erase annotation",
+			ptr: &maskRule{
+				OP:   maskOPRemove,
+				Path: "/annotations/risk",
+			},
+			event: `{"annotations": {"risk": 2, "role": "admin"}}`,
+			exp:   `{"erased": ["/annotations/risk"], "annotations": {"role": "admin"}}`,
+		},
+		{
+			note: "upsert annotations",
+			ptr: &maskRule{
+				OP:    maskOPUpsert,
+				Path:  "/annotations",
+				Value: "upserted",
+			},
+			event: `{"annotations": {"risk": 2}}`,
+			exp:   `{"masked": ["/annotations"], "annotations": "upserted"}`,
+		},
+		{
+			note: "erase undefined annotations",
+			ptr: &maskRule{
+				OP:   maskOPRemove,
+				Path: "/annotations/risk",
+			},
+			event: `{}`,
+			exp:   `{}`,
+		},
 		{
 			note: "erase undefined input",
 			ptr: &maskRule{
@@ -674,7 +702,7 @@ func TestNewMaskRuleSet(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.note, func(t *testing.T) {
-			_, err := newMaskRuleSet(tc.value, func(_ *maskRule, _ error) {})
+			_, err := newMaskRuleSet(tc.value, func(_ *maskRule, _ error) {}, nil)
 			if err != nil {
 				if exp, act := tc.err.Error(), err.Error(); exp != act {
 					t.Fatalf("Expected: %s\nGot: %s", exp, act)
@@ -0,0 +1,245 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package logs
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/open-policy-agent/opa/internal/tlsutil"
+	"github.com/open-policy-agent/opa/v1/util"
+)
+
+const (
+	otlpTypeGRPC              = "otlp/grpc"
+	otlpTypeHTTP              = "otlp/http"
+	defaultOTLPGRPCAddress    = "localhost:4317"
+	defaultOTLPHTTPAddress    = "localhost:4318"
+	defaultOTLPServiceName    = "opa"
+	defaultOTLPEncryption     = "off"
+	otlpInstrumentationScope  = "github.com/open-policy-agent/opa/v1/plugins/logs"
+	otlpDecisionLogRecordBody = "Decision Log"
+)
+
+// OTLPConfig represents the configuration of the OTLP logs exporter. The TLS
+// options are the same as for metrics export and distributed tracing.
+type OTLPConfig struct {
+	Type                  string `json:"type,omitempty"`
+	Address               string `json:"address,omitempty"`
+	ServiceName           string `json:"service_name,omitempty"`
+	EncryptionScheme      string `json:"encryption,omitempty"`
+	EncryptionSkipVerify  *bool  `json:"allow_insecure_tls,omitempty"`
+	TLSCertFile           string `json:"tls_cert_file,omitempty"`
+	TLSCertPrivateKeyFile string `json:"tls_private_key_file,omitempty"`
+	TLSCACertFile         string `json:"tls_ca_cert_file,omitempty"`
+}
+
+func (c *OTLPConfig) validateAndInjectDefaults() error {
+	switch strings.ToLower(c.Type) {
+	case otlpTypeGRPC:
+		if c.Address == "" {
+			c.Address = defaultOTLPGRPCAddress
+		}
+	case otlpTypeHTTP:
+		if c.Address == "" {
+			c.Address = defaultOTLPHTTPAddress
+		}
+	default:
+		return fmt.Errorf("invalid otlp type %q in decision_logs, expected %q or %q", c.Type, otlpTypeGRPC, otlpTypeHTTP)
+	}
+
+	if c.ServiceName == "" {
+		c.ServiceName = defaultOTLPServiceName
+	}
+
+	switch c.EncryptionScheme {
+	case "":
+		c.EncryptionScheme = defaultOTLPEncryption
+	case "off", "tls", "mtls":
+	default:
+		return fmt.Errorf("invalid otlp encryption %q in decision_logs", c.EncryptionScheme)
+	}
+
+	if c.EncryptionSkipVerify == nil {
+		skipVerify := false
+		c.EncryptionSkipVerify = &skipVerify
+	}
+
+	return nil
+}
+
+// otlpLogger exports decision log events as OTLP log records. Records are
+// batched and exported in the background.
+type otlpLogger struct {
+	provider *sdklog.LoggerProvider
+	logger   log.Logger
+}
+
+func newOTLPLogger(ctx context.Context, cfg *OTLPConfig) (*otlpLogger, error) {
+	certificate, err := tlsutil.LoadCertificate(cfg.TLSCertFile, cfg.TLSCertPrivateKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	certPool, err := tlsutil.LoadCertPool(cfg.TLSCACertFile)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, err := tlsutil.BuildTLSConfig(cfg.EncryptionScheme, *cfg.EncryptionSkipVerify, certificate, certPool)
+	if err != nil {
+		return nil, err
+	}
+
+	var exporter sdklog.Exporter
+	if strings.EqualFold(cfg.Type, otlpTypeGRPC) {
+		exporter, err = otlploggrpc.New(ctx,
+			otlploggrpc.WithEndpoint(cfg.Address),
+			grpcLogTLSOption(cfg.EncryptionScheme, tlsConfig),
+		)
+	} else {
+		exporter, err = otlploghttp.New(ctx,
+			otlploghttp.WithEndpoint(cfg.Address),
+			httpLogTLSOption(cfg.EncryptionScheme, tlsConfig),
+		)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("create OTLP log exporter: %w", err)
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(
+			semconv.ServiceNameKey.String(cfg.ServiceName),
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdklog.NewLoggerProvider(
+		sdklog.WithResource(res),
+		sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)),
+	)
+
+	return &otlpLogger{
+		provider: provider,
+		logger:   provider.Logger(otlpInstrumentationScope),
+	}, nil
+}
+
+func grpcLogTLSOption(encryptionScheme string, tlsConfig *tls.Config) otlploggrpc.Option {
+	if encryptionScheme == "off" {
+		return otlploggrpc.WithInsecure()
+	}
+	return otlploggrpc.WithTLSCredentials(credentials.NewTLS(tlsConfig))
+}
+
+func httpLogTLSOption(encryptionScheme string, tlsConfig *tls.Config) otlploghttp.Option {
+	if encryptionScheme == "off" {
+		return otlploghttp.WithInsecure()
+	}
+	return otlploghttp.WithTLSClientConfig(tlsConfig)
+}
+
+// Log emits the event as a log record. The event fields become attributes of
+// the record, and the trace and span IDs of the event, if any, set its trace
+// context so that backends can correlate the decision with its trace.
+func (l *otlpLogger) Log(ctx context.Context, event EventV1) error {
+	var fields any = eventToFields(event)
+	if err := util.RoundTrip(&fields); err != nil {
+		return err
+	}
+	attrs := fields.(map[string]any)
+
+	if sc := eventSpanContext(event); sc.IsValid() {
+		ctx = trace.ContextWithRemoteSpanContext(ctx, sc)
+		delete(attrs, "trace_id")
+		delete(attrs, "span_id")
+	}
+
+	var record log.Record
+	record.SetEventName(DecisionLogType)
+	record.SetTimestamp(event.Timestamp)
+	record.SetSeverity(log.SeverityInfo)
+	record.SetSeverityText("INFO")
+	record.SetBody(log.StringValue(otlpDecisionLogRecordBody))
+	for _, k := range slices.Sorted(maps.Keys(attrs)) {
+		record.AddAttributes(log.KeyValue{Key: k, Value: otlpValue(attrs[k])})
+	}
+
+	l.logger.Emit(ctx, record)
+	return nil
+}
+
+// Stop exports the pending records and shuts the exporter down.
+func (l *otlpLogger) Stop(ctx context.Context) error {
+	return l.provider.Shutdown(ctx)
+}
+
+// eventSpanContext returns the span context identified by the trace and span
+// IDs of the event, which is invalid if the event has none.
+func eventSpanContext(event EventV1) trace.SpanContext {
+	traceID, err := trace.TraceIDFromHex(event.TraceID)
+	if err != nil {
+		return trace.SpanContext{}
+	}
+	spanID, err := trace.SpanIDFromHex(event.SpanID)
+	if err != nil {
+		return trace.SpanContext{}
+	}
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+		Remote:  true,
+	})
+}
+
+// otlpValue converts a JSON value into a log value.
+func otlpValue(x any) log.Value {
+	switch x := x.(type) {
+	case nil:
+		return log.Value{}
+	case string:
+		return log.StringValue(x)
+	case bool:
+		return log.BoolValue(x)
+	case json.Number:
+		if i, err := x.Int64(); err == nil {
+			return log.Int64Value(i)
+		}
+		if f, err := x.Float64(); err == nil {
+			return log.Float64Value(f)
+		}
+		return log.StringValue(x.String())
+	case []any:
+		vs := make([]log.Value, 0, len(x))
+		for _, v := range x {
+			vs = append(vs, otlpValue(v))
+		}
+		return log.SliceValue(vs...)
+	case map[string]any:
+		kvs := make([]log.KeyValue, 0, len(x))
+		for _, k := range slices.Sorted(maps.Keys(x)) {
+			kvs = append(kvs, log.KeyValue{Key: k, Value: otlpValue(x[k])})
+		}
+		return log.MapValue(kvs...)
+	default:
+		return log.StringValue(fmt.Sprint(x))
+	}
+}
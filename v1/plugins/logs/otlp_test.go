@@ -0,0 +1,352 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package logs
+
+import (
+	"context"
+	"encoding/hex"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/open-policy-agent/opa/v1/plugins"
+	"github.com/open-policy-agent/opa/v1/server"
+	"github.com/open-policy-agent/opa/v1/storage/inmem"
+	"github.com/open-policy-agent/opa/v1/util"
+)
+
+func TestParseConfigOTLP(t *testing.T) {
+	tests := []struct {
+		note     string
+		config   string
+		services []string
+		expected *OTLPConfig
+		service  string
+		err      string
+	}{
+		{
+			note:   "grpc defaults",
+			config: `{"otlp": {"type": "otlp/grpc"}}`,
+			expected: &OTLPConfig{
+				Type:                 "otlp/grpc",
+				Address:              "localhost:4317",
+				ServiceName:          "opa",
+				EncryptionScheme:     "off",
+				EncryptionSkipVerify: new(false),
+			},
+		},
+		{
+			note:   "http defaults",
+			config: `{"otlp": {"type": "OTLP/HTTP"}}`,
+			expected: &OTLPConfig{
+				Type:                 "OTLP/HTTP",
+				Address:              "localhost:4318",
+				ServiceName:          "opa",
+				EncryptionScheme:     "off",
+				EncryptionSkipVerify: new(false),
+			},
+		},
+		{
+			note:   "custom",
+			config: `{"otlp": {"type": "otlp/grpc", "address": "collector:4317", "service_name": "authz", "encryption": "tls", "allow_insecure_tls": true}}`,
+			expected: &OTLPConfig{
+				Type:                 "otlp/grpc",
+				Address:              "collector:4317",
+				ServiceName:          "authz",
+				EncryptionScheme:     "tls",
+				EncryptionSkipVerify: new(true),
+			},
+		},
+		{
+			note:     "no default service",
+			config:   `{"otlp": {"type": "otlp/grpc"}}`,
+			services: []string{"svc"},
+			expected: &OTLPConfig{
+				Type:                 "otlp/grpc",
+				Address:              "localhost:4317",
+				ServiceName:          "opa",
+				EncryptionScheme:     "off",
+				EncryptionSkipVerify: new(false),
+			},
+		},
+		{
+			note:     "explicit service",
+			config:   `{"service": "svc", "otlp": {"type": "otlp/grpc"}}`,
+			services: []string{"svc"},
+			service:  "svc",
+			expected: &OTLPConfig{
+				Type:                 "otlp/grpc",
+				Address:              "localhost:4317",
+				ServiceName:          "opa",
+				EncryptionScheme:     "off",
+				EncryptionSkipVerify: new(false),
+			},
+		},
+		{
+			note:   "missing type",
+			config: `{"otlp": {"address": "collector:4317"}}`,
+			err:    `invalid otlp type "" in decision_logs, expected "otlp/grpc" or "otlp/http"`,
+		},
+		{
+			note:   "invalid encryption",
+			config: `{"otlp": {"type": "otlp/grpc", "encryption": "ssl"}}`,
+			err:    `invalid otlp encryption "ssl" in decision_logs`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			config, err := NewConfigBuilder().WithBytes([]byte(tc.config)).WithServices(tc.services).Parse()
+			if tc.err != "" {
+				if err == nil || err.Error() != tc.err {
+					t.Fatalf("Expected error %q but got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if config.Service != tc.service {
+				t.Fatalf("Expected service %q but got %q", tc.service, config.Service)
+			}
+			if !reflect.DeepEqual(config.OTLP, tc.expected) {
+				t.Fatalf("Expected config %+v but got %+v", tc.expected, config.OTLP)
+			}
+		})
+	}
+}
+
+func TestPluginOTLP(t *testing.T) {
+	for _, typ := range []string{"otlp/grpc", "otlp/http"} {
+		t.Run(typ, func(t *testing.T) {
+			collector := &testOTLPCollector{}
+
+			var address string
+			if typ == "otlp/grpc" {
+				address = collector.serveGRPC(t)
+			} else {
+				address = collector.serveHTTP(t)
+			}
+
+			ctx := context.Background()
+			manager, err := plugins.New(nil, "test", inmem.New())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			config, err := NewConfigBuilder().
+				WithBytes([]byte(`{"otlp": {"type": "` + typ + `", "address": "` + address + `", "service_name": "authz"}}`)).
+				Parse()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			plugin := New(config, manager)
+			if err := plugin.Start(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			var input any = map[string]any{"user": "alice", "roles": []any{"admin"}}
+			var result any = true
+			ts := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+			if err := plugin.Log(ctx, &server.Info{
+				DecisionID: "1",
+				TraceID:    "4bf92f3577b34da6a3ce929d0e0e4736",
+				SpanID:     "00f067aa0ba902b7",
+				Path:       "test/allow",
+				Input:      &input,
+				Results:    &result,
+				Timestamp:  ts,
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if err := plugin.Log(ctx, &server.Info{
+				DecisionID: "2",
+				Query:      "data.test.allow = x",
+				Timestamp:  ts,
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			stopCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+			defer cancel()
+			plugin.Stop(stopCtx)
+
+			resource, records := collector.export()
+			if len(records) != 2 {
+				t.Fatalf("Expected 2 log records but got %d", len(records))
+			}
+
+			if exp, act := "authz", attribute(resource.GetAttributes(), "service.name").GetStringValue(); act != exp {
+				t.Fatalf("Expected service name %q but got %q", exp, act)
+			}
+
+			r := records[0]
+			if r.GetEventName() != DecisionLogType || r.GetBody().GetStringValue() != "Decision Log" || r.GetSeverityNumber() != logspb.SeverityNumber_SEVERITY_NUMBER_INFO {
+				t.Fatalf("Unexpected log record: %v", r)
+			}
+			if r.GetTimeUnixNano() != uint64(ts.UnixNano()) {
+				t.Fatalf("Expected timestamp %v but got %v", ts.UnixNano(), r.GetTimeUnixNano())
+			}
+			if act := hex.EncodeToString(r.GetTraceId()); act != "4bf92f3577b34da6a3ce929d0e0e4736" {
+				t.Fatalf("Unexpected trace ID %v", act)
+			}
+			if act := hex.EncodeToString(r.GetSpanId()); act != "00f067aa0ba902b7" {
+				t.Fatalf("Unexpected span ID %v", act)
+			}
+			if attribute(r.GetAttributes(), "trace_id") != nil || attribute(r.GetAttributes(), "span_id") != nil {
+				t.Fatal("Expected trace and span IDs to be omitted from attributes")
+			}
+
+			if act := attribute(r.GetAttributes(), "decision_id").GetStringValue(); act != "1" {
+				t.Fatalf("Expected decision ID 1 but got %q", act)
+			}
+			if act := attribute(r.GetAttributes(), "path").GetStringValue(); act != "test/allow" {
+				t.Fatalf("Expected path test/allow but got %q", act)
+			}
+			if act := attribute(r.GetAttributes(), "result"); !act.GetBoolValue() {
+				t.Fatalf("Expected result true but got %v", act)
+			}
+			if act := attribute(attribute(r.GetAttributes(), "labels").GetKvlistValue().GetValues(), "id").GetStringValue(); act != "test" {
+				t.Fatalf("Expected label id test but got %q", act)
+			}
+			in := attribute(r.GetAttributes(), "input").GetKvlistValue().GetValues()
+			if act := attribute(in, "user").GetStringValue(); act != "alice" {
+				t.Fatalf("Expected input user alice but got %q", act)
+			}
+			if act := attribute(in, "roles").GetArrayValue().GetValues(); len(act) != 1 || act[0].GetStringValue() != "admin" {
+				t.Fatalf("Expected input roles [admin] but got %v", act)
+			}
+
+			r = records[1]
+			if len(r.GetTraceId()) != 0 {
+				t.Fatalf("Expected no trace ID but got %x", r.GetTraceId())
+			}
+			if act := attribute(r.GetAttributes(), "query").GetStringValue(); act != "data.test.allow = x" {
+				t.Fatalf("Expected query but got %q", act)
+			}
+		})
+	}
+}
+
+func TestOTLPValue(t *testing.T) {
+	var x any
+	if err := util.UnmarshalJSON([]byte(`{"a": [1, 2.5, "x", true, null], "b": {"c": 18446744073709551616}}`), &x); err != nil {
+		t.Fatal(err)
+	}
+
+	kvs := otlpValue(x).AsMap()
+	if len(kvs) != 2 || kvs[0].Key != "a" || kvs[1].Key != "b" {
+		t.Fatalf("Unexpected keys: %v", kvs)
+	}
+
+	a := kvs[0].Value.AsSlice()
+	if a[0].AsInt64() != 1 || a[1].AsFloat64() != 2.5 || a[2].AsString() != "x" || !a[3].AsBool() || !a[4].Empty() {
+		t.Fatalf("Unexpected array: %v", a)
+	}
+
+	if c := kvs[1].Value.AsMap()[0].Value.AsFloat64(); c != 18446744073709551616 {
+		t.Fatalf("Unexpected number: %v", c)
+	}
+}
+
+// testOTLPCollector is an in-process OTLP logs collector.
+type testOTLPCollector struct {
+	collogspb.UnimplementedLogsServiceServer
+	mtx      sync.Mutex
+	requests []*collogspb.ExportLogsServiceRequest
+}
+
+func (c *testOTLPCollector) Export(_ context.Context, req *collogspb.ExportLogsServiceRequest) (*collogspb.ExportLogsServiceResponse, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.requests = append(c.requests, req)
+	return &collogspb.ExportLogsServiceResponse{}, nil
+}
+
+// export returns the resource and the log records received by the collector.
+func (c *testOTLPCollector) export() (*resourcepb.Resource, []*logspb.LogRecord) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	var resource *resourcepb.Resource
+	var records []*logspb.LogRecord
+	for _, req := range c.requests {
+		for _, rl := range req.GetResourceLogs() {
+			resource = rl.GetResource()
+			for _, sl := range rl.GetScopeLogs() {
+				records = append(records, sl.GetLogRecords()...)
+			}
+		}
+	}
+	return resource, records
+}
+
+func (c *testOTLPCollector) serveGRPC(t *testing.T) string {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := grpc.NewServer()
+	collogspb.RegisterLogsServiceServer(srv, c)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	return lis.Addr().String()
+}
+
+func (c *testOTLPCollector) serveHTTP(t *testing.T) string {
+	t.Helper()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/logs" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		bs, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var req collogspb.ExportLogsServiceRequest
+		if err := proto.Unmarshal(bs, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		resp, _ := c.Export(r.Context(), &req)
+		bs, _ = proto.Marshal(resp)
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		_, _ = w.Write(bs)
+	}))
+	t.Cleanup(ts.Close)
+
+	return strings.TrimPrefix(ts.URL, "http://")
+}
+
+// attribute returns the value of the attribute with the given key, or nil.
+func attribute(kvs []*commonpb.KeyValue, key string) *commonpb.AnyValue {
+	for _, kv := range kvs {
+		if kv.GetKey() == key {
+			return kv.GetValue()
+		}
+	}
+	return nil
+}
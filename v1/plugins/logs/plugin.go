@@ -10,9 +10,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"hash/fnv"
 	"log/slog"
 	"math/rand"
 	"net/url"
+	"path/filepath"
 	"reflect"
 	"slices"
 	"strings"
@@ -61,8 +63,10 @@ type EventV1 struct {
 	IntermediateResults map[string]any          `json:"intermediate_results,omitempty"`
 	MappedResult        *any                    `json:"mapped_result,omitempty"`
 	NDBuiltinCache      *any                    `json:"nd_builtin_cache,omitempty"`
+	Annotations         *any                    `json:"annotations,omitempty"`
 	Erased              []string                `json:"erased,omitempty"`
 	Masked              []string                `json:"masked,omitempty"`
+	Encrypted           []string                `json:"encrypted,omitempty"`
 	Error               error                   `json:"error,omitempty"`
 	RequestedBy         string                  `json:"requested_by,omitempty"`
 	Timestamp           time.Time               `json:"timestamp"`
@@ -71,6 +75,8 @@ type EventV1 struct {
 	RuleLabels          []map[string]any        `json:"rule_labels,omitempty"`
 	RequestContext      *RequestContext         `json:"request_context,omitempty"`
 	Custom              map[string]any          `json:"custom,omitempty"`
+	SampleRate          float64                 `json:"sample_rate,omitempty"`
+	Aggregate           *AggregateV1            `json:"aggregate,omitempty"`
 
 	inputAST ast.Value
 }
@@ -182,6 +188,14 @@ func (e *EventV1) AST() (ast.Value, error) {
 		event.Insert(ast.InternedTerm("nd_builtin_cache"), ast.NewTerm(ndbCache))
 	}
 
+	if e.Annotations != nil {
+		annotations, err := roundtripJSONToAST(e.Annotations)
+		if err != nil {
+			return nil, err
+		}
+		event.Insert(ast.InternedTerm("annotations"), ast.NewTerm(annotations))
+	}
+
 	if len(e.Erased) > 0 {
 		erased := make([]*ast.Term, len(e.Erased))
 		for i, v := range e.Erased {
@@ -198,6 +212,14 @@ func (e *EventV1) AST() (ast.Value, error) {
 		event.Insert(ast.InternedTerm("masked"), ast.ArrayTerm(masked...))
 	}
 
+	if len(e.Encrypted) > 0 {
+		encrypted := make([]*ast.Term, len(e.Encrypted))
+		for i, v := range e.Encrypted {
+			encrypted[i] = ast.StringTerm(v)
+		}
+		event.Insert(ast.InternedTerm("encrypted"), ast.ArrayTerm(encrypted...))
+	}
+
 	if e.Error != nil {
 		evalErr, err := roundtripJSONToAST(e.Error)
 		if err != nil {
@@ -245,6 +267,18 @@ func (e *EventV1) AST() (ast.Value, error) {
 		event.Insert(ast.InternedTerm("custom"), ast.NewTerm(custom))
 	}
 
+	if e.SampleRate != 0 {
+		event.Insert(ast.InternedTerm("sample_rate"), ast.FloatNumberTerm(e.SampleRate))
+	}
+
+	if e.Aggregate != nil {
+		aggregate, err := roundtripJSONToAST(e.Aggregate)
+		if err != nil {
+			return nil, err
+		}
+		event.Insert(ast.InternedTerm("aggregate"), ast.NewTerm(aggregate))
+	}
+
 	return event, nil
 }
 
@@ -261,35 +295,49 @@ func roundtripJSONToAST(x any) (ast.Value, error) {
 
 const (
 	// min amount of time to wait following a failure
-	minRetryDelay                       = time.Millisecond * 100
-	defaultMinDelaySeconds              = int64(300)
-	defaultMaxDelaySeconds              = int64(600)
-	defaultBufferSizeLimitEvents        = int64(10000)
-	defaultUploadSizeLimitBytes         = int64(32768)      // 32KB limit
-	minUploadSizeLimitBytes             = int64(90)         // A single event with a decision ID (69 bytes) + empty gzip file (21 bytes)
-	maxUploadSizeLimitBytes             = int64(4294967296) // about 4GB
-	defaultBufferSizeLimitBytes         = int64(0)          // unlimited
-	defaultMaskDecisionPath             = "/system/log/mask"
-	defaultDropDecisionPath             = "/system/log/drop"
-	logRateLimitExDropCounterName       = "decision_logs_dropped_rate_limit_exceeded"
-	logBufferEventDropCounterName       = "decision_logs_dropped_buffer_size_limit_exceeded"
-	logBufferSizeLimitExDropCounterName = "decision_logs_dropped_buffer_size_limit_bytes_exceeded"
-	logEncodingFailureCounterName       = "decision_logs_encoding_failure"
-	defaultResourcePath                 = "/logs"
-	sizeBufferType                      = "size"
-	eventBufferType                     = "event"
+	minRetryDelay                        = time.Millisecond * 100
+	defaultMinDelaySeconds               = int64(300)
+	defaultMaxDelaySeconds               = int64(600)
+	defaultBufferSizeLimitEvents         = int64(10000)
+	defaultUploadSizeLimitBytes          = int64(32768)      // 32KB limit
+	minUploadSizeLimitBytes              = int64(90)         // A single event with a decision ID (69 bytes) + empty gzip file (21 bytes)
+	maxUploadSizeLimitBytes              = int64(4294967296) // about 4GB
+	defaultBufferSizeLimitBytes          = int64(0)          // unlimited
+	defaultMaskDecisionPath              = "/system/log/mask"
+	defaultDropDecisionPath              = "/system/log/drop"
+	defaultSampleDecisionPath            = "/system/log/sample"
+	logRateLimitExDropCounterName        = "decision_logs_dropped_rate_limit_exceeded"
+	logBufferEventDropCounterName        = "decision_logs_dropped_buffer_size_limit_exceeded"
+	logBufferSizeLimitExDropCounterName  = "decision_logs_dropped_buffer_size_limit_bytes_exceeded"
+	logEncodingFailureCounterName        = "decision_logs_encoding_failure"
+	logSampledDropCounterName            = "decision_logs_dropped_sampled"
+	logBufferDiskWriteFailureCounterName = "decision_logs_disk_buffer_write_failure"
+	logBufferDiskBytesHistogramName      = "decision_logs_disk_buffer_bytes"
+	defaultResourcePath                  = "/logs"
+	sizeBufferType                       = "size"
+	eventBufferType                      = "event"
+	diskBufferType                       = "disk"
+	defaultBufferSegmentSizeBytes        = int64(4194304) // 4MB
+	defaultBufferDirectory               = "decision_logs"
+	diskBufferFsyncAlways                = "always"
+	diskBufferFsyncPeriodic              = "periodic"
+	diskBufferFsyncNever                 = "never"
 )
 
 // ReportingConfig represents configuration for the plugin's reporting behaviour.
 type ReportingConfig struct {
-	BufferType            string               `json:"buffer_type,omitempty"`              // toggles how the buffer stores events, defaults to using bytes
-	BufferSizeLimitBytes  *int64               `json:"buffer_size_limit_bytes,omitempty"`  // max size of in-memory size buffer
-	BufferSizeLimitEvents *int64               `json:"buffer_size_limit_events,omitempty"` // max size of in-memory event channel buffer
-	UploadSizeLimitBytes  *int64               `json:"upload_size_limit_bytes,omitempty"`  // max size of upload payload
-	MinDelaySeconds       *int64               `json:"min_delay_seconds,omitempty"`        // min amount of time to wait between successful poll attempts
-	MaxDelaySeconds       *int64               `json:"max_delay_seconds,omitempty"`        // max amount of time to wait between poll attempts
-	MaxDecisionsPerSecond *float64             `json:"max_decisions_per_second,omitempty"` // max number of decision logs to buffer per second
-	Trigger               *plugins.TriggerMode `json:"trigger,omitempty"`                  // trigger mode
+	BufferType            string               `json:"buffer_type,omitempty"`               // toggles how the buffer stores events, defaults to using bytes
+	BufferSizeLimitBytes  *int64               `json:"buffer_size_limit_bytes,omitempty"`   // max size of in-memory size buffer or disk buffer
+	BufferSizeLimitEvents *int64               `json:"buffer_size_limit_events,omitempty"`  // max size of in-memory event channel buffer
+	BufferDirectory       string               `json:"buffer_directory,omitempty"`          // directory of the disk buffer, defaults to a directory in the persistence directory
+	BufferSegmentSize     *int64               `json:"buffer_segment_size_bytes,omitempty"` // max size of a disk buffer segment file
+	BufferFsync           string               `json:"buffer_fsync,omitempty"`              // when the disk buffer syncs writes to disk
+	UploadSizeLimitBytes  *int64               `json:"upload_size_limit_bytes,omitempty"`   // max size of upload payload
+	MinDelaySeconds       *int64               `json:"min_delay_seconds,omitempty"`         // min amount of time to wait between successful poll attempts
+	MaxDelaySeconds       *int64               `json:"max_delay_seconds,omitempty"`         // max amount of time to wait between poll attempts
+	MaxDecisionsPerSecond *float64             `json:"max_decisions_per_second,omitempty"`  // max number of decision logs to buffer per second
+	Trigger               *plugins.TriggerMode `json:"trigger,omitempty"`                   // trigger mode
+	Encoding              string               `json:"encoding,omitempty"`                  // encoding of the uploaded events, defaults to a JSON array
 }
 
 type RequestContextConfig struct {
@@ -302,18 +350,24 @@ type HTTPRequestContextConfig struct {
 
 // Config represents the plugin configuration.
 type Config struct {
-	Plugin          *string              `json:"plugin"`
-	Service         string               `json:"service"`
-	PartitionName   string               `json:"partition_name,omitempty"`
-	Reporting       ReportingConfig      `json:"reporting"`
-	RequestContext  RequestContextConfig `json:"request_context"`
-	MaskDecision    *string              `json:"mask_decision"`
-	DropDecision    *string              `json:"drop_decision"`
-	ConsoleLogs     bool                 `json:"console"`
-	Resource        *string              `json:"resource"`
-	NDBuiltinCache  bool                 `json:"nd_builtin_cache,omitempty"`
-	maskDecisionRef ast.Ref
-	dropDecisionRef ast.Ref
+	Plugin            *string                `json:"plugin"`
+	Service           string                 `json:"service"`
+	PartitionName     string                 `json:"partition_name,omitempty"`
+	Reporting         ReportingConfig        `json:"reporting"`
+	RequestContext    RequestContextConfig   `json:"request_context"`
+	MaskDecision      *string                `json:"mask_decision"`
+	DropDecision      *string                `json:"drop_decision"`
+	SampleDecision    *string                `json:"sample_decision"`
+	ConsoleLogs       bool                   `json:"console"`
+	OTLP              *OTLPConfig            `json:"otlp,omitempty"`
+	Aggregation       *AggregationConfig     `json:"aggregation,omitempty"`
+	RecentDecisions   *RecentDecisionsConfig `json:"recent_decisions,omitempty"`
+	Sinks             map[string]*SinkConfig `json:"sinks,omitempty"`
+	Resource          *string                `json:"resource"`
+	NDBuiltinCache    bool                   `json:"nd_builtin_cache,omitempty"`
+	maskDecisionRef   ast.Ref
+	dropDecisionRef   ast.Ref
+	sampleDecisionRef ast.Ref
 }
 
 func (c *Config) validateAndInjectDefaults(services []string, pluginsList []string, trigger *plugins.TriggerMode, l logging.Logger) error {
@@ -326,11 +380,11 @@ func (c *Config) validateAndInjectDefaults(services []string, pluginsList []stri
 		if !found {
 			return fmt.Errorf("invalid plugin name %q in decision_logs", *c.Plugin)
 		}
-	} else if c.Service == "" && len(services) != 0 && !c.ConsoleLogs {
+	} else if c.Service == "" && len(services) != 0 && !c.ConsoleLogs && c.OTLP == nil && len(c.Sinks) == 0 {
 		// For backwards compatibility allow defaulting to the first
-		// service listed, but only if console logging is disabled. If enabled
-		// we can't tell if the deployer wanted to use only console logs or
-		// both console logs and the default service option.
+		// service listed, but only if console logging, OTLP export and sinks
+		// are disabled. If enabled we can't tell if the deployer wanted to use
+		// only those or both them and the default service option.
 		c.Service = services[0]
 	} else if c.Service != "" {
 		found := slices.Contains(services, c.Service)
@@ -340,6 +394,12 @@ func (c *Config) validateAndInjectDefaults(services []string, pluginsList []stri
 		}
 	}
 
+	if c.OTLP != nil {
+		if err := c.OTLP.validateAndInjectDefaults(); err != nil {
+			return err
+		}
+	}
+
 	t, err := plugins.ValidateAndInjectDefaultsForTriggerMode(trigger, c.Reporting.Trigger)
 	if err != nil {
 		return fmt.Errorf("invalid decision_log config: %w", err)
@@ -392,17 +452,50 @@ func (c *Config) validateAndInjectDefaults(services []string, pluginsList []stri
 		c.Reporting.UploadSizeLimitBytes = &uploadLimit
 	}
 
-	if c.Reporting.BufferType == "" {
+	switch c.Reporting.BufferType {
+	case "":
 		c.Reporting.BufferType = sizeBufferType
-	} else if c.Reporting.BufferType != eventBufferType && c.Reporting.BufferType != sizeBufferType {
-		return fmt.Errorf("invalid buffer type %q, expected %q or %q", c.Reporting.BufferType, eventBufferType, sizeBufferType)
+	case sizeBufferType, eventBufferType, diskBufferType:
+	default:
+		return fmt.Errorf("invalid buffer type %q, expected %q, %q or %q", c.Reporting.BufferType, eventBufferType, sizeBufferType, diskBufferType)
+	}
+
+	switch c.Reporting.Encoding {
+	case "":
+		c.Reporting.Encoding = encodingJSON
+	case encodingJSON, encodingCloudEvents, encodingCloudEventsBatch, encodingProtobuf:
+	default:
+		return fmt.Errorf("invalid decision_log config, 'encoding' must be %q, %q, %q or %q", encodingJSON, encodingCloudEvents, encodingCloudEventsBatch, encodingProtobuf)
 	}
 
 	if c.Reporting.BufferType == eventBufferType && c.Reporting.BufferSizeLimitBytes != nil {
 		return fmt.Errorf("invalid decision_log config, 'buffer_size_limit_bytes' isn't supported for the %v buffer type", eventBufferType)
 	}
-	if c.Reporting.BufferType == sizeBufferType && c.Reporting.BufferSizeLimitEvents != nil {
-		return fmt.Errorf("invalid decision_log config, 'buffer_size_limit_events' isn't supported for the %v buffer type", sizeBufferType)
+	if c.Reporting.BufferType != eventBufferType && c.Reporting.BufferSizeLimitEvents != nil {
+		return fmt.Errorf("invalid decision_log config, 'buffer_size_limit_events' isn't supported for the %v buffer type", c.Reporting.BufferType)
+	}
+
+	if c.Reporting.BufferType != diskBufferType {
+		if c.Reporting.BufferDirectory != "" || c.Reporting.BufferSegmentSize != nil || c.Reporting.BufferFsync != "" {
+			return fmt.Errorf("invalid decision_log config, 'buffer_directory', 'buffer_segment_size_bytes' and 'buffer_fsync' are only supported for the %v buffer type", diskBufferType)
+		}
+	} else {
+		switch c.Reporting.BufferFsync {
+		case "":
+			c.Reporting.BufferFsync = diskBufferFsyncAlways
+		case diskBufferFsyncAlways, diskBufferFsyncPeriodic, diskBufferFsyncNever:
+		default:
+			return fmt.Errorf("invalid decision_log config, 'buffer_fsync' must be %q, %q or %q", diskBufferFsyncAlways, diskBufferFsyncPeriodic, diskBufferFsyncNever)
+		}
+
+		segmentSize := defaultBufferSegmentSizeBytes
+		if c.Reporting.BufferSegmentSize != nil {
+			if *c.Reporting.BufferSegmentSize <= int64(0) {
+				return errors.New("invalid decision_log config, 'buffer_segment_size_bytes' must be higher than 0")
+			}
+			segmentSize = *c.Reporting.BufferSegmentSize
+		}
+		c.Reporting.BufferSegmentSize = &segmentSize
 	}
 
 	if c.Reporting.BufferSizeLimitBytes != nil && c.Reporting.MaxDecisionsPerSecond != nil {
@@ -448,6 +541,16 @@ func (c *Config) validateAndInjectDefaults(services []string, pluginsList []stri
 		return fmt.Errorf("invalid drop_decision in decision_logs: %w", err)
 	}
 
+	if c.SampleDecision == nil {
+		sampleDecision := defaultSampleDecisionPath
+		c.SampleDecision = &sampleDecision
+	}
+
+	c.sampleDecisionRef, err = ref.ParseDataPath(*c.SampleDecision)
+	if err != nil {
+		return fmt.Errorf("invalid sample_decision in decision_logs: %w", err)
+	}
+
 	if c.PartitionName != "" {
 		resourcePath := fmt.Sprintf("/logs/%v", c.PartitionName)
 		c.Resource = &resourcePath
@@ -460,9 +563,37 @@ func (c *Config) validateAndInjectDefaults(services []string, pluginsList []stri
 		}
 	}
 
+	if c.Aggregation != nil {
+		if err := c.Aggregation.validateAndInjectDefaults(); err != nil {
+			return err
+		}
+	}
+
+	if c.RecentDecisions != nil {
+		if err := c.RecentDecisions.validateAndInjectDefaults(); err != nil {
+			return err
+		}
+	}
+
+	for name, sink := range c.Sinks {
+		if sink == nil {
+			return fmt.Errorf("invalid sink %q in decision_logs", name)
+		}
+		if err := sink.validateAndInjectDefaults(name, c, services, pluginsList, trigger, l); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// hasDestination returns true if decisions are logged by the plugin itself,
+// and not only by its sinks.
+func (p *Plugin) hasDestination() bool {
+	return p.config.Service != "" || p.config.ConsoleLogs || p.config.Plugin != nil || p.config.OTLP != nil ||
+		p.config.RecentDecisions != nil || (p.sink != nil && p.sink.file != nil)
+}
+
 type buffer interface {
 	Name() string
 	Push(*EventV1)
@@ -474,20 +605,27 @@ type buffer interface {
 
 // Plugin implements decision log buffering and uploading.
 type Plugin struct {
-	manager       *plugins.Manager
-	config        Config
-	reconfigMtx   sync.RWMutex // reconfigMtx blocks reads/writes on buffer reconfiguration
-	b             buffer
-	statusMtx     sync.Mutex
-	stop          chan chan struct{}
-	reconfig      chan reconfigure
-	preparedMask  prepareOnce
-	preparedDrop  prepareOnce
-	metrics       metrics.Metrics
-	logger        logging.Logger
-	status        *lstat.Status
-	cachedSlogger *slog.Logger
-	sloggerMtx    sync.RWMutex
+	manager        *plugins.Manager
+	config         Config
+	reconfigMtx    sync.RWMutex // reconfigMtx blocks reads/writes on buffer reconfiguration
+	b              buffer
+	otlp           *otlpLogger
+	recent         *recentDecisions
+	sinks          map[string]*Plugin
+	sink           *sink // set if the plugin logs to a sink of its parent
+	aggregatorMtx  sync.Mutex
+	aggregator     *aggregator
+	statusMtx      sync.Mutex
+	stop           chan chan struct{}
+	reconfig       chan reconfigure
+	preparedMask   prepareOnce
+	preparedDrop   prepareOnce
+	preparedSample prepareOnce
+	metrics        metrics.Metrics
+	logger         logging.Logger
+	status         *lstat.Status
+	cachedSlogger  *slog.Logger
+	sloggerMtx     sync.RWMutex
 }
 
 type prepareOnce struct {
@@ -584,7 +722,7 @@ func (b *ConfigBuilder) Parse() (*Config, error) {
 		return nil, err
 	}
 
-	if parsedConfig.Plugin == nil && parsedConfig.Service == "" && len(b.services) == 0 && !parsedConfig.ConsoleLogs {
+	if parsedConfig.Plugin == nil && parsedConfig.Service == "" && len(b.services) == 0 && !parsedConfig.ConsoleLogs && parsedConfig.OTLP == nil && len(parsedConfig.Sinks) == 0 && parsedConfig.RecentDecisions == nil {
 		// Nothing to validate or inject
 		return nil, nil
 	}
@@ -599,33 +737,26 @@ func (b *ConfigBuilder) Parse() (*Config, error) {
 // New returns a new Plugin with the given config.
 func New(parsedConfig *Config, manager *plugins.Manager) *Plugin {
 	plugin := &Plugin{
-		manager:      manager,
-		config:       *parsedConfig,
-		stop:         make(chan chan struct{}),
-		reconfig:     make(chan reconfigure),
-		logger:       manager.Logger().WithFields(map[string]any{"plugin": Name}),
-		status:       &lstat.Status{},
-		preparedDrop: *newPrepareOnce(),
-		preparedMask: *newPrepareOnce(),
+		manager:        manager,
+		config:         *parsedConfig,
+		stop:           make(chan chan struct{}),
+		reconfig:       make(chan reconfigure),
+		logger:         manager.Logger().WithFields(map[string]any{"plugin": Name}),
+		status:         &lstat.Status{},
+		preparedDrop:   *newPrepareOnce(),
+		preparedSample: *newPrepareOnce(),
+		preparedMask:   *newPrepareOnce(),
 	}
 
-	switch parsedConfig.Reporting.BufferType {
-	case eventBufferType:
-		plugin.b = newEventBuffer(
-			*parsedConfig.Reporting.BufferSizeLimitEvents,
-			*parsedConfig.Reporting.UploadSizeLimitBytes,
-			plugin.manager.Client(plugin.config.Service),
-			*parsedConfig.Resource,
-			*parsedConfig.Reporting.Trigger,
-		).WithLogger(plugin.logger).WithLimiter(parsedConfig.Reporting.MaxDecisionsPerSecond)
-	case sizeBufferType:
-		plugin.b = newSizeBuffer(
-			*parsedConfig.Reporting.BufferSizeLimitBytes,
-			*parsedConfig.Reporting.UploadSizeLimitBytes,
-			plugin.manager.Client(plugin.config.Service),
-			*parsedConfig.Resource,
-			*parsedConfig.Reporting.Trigger,
-		).WithLogger(plugin.logger).WithLimiter(parsedConfig.Reporting.MaxDecisionsPerSecond)
+	plugin.b = plugin.newBuffer()
+
+	if parsedConfig.RecentDecisions != nil {
+		plugin.recent = newRecentDecisions(parsedConfig.RecentDecisions.Size)
+	}
+
+	plugin.sinks = make(map[string]*Plugin, len(parsedConfig.Sinks))
+	for name, config := range parsedConfig.Sinks {
+		plugin.sinks[name] = plugin.newSink(name, config)
 	}
 
 	manager.RegisterCompilerTrigger(plugin.compilerUpdated)
@@ -635,10 +766,65 @@ func New(parsedConfig *Config, manager *plugins.Manager) *Plugin {
 	return plugin
 }
 
+// newBuffer returns a buffer of the configured type.
+func (p *Plugin) newBuffer() buffer {
+	switch p.config.Reporting.BufferType {
+	case eventBufferType:
+		return newEventBuffer(
+			*p.config.Reporting.BufferSizeLimitEvents,
+			*p.config.Reporting.UploadSizeLimitBytes,
+			p.manager.Client(p.config.Service),
+			*p.config.Resource,
+			*p.config.Reporting.Trigger,
+		).WithFormat(eventFormats[p.config.Reporting.Encoding]).WithLogger(p.logger).WithLimiter(p.config.Reporting.MaxDecisionsPerSecond)
+	case diskBufferType:
+		return newDiskBuffer(
+			p.bufferDirectory(),
+			*p.config.Reporting.BufferSizeLimitBytes,
+			*p.config.Reporting.BufferSegmentSize,
+			p.config.Reporting.BufferFsync,
+			*p.config.Reporting.UploadSizeLimitBytes,
+			p.manager.Client(p.config.Service),
+			*p.config.Resource,
+			*p.config.Reporting.Trigger,
+		).WithFormat(eventFormats[p.config.Reporting.Encoding]).WithLogger(p.logger).WithLimiter(p.config.Reporting.MaxDecisionsPerSecond)
+	default:
+		return newSizeBuffer(
+			*p.config.Reporting.BufferSizeLimitBytes,
+			*p.config.Reporting.UploadSizeLimitBytes,
+			p.manager.Client(p.config.Service),
+			*p.config.Resource,
+			*p.config.Reporting.Trigger,
+		).WithFormat(eventFormats[p.config.Reporting.Encoding]).WithLogger(p.logger).WithLimiter(p.config.Reporting.MaxDecisionsPerSecond)
+	}
+}
+
+// bufferDirectory returns the directory of the disk buffer, which defaults to
+// a directory in the persistence directory.
+func (p *Plugin) bufferDirectory() string {
+	if p.config.Reporting.BufferDirectory != "" {
+		return p.config.Reporting.BufferDirectory
+	}
+	dir := defaultBufferDirectory
+	if persistenceDir, err := p.manager.GetConfig().GetPersistenceDirectory(); err != nil {
+		p.logger.Error("Failed to get persistence directory, using %q: %v", defaultBufferDirectory, err)
+	} else {
+		dir = filepath.Join(persistenceDir, defaultBufferDirectory)
+	}
+	if p.sink != nil {
+		// Each sink has its own buffer, so it needs its own directory.
+		dir = filepath.Join(dir, p.sink.name)
+	}
+	return dir
+}
+
 // WithMetrics sets the global metrics provider to be used by the plugin.
 func (p *Plugin) WithMetrics(m metrics.Metrics) *Plugin {
 	p.metrics = m
 	p.b.WithMetrics(m)
+	for _, s := range p.sinks {
+		s.WithMetrics(m)
+	}
 	return p
 }
 
@@ -654,16 +840,36 @@ func Lookup(manager *plugins.Manager) *Plugin {
 }
 
 // Start starts the plugin.
-func (p *Plugin) Start(_ context.Context) error {
+func (p *Plugin) Start(ctx context.Context) error {
 	p.logger.Info("Starting decision logger.")
+	if p.config.OTLP != nil {
+		otlp, err := newOTLPLogger(ctx, p.config.OTLP)
+		if err != nil {
+			return err
+		}
+		p.otlp = otlp
+	}
+	if p.sink != nil {
+		if err := p.openFile(p.sink.config.File); err != nil {
+			return err
+		}
+	}
+	if err := p.startSinks(ctx); err != nil {
+		return err
+	}
+	p.startAggregation()
 	go p.loop()
-	p.manager.UpdatePluginStatus(Name, &plugins.Status{State: plugins.StateOK})
+	if p.sink == nil {
+		p.manager.UpdatePluginStatus(Name, &plugins.Status{State: plugins.StateOK})
+	}
 	return nil
 }
 
 // Stop stops the plugin.
 func (p *Plugin) Stop(ctx context.Context) {
 	p.logger.Info("Stopping decision logger.")
+	p.stopSinks(ctx)
+	p.stopAggregation()
 	p.b.Stop(ctx)
 
 	if *p.config.Reporting.Trigger == plugins.TriggerPeriodic || *p.config.Reporting.Trigger == plugins.TriggerImmediate {
@@ -675,7 +881,17 @@ func (p *Plugin) Stop(ctx context.Context) {
 	done := make(chan struct{})
 	p.stop <- done
 	<-done
-	p.manager.UpdatePluginStatus(Name, &plugins.Status{State: plugins.StateNotReady})
+
+	p.reconfigMtx.Lock()
+	p.stopOTLP(ctx)
+	if p.sink != nil {
+		p.closeFile()
+	}
+	p.reconfigMtx.Unlock()
+
+	if p.sink == nil {
+		p.manager.UpdatePluginStatus(Name, &plugins.Status{State: plugins.StateNotReady})
+	}
 }
 
 // Config returns the plugin's current configuration
@@ -714,6 +930,11 @@ func (p *Plugin) flushDecisions(ctx context.Context) {
 
 // Log appends a decision log event to the buffer for uploading.
 func (p *Plugin) Log(ctx context.Context, decision *server.Info) error {
+	p.logSinks(ctx, decision)
+	if !p.hasDestination() {
+		return nil
+	}
+
 	bundles := map[string]BundleInfoV1{}
 	for name, info := range decision.Bundles {
 		bundles[name] = BundleInfoV1{Revision: info.Revision}
@@ -742,6 +963,11 @@ func (p *Plugin) Log(ctx context.Context, decision *server.Info) error {
 		Custom:              decision.Custom,
 	}
 
+	if len(decision.Annotations) > 0 {
+		var annotations any = decision.Annotations
+		event.Annotations = &annotations
+	}
+
 	headers := map[string][]string{}
 	rctx := p.config.RequestContext
 
@@ -763,6 +989,17 @@ func (p *Plugin) Log(ctx context.Context, decision *server.Info) error {
 		return err
 	}
 
+	if p.sink != nil {
+		route, err := p.routeEvent(ctx, decision.Txn, event.Path, input)
+		if err != nil {
+			p.logger.Error("Log route decision failed: %v.", err)
+			return nil
+		}
+		if !route {
+			return nil
+		}
+	}
+
 	drop, err := p.dropEvent(ctx, decision.Txn, input)
 	if err != nil {
 		p.logger.Error("Log drop decision failed: %v.", err)
@@ -774,6 +1011,29 @@ func (p *Plugin) Log(ctx context.Context, decision *server.Info) error {
 		return nil
 	}
 
+	if a := p.aggregatorFor(decision.Path); a != nil {
+		// The aggregates are keyed by the input and result of the decisions,
+		// which are masked like those of logged events.
+		if err := p.maskEvent(ctx, decision.Txn, input, &event); err != nil {
+			p.logger.Error("Log event masking failed: %v.", err)
+			return nil
+		}
+		p.aggregate(a, decision, &event)
+		return nil
+	}
+
+	rate, keep, err := p.sampleEvent(ctx, decision.Txn, input)
+	if err != nil {
+		// Keep the event, so that a broken sampling policy does not lose decisions.
+		p.logger.Error("Log sample decision failed: %v.", err)
+	} else if !keep {
+		p.incrMetric(logSampledDropCounterName)
+		return nil
+	}
+	if rate < 1 {
+		event.SampleRate = rate
+	}
+
 	if decision.Metrics != nil {
 		event.Metrics = decision.Metrics.All()
 	}
@@ -787,6 +1047,13 @@ func (p *Plugin) Log(ctx context.Context, decision *server.Info) error {
 		return nil
 	}
 
+	p.recordRecent(event)
+
+	return p.emit(ctx, event)
+}
+
+// emit logs the event to the destinations of the plugin.
+func (p *Plugin) emit(ctx context.Context, event EventV1) error {
 	if p.config.ConsoleLogs {
 		if err := p.logEvent(event); err != nil {
 			p.logger.Error("Failed to log to console: %v.", err)
@@ -797,6 +1064,14 @@ func (p *Plugin) Log(ctx context.Context, decision *server.Info) error {
 		p.push(event)
 	}
 
+	p.exportOTLP(ctx, event)
+
+	if p.sink != nil && p.sink.file != nil {
+		if err := p.sink.file.Log(event); err != nil {
+			p.logger.Error("Failed to log to file: %v.", err)
+		}
+	}
+
 	if p.config.Plugin != nil {
 		plugin := p.manager.Plugin(*p.config.Plugin)
 		if plugin == nil {
@@ -854,21 +1129,28 @@ func (p *Plugin) clearSlogCache() {
 
 // Reconfigure notifies the plugin with a new configuration.
 func (p *Plugin) Reconfigure(_ context.Context, config any) {
+	// The aggregates are flushed with the current config, and aggregation
+	// restarts with the new one.
+	p.stopAggregation()
 
 	done := make(chan struct{})
 	p.reconfig <- reconfigure{config: config, done: done}
 
 	p.preparedMask.drop()
 	p.preparedDrop.drop()
+	p.preparedSample.drop()
 	p.clearSlogCache()
 
 	<-done
+	p.startAggregation()
 	go p.loop()
 }
 
 // Trigger can be used to control when the plugin attempts to upload
 // a new decision log in manual triggering mode.
 func (p *Plugin) Trigger(ctx context.Context) error {
+	sinksErr := p.triggerSinks(ctx)
+
 	done := make(chan error)
 
 	go func() {
@@ -885,7 +1167,7 @@ func (p *Plugin) Trigger(ctx context.Context) error {
 
 	select {
 	case err := <-done:
-		return err
+		return errors.Join(err, sinksErr)
 	case <-ctx.Done():
 		return ctx.Err()
 	}
@@ -894,9 +1176,11 @@ func (p *Plugin) Trigger(ctx context.Context) error {
 // compilerUpdated is called when a compiler trigger on the plugin manager
 // fires. This indicates a new compiler instance is available. The decision
 // logger needs to prepare a new masking query.
-func (p *Plugin) compilerUpdated(storage.Transaction) {
+func (p *Plugin) compilerUpdated(txn storage.Transaction) {
 	p.preparedMask.drop()
 	p.preparedDrop.drop()
+	p.preparedSample.drop()
+	p.compilerUpdatedSinks(txn)
 }
 
 func (p *Plugin) loop() {
@@ -1010,31 +1294,59 @@ func (p *Plugin) reconfigure(ctx context.Context, config any) {
 		p.setStatus(err)
 	}
 	p.b.Stop(ctx)
-	events := p.b.Flush()
 
-	switch newConfig.Reporting.BufferType {
-	case eventBufferType:
-		p.b = newEventBuffer(
-			*p.config.Reporting.BufferSizeLimitEvents,
-			*p.config.Reporting.UploadSizeLimitBytes,
-			p.manager.Client(p.config.Service),
-			*p.config.Resource,
-			*p.config.Reporting.Trigger,
-		).WithLogger(p.logger).WithLimiter(p.config.Reporting.MaxDecisionsPerSecond)
-	case sizeBufferType:
-		p.b = newSizeBuffer(
-			*p.config.Reporting.BufferSizeLimitBytes,
-			*p.config.Reporting.UploadSizeLimitBytes,
-			p.manager.Client(p.config.Service),
-			*p.config.Resource,
-			*p.config.Reporting.Trigger,
-		).WithLogger(p.logger).WithLimiter(p.config.Reporting.MaxDecisionsPerSecond)
+	// A disk buffer in the same directory keeps its segments, which the new
+	// buffer loads again, so that no events are lost if OPA stops meanwhile.
+	var events []*EventV1
+	if b, ok := p.b.(*diskBuffer); !ok || p.config.Reporting.BufferType != diskBufferType || b.dir != p.bufferDirectory() {
+		events = p.b.Flush()
 	}
+
+	p.b = p.newBuffer()
 	p.b.WithMetrics(p.metrics)
 
 	for _, event := range events {
 		p.b.Push(event)
 	}
+
+	p.reconfigureRecent()
+
+	p.stopOTLP(ctx)
+	if p.config.OTLP != nil {
+		otlp, err := newOTLPLogger(ctx, p.config.OTLP)
+		if err != nil {
+			p.logger.Error("Failed to create OTLP log exporter: %v.", err)
+			return
+		}
+		p.otlp = otlp
+	}
+
+	p.reconfigureSinks(ctx)
+}
+
+// exportOTLP exports the event to the OTLP logs exporter, if configured.
+func (p *Plugin) exportOTLP(ctx context.Context, event EventV1) {
+	p.reconfigMtx.RLock()
+	defer p.reconfigMtx.RUnlock()
+
+	if p.otlp == nil {
+		return
+	}
+	if err := p.otlp.Log(ctx, event); err != nil {
+		p.logger.Error("Failed to export decision log to OTLP: %v.", err)
+	}
+}
+
+// stopOTLP stops the OTLP logs exporter, if any. The caller must hold the
+// reconfigMtx write lock.
+func (p *Plugin) stopOTLP(ctx context.Context) {
+	if p.otlp == nil {
+		return
+	}
+	if err := p.otlp.Stop(ctx); err != nil {
+		p.logger.Error("Failed to stop OTLP log exporter: %v.", err)
+	}
+	p.otlp = nil
 }
 
 func (p *Plugin) push(event EventV1) {
@@ -1089,6 +1401,7 @@ func (p *Plugin) maskEvent(ctx context.Context, txn storage.Transaction, input a
 		func(mRule *maskRule, err error) {
 			p.logger.Error("mask rule skipped: %s: %s", mRule.String(), err.Error())
 		},
+		p.manager.PublicKeys(),
 	)
 	if err != nil {
 		return err
@@ -1140,10 +1453,103 @@ func (p *Plugin) dropEvent(ctx context.Context, txn storage.Transaction, input a
 	return rs.Allowed(), nil
 }
 
-func uploadChunk(ctx context.Context, client rest.Client, uploadPath string, data []byte) error {
+// sampleEvent evaluates the sample decision, which is either a sampling rate
+// between 0 and 1, or an object with the rate and a key to sample by. Events are
+// sampled at random, or by the hash of the key so that all events with the same
+// key are either kept or dropped. It returns the sampling rate and whether the
+// event is kept. Events are kept at a rate of 1 if the decision is undefined.
+func (p *Plugin) sampleEvent(ctx context.Context, txn storage.Transaction, input ast.Value) (float64, bool, error) {
+	pq, err := p.preparedSample.prepareOnce(func() (*rego.PreparedEvalQuery, error) {
+		var pq rego.PreparedEvalQuery
+
+		query := ast.NewBody(ast.NewExpr(ast.NewTerm(p.config.sampleDecisionRef)))
+		r := rego.New(
+			rego.ParsedQuery(query),
+			rego.Compiler(p.manager.GetCompiler()),
+			rego.Store(p.manager.Store),
+			rego.Transaction(txn),
+			rego.Runtime(p.manager.Info),
+			rego.EnablePrintStatements(p.manager.EnablePrintStatements()),
+			rego.PrintHook(p.manager.PrintHook()),
+		)
+
+		pq, err := r.PrepareForEval(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		return &pq, nil
+	})
+
+	if err != nil {
+		return 1, true, err
+	}
+
+	rs, err := pq.Eval(
+		ctx,
+		rego.EvalParsedInput(input),
+		rego.EvalTransaction(txn),
+	)
+
+	if err != nil {
+		return 1, true, err
+	} else if len(rs) == 0 {
+		return 1, true, nil
+	}
+
+	rate, key, err := parseSampleDecision(rs[0].Expressions[0].Value)
+	if err != nil {
+		return 1, true, err
+	}
+
+	switch {
+	case rate >= 1:
+		return 1, true, nil
+	case rate <= 0:
+		return 0, false, nil
+	case key == nil:
+		return rate, rand.Float64() < rate, nil
+	}
+
+	h := fnv.New64a()
+	if s, ok := key.(string); ok {
+		_, _ = h.Write([]byte(s))
+	} else {
+		_, _ = h.Write(util.MustMarshalJSON(key))
+	}
+	// The top 53 bits of the hash, as a float in [0, 1).
+	return rate, float64(h.Sum64()>>11)/(1<<53) < rate, nil
+}
+
+func parseSampleDecision(x any) (float64, any, error) {
+	var rate, key any
+	switch x := x.(type) {
+	case map[string]any:
+		rate, key = x["rate"], x["key"]
+	default:
+		rate = x
+	}
+
+	n, ok := rate.(json.Number)
+	if !ok {
+		return 0, nil, fmt.Errorf("sample decision must be a rate or an object with a rate and a key, got %v", x)
+	}
+	f, err := n.Float64()
+	if err != nil || f < 0 || f > 1 {
+		return 0, nil, fmt.Errorf("sample decision rate must be between 0 and 1, got %v", n)
+	}
+	return f, key, nil
+}
+
+func (p *Plugin) incrMetric(name string) {
+	if p.metrics != nil {
+		p.metrics.Counter(name).Incr()
+	}
+}
+
+func uploadChunk(ctx context.Context, client rest.Client, uploadPath string, data []byte, format *eventFormat) error {
 
 	resp, err := client.
-		WithHeader("Content-Type", "application/json").
+		WithHeader("Content-Type", format.contentType).
 		WithHeader("Content-Encoding", "gzip").
 		WithBytes(data).
 		Do(ctx, "POST", uploadPath)
@@ -1217,8 +1623,10 @@ func eventToAttrs(event EventV1) []slog.Attr {
 	addAttrIfHasLen(&attrs, "intermediate_results", event.IntermediateResults)
 	addAttrIfNotNil(&attrs, "mapped_result", event.MappedResult)
 	addAttrIfNotNil(&attrs, "nd_builtin_cache", event.NDBuiltinCache)
+	addAttrIfNotNil(&attrs, "annotations", event.Annotations)
 	addAttrIfSliceNotEmpty(&attrs, "erased", event.Erased)
 	addAttrIfSliceNotEmpty(&attrs, "masked", event.Masked)
+	addAttrIfSliceNotEmpty(&attrs, "encrypted", event.Encrypted)
 
 	if event.Error != nil {
 		attrs = append(attrs, slog.String("error", event.Error.Error()))
@@ -1234,6 +1642,8 @@ func eventToAttrs(event EventV1) []slog.Attr {
 
 	addAttrIfSliceNotEmpty(&attrs, "rule_labels", event.RuleLabels)
 	addAttrIfHasLen(&attrs, "custom", event.Custom)
+	addAttrIfNonZero(&attrs, "sample_rate", event.SampleRate)
+	addAttrIfNotNil(&attrs, "aggregate", event.Aggregate)
 
 	return attrs
 }
@@ -1325,8 +1735,15 @@ func eventToFields(event EventV1) map[string]any {
 			fields["nd_builtin_cache"] = v
 		}
 	}
+	if event.Annotations != nil {
+		v := *event.Annotations
+		if err := util.RoundTrip(&v); err == nil {
+			fields["annotations"] = v
+		}
+	}
 	addIfSliceNotEmpty(fields, "erased", util.ToSliceOfAny(event.Erased))
 	addIfSliceNotEmpty(fields, "masked", util.ToSliceOfAny(event.Masked))
+	addIfSliceNotEmpty(fields, "encrypted", util.ToSliceOfAny(event.Encrypted))
 
 	if event.Error != nil {
 		fields["error"] = event.Error.Error()
@@ -1359,16 +1776,38 @@ func eventToFields(event EventV1) map[string]any {
 		}
 	}
 
+	if event.SampleRate != 0 {
+		var v any = event.SampleRate
+		if err := util.RoundTrip(&v); err == nil {
+			fields["sample_rate"] = v
+		}
+	}
+
+	if event.Aggregate != nil {
+		var v any = event.Aggregate
+		if err := util.RoundTrip(&v); err == nil {
+			fields["aggregate"] = v
+		}
+	}
+
 	return fields
 }
 
 func (p *Plugin) setStatus(err error) {
 	p.statusMtx.Lock()
 	p.status.SetError(err)
-	oldStatus := p.status
+	current := *p.status
 	p.statusMtx.Unlock()
 
+	if p.sink != nil {
+		p.sink.parent.setSinkStatus(p.sink.name, current)
+		return
+	}
+	p.reportStatus(current)
+}
+
+func (p *Plugin) reportStatus(current lstat.Status) {
 	if s := status.Lookup(p.manager); s != nil {
-		s.UpdateDecisionLogsStatus(*oldStatus)
+		s.UpdateDecisionLogsStatus(current)
 	}
 }
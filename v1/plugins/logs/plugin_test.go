@@ -20,6 +20,7 @@ import (
 	"os"
 	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -2043,6 +2044,16 @@ func TestPluginGracefulShutdownFlushesDecisions(t *testing.T) {
 			bufferType: sizeBufferType,
 			mode:       plugins.TriggerPeriodic,
 		},
+		{
+			name:       "immediate mode, disk buffer",
+			bufferType: diskBufferType,
+			mode:       plugins.TriggerImmediate,
+		},
+		{
+			name:       "periodic mode, disk buffer",
+			bufferType: diskBufferType,
+			mode:       plugins.TriggerPeriodic,
+		},
 	}
 
 	for _, tc := range tests {
@@ -2253,6 +2264,10 @@ func TestPluginFlush(t *testing.T) {
 			name:       "Flush size buffer",
 			bufferType: sizeBufferType,
 		},
+		{
+			name:       "Flush disk buffer",
+			bufferType: diskBufferType,
+		},
 	}
 
 	for _, tc := range tests {
@@ -2870,6 +2885,293 @@ func TestPluginDrop(t *testing.T) {
 	}
 }
 
+func TestPluginSample(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		note      string
+		rawPolicy string
+		event     *EventV1
+		rate      float64
+		keep      bool
+		err       string
+	}{
+		{
+			note: "undefined",
+			rawPolicy: `package system.log
+			sample := 0 if input.path == "foo"`,
+			event: &EventV1{Path: "bar"},
+			rate:  1,
+			keep:  true,
+		},
+		{
+			note: "rate zero",
+			rawPolicy: `package system.log
+			sample := 0 if input.path == "foo"`,
+			event: &EventV1{Path: "foo"},
+			rate:  0,
+			keep:  false,
+		},
+		{
+			note: "rate one",
+			rawPolicy: `package system.log
+			sample := 1`,
+			event: &EventV1{Path: "foo"},
+			rate:  1,
+			keep:  true,
+		},
+		{
+			note: "keyed",
+			rawPolicy: `package system.log
+			sample := {"rate": 0.999999, "key": input.path}`,
+			event: &EventV1{Path: "foo"},
+			rate:  0.999999,
+			keep:  true,
+		},
+		{
+			note: "keyed miss",
+			rawPolicy: `package system.log
+			sample := {"rate": 0.000001, "key": input.path}`,
+			event: &EventV1{Path: "foo"},
+			rate:  0.000001,
+			keep:  false,
+		},
+		{
+			note: "rate out of range",
+			rawPolicy: `package system.log
+			sample := 2`,
+			event: &EventV1{Path: "foo"},
+			rate:  1,
+			keep:  true,
+			err:   "sample decision rate must be between 0 and 1, got 2",
+		},
+		{
+			note: "not a number",
+			rawPolicy: `package system.log
+			sample := {"key": input.path}`,
+			event: &EventV1{Path: "foo"},
+			rate:  1,
+			keep:  true,
+			err:   "sample decision must be a rate or an object with a rate and a key",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			ctx := context.Background()
+			store := inmem.New()
+
+			err := storage.Txn(ctx, store, storage.WriteParams, func(txn storage.Transaction) error {
+				return store.UpsertPolicy(ctx, txn, "test.rego", []byte(tc.rawPolicy))
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			manager, err := plugins.New(nil, "test", store)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := manager.Start(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg := &Config{Service: "svc"}
+			trigger := plugins.DefaultTriggerMode
+			if err := cfg.validateAndInjectDefaults([]string{"svc"}, nil, &trigger, nil); err != nil {
+				t.Fatal(err)
+			}
+
+			plugin := New(cfg, manager)
+			if err := plugin.Start(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			input, err := tc.event.AST()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Keyed decisions must be stable across evaluations.
+			for range 3 {
+				rate, keep, err := plugin.sampleEvent(ctx, nil, input)
+				if tc.err != "" {
+					if err == nil || !strings.Contains(err.Error(), tc.err) {
+						t.Fatalf("Expected error %q but got %v", tc.err, err)
+					}
+				} else if err != nil {
+					t.Fatal(err)
+				}
+				if rate != tc.rate || keep != tc.keep {
+					t.Fatalf("Expected rate %v and keep %v but got %v and %v", tc.rate, tc.keep, rate, keep)
+				}
+			}
+		})
+	}
+}
+
+func TestPluginSampleLog(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := inmem.New()
+
+	policy := `package system.log
+
+sample := 1 if input.result == false
+
+sample := {"rate": 0.5, "key": input.input.user} if input.result == true
+`
+	err := storage.Txn(ctx, store, storage.WriteParams, func(txn storage.Transaction) error {
+		return store.UpsertPolicy(ctx, txn, "test.rego", []byte(policy))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manager, err := plugins.New(nil, "test", store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &Config{Service: "svc"}
+	trigger := plugins.DefaultTriggerMode
+	if err := cfg.validateAndInjectDefaults([]string{"svc"}, nil, &trigger, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	plugin := New(cfg, manager).WithMetrics(metrics.New())
+
+	const n = 200
+	for i := range n {
+		var input any = map[string]any{"user": fmt.Sprintf("user%d", i)}
+		for _, result := range []any{false, true} {
+			if err := plugin.Log(ctx, &server.Info{
+				DecisionID: fmt.Sprint(i),
+				Path:       "test/allow",
+				Input:      &input,
+				Results:    &result,
+				Timestamp:  time.Now(),
+			}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	dropped := plugin.metrics.Counter(logSampledDropCounterName).Value().(uint64)
+	if dropped < n/4 || dropped > 3*n/4 {
+		t.Fatalf("Expected about half of the allowed decisions to be dropped but got %d", dropped)
+	}
+
+	var denied, allowed int
+	for _, e := range plugin.b.Flush() {
+		switch *e.Result {
+		case false:
+			denied++
+			if e.SampleRate != 0 {
+				t.Fatalf("Expected no sample rate on denied decision but got %v", e.SampleRate)
+			}
+		case true:
+			allowed++
+			if e.SampleRate != 0.5 {
+				t.Fatalf("Expected sample rate 0.5 on allowed decision but got %v", e.SampleRate)
+			}
+		}
+	}
+	if denied != n || allowed+int(dropped) != n {
+		t.Fatalf("Expected %d denied and %d allowed decisions but got %d and %d", n, n-int(dropped), denied, allowed)
+	}
+}
+
+func TestPluginMaskingAnnotationsWithNDBCache(t *testing.T) {
+	ctx := context.Background()
+	store := inmem.New()
+
+	policy := `package system.log
+
+	mask contains "/annotations/secret"`
+
+	err := storage.Txn(ctx, store, storage.WriteParams, func(txn storage.Transaction) error {
+		return store.UpsertPolicy(ctx, txn, "test.rego", []byte(policy))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manager, err := plugins.New(nil, "test", store)
+	if err != nil {
+		t.Fatal(err)
+	} else if err := manager.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &Config{Service: "svc"}
+	trigger := plugins.DefaultTriggerMode
+	if err := cfg.validateAndInjectDefaults([]string{"svc"}, nil, &trigger, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	plugin := New(cfg, manager)
+	if err := plugin.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// Evaluate a policy annotating its decision, with the NDBCache enabled.
+	annotations := topdown.NewDecisionLogAnnotations()
+	ndbc := builtins.NDBCache{}
+	_, err = rego.New(
+		rego.Query("data.test.allow"),
+		rego.Module("test.rego", `package test
+
+		allow if {
+			opa.decision_log.annotate("secret", "s3cr3t")
+			opa.decision_log.annotate("role", "admin")
+			rand.intn("x", 1) == 0
+		}`),
+		rego.NDBuiltinCache(ndbc),
+	).Eval(topdown.WithDecisionLogAnnotations(ctx, annotations))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values, err := annotations.Values()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ann any = values
+	var cache any = ndbc
+	event := &EventV1{Annotations: &ann, NDBuiltinCache: &cache}
+	input, err := event.AST()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := plugin.maskEvent(ctx, nil, input, event); err != nil {
+		t.Fatal(err)
+	}
+
+	if exp := map[string]any{"role": "admin"}; !reflect.DeepEqual(*event.Annotations, exp) {
+		t.Fatalf("expected annotations %v, got %v", exp, *event.Annotations)
+	}
+
+	if _, ok := ndbc[ast.OPADecisionLogAnnotate.Name]; ok {
+		t.Fatalf("expected no %v entry in the NDBCache, got %v", ast.OPADecisionLogAnnotate.Name, ndbc)
+	} else if _, ok := ndbc[ast.RandIntn.Name]; !ok {
+		t.Fatalf("expected %v entry in the NDBCache, got %v", ast.RandIntn.Name, ndbc)
+	}
+
+	bs, err := json.Marshal(event)
+	if err != nil {
+		t.Fatal(err)
+	} else if bytes.Contains(bs, []byte("s3cr3t")) {
+		t.Fatalf("expected masked annotation to be absent from the event, got %s", bs)
+	}
+}
+
 func TestPluginMaskErrorHandling(t *testing.T) {
 	t.Parallel()
 
@@ -3155,6 +3457,13 @@ func newTestFixture(t *testing.T, opts ...testFixtureOptions) testFixture {
 		config.Reporting.BufferType = options.ReportingBufferType
 	}
 
+	if config.Reporting.BufferType == diskBufferType {
+		segmentSize := defaultBufferSegmentSizeBytes
+		config.Reporting.BufferDirectory = t.TempDir()
+		config.Reporting.BufferSegmentSize = &segmentSize
+		config.Reporting.BufferFsync = diskBufferFsyncAlways
+	}
+
 	if options.TriggerMode != "" {
 		config.Reporting.Trigger = &options.TriggerMode
 	}
@@ -3320,6 +3629,8 @@ func TestEventV1ToAST(t *testing.T) {
 		}),
 	}.AsValue())
 
+	var annotations any = map[string]any{"role": "admin", "risk": json.Number("2")}
+
 	cases := []struct {
 		note  string
 		event EventV1
@@ -3434,6 +3745,20 @@ func TestEventV1ToAST(t *testing.T) {
 				inputAST:    astInput,
 			},
 		},
+		{
+			note: "event with sample rate",
+			event: EventV1{
+				Labels:      map[string]string{"foo": "1", "bar": "2"},
+				DecisionID:  "1234567890",
+				Input:       &goInput,
+				Path:        "/http/authz/allow",
+				RequestedBy: "[::1]:59943",
+				Result:      &result,
+				SampleRate:  0.25,
+				Timestamp:   time.Now(),
+				inputAST:    astInput,
+			},
+		},
 		{
 			note:  "big event",
 			event: bigEvent,
@@ -3516,6 +3841,19 @@ func TestEventV1ToAST(t *testing.T) {
 				inputAST:            astInput,
 			},
 		},
+		{
+			note: "event with annotations",
+			event: EventV1{
+				Labels:      map[string]string{"foo": "1", "bar": "2"},
+				DecisionID:  "1234567890",
+				Input:       &goInput,
+				Path:        "/http/authz/allow",
+				Result:      &result,
+				Annotations: &annotations,
+				Timestamp:   time.Now(),
+				inputAST:    astInput,
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -0,0 +1,222 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package logs
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/open-policy-agent/opa/v1/plugins/logs/v1pb"
+	"github.com/open-policy-agent/opa/v1/util"
+)
+
+// EventToProto converts a decision log event to its protobuf wire-form,
+// defined in v1/plugins/logs/event.proto. Free-form values are carried as
+// JSON encoded bytes.
+func EventToProto(e *EventV1) (*pb.Event, error) {
+	if e == nil {
+		return nil, nil
+	}
+	out := &pb.Event{
+		Labels:          e.Labels,
+		DecisionId:      new(e.DecisionID),
+		BatchDecisionId: nonZero(e.BatchDecisionID),
+		TraceId:         nonZero(e.TraceID),
+		SpanId:          nonZero(e.SpanID),
+		Revision:        nonZero(e.Revision),
+		Path:            nonZero(e.Path),
+		Query:           nonZero(e.Query),
+		Erased:          e.Erased,
+		Masked:          e.Masked,
+		Encrypted:       e.Encrypted,
+		RequestedBy:     nonZero(e.RequestedBy),
+		ReqId:           nonZero(e.RequestID),
+		SampleRate:      nonZero(e.SampleRate),
+	}
+
+	if len(e.Bundles) > 0 {
+		out.Bundles = make(map[string]*pb.BundleInfo, len(e.Bundles))
+		for name, b := range e.Bundles {
+			out.Bundles[name] = &pb.BundleInfo{Revision: nonZero(b.Revision)}
+		}
+	}
+
+	if !e.Timestamp.IsZero() {
+		out.Timestamp = timestamppb.New(e.Timestamp)
+	}
+
+	var err error
+	for _, f := range []struct {
+		name  string
+		dst   *[]byte
+		value any
+		unset bool
+	}{
+		{"input", &out.Input, e.Input, e.Input == nil},
+		{"result", &out.Result, e.Result, e.Result == nil},
+		{"intermediate_results", &out.IntermediateResults, e.IntermediateResults, e.IntermediateResults == nil},
+		{"mapped_result", &out.MappedResult, e.MappedResult, e.MappedResult == nil},
+		{"nd_builtin_cache", &out.NdBuiltinCache, e.NDBuiltinCache, e.NDBuiltinCache == nil},
+		{"annotations", &out.Annotations, e.Annotations, e.Annotations == nil},
+		{"error", &out.Error, e.Error, e.Error == nil},
+		{"metrics", &out.Metrics, e.Metrics, e.Metrics == nil},
+		{"rule_labels", &out.RuleLabels, e.RuleLabels, e.RuleLabels == nil},
+		{"custom", &out.Custom, e.Custom, e.Custom == nil},
+	} {
+		if f.unset {
+			continue
+		}
+		if *f.dst, err = json.Marshal(f.value); err != nil {
+			return nil, fmt.Errorf("event %v: %w", f.name, err)
+		}
+	}
+
+	if rc := e.RequestContext; rc != nil {
+		out.RequestContext = &pb.RequestContext{}
+		if rc.HTTPRequest != nil {
+			out.RequestContext.Http = &pb.HTTPRequestContext{}
+			if len(rc.HTTPRequest.Headers) > 0 {
+				out.RequestContext.Http.Headers = make(map[string]*pb.HeaderValues, len(rc.HTTPRequest.Headers))
+				for k, vs := range rc.HTTPRequest.Headers {
+					out.RequestContext.Http.Headers[k] = &pb.HeaderValues{Values: vs}
+				}
+			}
+		}
+	}
+
+	if a := e.Aggregate; a != nil {
+		out.Aggregate = &pb.Aggregate{
+			Start:  timestamppb.New(a.Start),
+			End:    timestamppb.New(a.End),
+			Count:  new(a.Count),
+			Errors: nonZero(a.Errors),
+			Latency: &pb.LatencyHistogram{
+				BoundsMs: a.Latency.BoundsMs,
+				Counts:   a.Latency.Counts,
+				SumMs:    new(a.Latency.SumMs),
+				MinMs:    new(a.Latency.MinMs),
+				MaxMs:    new(a.Latency.MaxMs),
+			},
+		}
+		if a.Input != nil {
+			if out.Aggregate.Input, err = json.Marshal(a.Input); err != nil {
+				return nil, fmt.Errorf("event aggregate input: %w", err)
+			}
+		}
+	}
+
+	return out, nil
+}
+
+// EventFromProto converts the protobuf wire-form of a decision log event back
+// to an EventV1. Errors are restored as their JSON encoding, which is
+// preserved when the event is encoded again.
+func EventFromProto(p *pb.Event) (*EventV1, error) {
+	if p == nil {
+		return nil, nil
+	}
+	out := &EventV1{
+		Labels:          p.GetLabels(),
+		DecisionID:      p.GetDecisionId(),
+		BatchDecisionID: p.GetBatchDecisionId(),
+		TraceID:         p.GetTraceId(),
+		SpanID:          p.GetSpanId(),
+		Revision:        p.GetRevision(),
+		Path:            p.GetPath(),
+		Query:           p.GetQuery(),
+		Erased:          p.GetErased(),
+		Masked:          p.GetMasked(),
+		Encrypted:       p.GetEncrypted(),
+		RequestedBy:     p.GetRequestedBy(),
+		RequestID:       p.GetReqId(),
+		SampleRate:      p.GetSampleRate(),
+	}
+
+	if len(p.GetBundles()) > 0 {
+		out.Bundles = make(map[string]BundleInfoV1, len(p.GetBundles()))
+		for name, b := range p.GetBundles() {
+			out.Bundles[name] = BundleInfoV1{Revision: b.GetRevision()}
+		}
+	}
+
+	if p.Timestamp != nil {
+		out.Timestamp = p.GetTimestamp().AsTime()
+	}
+
+	for _, f := range []struct {
+		name string
+		src  []byte
+		dst  any
+	}{
+		{"input", p.GetInput(), &out.Input},
+		{"result", p.GetResult(), &out.Result},
+		{"intermediate_results", p.GetIntermediateResults(), &out.IntermediateResults},
+		{"mapped_result", p.GetMappedResult(), &out.MappedResult},
+		{"nd_builtin_cache", p.GetNdBuiltinCache(), &out.NDBuiltinCache},
+		{"annotations", p.GetAnnotations(), &out.Annotations},
+		{"metrics", p.GetMetrics(), &out.Metrics},
+		{"rule_labels", p.GetRuleLabels(), &out.RuleLabels},
+		{"custom", p.GetCustom(), &out.Custom},
+	} {
+		if f.src == nil {
+			continue
+		}
+		if err := util.UnmarshalJSON(f.src, f.dst); err != nil {
+			return nil, fmt.Errorf("event %v: %w", f.name, err)
+		}
+	}
+
+	if p.Error != nil {
+		out.Error = rawEventError(p.GetError())
+	}
+
+	if rc := p.GetRequestContext(); rc != nil {
+		out.RequestContext = &RequestContext{}
+		if h := rc.GetHttp(); h != nil {
+			out.RequestContext.HTTPRequest = &HTTPRequestContext{}
+			if len(h.GetHeaders()) > 0 {
+				out.RequestContext.HTTPRequest.Headers = make(map[string][]string, len(h.GetHeaders()))
+				for k, vs := range h.GetHeaders() {
+					out.RequestContext.HTTPRequest.Headers[k] = vs.GetValues()
+				}
+			}
+		}
+	}
+
+	if a := p.GetAggregate(); a != nil {
+		out.Aggregate = &AggregateV1{
+			Start:  a.GetStart().AsTime(),
+			End:    a.GetEnd().AsTime(),
+			Count:  a.GetCount(),
+			Errors: a.GetErrors(),
+			Latency: LatencyHistogramV1{
+				BoundsMs: a.GetLatency().GetBoundsMs(),
+				Counts:   a.GetLatency().GetCounts(),
+				SumMs:    a.GetLatency().GetSumMs(),
+				MinMs:    a.GetLatency().GetMinMs(),
+				MaxMs:    a.GetLatency().GetMaxMs(),
+			},
+		}
+		if a.Input != nil {
+			if err := util.UnmarshalJSON(a.GetInput(), &out.Aggregate.Input); err != nil {
+				return nil, fmt.Errorf("event aggregate input: %w", err)
+			}
+		}
+	}
+
+	return out, nil
+}
+
+// nonZero returns a pointer to v, or nil if v is the zero value, mirroring
+// the omitempty JSON encoding of the event.
+func nonZero[T comparable](v T) *T {
+	var zero T
+	if v == zero {
+		return nil
+	}
+	return &v
+}
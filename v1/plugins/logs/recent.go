@@ -0,0 +1,160 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package logs
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/open-policy-agent/opa/v1/server"
+)
+
+// RecentDecisionsConfig represents the configuration of the in-memory ring of
+// recently logged decisions, which can be queried through the Decisions API.
+type RecentDecisionsConfig struct {
+	Size int `json:"size"`
+}
+
+func (c *RecentDecisionsConfig) validateAndInjectDefaults() error {
+	if c.Size <= 0 {
+		return errors.New("invalid decision_log config, 'recent_decisions.size' must be higher than 0")
+	}
+	return nil
+}
+
+// errRecentDecisionsDisabled is returned when recent decisions are looked up,
+// but not kept.
+var errRecentDecisionsDisabled = fmt.Errorf("%w, set 'decision_logs.recent_decisions.size'", server.ErrRecentDecisionsNotEnabled)
+
+// recentDecisions is a ring of the most recently logged events.
+type recentDecisions struct {
+	mtx    sync.RWMutex
+	events []EventV1
+	next   int // index of the next event, and of the oldest one if the ring is full
+	full   bool
+}
+
+func newRecentDecisions(size int) *recentDecisions {
+	return &recentDecisions{events: make([]EventV1, size)}
+}
+
+// Add adds the event to the ring, replacing the oldest event if it is full.
+func (r *recentDecisions) Add(event EventV1) {
+	event.inputAST = nil
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	r.events[r.next] = event
+	r.next++
+	if r.next == len(r.events) {
+		r.next = 0
+		r.full = true
+	}
+}
+
+// Query returns the events selected by the query, most recent first.
+func (r *recentDecisions) Query(query server.DecisionsQuery) []EventV1 {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+
+	var result []EventV1
+	r.each(func(event EventV1) bool {
+		if query.Limit > 0 && len(result) == query.Limit {
+			return false
+		}
+		if !query.Since.IsZero() && event.Timestamp.Before(query.Since) {
+			return true
+		}
+		if query.DecisionID != "" && event.DecisionID != query.DecisionID {
+			return true
+		}
+		if query.Path != "" {
+			path := strings.Trim(event.Path, "/")
+			if path != query.Path && !strings.HasPrefix(path, query.Path+"/") {
+				return true
+			}
+		}
+		result = append(result, event)
+		return true
+	})
+	return result
+}
+
+// Resize returns a ring of the given size holding the most recent events of r.
+func (r *recentDecisions) Resize(size int) *recentDecisions {
+	if size == len(r.events) {
+		return r
+	}
+
+	r.mtx.RLock()
+	var events []EventV1
+	r.each(func(event EventV1) bool {
+		events = append(events, event)
+		return len(events) < size
+	})
+	r.mtx.RUnlock()
+
+	resized := newRecentDecisions(size)
+	for i := len(events) - 1; i >= 0; i-- {
+		resized.Add(events[i])
+	}
+	return resized
+}
+
+// each calls f for each event, most recent first, until f returns false. The
+// caller must hold the lock.
+func (r *recentDecisions) each(f func(EventV1) bool) {
+	n := r.next
+	if r.full {
+		n = len(r.events)
+	}
+	for i := range n {
+		j := r.next - 1 - i
+		if j < 0 {
+			j += len(r.events)
+		}
+		if !f(r.events[j]) {
+			return
+		}
+	}
+}
+
+// RecentDecisions returns the recently logged decisions selected by the query,
+// most recent first. The decisions are recorded after the mask, drop and sample
+// decisions were applied, so that they expose no more than the logged events.
+func (p *Plugin) RecentDecisions(query server.DecisionsQuery) ([]EventV1, error) {
+	p.reconfigMtx.RLock()
+	defer p.reconfigMtx.RUnlock()
+
+	if p.recent == nil {
+		return nil, errRecentDecisionsDisabled
+	}
+	return p.recent.Query(query), nil
+}
+
+func (p *Plugin) recordRecent(event EventV1) {
+	p.reconfigMtx.RLock()
+	defer p.reconfigMtx.RUnlock()
+
+	if p.recent != nil {
+		p.recent.Add(event)
+	}
+}
+
+// reconfigureRecent resizes the ring of recent decisions, keeping the most
+// recent ones. The caller must hold the reconfigMtx write lock.
+func (p *Plugin) reconfigureRecent() {
+	switch {
+	case p.config.RecentDecisions == nil:
+		p.recent = nil
+	case p.recent == nil:
+		p.recent = newRecentDecisions(p.config.RecentDecisions.Size)
+	default:
+		p.recent = p.recent.Resize(p.config.RecentDecisions.Size)
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package logs
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/open-policy-agent/opa/v1/plugins"
+	"github.com/open-policy-agent/opa/v1/server"
+	"github.com/open-policy-agent/opa/v1/storage"
+	"github.com/open-policy-agent/opa/v1/storage/inmem"
+)
+
+func recentDecisionIDs(events []EventV1) []string {
+	ids := make([]string, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.DecisionID)
+	}
+	return ids
+}
+
+func TestRecentDecisions(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	r := newRecentDecisions(4)
+	if ids := recentDecisionIDs(r.Query(server.DecisionsQuery{})); len(ids) != 0 {
+		t.Fatalf("expected no decisions, got %v", ids)
+	}
+
+	for i, path := range []string{"authz/allow", "authz/deny", "features/enabled", "authz/allow", "authz", "authzx/allow"} {
+		r.Add(EventV1{
+			DecisionID: fmt.Sprint(i),
+			Path:       path,
+			Timestamp:  start.Add(time.Duration(i) * time.Minute),
+		})
+	}
+
+	tests := []struct {
+		note  string
+		query server.DecisionsQuery
+		exp   []string
+	}{
+		{
+			note:  "all",
+			query: server.DecisionsQuery{},
+			exp:   []string{"5", "4", "3", "2"},
+		},
+		{
+			note:  "path",
+			query: server.DecisionsQuery{Path: "authz"},
+			exp:   []string{"4", "3"},
+		},
+		{
+			note:  "decision id",
+			query: server.DecisionsQuery{DecisionID: "2"},
+			exp:   []string{"2"},
+		},
+		{
+			note:  "evicted decision id",
+			query: server.DecisionsQuery{DecisionID: "1"},
+			exp:   []string{},
+		},
+		{
+			note:  "since",
+			query: server.DecisionsQuery{Since: start.Add(4 * time.Minute)},
+			exp:   []string{"5", "4"},
+		},
+		{
+			note:  "limit",
+			query: server.DecisionsQuery{Limit: 3},
+			exp:   []string{"5", "4", "3"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			if act := recentDecisionIDs(r.Query(tc.query)); !slices.Equal(act, tc.exp) {
+				t.Fatalf("expected %v, got %v", tc.exp, act)
+			}
+		})
+	}
+
+	smaller := r.Resize(2)
+	if act, exp := recentDecisionIDs(smaller.Query(server.DecisionsQuery{})), []string{"5", "4"}; !slices.Equal(act, exp) {
+		t.Fatalf("expected %v after shrinking, got %v", exp, act)
+	}
+
+	larger := r.Resize(8)
+	larger.Add(EventV1{DecisionID: "6"})
+	if act, exp := recentDecisionIDs(larger.Query(server.DecisionsQuery{})), []string{"6", "5", "4", "3", "2"}; !slices.Equal(act, exp) {
+		t.Fatalf("expected %v after growing, got %v", exp, act)
+	}
+}
+
+func TestParseConfigRecentDecisions(t *testing.T) {
+	c, err := ParseConfig([]byte(`{"recent_decisions": {"size": 10}}`), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c == nil || c.RecentDecisions.Size != 10 {
+		t.Fatalf("expected recent decisions to be kept, got %+v", c)
+	}
+
+	_, err = ParseConfig([]byte(`{"recent_decisions": {"size": 0}}`), nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "'recent_decisions.size' must be higher than 0") {
+		t.Fatalf("expected size error, got %v", err)
+	}
+}
+
+func TestPluginRecentDecisions(t *testing.T) {
+	ctx := t.Context()
+
+	store := inmem.New()
+	policy := `package system.log
+
+mask contains "/input/password"
+
+drop if input.path == "health"
+`
+	err := storage.Txn(ctx, store, storage.WriteParams, func(txn storage.Transaction) error {
+		return store.UpsertPolicy(ctx, txn, "test.rego", []byte(policy))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manager, err := plugins.New([]byte(`{}`), "test", store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := ParseConfig([]byte(`{"recent_decisions": {"size": 10}}`), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := New(config, manager)
+	if err := p.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer p.Stop(ctx)
+
+	for i, path := range []string{"authz/allow", "health"} {
+		var input any = map[string]any{"user": "alice", "password": "secret"}
+		if err := p.Log(ctx, &server.Info{DecisionID: fmt.Sprint(i), Path: path, Input: &input}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	events, err := p.RecentDecisions(server.DecisionsQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids := recentDecisionIDs(events); !slices.Equal(ids, []string{"0"}) {
+		t.Fatalf("expected only decision 0 to be kept, got %v", ids)
+	}
+	if exp := map[string]any{"user": "alice"}; !reflect.DeepEqual(*events[0].Input, exp) {
+		t.Fatalf("expected masked input %v, got %v", exp, *events[0].Input)
+	}
+
+	disabled := *config
+	disabled.RecentDecisions = nil
+	p.Reconfigure(ctx, &disabled)
+
+	if _, err := p.RecentDecisions(server.DecisionsQuery{}); err != errRecentDecisionsDisabled {
+		t.Fatalf("expected recent decisions to be disabled, got %v", err)
+	}
+}
@@ -0,0 +1,389 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package logs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/open-policy-agent/opa/internal/ref"
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/logging"
+	"github.com/open-policy-agent/opa/v1/plugins"
+	lstat "github.com/open-policy-agent/opa/v1/plugins/logs/status"
+	"github.com/open-policy-agent/opa/v1/rego"
+	"github.com/open-policy-agent/opa/v1/server"
+	"github.com/open-policy-agent/opa/v1/storage"
+)
+
+// SinkConfig represents the configuration of a named decision log sink. A sink
+// has a single destination, its own buffer, its own mask, drop and sample
+// decisions and its own aggregation. It receives the decisions whose path
+// matches one of its paths, if any, and for which its route decision, if any,
+// is true.
+type SinkConfig struct {
+	Service          string             `json:"service,omitempty"`
+	Plugin           *string            `json:"plugin,omitempty"`
+	ConsoleLogs      bool               `json:"console,omitempty"`
+	File             string             `json:"file,omitempty"`
+	Resource         *string            `json:"resource,omitempty"`
+	Reporting        ReportingConfig    `json:"reporting"`
+	MaskDecision     *string            `json:"mask_decision,omitempty"`
+	DropDecision     *string            `json:"drop_decision,omitempty"`
+	SampleDecision   *string            `json:"sample_decision,omitempty"`
+	Aggregation      *AggregationConfig `json:"aggregation,omitempty"`
+	Paths            []string           `json:"paths,omitempty"`
+	RouteDecision    *string            `json:"route_decision,omitempty"`
+	config           Config
+	paths            []string
+	routeDecisionRef ast.Ref
+}
+
+func (c *SinkConfig) validateAndInjectDefaults(name string, parent *Config, services []string, pluginsList []string, trigger *plugins.TriggerMode, l logging.Logger) error {
+	var destinations int
+	for _, ok := range []bool{c.Service != "", c.Plugin != nil, c.ConsoleLogs, c.File != ""} {
+		if ok {
+			destinations++
+		}
+	}
+	if destinations != 1 {
+		return fmt.Errorf("invalid sink %q in decision_logs, exactly one of 'service', 'plugin', 'console' or 'file' must be set", name)
+	}
+
+	c.config = Config{
+		Plugin:         c.Plugin,
+		Service:        c.Service,
+		Reporting:      c.Reporting,
+		RequestContext: parent.RequestContext,
+		MaskDecision:   c.MaskDecision,
+		DropDecision:   c.DropDecision,
+		SampleDecision: c.SampleDecision,
+		Aggregation:    c.Aggregation,
+		ConsoleLogs:    c.ConsoleLogs,
+		Resource:       c.Resource,
+	}
+
+	// Only pass the services when one is set, so that a sink without a service
+	// is not defaulted to the first one.
+	var sinkServices []string
+	if c.Service != "" {
+		sinkServices = services
+	}
+	if err := c.config.validateAndInjectDefaults(sinkServices, pluginsList, trigger, l); err != nil {
+		return fmt.Errorf("invalid sink %q in decision_logs: %w", name, err)
+	}
+
+	c.paths = make([]string, 0, len(c.Paths))
+	for _, path := range c.Paths {
+		c.paths = append(c.paths, strings.Trim(path, "/"))
+	}
+
+	if c.RouteDecision != nil {
+		var err error
+		c.routeDecisionRef, err = ref.ParseDataPath(*c.RouteDecision)
+		if err != nil {
+			return fmt.Errorf("invalid route_decision of sink %q in decision_logs: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// sink holds the state of a plugin that logs the decisions routed to it by the
+// sink of its parent plugin with the same name. It is guarded by the
+// reconfigMtx of the parent.
+type sink struct {
+	name          string
+	parent        *Plugin
+	config        *SinkConfig
+	preparedRoute prepareOnce
+	file          *fileLogger
+}
+
+// newSink returns a plugin logging to the named sink. Unlike New, it neither
+// registers a compiler trigger nor reports its own plugin status, which are
+// both handled by the parent.
+func (p *Plugin) newSink(name string, config *SinkConfig) *Plugin {
+	s := &Plugin{
+		manager:        p.manager,
+		config:         config.config,
+		stop:           make(chan chan struct{}),
+		reconfig:       make(chan reconfigure),
+		logger:         p.logger.WithFields(map[string]any{"sink": name}),
+		status:         &lstat.Status{},
+		preparedDrop:   *newPrepareOnce(),
+		preparedSample: *newPrepareOnce(),
+		preparedMask:   *newPrepareOnce(),
+		sink: &sink{
+			name:          name,
+			parent:        p,
+			config:        config,
+			preparedRoute: *newPrepareOnce(),
+		},
+	}
+
+	s.b = s.newBuffer()
+	if p.metrics != nil {
+		s.WithMetrics(p.metrics)
+	}
+
+	return s
+}
+
+// startSinks starts the plugins of all sinks.
+func (p *Plugin) startSinks(ctx context.Context) error {
+	p.reconfigMtx.RLock()
+	defer p.reconfigMtx.RUnlock()
+
+	for _, s := range p.sinks {
+		if err := s.Start(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stopSinks stops the plugins of all sinks.
+func (p *Plugin) stopSinks(ctx context.Context) {
+	p.reconfigMtx.RLock()
+	defer p.reconfigMtx.RUnlock()
+
+	for _, s := range p.sinks {
+		s.Stop(ctx)
+	}
+}
+
+// logSinks logs the decision to all sinks, each of which decides whether the
+// decision is routed to it.
+func (p *Plugin) logSinks(ctx context.Context, decision *server.Info) {
+	p.reconfigMtx.RLock()
+	defer p.reconfigMtx.RUnlock()
+
+	for name, s := range p.sinks {
+		if err := s.Log(ctx, decision); err != nil {
+			p.logger.Error("Failed to log to sink %q: %v.", name, err)
+		}
+	}
+}
+
+// triggerSinks triggers an upload of the sinks with a service.
+func (p *Plugin) triggerSinks(ctx context.Context) error {
+	p.reconfigMtx.RLock()
+	defer p.reconfigMtx.RUnlock()
+
+	var errs []error
+	for name, s := range p.sinks {
+		if err := s.Trigger(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("sink %q: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// compilerUpdatedSinks drops the prepared queries of all sinks.
+func (p *Plugin) compilerUpdatedSinks(txn storage.Transaction) {
+	p.reconfigMtx.RLock()
+	defer p.reconfigMtx.RUnlock()
+
+	for _, s := range p.sinks {
+		s.sink.preparedRoute.drop()
+		s.compilerUpdated(txn)
+	}
+}
+
+// reconfigureSinks starts the plugins of new sinks, reconfigures those of
+// changed sinks and stops those of removed sinks. The caller must hold the
+// reconfigMtx write lock.
+func (p *Plugin) reconfigureSinks(ctx context.Context) {
+	sinks := make(map[string]*Plugin, len(p.config.Sinks))
+
+	for name, config := range p.config.Sinks {
+		s, ok := p.sinks[name]
+		if !ok {
+			s = p.newSink(name, config)
+			if err := s.Start(ctx); err != nil {
+				p.logger.Error("Failed to start sink %q: %v.", name, err)
+			}
+			sinks[name] = s
+			continue
+		}
+
+		sinks[name] = s
+		if reflect.DeepEqual(s.sink.config, config) {
+			continue
+		}
+
+		if s.sink.config.File != config.File {
+			// The aggregates of the sink are flushed to the current file.
+			s.stopAggregation()
+			s.closeFile()
+			if err := s.openFile(config.File); err != nil {
+				p.logger.Error("Failed to open file of sink %q: %v.", name, err)
+			}
+		}
+		s.sink.config = config
+		s.sink.preparedRoute.drop()
+		s.Reconfigure(ctx, &config.config)
+	}
+
+	for name, s := range p.sinks {
+		if _, ok := sinks[name]; !ok {
+			s.Stop(ctx)
+		}
+	}
+
+	p.sinks = sinks
+
+	p.statusMtx.Lock()
+	if p.status.Sinks != nil {
+		p.status.Sinks = maps.Clone(p.status.Sinks)
+		maps.DeleteFunc(p.status.Sinks, func(name string, _ *lstat.Status) bool {
+			_, ok := sinks[name]
+			return !ok
+		})
+	}
+	p.statusMtx.Unlock()
+}
+
+// setSinkStatus records the status of the named sink and reports the status
+// of the plugin, which includes the status of all sinks.
+func (p *Plugin) setSinkStatus(name string, s lstat.Status) {
+	p.statusMtx.Lock()
+	// The map is copied, as the status reported before may still be read.
+	sinks := maps.Clone(p.status.Sinks)
+	if sinks == nil {
+		sinks = map[string]*lstat.Status{}
+	}
+	sinks[name] = &s
+	p.status.Sinks = sinks
+	current := *p.status
+	p.statusMtx.Unlock()
+
+	p.reportStatus(current)
+}
+
+// routeEvent returns true if the event is routed to the sink, that is, if its
+// path matches one of the paths of the sink and its route decision is true.
+func (p *Plugin) routeEvent(ctx context.Context, txn storage.Transaction, path string, input ast.Value) (bool, error) {
+	config := p.sink.config
+
+	if len(config.paths) > 0 {
+		path = strings.Trim(path, "/")
+		var found bool
+		for _, prefix := range config.paths {
+			if prefix == "" || path == prefix || strings.HasPrefix(path, prefix+"/") {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false, nil
+		}
+	}
+
+	if config.routeDecisionRef == nil {
+		return true, nil
+	}
+
+	pq, err := p.sink.preparedRoute.prepareOnce(func() (*rego.PreparedEvalQuery, error) {
+		query := ast.NewBody(ast.NewExpr(ast.NewTerm(config.routeDecisionRef)))
+		r := rego.New(
+			rego.ParsedQuery(query),
+			rego.Compiler(p.manager.GetCompiler()),
+			rego.Store(p.manager.Store),
+			rego.Transaction(txn),
+			rego.Runtime(p.manager.Info),
+			rego.EnablePrintStatements(p.manager.EnablePrintStatements()),
+			rego.PrintHook(p.manager.PrintHook()),
+		)
+
+		pq, err := r.PrepareForEval(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		return &pq, nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	rs, err := pq.Eval(
+		ctx,
+		rego.EvalParsedInput(input),
+		rego.EvalTransaction(txn),
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return rs.Allowed(), nil
+}
+
+func (p *Plugin) openFile(path string) error {
+	if path == "" {
+		return nil
+	}
+	l, err := openFileLogger(path)
+	if err != nil {
+		return err
+	}
+	p.sink.file = l
+	return nil
+}
+
+func (p *Plugin) closeFile() {
+	if p.sink.file == nil {
+		return
+	}
+	if err := p.sink.file.Close(); err != nil {
+		p.logger.Error("Failed to close decision log file: %v.", err)
+	}
+	p.sink.file = nil
+}
+
+// fileLogger appends decision log events to a file, one JSON object per line.
+type fileLogger struct {
+	mtx sync.Mutex
+	f   *os.File
+}
+
+func openFileLogger(path string) (*fileLogger, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &fileLogger{f: f}, nil
+}
+
+func (l *fileLogger) Log(event EventV1) error {
+	bs, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	_, err = l.f.Write(append(bs, '\n'))
+	return err
+}
+
+func (l *fileLogger) Close() error {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	return l.f.Close()
+}
@@ -0,0 +1,407 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package logs
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/open-policy-agent/opa/v1/plugins"
+	"github.com/open-policy-agent/opa/v1/server"
+	"github.com/open-policy-agent/opa/v1/storage"
+	"github.com/open-policy-agent/opa/v1/storage/inmem"
+)
+
+func TestParseConfigSinks(t *testing.T) {
+	services := []string{"s0", "s1"}
+	pluginsList := []string{"p0"}
+
+	tests := []struct {
+		note  string
+		raw   string
+		check func(*testing.T, *Config)
+		err   string
+	}{
+		{
+			note: "sinks",
+			raw: `{"sinks": {
+				"siem": {"service": "s1", "paths": ["/authz/", "admin"], "mask_decision": "/system/log/siem/mask"},
+				"analytics": {"plugin": "p0", "route_decision": "system/log/analytics/route"},
+				"audit": {"file": "/var/log/opa/decisions.jsonl"}
+			}}`,
+			check: func(t *testing.T, c *Config) {
+				if c.Service != "" {
+					t.Fatalf("expected no default service, got %q", c.Service)
+				}
+
+				siem := c.Sinks["siem"]
+				if siem.config.Service != "s1" || *siem.config.Resource != defaultResourcePath || *siem.config.Reporting.Trigger != plugins.DefaultTriggerMode {
+					t.Fatalf("unexpected siem config: %+v", siem.config)
+				}
+				if exp := []string{"authz", "admin"}; !slices.Equal(siem.paths, exp) {
+					t.Fatalf("expected paths %v, got %v", exp, siem.paths)
+				}
+				if act := siem.config.maskDecisionRef.String(); act != "data.system.log.siem.mask" {
+					t.Fatalf("unexpected mask decision %v", act)
+				}
+				if act := siem.config.dropDecisionRef.String(); act != "data.system.log.drop" {
+					t.Fatalf("unexpected drop decision %v", act)
+				}
+
+				analytics := c.Sinks["analytics"]
+				if *analytics.config.Plugin != "p0" || analytics.config.Service != "" {
+					t.Fatalf("unexpected analytics config: %+v", analytics.config)
+				}
+				if act := analytics.routeDecisionRef.String(); act != "data.system.log.analytics.route" {
+					t.Fatalf("unexpected route decision %v", act)
+				}
+
+				audit := c.Sinks["audit"]
+				if audit.config.Service != "" || audit.routeDecisionRef != nil || len(audit.paths) != 0 {
+					t.Fatalf("unexpected audit config: %+v", audit)
+				}
+			},
+		},
+		{
+			note: "sinks with service",
+			raw:  `{"service": "s0", "sinks": {"audit": {"console": true}}}`,
+			check: func(t *testing.T, c *Config) {
+				if c.Service != "s0" {
+					t.Fatalf("expected service s0, got %q", c.Service)
+				}
+			},
+		},
+		{
+			note: "no destination",
+			raw:  `{"sinks": {"audit": {"paths": ["authz"]}}}`,
+			err:  `invalid sink "audit" in decision_logs, exactly one of 'service', 'plugin', 'console' or 'file' must be set`,
+		},
+		{
+			note: "multiple destinations",
+			raw:  `{"sinks": {"audit": {"console": true, "file": "decisions.jsonl"}}}`,
+			err:  `invalid sink "audit" in decision_logs, exactly one of 'service', 'plugin', 'console' or 'file' must be set`,
+		},
+		{
+			note: "invalid service",
+			raw:  `{"sinks": {"siem": {"service": "s2"}}}`,
+			err:  `invalid sink "siem" in decision_logs: invalid service name "s2" in decision_logs`,
+		},
+		{
+			note: "invalid plugin",
+			raw:  `{"sinks": {"analytics": {"plugin": "p1"}}}`,
+			err:  `invalid sink "analytics" in decision_logs: invalid plugin name "p1" in decision_logs`,
+		},
+		{
+			note: "invalid reporting",
+			raw:  `{"sinks": {"siem": {"service": "s0", "reporting": {"buffer_type": "foo"}}}}`,
+			err:  `invalid sink "siem" in decision_logs: invalid buffer type "foo"`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			c, err := ParseConfig([]byte(tc.raw), services, pluginsList)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("expected error %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			tc.check(t, c)
+		})
+	}
+}
+
+// testEventServer collects the uploaded events.
+type testEventServer struct {
+	mtx    sync.Mutex
+	events []map[string]any
+	fail   bool
+}
+
+func (s *testEventServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.fail {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	gr, err := gzip.NewReader(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	var events []map[string]any
+	if err := json.NewDecoder(gr).Decode(&events); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	s.events = append(s.events, events...)
+}
+
+func (s *testEventServer) uploaded() []map[string]any {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return slices.Clone(s.events)
+}
+
+func TestPluginSinks(t *testing.T) {
+	ctx := t.Context()
+	dir := t.TempDir()
+
+	siem := &testEventServer{}
+	siemServer := httptest.NewServer(siem)
+	defer siemServer.Close()
+
+	broken := &testEventServer{fail: true}
+	brokenServer := httptest.NewServer(broken)
+	defer brokenServer.Close()
+
+	store := inmem.New()
+	policy := `package system.log
+
+siem.mask contains "/input/password"
+
+analytics.route if startswith(input.path, "features/")
+`
+	err := storage.Txn(ctx, store, storage.WriteParams, func(txn storage.Transaction) error {
+		return store.UpsertPolicy(ctx, txn, "test.rego", []byte(policy))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manager, err := plugins.New([]byte(fmt.Sprintf(`{
+		"services": {
+			"siem": {"url": %q},
+			"broken": {"url": %q}
+		},
+		"persistence_directory": %q
+	}`, siemServer.URL, brokenServer.URL, filepath.ToSlash(dir))), "test", store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	file := filepath.Join(dir, "analytics", "decisions.jsonl")
+	raw := fmt.Sprintf(`{"sinks": {
+		"siem": {"service": "siem", "paths": ["authz"], "mask_decision": "/system/log/siem/mask", "reporting": {"buffer_type": "disk"}},
+		"analytics": {"file": %q, "route_decision": "/system/log/analytics/route"},
+		"broken": {"service": "broken"}
+	}}`, filepath.ToSlash(file))
+	config, err := parseManualConfig(raw, manager.Services())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := New(config, manager)
+	if err := p.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	b, ok := p.sinks["siem"].b.(*diskBuffer)
+	if !ok {
+		t.Fatalf("expected disk buffer, got %T", p.sinks["siem"].b)
+	}
+	if exp := filepath.Join(dir, defaultBufferDirectory, "siem"); b.dir != exp {
+		t.Fatalf("expected directory %v, got %v", exp, b.dir)
+	}
+
+	for i, path := range []string{"authz/allow", "features/enabled", "other"} {
+		var input any = map[string]any{"user": "alice", "password": "secret"}
+		if err := p.Log(ctx, &server.Info{
+			DecisionID: fmt.Sprint(i),
+			Path:       path,
+			Input:      &input,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = p.Trigger(ctx)
+	if err == nil || !strings.Contains(err.Error(), `sink "broken"`) {
+		t.Fatalf("expected error of broken sink, got %v", err)
+	}
+
+	events := siem.uploaded()
+	if len(events) != 1 || events[0]["decision_id"] != "0" {
+		t.Fatalf("expected decision 0 to be uploaded, got %v", events)
+	}
+	if exp := map[string]any{"user": "alice"}; !reflect.DeepEqual(events[0]["input"], exp) {
+		t.Fatalf("expected masked input %v, got %v", exp, events[0]["input"])
+	}
+
+	if ids := fileDecisionIDs(t, file); !slices.Equal(ids, []string{"1"}) {
+		t.Fatalf("expected decision 1 to be logged to file, got %v", ids)
+	}
+
+	p.statusMtx.Lock()
+	sinks := maps.Clone(p.status.Sinks)
+	p.statusMtx.Unlock()
+	if s := sinks["siem"]; s == nil || s.Code != "" {
+		t.Fatalf("expected siem status without error, got %+v", s)
+	}
+	if s := sinks["broken"]; s == nil || s.Code != "decision_log_error" || s.HTTPCode != "500" {
+		t.Fatalf("expected broken status with error, got %+v", s)
+	}
+
+	// Remove the broken sink and route all decisions to the analytics sink.
+	raw = fmt.Sprintf(`{"sinks": {
+		"siem": {"service": "siem", "paths": ["authz"], "mask_decision": "/system/log/siem/mask", "reporting": {"buffer_type": "disk"}},
+		"analytics": {"file": %q}
+	}}`, filepath.ToSlash(file))
+	config, err = parseManualConfig(raw, manager.Services())
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Reconfigure(ctx, config)
+
+	if act := slices.Sorted(maps.Keys(p.sinks)); !slices.Equal(act, []string{"analytics", "siem"}) {
+		t.Fatalf("expected sinks analytics and siem, got %v", act)
+	}
+	p.statusMtx.Lock()
+	_, ok = p.status.Sinks["broken"]
+	p.statusMtx.Unlock()
+	if ok {
+		t.Fatal("expected status of broken sink to be removed")
+	}
+
+	if err := p.Log(ctx, &server.Info{DecisionID: "3", Path: "other"}); err != nil {
+		t.Fatal(err)
+	}
+	if ids := fileDecisionIDs(t, file); !slices.Equal(ids, []string{"1", "3"}) {
+		t.Fatalf("expected decisions 1 and 3 to be logged to file, got %v", ids)
+	}
+
+	p.Stop(ctx)
+}
+
+// TestPluginSinksMaskIsolation checks that the mask rules of a sink don't
+// affect the input logged by other sinks, or the input of the decision.
+func TestPluginSinksMaskIsolation(t *testing.T) {
+	ctx := t.Context()
+	dir := t.TempDir()
+
+	store := inmem.New()
+	policy := `package system.log
+
+a.mask contains "/input/password"
+
+b.mask contains {"op": "upsert", "path": "/input/password", "value": "***"}
+
+b.mask contains "/input/user"
+`
+	err := storage.Txn(ctx, store, storage.WriteParams, func(txn storage.Transaction) error {
+		return store.UpsertPolicy(ctx, txn, "test.rego", []byte(policy))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manager, err := plugins.New([]byte(`{}`), "test", store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	fileA, fileB := filepath.Join(dir, "a.jsonl"), filepath.Join(dir, "b.jsonl")
+	raw := fmt.Sprintf(`{"sinks": {
+		"a": {"file": %q, "mask_decision": "/system/log/a/mask"},
+		"b": {"file": %q, "mask_decision": "/system/log/b/mask"}
+	}}`, filepath.ToSlash(fileA), filepath.ToSlash(fileB))
+	config, err := parseManualConfig(raw, manager.Services())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := New(config, manager)
+	if err := p.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer p.Stop(ctx)
+
+	var input any = map[string]any{"user": "alice", "password": "secret"}
+	if err := p.Log(ctx, &server.Info{DecisionID: "0", Path: "authz/allow", Input: &input}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		file string
+		exp  any
+	}{
+		{fileA, map[string]any{"user": "alice"}},
+		{fileB, map[string]any{"password": "***"}},
+	} {
+		events := fileEvents(t, tc.file)
+		if len(events) != 1 || events[0].Input == nil {
+			t.Fatalf("expected one event with input in %v, got %v", tc.file, events)
+		}
+		if !reflect.DeepEqual(*events[0].Input, tc.exp) {
+			t.Fatalf("expected input %v in %v, got %v", tc.exp, tc.file, *events[0].Input)
+		}
+	}
+
+	if exp := map[string]any{"user": "alice", "password": "secret"}; !reflect.DeepEqual(input, exp) {
+		t.Fatalf("expected decision input %v to be unchanged, got %v", exp, input)
+	}
+}
+
+func parseManualConfig(raw string, services []string) (*Config, error) {
+	trigger := plugins.TriggerManual
+	return NewConfigBuilder().WithBytes([]byte(raw)).WithServices(services).WithTriggerMode(&trigger).Parse()
+}
+
+func fileDecisionIDs(t *testing.T, path string) []string {
+	t.Helper()
+
+	var ids []string
+	for _, event := range fileEvents(t, path) {
+		ids = append(ids, event.DecisionID)
+	}
+	return ids
+}
+
+func fileEvents(t *testing.T, path string) []EventV1 {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var events []EventV1
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var event EventV1
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatal(err)
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return events
+}
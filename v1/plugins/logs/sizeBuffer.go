@@ -2,7 +2,6 @@ package logs
 
 import (
 	"context"
-	"encoding/json"
 	"math"
 	"sync"
 
@@ -18,6 +17,7 @@ type sizeBuffer struct {
 	uploadMtx  sync.Mutex // used only in immediate upload mode
 	buffer     *logBuffer
 	enc        *chunkEncoder // encoder appends events into the gzip compressed JSON array
+	format     *eventFormat
 	limiter    *rate.Limiter
 	metrics    metrics.Metrics
 	logger     logging.Logger
@@ -30,6 +30,7 @@ func newSizeBuffer(bufferSizeLimitBytes int64, uploadSizeLimitBytes int64, clien
 	return &sizeBuffer{
 		enc:        newChunkEncoder(uploadSizeLimitBytes),
 		buffer:     newLogBuffer(bufferSizeLimitBytes),
+		format:     jsonEventFormat,
 		client:     client,
 		uploadPath: uploadPath,
 		mode:       mode,
@@ -43,6 +44,13 @@ func (b *sizeBuffer) WithLimiter(maxDecisionsPerSecond *float64) *sizeBuffer {
 	return b
 }
 
+// WithFormat sets the encoding of the uploaded events.
+func (b *sizeBuffer) WithFormat(format *eventFormat) *sizeBuffer {
+	b.format = format
+	b.enc.WithFormat(format)
+	return b
+}
+
 func (b *sizeBuffer) WithMetrics(m metrics.Metrics) {
 	b.metrics = m
 	b.enc.metrics = m
@@ -80,7 +88,7 @@ func (b *sizeBuffer) Flush() []*EventV1 {
 	}
 
 	for bs := b.buffer.Pop(); bs != nil; bs = b.buffer.Pop() {
-		decodedEvents, err := newChunkDecoder(bs).decode()
+		decodedEvents, err := newChunkDecoder(bs).WithFormat(b.format).decode()
 		if err != nil {
 			b.incrMetric(logEncodingFailureCounterName)
 			if b.logger != nil {
@@ -119,10 +127,10 @@ func (b *sizeBuffer) Push(event *EventV1) {
 		return
 	}
 
-	eventBytes, err := json.Marshal(&event)
+	eventBytes, err := b.format.marshal(event)
 	if err != nil {
 		if b.logger != nil {
-			b.logger.Error("Decision log dropped due to error serializing event to JSON with decision ID %v", event.DecisionID)
+			b.logger.Error("Decision log dropped due to error serializing event with decision ID %v", event.DecisionID)
 		}
 
 		return
@@ -149,7 +157,7 @@ func (b *sizeBuffer) Push(event *EventV1) {
 
 			var uploadErr error
 			for _, chunk := range result {
-				uploadErr = uploadChunk(ctx, b.client, b.uploadPath, chunk)
+				uploadErr = uploadChunk(ctx, b.client, b.uploadPath, chunk, b.format)
 				if uploadErr != nil {
 					b.mtx.Lock()
 					b.bufferChunk(b.buffer, chunk)
@@ -174,7 +182,7 @@ func (b *sizeBuffer) Upload(ctx context.Context) error {
 	oldChunkEnc := b.enc
 	oldBuffer := b.buffer
 	b.buffer = newLogBuffer(b.buffer.limit)
-	b.enc = newChunkEncoder(b.enc.limit).WithMetrics(b.metrics).WithLogger(b.logger).
+	b.enc = newChunkEncoder(b.enc.limit).WithFormat(b.format).WithMetrics(b.metrics).WithLogger(b.logger).
 		WithUncompressedLimit(oldChunkEnc.uncompressedLimit, oldChunkEnc.uncompressedLimitScaleDownExponent, oldChunkEnc.uncompressedLimitScaleUpExponent)
 	b.mtx.Unlock()
 
@@ -199,11 +207,11 @@ func (b *sizeBuffer) Upload(ctx context.Context) error {
 
 	for bs := oldBuffer.Pop(); bs != nil; bs = oldBuffer.Pop() {
 		if err == nil {
-			err = uploadChunk(ctx, b.client, b.uploadPath, bs)
+			err = uploadChunk(ctx, b.client, b.uploadPath, bs, b.format)
 		}
 		if err != nil {
 			if b.limiter != nil {
-				events, decErr := newChunkDecoder(bs).decode()
+				events, decErr := newChunkDecoder(bs).WithFormat(b.format).decode()
 				if decErr != nil {
 					continue
 				}
@@ -21,10 +21,11 @@ const (
 
 // Status represents the status of processing a decision log.
 type Status struct {
-	Code     string          `json:"code,omitempty"`
-	Message  string          `json:"message,omitempty"`
-	HTTPCode json.Number     `json:"http_code,omitempty"`
-	Metrics  metrics.Metrics `json:"metrics,omitempty"`
+	Code     string             `json:"code,omitempty"`
+	Message  string             `json:"message,omitempty"`
+	HTTPCode json.Number        `json:"http_code,omitempty"`
+	Metrics  metrics.Metrics    `json:"metrics,omitempty"`
+	Sinks    map[string]*Status `json:"sinks,omitempty"`
 }
 
 // SetError updates the status object to reflect a failure to upload or
@@ -719,6 +719,7 @@ func (rt *Runtime) Serve(ctx context.Context) (err error) {
 		WithAuthorization(rt.Params.Authorization).
 		WithDecisionIDFactory(rt.decisionIDFactory).
 		WithDecisionLoggerWithErr(rt.decisionLogger).
+		WithDecisionLookup(rt.decisionLookup).
 		WithRuntime(rt.Manager.Info).
 		WithMetrics(rt.metrics).
 		WithMinTLSVersion(rt.Params.MinTLSVersion).
@@ -971,6 +972,24 @@ func (rt *Runtime) decisionLogger(ctx context.Context, event *server.Info) error
 	return plugin.Log(ctx, event)
 }
 
+func (rt *Runtime) decisionLookup(_ context.Context, query server.DecisionsQuery) ([]any, error) {
+	plugin := logs.Lookup(rt.Manager)
+	if plugin == nil {
+		return nil, fmt.Errorf("%w, decision logs plugin not enabled", server.ErrRecentDecisionsNotEnabled)
+	}
+
+	events, err := plugin.RecentDecisions(query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]any, 0, len(events))
+	for _, event := range events {
+		result = append(result, event)
+	}
+	return result, nil
+}
+
 func (rt *Runtime) startWatcher(ctx context.Context, paths []string, onReload func(time.Duration, error)) error {
 	watcher, err := rt.getWatcher(paths)
 	if err != nil {
@@ -319,11 +319,13 @@ func (opa *OPA) Decision(ctx context.Context, options DecisionOptions) (*Decisio
 
 	// TODO: make extractor configurable via SDK options
 	tracker := &topdown.EvaluatedRuleTracker{}
+	annotations := topdown.NewDecisionLogAnnotations()
 
 	result, err := opa.executeTransaction(
 		ctx,
 		&record,
 		func(s state, result *DecisionResult) {
+			ctx := topdown.WithDecisionLogAnnotations(ctx, annotations)
 			result.Result, result.Provenance, record.InputAST, record.Bundles, record.Error = evaluate(ctx, evalArgs{
 				runtime:                     s.manager.Info,
 				printHook:                   s.manager.PrintHook(),
@@ -349,6 +351,9 @@ func (opa *OPA) Decision(ctx context.Context, options DecisionOptions) (*Decisio
 				record.Results = &result.Result
 			}
 			record.EvaluatedRuleLabels = tracker.Labels
+			if values, err := annotations.Values(); err == nil {
+				record.Annotations = values
+			}
 		},
 	)
 	if err != nil {
@@ -5,6 +5,7 @@
 package server
 
 import (
+	"errors"
 	"time"
 
 	"github.com/open-policy-agent/opa/v1/ast"
@@ -40,9 +41,23 @@ type Info struct {
 	RequestID           uint64
 	EvaluatedRuleLabels []map[string]any
 	Custom              map[string]any
+	Annotations         map[string]any
 }
 
 // BundleInfo contains information describing a bundle.
 type BundleInfo struct {
 	Revision string
 }
+
+// DecisionsQuery selects recently logged decisions. Empty fields select all
+// decisions.
+type DecisionsQuery struct {
+	Path       string    // path of the decisions, or of a prefix of it
+	DecisionID string    // ID of the decision
+	Since      time.Time // time after which the decisions were made
+	Limit      int       // maximum number of decisions, or 0 for no limit
+}
+
+// ErrRecentDecisionsNotEnabled is returned by decision lookups when recent
+// decisions are not kept.
+var ErrRecentDecisionsNotEnabled = errors.New("recent decisions not enabled")
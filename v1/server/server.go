@@ -81,17 +81,18 @@ const (
 
 const (
 	// Set of handlers for use in the "handler" dimension of the duration metric.
-	PromHandlerV0Data     = "v0/data"
-	PromHandlerV1Data     = "v1/data"
-	PromHandlerV1Query    = "v1/query"
-	PromHandlerV1Policies = "v1/policies"
-	PromHandlerV1Compile  = "v1/compile"
-	PromHandlerV1Config   = "v1/config"
-	PromHandlerV1Status   = "v1/status"
-	PromHandlerIndex      = "index"
-	PromHandlerCatch      = "catchall"
-	PromHandlerHealth     = "health"
-	PromHandlerAPIAuthz   = "authz"
+	PromHandlerV0Data      = "v0/data"
+	PromHandlerV1Data      = "v1/data"
+	PromHandlerV1Query     = "v1/query"
+	PromHandlerV1Policies  = "v1/policies"
+	PromHandlerV1Compile   = "v1/compile"
+	PromHandlerV1Config    = "v1/config"
+	PromHandlerV1Status    = "v1/status"
+	PromHandlerV1Decisions = "v1/decisions"
+	PromHandlerIndex       = "index"
+	PromHandlerCatch       = "catchall"
+	PromHandlerHealth      = "health"
+	PromHandlerAPIAuthz    = "authz"
 
 	pqMaxCacheSize = 100
 
@@ -135,6 +136,7 @@ type Server struct {
 	manager                     *plugins.Manager
 	decisionIDFactory           func() string
 	logger                      func(context.Context, *Info) error
+	decisions                   func(context.Context, DecisionsQuery) ([]any, error)
 	errLimit                    int
 	pprofEnabled                bool
 	runtime                     *ast.Term
@@ -393,6 +395,13 @@ func (s *Server) WithDecisionLoggerWithErr(logger func(context.Context, *Info) e
 	return s
 }
 
+// WithDecisionLookup sets the function used by the server to look up recently
+// logged decisions.
+func (s *Server) WithDecisionLookup(lookup func(context.Context, DecisionsQuery) ([]any, error)) *Server {
+	s.decisions = lookup
+	return s
+}
+
 // WithDecisionIDFactory sets a function on the server to generate decision IDs.
 func (s *Server) WithDecisionIDFactory(f func() string) *Server {
 	s.decisionIDFactory = f
@@ -923,6 +932,7 @@ func (s *Server) initRouters(ctx context.Context) {
 	mainRouter.Handle("GET /v1/compile/{path...}", s.instrumentHandler(s.v1CompileFilters, PromHandlerV1Compile))
 	mainRouter.Handle("GET /v1/config", s.instrumentHandler(s.v1ConfigGet, PromHandlerV1Config))
 	mainRouter.Handle("GET /v1/status", s.instrumentHandler(s.v1StatusGet, PromHandlerV1Status))
+	mainRouter.Handle("GET /v1/decisions", s.instrumentHandler(s.v1DecisionsGet, PromHandlerV1Decisions))
 	mainRouter.Handle("POST /{$}", s.instrumentHandler(s.unversionedPost, PromHandlerIndex))
 	mainRouter.Handle("GET /{$}", s.instrumentHandler(s.indexGet, PromHandlerIndex))
 
@@ -2484,6 +2494,62 @@ func (s *Server) v1StatusGet(w http.ResponseWriter, r *http.Request) {
 	writer.JSONOK(w, types.StatusResponseV1{Result: &st}, pretty(r))
 }
 
+func (s *Server) v1DecisionsGet(w http.ResponseWriter, r *http.Request) {
+	if s.decisions == nil {
+		writer.ErrorString(w, http.StatusNotFound, types.CodeNotEnabled, ErrRecentDecisionsNotEnabled)
+		return
+	}
+
+	query, err := parseDecisionsQuery(r.URL.Query(), time.Now())
+	if err != nil {
+		writer.ErrorString(w, http.StatusBadRequest, types.CodeInvalidParameter, err)
+		return
+	}
+
+	result, err := s.decisions(r.Context(), query)
+	if errors.Is(err, ErrRecentDecisionsNotEnabled) {
+		writer.ErrorString(w, http.StatusNotFound, types.CodeNotEnabled, err)
+		return
+	} else if err != nil {
+		writer.ErrorAuto(w, err)
+		return
+	}
+	if result == nil {
+		result = []any{}
+	}
+
+	writer.JSONOK(w, types.DecisionsResponseV1{Result: result}, pretty(r))
+}
+
+// parseDecisionsQuery parses the parameters of a Decisions API request. The
+// since parameter is either a RFC3339 timestamp or a duration before now.
+func parseDecisionsQuery(values url.Values, now time.Time) (DecisionsQuery, error) {
+	query := DecisionsQuery{
+		Path:       strings.Trim(values.Get(types.ParamPathV1), "/"),
+		DecisionID: values.Get(types.ParamDecisionIDV1),
+	}
+
+	if since := values.Get(types.ParamSinceV1); since != "" {
+		if t, err := time.Parse(time.RFC3339Nano, since); err == nil {
+			query.Since = t
+		} else if d, err := time.ParseDuration(since); err == nil {
+			query.Since = now.Add(-d)
+		} else {
+			return query, fmt.Errorf("invalid %v parameter %q: expected RFC3339 timestamp or duration", types.ParamSinceV1, since)
+		}
+	}
+
+	if limit := values.Get(types.ParamLimitV1); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 0 {
+			return query, fmt.Errorf("invalid %v parameter %q: expected non-negative integer", types.ParamLimitV1, limit)
+		}
+		query.Limit = n
+	}
+
+	return query, nil
+}
+
 func (s *Server) checkPolicyIDScope(ctx context.Context, txn storage.Transaction, id string) error {
 	bs, err := s.store.GetPolicy(ctx, txn, id)
 	if err != nil {
@@ -2570,6 +2636,9 @@ func (s *Server) getDecisionLogger(ctx context.Context, br bundleRevisions) (con
 		logger.revisions = br.Revisions
 	}
 	logger.logger = s.logger
+	if s.logger != nil {
+		ctx = topdown.WithDecisionLogAnnotations(ctx, topdown.NewDecisionLogAnnotations())
+	}
 	return ctx, logger
 }
 
@@ -3181,6 +3250,14 @@ func (l decisionLogger) Log(
 		}
 	}
 
+	if annotations, ok := topdown.DecisionLogAnnotationsFromContext(ctx); ok {
+		values, err := annotations.Values()
+		if err != nil {
+			return err
+		}
+		info.Annotations = values
+	}
+
 	if l.logger != nil {
 		// Decouple from request cancellation/deadline so a client disconnect can't
 		// race a mask/drop policy eval in the logger and drop the decision event.
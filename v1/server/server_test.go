@@ -4113,6 +4113,77 @@ func TestStatusV1(t *testing.T) {
 	}
 }
 
+func TestDecisionsV1(t *testing.T) {
+	t.Parallel()
+
+	f := newFixture(t)
+
+	if err := f.v1(http.MethodGet, "/decisions", "", 404, `{
+		"code": "not_enabled",
+		"message": "recent decisions not enabled"
+	}`); err != nil {
+		t.Fatal(err)
+	}
+
+	var queries []DecisionsQuery
+	f = newFixture(t, func(s *Server) {
+		s.WithDecisionLookup(func(_ context.Context, query DecisionsQuery) ([]any, error) {
+			queries = append(queries, query)
+			switch query.DecisionID {
+			case "missing":
+				return nil, nil
+			case "disabled":
+				return nil, fmt.Errorf("%w, set 'decision_logs.recent_decisions.size'", ErrRecentDecisionsNotEnabled)
+			}
+			return []any{map[string]any{"decision_id": "1", "path": "authz/allow"}}, nil
+		})
+	})
+
+	if err := f.v1TestRequests([]tr{
+		{http.MethodGet, "/decisions?path=/authz/&decision_id=1&since=2026-01-01T00:00:00Z&limit=5", "", 200, `{
+			"result": [{"decision_id": "1", "path": "authz/allow"}]
+		}`},
+		{http.MethodGet, "/decisions?decision_id=missing", "", 200, `{"result": []}`},
+		{http.MethodGet, "/decisions?decision_id=disabled", "", 404, `{
+			"code": "not_enabled",
+			"message": "recent decisions not enabled, set 'decision_logs.recent_decisions.size'"
+		}`},
+		{http.MethodGet, "/decisions?since=yesterday", "", 400, `{
+			"code": "invalid_parameter",
+			"message": "invalid since parameter \"yesterday\": expected RFC3339 timestamp or duration"
+		}`},
+		{http.MethodGet, "/decisions?limit=-1", "", 400, `{
+			"code": "invalid_parameter",
+			"message": "invalid limit parameter \"-1\": expected non-negative integer"
+		}`},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	exp := DecisionsQuery{
+		Path:       "authz",
+		DecisionID: "1",
+		Since:      time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Limit:      5,
+	}
+	if len(queries) != 3 || queries[0] != exp {
+		t.Fatalf("expected query %+v, got %+v", exp, queries)
+	}
+}
+
+func TestParseDecisionsQuery(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	query, err := parseDecisionsQuery(url.Values{"since": {"5m"}}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := now.Add(-5 * time.Minute); !query.Since.Equal(exp) {
+		t.Fatalf("expected since %v, got %v", exp, query.Since)
+	}
+}
+
 func TestStatusV1MetricsWithSystemAuthzPolicy(t *testing.T) {
 	t.Parallel()
 
@@ -4704,6 +4775,64 @@ func TestDecisionLogRequestMetadata(t *testing.T) {
 	}
 }
 
+func TestDecisionLogAnnotations(t *testing.T) {
+	t.Parallel()
+
+	f := newFixture(t)
+
+	var logged *Info
+
+	f.server = f.server.WithDecisionLoggerWithErr(func(_ context.Context, info *Info) error {
+		logged = info
+		return nil
+	})
+
+	policy := `package test
+
+allow if {
+	input.user == "alice"
+	opa.decision_log.annotate("role", "admin")
+}`
+	if err := f.v1("PUT", "/policies/test", policy, http.StatusOK, "{}"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.v1("POST", "/data/test/allow", `{"input": {"user": "alice"}}`, http.StatusOK, `{"result": true}`); err != nil {
+		t.Fatal(err)
+	}
+	if exp := map[string]any{"role": "admin"}; !reflect.DeepEqual(logged.Annotations, exp) {
+		t.Fatalf("expected annotations %v, got %v", exp, logged.Annotations)
+	}
+
+	if err := f.v1("POST", "/data/test/allow", `{"input": {"user": "bob"}}`, http.StatusOK, `{}`); err != nil {
+		t.Fatal(err)
+	}
+	if logged.Annotations != nil {
+		t.Fatalf("expected no annotations, got %v", logged.Annotations)
+	}
+
+	// Annotations of roles that didn't match are discarded.
+	policy = `package authz
+
+roles contains r if {
+	some r in input.roles
+	opa.decision_log.annotate("matched_role", r)
+	r == "admin"
+}
+
+allow if "admin" in roles`
+	if err := f.v1("PUT", "/policies/authz", policy, http.StatusOK, "{}"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.v1("POST", "/data/authz/allow", `{"input": {"roles": ["admin", "viewer"]}}`, http.StatusOK, `{"result": true}`); err != nil {
+		t.Fatal(err)
+	}
+	if exp := map[string]any{"matched_role": "admin"}; !reflect.DeepEqual(logged.Annotations, exp) {
+		t.Fatalf("expected annotations %v, got %v", exp, logged.Annotations)
+	}
+}
+
 func TestDecisionLogResponseMetadata(t *testing.T) {
 	t.Parallel()
 
@@ -124,6 +124,7 @@ const (
 	CodeResourceNotFound  = "resource_not_found"
 	CodeResourceConflict  = "resource_conflict"
 	CodeUndefinedDocument = "undefined_document"
+	CodeNotEnabled        = "not_enabled"
 )
 
 // ErrorV1 models an error response sent to the client.
@@ -547,6 +548,11 @@ type StatusResponseV1 struct {
 	Result *any `json:"result,omitempty"`
 }
 
+// DecisionsResponseV1 models the response message for Decisions API operations.
+type DecisionsResponseV1 struct {
+	Result []any `json:"result"`
+}
+
 // HealthResponseV1 models the response message for Health API operations.
 type HealthResponseV1 struct {
 	Error string `json:"error,omitempty"`
@@ -605,6 +611,22 @@ const (
 	// of the health API for the specified plugin(s)
 	ParamExcludePluginV1 = "exclude-plugin"
 
+	// ParamPathV1 defines the name of the HTTP URL parameter that selects the
+	// decisions of the Decisions API by path.
+	ParamPathV1 = "path"
+
+	// ParamDecisionIDV1 defines the name of the HTTP URL parameter that selects
+	// the decisions of the Decisions API by decision ID.
+	ParamDecisionIDV1 = "decision_id"
+
+	// ParamSinceV1 defines the name of the HTTP URL parameter that selects the
+	// decisions of the Decisions API made since a timestamp or duration.
+	ParamSinceV1 = "since"
+
+	// ParamLimitV1 defines the name of the HTTP URL parameter that limits the
+	// number of decisions returned by the Decisions API.
+	ParamLimitV1 = "limit"
+
 	// ParamStrictBuiltinErrors names the HTTP URL parameter that indicates the client
 	// wants built-in function errors to be treated as fatal.
 	ParamStrictBuiltinErrors = "strict-builtin-errors"
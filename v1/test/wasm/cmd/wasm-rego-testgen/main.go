@@ -180,7 +180,7 @@ func run(params params) error {
 	return copyFile(tgw, "test.js", params.TestRunner)
 }
 
-func copyFile(tgw *archive.TarGzWriter, dst, src string) error {
+func copyFile(tgw *archive.TarWriter, dst, src string) error {
 	in, err := os.Open(src)
 	if err != nil {
 		return err
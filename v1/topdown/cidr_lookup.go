@@ -0,0 +1,165 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package topdown
+
+import (
+	"net/netip"
+
+	"github.com/open-policy-agent/opa/internal/cidrtree"
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/topdown/builtins"
+	"github.com/open-policy-agent/opa/v1/topdown/cache"
+	"github.com/open-policy-agent/opa/v1/util"
+)
+
+const (
+	cidrLookupCacheName = "net_cidr_lookup"
+
+	cidrLookupInterQueryValueCacheHits = "rego_builtin_cidr_lookup_interquery_value_cache_hits"
+)
+
+// cidrTable is a compiled net.cidr_lookup table. Entries hold the prefix as
+// written in the table, and its value.
+type cidrTable = cidrtree.Tree[[2]*ast.Term]
+
+type cidrTablesCacheKey string
+
+func builtinNetCIDRLookup(bctx BuiltinContext, operands []*ast.Term, iter func(*ast.Term) error) error {
+	switch operands[0].Value.(type) {
+	case ast.Object, ast.Set, *ast.Array:
+	default:
+		return builtins.NewOperandTypeErr(1, operands[0].Value, "object", "set", "array")
+	}
+
+	s, err := builtins.StringOperand(operands[1].Value, 2)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(string(s))
+	if err != nil {
+		return builtins.NewOperandErr(2, "invalid IP address %q", string(s))
+	}
+
+	table, err := getCIDRTable(bctx, operands[0].Value)
+	if err != nil {
+		return err
+	}
+
+	_, entry, ok := table.Lookup(addr.WithZone(""))
+	if !ok {
+		return nil
+	}
+	return iter(ast.ObjectTerm(
+		ast.Item(ast.InternedTerm("prefix"), entry[0]),
+		ast.Item(ast.InternedTerm("value"), entry[1]),
+	))
+}
+
+// getCIDRTable returns the compiled table for x. Tables are cached by value in
+// the inter-query value cache if it is enabled, and for the duration of the
+// query otherwise. Since values read from the store are only replaced when
+// data changes, a table stored in data is compiled once per revision.
+func getCIDRTable(bctx BuiltinContext, x ast.Value) (*cidrTable, error) {
+	var c cache.InterQueryValueCacheBucket
+	if bctx.InterQueryBuiltinValueCache != nil {
+		c = bctx.InterQueryBuiltinValueCache.GetCache(cidrLookupCacheName)
+	}
+
+	if c != nil {
+		if val, ok := c.Get(x); ok {
+			if table, ok := val.(*cidrTable); ok {
+				bctx.Metrics.Counter(cidrLookupInterQueryValueCacheHits).Incr()
+				return table, nil
+			}
+		}
+	} else {
+		tables, ok := bctx.Cache.Get(cidrTablesCacheKey(cidrLookupCacheName))
+		if !ok {
+			tables = util.NewHasherMap[ast.Value, *cidrTable](ast.ValueEqual)
+			bctx.Cache.Put(cidrTablesCacheKey(cidrLookupCacheName), tables)
+		}
+		if table, ok := tables.(*util.HasherMap[ast.Value, *cidrTable]).Get(x); ok {
+			return table, nil
+		}
+	}
+
+	table, err := compileCIDRTable(x)
+	if err != nil {
+		return nil, err
+	}
+
+	if c != nil {
+		c.Insert(x, table)
+	} else if tables, ok := bctx.Cache.Get(cidrTablesCacheKey(cidrLookupCacheName)); ok {
+		tables.(*util.HasherMap[ast.Value, *cidrTable]).Put(x, table)
+	}
+	return table, nil
+}
+
+// compileCIDRTable builds a table from an object mapping CIDRs or IP addresses
+// to values, or from an array or set of them. The values of array elements are
+// their indices, and set elements map to themselves, like the indices returned
+// by net.cidr_contains_matches. If several entries denote the same prefix, the
+// first one in iteration order is used.
+func compileCIDRTable(x ast.Value) (*cidrTable, error) {
+	table := &cidrTable{}
+	insert := func(prefix, value *ast.Term) error {
+		s, ok := prefix.Value.(ast.String)
+		if !ok {
+			return builtins.NewOperandErr(1, "table keys must be strings but got %v", ast.ValueName(prefix.Value))
+		}
+		p, err := parseCIDROrIP(string(s))
+		if err != nil {
+			return builtins.NewOperandErr(1, "invalid CIDR %q", string(s))
+		}
+		table.Insert(p, [2]*ast.Term{prefix, value})
+		return nil
+	}
+
+	var err error
+	switch x := x.(type) {
+	case ast.Object:
+		err = x.Iter(insert)
+	case ast.Set:
+		err = x.Iter(func(t *ast.Term) error {
+			return insert(t, t)
+		})
+	case *ast.Array:
+		for i := 0; i < x.Len() && err == nil; i++ {
+			err = insert(x.Elem(i), ast.InternedTerm(i))
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return table, nil
+}
+
+// parseCIDROrIP parses a CIDR, or an IP address as a single address prefix.
+// IPv4-mapped IPv6 prefixes are converted to IPv4 prefixes, as they match the
+// same addresses.
+func parseCIDROrIP(s string) (netip.Prefix, error) {
+	p, err := netip.ParsePrefix(s)
+	if err != nil {
+		a, err2 := netip.ParseAddr(s)
+		if err2 != nil || a.Zone() != "" {
+			return netip.Prefix{}, err
+		}
+		p = netip.PrefixFrom(a, a.BitLen())
+	}
+	if p.Addr().Is4In6() && p.Bits() >= 96 {
+		p = netip.PrefixFrom(p.Addr().Unmap(), p.Bits()-96)
+	}
+	return p, nil
+}
+
+func init() {
+	defaultCacheEntries := 10
+	cache.RegisterDefaultInterQueryBuiltinValueCacheConfig(cidrLookupCacheName, &cache.NamedValueCacheConfig{
+		MaxNumEntries: &defaultCacheEntries,
+	})
+
+	RegisterBuiltinFunc(ast.NetCIDRLookup.Name, builtinNetCIDRLookup)
+}
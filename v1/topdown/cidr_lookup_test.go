@@ -0,0 +1,121 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package topdown
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/metrics"
+	"github.com/open-policy-agent/opa/v1/topdown/builtins"
+	"github.com/open-policy-agent/opa/v1/topdown/cache"
+)
+
+func TestNetCIDRLookupInterQueryValueCache(t *testing.T) {
+	t.Parallel()
+
+	config, err := cache.ParseCachingConfig([]byte(`{"inter_query_builtin_value_cache": {"max_num_entries": 10}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	interQueryValueCache := cache.NewInterQueryValueCache(t.Context(), config)
+
+	m := metrics.New()
+	ctx := BuiltinContext{InterQueryBuiltinValueCache: interQueryValueCache, Metrics: m}
+
+	var result *ast.Term
+	iter := func(t *ast.Term) error {
+		result = t
+		return nil
+	}
+
+	// Equal tables share a compiled table, even if they are distinct values.
+	for i := range 3 {
+		table := ast.MustParseTerm(`{"10.0.0.0/8": "a", "10.1.0.0/16": "b"}`)
+		if err := builtinNetCIDRLookup(ctx, []*ast.Term{table, ast.StringTerm(fmt.Sprintf("10.1.0.%d", i))}, iter); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if exp := ast.MustParseTerm(`{"prefix": "10.1.0.0/16", "value": "b"}`); !exp.Equal(result) {
+			t.Fatalf("Expected %v but got %v", exp, result)
+		}
+	}
+
+	table := ast.MustParseTerm(`{"10.0.0.0/8": "a", "10.1.0.0/16": "b"}`)
+	if _, ok := interQueryValueCache.GetCache(cidrLookupCacheName).Get(table.Value); !ok {
+		t.Fatalf("Expected table to be cached: %v", table)
+	}
+	if hits := m.Counter(cidrLookupInterQueryValueCacheHits).Value(); hits != uint64(2) {
+		t.Fatalf("Expected 2 cache hits but got %v", hits)
+	}
+
+	// A changed table is compiled again.
+	table = ast.MustParseTerm(`{"10.0.0.0/8": "a", "10.1.0.0/16": "c"}`)
+	if err := builtinNetCIDRLookup(ctx, []*ast.Term{table, ast.StringTerm("10.1.0.1")}, iter); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if exp := ast.MustParseTerm(`{"prefix": "10.1.0.0/16", "value": "c"}`); !exp.Equal(result) {
+		t.Fatalf("Expected %v but got %v", exp, result)
+	}
+	if hits := m.Counter(cidrLookupInterQueryValueCacheHits).Value(); hits != uint64(2) {
+		t.Fatalf("Expected 2 cache hits but got %v", hits)
+	}
+}
+
+func TestNetCIDRLookupIntraQueryCache(t *testing.T) {
+	t.Parallel()
+
+	ctx := BuiltinContext{Cache: builtins.Cache{}}
+	iter := func(*ast.Term) error { return nil }
+	table := ast.MustParseTerm(`["10.0.0.0/8", "192.168.0.0/16"]`)
+
+	for _, ip := range []string{"10.0.0.1", "192.168.0.1"} {
+		if err := builtinNetCIDRLookup(ctx, []*ast.Term{table, ast.StringTerm(ip)}, iter); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	compiled, err := getCIDRTable(ctx, ast.MustParseTerm(`["10.0.0.0/8", "192.168.0.0/16"]`).Value)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	again, err := getCIDRTable(ctx, table.Value)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if compiled != again || compiled.Len() != 2 {
+		t.Fatalf("Expected table to be compiled once")
+	}
+}
+
+func BenchmarkNetCIDRLookup(b *testing.B) {
+	config, err := cache.ParseCachingConfig([]byte(`{"inter_query_builtin_value_cache": {"max_num_entries": 10}}`))
+	if err != nil {
+		b.Fatal(err)
+	}
+	ctx := BuiltinContext{
+		InterQueryBuiltinValueCache: cache.NewInterQueryValueCache(b.Context(), config),
+		Metrics:                     metrics.New(),
+	}
+
+	items := make([][2]*ast.Term, 0, 50000)
+	for i := range cap(items) {
+		cidr := fmt.Sprintf("10.%d.%d.0/24", i/256%256, i%256)
+		items = append(items, ast.Item(ast.StringTerm(cidr), ast.InternedTerm(i)))
+	}
+	operands := []*ast.Term{ast.ObjectTerm(items...), ast.StringTerm("10.42.7.1")}
+	iter := func(*ast.Term) error { return nil }
+
+	// Compile the table before measuring lookups.
+	if err := builtinNetCIDRLookup(ctx, operands, iter); err != nil {
+		b.Fatal(err)
+	}
+
+	for b.Loop() {
+		if err := builtinNetCIDRLookup(ctx, operands, iter); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
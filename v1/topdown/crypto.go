@@ -7,10 +7,14 @@ package topdown
 import (
 	"bytes"
 	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/hmac"
 	"crypto/md5"
+	"crypto/rsa"
 	"crypto/sha1"
 	"crypto/sha256"
+	"crypto/sha3"
 	"crypto/sha512"
 	"crypto/tls"
 	"crypto/x509"
@@ -21,11 +25,13 @@ import (
 	"errors"
 	"fmt"
 	"hash"
+	"math/big"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/lestrrat-go/jwx/v3/jwk"
+	"golang.org/x/crypto/blake2b"
 
 	"github.com/open-policy-agent/opa/v1/ast"
 	"github.com/open-policy-agent/opa/v1/topdown/builtins"
@@ -460,6 +466,61 @@ func builtinCryptoSha256(_ BuiltinContext, operands []*ast.Term, iter func(*ast.
 	return iter(ast.StringTerm(toHexEncodedString(sha256sum[:])))
 }
 
+func builtinCryptoSha512(_ BuiltinContext, operands []*ast.Term, iter func(*ast.Term) error) error {
+	bs, err := builtins.StringOperandByteSlice(operands[0].Value, 1)
+	if err != nil {
+		return err
+	}
+
+	sha512sum := sha512.Sum512(bs)
+
+	return iter(ast.StringTerm(toHexEncodedString(sha512sum[:])))
+}
+
+func builtinCryptoSha3256(_ BuiltinContext, operands []*ast.Term, iter func(*ast.Term) error) error {
+	bs, err := builtins.StringOperandByteSlice(operands[0].Value, 1)
+	if err != nil {
+		return err
+	}
+
+	sha3sum := sha3.Sum256(bs)
+
+	return iter(ast.StringTerm(toHexEncodedString(sha3sum[:])))
+}
+
+func builtinCryptoSha3512(_ BuiltinContext, operands []*ast.Term, iter func(*ast.Term) error) error {
+	bs, err := builtins.StringOperandByteSlice(operands[0].Value, 1)
+	if err != nil {
+		return err
+	}
+
+	sha3sum := sha3.Sum512(bs)
+
+	return iter(ast.StringTerm(toHexEncodedString(sha3sum[:])))
+}
+
+func builtinCryptoBlake2b256(_ BuiltinContext, operands []*ast.Term, iter func(*ast.Term) error) error {
+	bs, err := builtins.StringOperandByteSlice(operands[0].Value, 1)
+	if err != nil {
+		return err
+	}
+
+	blake2bsum := blake2b.Sum256(bs)
+
+	return iter(ast.StringTerm(toHexEncodedString(blake2bsum[:])))
+}
+
+func builtinCryptoBlake2b512(_ BuiltinContext, operands []*ast.Term, iter func(*ast.Term) error) error {
+	bs, err := builtins.StringOperandByteSlice(operands[0].Value, 1)
+	if err != nil {
+		return err
+	}
+
+	blake2bsum := blake2b.Sum512(bs)
+
+	return iter(ast.StringTerm(toHexEncodedString(blake2bsum[:])))
+}
+
 func hmacHelper(operands []*ast.Term, iter func(*ast.Term) error, h func() hash.Hash) error {
 	message, err := builtins.StringOperandByteSlice(operands[0].Value, 1)
 	if err != nil {
@@ -508,6 +569,128 @@ func builtinCryptoHmacEqual(_ BuiltinContext, operands []*ast.Term, iter func(*a
 	return iter(ast.InternedTerm(hmac.Equal(mac1, mac2)))
 }
 
+// signatureVerifiers verify the signature of a payload by algorithm.
+var signatureVerifiers = map[string]func(key any, payload []byte, signature []byte) bool{
+	"RS256": verifyRSAPKCS1v15(crypto.SHA256),
+	"RS384": verifyRSAPKCS1v15(crypto.SHA384),
+	"RS512": verifyRSAPKCS1v15(crypto.SHA512),
+	"PS256": verifyRSAPSS(crypto.SHA256),
+	"PS384": verifyRSAPSS(crypto.SHA384),
+	"PS512": verifyRSAPSS(crypto.SHA512),
+	"ES256": verifyECDSA(crypto.SHA256, 256),
+	"ES384": verifyECDSA(crypto.SHA384, 384),
+	"ES512": verifyECDSA(crypto.SHA512, 521),
+	"EdDSA": verifyEdDSA,
+}
+
+func builtinCryptoVerify(_ BuiltinContext, operands []*ast.Term, iter func(*ast.Term) error) error {
+	alg, err := builtins.StringOperand(operands[0].Value, 1)
+	if err != nil {
+		return err
+	}
+
+	keyStr, err := builtins.StringOperand(operands[1].Value, 2)
+	if err != nil {
+		return err
+	}
+
+	payload, err := builtins.StringOperandByteSlice(operands[2].Value, 3)
+	if err != nil {
+		return err
+	}
+
+	sig, err := builtins.StringOperand(operands[3].Value, 4)
+	if err != nil {
+		return err
+	}
+
+	verify, ok := signatureVerifiers[string(alg)]
+	if !ok {
+		return fmt.Errorf("unsupported signature algorithm: %s", string(alg))
+	}
+
+	keys, err := getKeysFromCertOrJWK(string(keyStr))
+	if err != nil {
+		return err
+	}
+
+	signature, err := decodeSignature(string(sig))
+	if err != nil {
+		return fmt.Errorf("signature had invalid encoding: %v", err)
+	}
+
+	for _, key := range keys {
+		// keys of a JWKS that are restricted to another algorithm are skipped
+		if key.alg != "" && key.alg != string(alg) {
+			continue
+		}
+		if verify(key.key, payload, signature) {
+			return iter(ast.InternedTerm(true))
+		}
+	}
+
+	return iter(ast.InternedTerm(false))
+}
+
+// decodeSignature decodes a base64 or base64url encoded signature, with or
+// without padding.
+func decodeSignature(s string) ([]byte, error) {
+	s = strings.TrimRight(s, "=")
+	if strings.ContainsAny(s, "-_") {
+		return base64.RawURLEncoding.DecodeString(s)
+	}
+	return base64.RawStdEncoding.DecodeString(s)
+}
+
+func digestOf(h crypto.Hash, payload []byte) []byte {
+	hasher := h.New()
+	hasher.Write(payload)
+	return hasher.Sum(nil)
+}
+
+func verifyRSAPKCS1v15(h crypto.Hash) func(any, []byte, []byte) bool {
+	return func(key any, payload []byte, signature []byte) bool {
+		pub, ok := key.(*rsa.PublicKey)
+		return ok && rsa.VerifyPKCS1v15(pub, h, digestOf(h, payload), signature) == nil
+	}
+}
+
+func verifyRSAPSS(h crypto.Hash) func(any, []byte, []byte) bool {
+	return func(key any, payload []byte, signature []byte) bool {
+		pub, ok := key.(*rsa.PublicKey)
+		return ok && rsa.VerifyPSS(pub, h, digestOf(h, payload), signature, nil) == nil
+	}
+}
+
+func verifyECDSA(h crypto.Hash, bits int) func(any, []byte, []byte) bool {
+	return func(key any, payload []byte, signature []byte) bool {
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok || pub.Curve.Params().BitSize != bits {
+			return false
+		}
+
+		digest := digestOf(h, payload)
+
+		// JWS style signatures are the fixed size concatenation of r and s,
+		// anything else is expected to be ASN.1 DER encoded. DER encoded
+		// signatures can have the same size, e.g. for P-521, so they are
+		// tried if the signature is not a valid concatenation.
+		if n := (bits + 7) / 8; len(signature) == 2*n {
+			r := new(big.Int).SetBytes(signature[:n])
+			s := new(big.Int).SetBytes(signature[n:])
+			if ecdsa.Verify(pub, digest, r, s) {
+				return true
+			}
+		}
+		return ecdsa.VerifyASN1(pub, digest, signature)
+	}
+}
+
+func verifyEdDSA(key any, payload []byte, signature []byte) bool {
+	pub, ok := key.(ed25519.PublicKey)
+	return ok && len(pub) == ed25519.PublicKeySize && ed25519.Verify(pub, payload, signature)
+}
+
 func init() {
 	RegisterBuiltinFunc(ast.CryptoX509ParseCertificates.Name, builtinCryptoX509ParseCertificates)
 	RegisterBuiltinFunc(ast.CryptoX509ParseAndVerifyCertificates.Name, builtinCryptoX509ParseAndVerifyCertificates)
@@ -515,6 +698,11 @@ func init() {
 	RegisterBuiltinFunc(ast.CryptoMd5.Name, builtinCryptoMd5)
 	RegisterBuiltinFunc(ast.CryptoSha1.Name, builtinCryptoSha1)
 	RegisterBuiltinFunc(ast.CryptoSha256.Name, builtinCryptoSha256)
+	RegisterBuiltinFunc(ast.CryptoSha512.Name, builtinCryptoSha512)
+	RegisterBuiltinFunc(ast.CryptoSha3256.Name, builtinCryptoSha3256)
+	RegisterBuiltinFunc(ast.CryptoSha3512.Name, builtinCryptoSha3512)
+	RegisterBuiltinFunc(ast.CryptoBlake2b256.Name, builtinCryptoBlake2b256)
+	RegisterBuiltinFunc(ast.CryptoBlake2b512.Name, builtinCryptoBlake2b512)
 	RegisterBuiltinFunc(ast.CryptoX509ParseCertificateRequest.Name, builtinCryptoX509ParseCertificateRequest)
 	RegisterBuiltinFunc(ast.CryptoX509ParseRSAPrivateKey.Name, builtinCryptoJWKFromPrivateKey)
 	RegisterBuiltinFunc(ast.CryptoParsePrivateKeys.Name, builtinCryptoParsePrivateKeys)
@@ -524,6 +712,7 @@ func init() {
 	RegisterBuiltinFunc(ast.CryptoHmacSha256.Name, builtinCryptoHmacSha256)
 	RegisterBuiltinFunc(ast.CryptoHmacSha512.Name, builtinCryptoHmacSha512)
 	RegisterBuiltinFunc(ast.CryptoHmacEqual.Name, builtinCryptoHmacEqual)
+	RegisterBuiltinFunc(ast.CryptoVerify.Name, builtinCryptoVerify)
 }
 
 func verifyX509CertificateChain(certs []*x509.Certificate, vo x509.VerifyOptions) ([]*x509.Certificate, error) {
@@ -0,0 +1,119 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package topdown
+
+import (
+	"context"
+	"sync"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/topdown/builtins"
+)
+
+// DecisionLogAnnotations collects the values annotated by the
+// opa.decision_log.annotate built-in function during evaluation, so that they
+// can be written to the decision log. An annotation is only kept once the
+// query, rule or function body it was made in succeeds, so annotations made on
+// branches of the evaluation that fail are discarded, as are annotations made
+// while evaluating negated expressions. If a key is annotated by several
+// successful bodies, the value of the last one to succeed is kept.
+type DecisionLogAnnotations struct {
+	mtx     sync.Mutex
+	values  map[string]*ast.Term
+	pending []pendingAnnotation
+}
+
+// pendingAnnotation is an annotation made by a body that hasn't succeeded yet.
+type pendingAnnotation struct {
+	queryID uint64
+	key     string
+	value   *ast.Term
+}
+
+// NewDecisionLogAnnotations returns an empty collection of annotations.
+func NewDecisionLogAnnotations() *DecisionLogAnnotations {
+	return &DecisionLogAnnotations{values: map[string]*ast.Term{}}
+}
+
+// push records an annotation made by the body of the query, until the
+// evaluation backtracks over it and pops it again.
+func (a *DecisionLogAnnotations) push(queryID uint64, key string, value *ast.Term) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	a.pending = append(a.pending, pendingAnnotation{queryID: queryID, key: key, value: value})
+}
+
+func (a *DecisionLogAnnotations) pop() {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	a.pending[len(a.pending)-1] = pendingAnnotation{}
+	a.pending = a.pending[:len(a.pending)-1]
+}
+
+// commit keeps the pending annotations made by the body of the query, which
+// has succeeded, replacing any previous values of their keys.
+func (a *DecisionLogAnnotations) commit(queryID uint64) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	for _, p := range a.pending {
+		if p.queryID == queryID {
+			a.values[p.key] = p.value
+		}
+	}
+}
+
+// Values returns the JSON representation of the annotations, or nil if there
+// are none.
+func (a *DecisionLogAnnotations) Values() (map[string]any, error) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if len(a.values) == 0 {
+		return nil, nil
+	}
+	values := make(map[string]any, len(a.values))
+	for k, v := range a.values {
+		x, err := ast.JSON(v.Value)
+		if err != nil {
+			return nil, err
+		}
+		values[k] = x
+	}
+	return values, nil
+}
+
+type decisionLogAnnotationsKey struct{}
+
+// WithDecisionLogAnnotations returns a context collecting the annotations of
+// the evaluations it is passed to. Without it, annotations are ignored.
+func WithDecisionLogAnnotations(ctx context.Context, a *DecisionLogAnnotations) context.Context {
+	return context.WithValue(ctx, decisionLogAnnotationsKey{}, a)
+}
+
+// DecisionLogAnnotationsFromContext returns the annotations collected in the
+// context, if any.
+func DecisionLogAnnotationsFromContext(ctx context.Context) (*DecisionLogAnnotations, bool) {
+	a, ok := ctx.Value(decisionLogAnnotationsKey{}).(*DecisionLogAnnotations)
+	return a, ok && a != nil
+}
+
+func builtinOPADecisionLogAnnotate(bctx BuiltinContext, operands []*ast.Term, iter func(*ast.Term) error) error {
+	key, err := builtins.StringOperand(operands[0].Value, 1)
+	if err != nil {
+		return err
+	}
+
+	if bctx.Context != nil {
+		if a, ok := DecisionLogAnnotationsFromContext(bctx.Context); ok {
+			a.push(bctx.QueryID, string(key), operands[1])
+			defer a.pop()
+		}
+	}
+	return iter(ast.InternedTerm(true))
+}
+
+func init() {
+	RegisterBuiltinFunc(ast.OPADecisionLogAnnotate.Name, builtinOPADecisionLogAnnotate)
+}
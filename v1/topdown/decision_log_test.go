@@ -0,0 +1,173 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package topdown
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/storage"
+	"github.com/open-policy-agent/opa/v1/storage/inmem"
+)
+
+func TestOPADecisionLogAnnotate(t *testing.T) {
+	t.Parallel()
+
+	body := ast.MustParseBody(`opa.decision_log.annotate("role", "admin", x); opa.decision_log.annotate("score", 1); opa.decision_log.annotate("score", {"risk": 2, "rules": {"r1"}})`)
+
+	// Without a collector, annotations are ignored.
+	rs, err := NewQuery(body).Run(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	} else if len(rs) != 1 || ast.Compare(rs[0][ast.Var("x")], ast.BooleanTerm(true)) != 0 {
+		t.Fatalf("expected a single true result, got %v", rs)
+	}
+
+	annotations := NewDecisionLogAnnotations()
+	if values, err := annotations.Values(); err != nil || values != nil {
+		t.Fatalf("expected no values, got %v (err: %v)", values, err)
+	}
+
+	ctx := WithDecisionLogAnnotations(t.Context(), annotations)
+	if _, err := NewQuery(body).Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	values, err := annotations.Values()
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := map[string]any{
+		"role":  "admin",
+		"score": map[string]any{"risk": json.Number("2"), "rules": []any{"r1"}},
+	}
+	if !reflect.DeepEqual(values, exp) {
+		t.Fatalf("expected %v, got %v", exp, values)
+	}
+}
+
+func TestOPADecisionLogAnnotateBacktracking(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		note   string
+		module string
+		query  string
+		input  string
+		exp    map[string]any
+	}{
+		{
+			note: "failing branch after successful one",
+			module: `package authz
+
+roles contains r if {
+	some r in input.roles
+	opa.decision_log.annotate("matched_role", r)
+	r == "admin"
+}
+
+allow if "admin" in roles`,
+			query: "data.authz.allow",
+			input: `{"roles": ["admin", "viewer"]}`,
+			exp:   map[string]any{"matched_role": "admin"},
+		},
+		{
+			note: "failing body",
+			module: `package authz
+
+allow if {
+	opa.decision_log.annotate("reason", "first")
+	input.x == 1
+}
+
+allow if {
+	opa.decision_log.annotate("reason", "second")
+	input.x == 2
+}`,
+			query: "data.authz.allow",
+			input: `{"x": 2}`,
+			exp:   map[string]any{"reason": "second"},
+		},
+		{
+			note: "nothing succeeds",
+			module: `package authz
+
+allow if {
+	some r in input.roles
+	opa.decision_log.annotate("matched_role", r)
+	r == "admin"
+}`,
+			query: "data.authz.allow",
+			input: `{"roles": ["viewer"]}`,
+		},
+		{
+			note: "failing query",
+			module: `package authz
+
+role := r if {
+	some r in input.roles
+	opa.decision_log.annotate("role", r)
+}`,
+			query: `opa.decision_log.annotate("query", true); data.authz.role == "admin"`,
+			input: `{"roles": ["viewer"]}`,
+			exp:   map[string]any{"role": "viewer"},
+		},
+		{
+			note: "negation",
+			module: `package authz
+
+admin if {
+	opa.decision_log.annotate("admin", true)
+	"admin" in input.roles
+}
+
+deny if not admin`,
+			query: "data.authz.deny",
+			input: `{"roles": ["viewer"]}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			t.Parallel()
+
+			compiler := ast.MustCompileModules(map[string]string{"test.rego": tc.module})
+			store := inmem.New()
+			txn := storage.NewTransactionOrDie(t.Context(), store)
+			defer store.Abort(t.Context(), txn)
+
+			annotations := NewDecisionLogAnnotations()
+			ctx := WithDecisionLogAnnotations(t.Context(), annotations)
+
+			query, err := compiler.QueryCompiler().Compile(ast.MustParseBody(tc.query))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = NewQuery(query).
+				WithCompiler(compiler).
+				WithStore(store).
+				WithTransaction(txn).
+				WithInput(ast.MustParseTerm(tc.input)).
+				Run(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			values, err := annotations.Values()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(tc.exp) == 0 && len(values) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(values, tc.exp) {
+				t.Fatalf("expected %v, got %v", tc.exp, values)
+			}
+		})
+	}
+}
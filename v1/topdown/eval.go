@@ -109,6 +109,7 @@ type eval struct {
 	builtinErrors               *builtinErrors
 	roundTripper                CustomizeRoundTripper
 	evaluated                   *EvaluatedRuleTracker
+	decisionLogAnnotations      *DecisionLogAnnotations
 	genvarprefix                string
 	query                       ast.Body
 	tracers                     []QueryTracer
@@ -391,6 +392,10 @@ func (e *eval) evalExpr(iter evalIterator) error {
 	}
 
 	if e.index >= len(e.query) {
+		if e.decisionLogAnnotations != nil {
+			e.decisionLogAnnotations.commit(e.queryID)
+		}
+
 		if err := iter(e); err != nil {
 			switch err := err.(type) {
 			case *deferredEarlyExitError, *earlyExitError:
@@ -641,6 +646,10 @@ func (e *eval) evalNot(iter evalIterator) error {
 		child.traceEnter(negation)
 	}
 
+	// The negated expression succeeding makes the negation fail, so nothing
+	// annotated while evaluating it is kept.
+	child.decisionLogAnnotations = nil
+
 	if err := child.eval(func(*eval) error {
 		if e.traceEnabled {
 			child.traceExit(negation)
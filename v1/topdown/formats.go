@@ -0,0 +1,159 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package topdown
+
+import (
+	"unicode/utf8"
+
+	"github.com/open-policy-agent/opa/internal/dataformat"
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/topdown/builtins"
+)
+
+func builtinTOMLUnmarshal(_ BuiltinContext, operands []*ast.Term, iter func(*ast.Term) error) error {
+	return unmarshalFormat(dataformat.UnmarshalTOML, operands, iter)
+}
+
+func builtinTOMLIsValid(_ BuiltinContext, operands []*ast.Term, iter func(*ast.Term) error) error {
+	return isValidFormat(dataformat.UnmarshalTOML, operands, iter)
+}
+
+func builtinXMLUnmarshal(_ BuiltinContext, operands []*ast.Term, iter func(*ast.Term) error) error {
+	return unmarshalFormat(dataformat.UnmarshalXML, operands, iter)
+}
+
+func builtinXMLIsValid(_ BuiltinContext, operands []*ast.Term, iter func(*ast.Term) error) error {
+	return isValidFormat(dataformat.UnmarshalXML, operands, iter)
+}
+
+func builtinHCLUnmarshal(_ BuiltinContext, operands []*ast.Term, iter func(*ast.Term) error) error {
+	return unmarshalFormat(dataformat.UnmarshalHCL, operands, iter)
+}
+
+func builtinHCLIsValid(_ BuiltinContext, operands []*ast.Term, iter func(*ast.Term) error) error {
+	return isValidFormat(dataformat.UnmarshalHCL, operands, iter)
+}
+
+func builtinINIUnmarshal(_ BuiltinContext, operands []*ast.Term, iter func(*ast.Term) error) error {
+	return unmarshalFormat(dataformat.UnmarshalINI, operands, iter)
+}
+
+func builtinINIIsValid(_ BuiltinContext, operands []*ast.Term, iter func(*ast.Term) error) error {
+	return isValidFormat(dataformat.UnmarshalINI, operands, iter)
+}
+
+func unmarshalFormat(unmarshal func([]byte) (any, error), operands []*ast.Term, iter func(*ast.Term) error) error {
+	bs, err := builtins.StringOperandByteSlice(operands[0].Value, 1)
+	if err != nil {
+		return err
+	}
+
+	x, err := unmarshal(bs)
+	if err != nil {
+		return err
+	}
+
+	v, err := ast.InterfaceToValue(x)
+	if err != nil {
+		return err
+	}
+	return iter(ast.NewTerm(v))
+}
+
+func isValidFormat(unmarshal func([]byte) (any, error), operands []*ast.Term, iter func(*ast.Term) error) error {
+	bs, err := builtins.StringOperandByteSlice(operands[0].Value, 1)
+	if err != nil {
+		return iter(ast.InternedTerm(false))
+	}
+
+	_, err = unmarshal(bs)
+	return iter(ast.InternedTerm(err == nil))
+}
+
+func builtinCSVParse(_ BuiltinContext, operands []*ast.Term, iter func(*ast.Term) error) error {
+	bs, err := builtins.StringOperandByteSlice(operands[0].Value, 1)
+	if err != nil {
+		return err
+	}
+
+	opts, err := csvOptions(operands[1].Value)
+	if err != nil {
+		return err
+	}
+
+	records, err := dataformat.ParseCSV(bs, opts)
+	if err != nil {
+		return err
+	}
+
+	v, err := ast.InterfaceToValue(records)
+	if err != nil {
+		return err
+	}
+	return iter(ast.NewTerm(v))
+}
+
+func builtinCSVIsValid(_ BuiltinContext, operands []*ast.Term, iter func(*ast.Term) error) error {
+	bs, err := builtins.StringOperandByteSlice(operands[0].Value, 1)
+	if err != nil {
+		return iter(ast.InternedTerm(false))
+	}
+
+	opts, err := csvOptions(operands[1].Value)
+	if err != nil {
+		return err
+	}
+
+	_, err = dataformat.ParseCSV(bs, opts)
+	return iter(ast.InternedTerm(err == nil))
+}
+
+func csvOptions(v ast.Value) (dataformat.CSVOptions, error) {
+	var opts dataformat.CSVOptions
+
+	obj, err := builtins.ObjectOperand(v, 2)
+	if err != nil {
+		return opts, err
+	}
+
+	err = obj.Iter(func(k, v *ast.Term) error {
+		key, err := builtins.StringOperand(k.Value, 2)
+		if err != nil {
+			return err
+		}
+
+		switch key {
+		case "header":
+			header, ok := v.Value.(ast.Boolean)
+			if !ok {
+				return builtins.NewOperandErr(2, "key %s failed cast to bool", key)
+			}
+			opts.Header = bool(header)
+		case "delimiter":
+			delimiter, ok := v.Value.(ast.String)
+			if !ok || utf8.RuneCountInString(string(delimiter)) != 1 {
+				return builtins.NewOperandErr(2, "key %s must be a single character", key)
+			}
+			opts.Delimiter, _ = utf8.DecodeRuneInString(string(delimiter))
+		default:
+			return builtins.NewOperandErr(2, "object contained unknown key %s", key)
+		}
+		return nil
+	})
+	return opts, err
+}
+
+func init() {
+	RegisterBuiltinFunc(ast.TOMLUnmarshal.Name, builtinTOMLUnmarshal)
+	RegisterBuiltinFunc(ast.TOMLIsValid.Name, builtinTOMLIsValid)
+	RegisterBuiltinFunc(ast.XMLUnmarshal.Name, builtinXMLUnmarshal)
+	RegisterBuiltinFunc(ast.XMLIsValid.Name, builtinXMLIsValid)
+	RegisterBuiltinFunc(ast.CSVParse.Name, builtinCSVParse)
+	RegisterBuiltinFunc(ast.CSVIsValid.Name, builtinCSVIsValid)
+	RegisterBuiltinFunc(ast.HCLUnmarshal.Name, builtinHCLUnmarshal)
+	RegisterBuiltinFunc(ast.HCLIsValid.Name, builtinHCLIsValid)
+	RegisterBuiltinFunc(ast.INIUnmarshal.Name, builtinINIUnmarshal)
+	RegisterBuiltinFunc(ast.INIIsValid.Name, builtinINIIsValid)
+}
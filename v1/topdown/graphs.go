@@ -0,0 +1,504 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package topdown
+
+import (
+	"container/heap"
+	"errors"
+	"slices"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/topdown/builtins"
+	"github.com/open-policy-agent/opa/v1/util"
+)
+
+// indexedGraph is a graph in the adjacency object representation of
+// graph.reachable, with vertices numbered in sort order. Neighbors that are
+// not keys of the object are vertices without edges. The neighbors of each
+// vertex are sorted, so that all algorithms visit vertices in sort order and
+// produce the same results for equal graphs.
+type indexedGraph struct {
+	vertices []*ast.Term
+	edges    [][]int
+}
+
+func newIndexedGraph(x ast.Value, pos int) (*indexedGraph, error) {
+	obj, err := builtins.ObjectOperand(x, pos)
+	if err != nil {
+		return nil, err
+	}
+
+	// Vertices are numbered in the order they are found first, and renumbered
+	// in sort order afterwards.
+	var vertices []*ast.Term
+	ids := util.NewHasherMap[ast.Value, int](ast.ValueEqual)
+	id := func(t *ast.Term) int {
+		if i, ok := ids.Get(t.Value); ok {
+			return i
+		}
+		ids.Put(t.Value, len(vertices))
+		vertices = append(vertices, t)
+		return len(vertices) - 1
+	}
+
+	var edges [][]int
+	err = obj.Iter(func(k, v *ast.Term) error {
+		switch v.Value.(type) {
+		case ast.Set, *ast.Array:
+		default:
+			return builtins.NewOperandErr(pos, "neighbors of %v must be set or array but got %v", k, ast.ValueName(v.Value))
+		}
+		i := id(k)
+		for len(edges) <= i {
+			edges = append(edges, nil)
+		}
+		neighbors := make([]int, 0, numberOfEdges(v))
+		foreachVertex(v, func(t *ast.Term) {
+			neighbors = append(neighbors, id(t))
+		})
+		edges[i] = neighbors
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	order := make([]int, len(vertices))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortFunc(order, func(a, b int) int {
+		return vertices[a].Value.Compare(vertices[b].Value)
+	})
+	rank := make([]int, len(vertices))
+	for r, i := range order {
+		rank[i] = r
+	}
+
+	g := &indexedGraph{vertices: make([]*ast.Term, len(vertices)), edges: make([][]int, len(vertices))}
+	for i, t := range vertices {
+		g.vertices[rank[i]] = t
+	}
+	for i, neighbors := range edges {
+		for j, w := range neighbors {
+			neighbors[j] = rank[w]
+		}
+		slices.Sort(neighbors)
+		g.edges[rank[i]] = slices.Compact(neighbors)
+	}
+	return g, nil
+}
+
+func (g *indexedGraph) index(t *ast.Term) (int, bool) {
+	return slices.BinarySearchFunc(g.vertices, t, func(a, b *ast.Term) int {
+		return a.Value.Compare(b.Value)
+	})
+}
+
+// all returns all vertices.
+func (g *indexedGraph) all() []int {
+	vs := make([]int, len(g.vertices))
+	for i := range vs {
+		vs[i] = i
+	}
+	return vs
+}
+
+func (g *indexedGraph) terms(vs []int) []*ast.Term {
+	terms := make([]*ast.Term, len(vs))
+	for i, v := range vs {
+		terms[i] = g.vertices[v]
+	}
+	return terms
+}
+
+func (g *indexedGraph) hasSelfLoop(v int) bool {
+	_, ok := slices.BinarySearch(g.edges[v], v)
+	return ok
+}
+
+// topologicalOrder returns the vertices in topological order, picking the
+// least vertex whenever there is a choice, and whether all vertices could be
+// ordered. If not, the graph has a cycle.
+func (g *indexedGraph) topologicalOrder() ([]int, bool) {
+	indegree := make([]int, len(g.vertices))
+	for _, edges := range g.edges {
+		for _, w := range edges {
+			indegree[w]++
+		}
+	}
+
+	ready := &intHeap{}
+	for v, d := range indegree {
+		if d == 0 {
+			*ready = append(*ready, v)
+		}
+	}
+	heap.Init(ready)
+
+	order := make([]int, 0, len(g.vertices))
+	for ready.Len() > 0 {
+		v := heap.Pop(ready).(int)
+		order = append(order, v)
+		for _, w := range g.edges[v] {
+			if indegree[w]--; indegree[w] == 0 {
+				heap.Push(ready, w)
+			}
+		}
+	}
+	return order, len(order) == len(g.vertices)
+}
+
+type intHeap []int
+
+func (h intHeap) Len() int           { return len(h) }
+func (h intHeap) Less(i, j int) bool { return h[i] < h[j] }
+func (h intHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *intHeap) Push(x any)        { *h = append(*h, x.(int)) }
+func (h *intHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// sccFinder finds strongly connected components with Tarjan's algorithm. It
+// is iterative, so that long paths don't exhaust the stack, and can be reused
+// for subgraphs.
+type sccFinder struct {
+	g       *indexedGraph
+	index   []int
+	low     []int
+	onStack []bool
+}
+
+func newSCCFinder(g *indexedGraph) *sccFinder {
+	f := &sccFinder{
+		g:       g,
+		index:   make([]int, len(g.vertices)),
+		low:     make([]int, len(g.vertices)),
+		onStack: make([]bool, len(g.vertices)),
+	}
+	for i := range f.index {
+		f.index[i] = -1
+	}
+	return f
+}
+
+// components returns the strongly connected components of the subgraph
+// induced by vs, whose vertices include marks. If include is nil, vs must be
+// all vertices. The vertices of each component are sorted.
+func (f *sccFinder) components(vs []int, include []bool) [][]int {
+	type frame struct{ v, i int }
+
+	var comps [][]int
+	var stack []int
+	var call []frame
+	counter := 0
+
+	for _, root := range vs {
+		if f.index[root] != -1 {
+			continue
+		}
+		f.index[root], f.low[root] = counter, counter
+		counter++
+		stack = append(stack, root)
+		f.onStack[root] = true
+		call = append(call, frame{v: root})
+
+		for len(call) > 0 {
+			top := &call[len(call)-1]
+			v := top.v
+			if top.i < len(f.g.edges[v]) {
+				w := f.g.edges[v][top.i]
+				top.i++
+				if include != nil && !include[w] {
+					continue
+				}
+				if f.index[w] == -1 {
+					f.index[w], f.low[w] = counter, counter
+					counter++
+					stack = append(stack, w)
+					f.onStack[w] = true
+					call = append(call, frame{v: w})
+				} else if f.onStack[w] {
+					f.low[v] = min(f.low[v], f.index[w])
+				}
+				continue
+			}
+
+			if f.low[v] == f.index[v] {
+				i := len(stack) - 1
+				for stack[i] != v {
+					i--
+				}
+				comp := slices.Clone(stack[i:])
+				for _, w := range comp {
+					f.onStack[w] = false
+				}
+				stack = stack[:i]
+				slices.Sort(comp)
+				comps = append(comps, comp)
+			}
+			call = call[:len(call)-1]
+			if len(call) > 0 {
+				u := call[len(call)-1].v
+				f.low[u] = min(f.low[u], f.low[v])
+			}
+		}
+	}
+
+	for _, v := range vs {
+		f.index[v] = -1
+	}
+	return comps
+}
+
+// cycleFinder enumerates the elementary cycles of a graph with Johnson's
+// algorithm. Each cycle starts at its least vertex.
+type cycleFinder struct {
+	g       *indexedGraph
+	bctx    BuiltinContext
+	include []bool
+	blocked []bool
+	b       []map[int]struct{}
+	stack   []int
+	cycles  [][]int
+}
+
+func (g *indexedGraph) cycles(bctx BuiltinContext) ([][]int, error) {
+	n := len(g.vertices)
+	c := &cycleFinder{
+		g:       g,
+		bctx:    bctx,
+		include: make([]bool, n),
+		blocked: make([]bool, n),
+		b:       make([]map[int]struct{}, n),
+	}
+	scc := newSCCFinder(g)
+
+	// Each component is searched for the cycles through its least vertex,
+	// which is then removed, and the rest of the component is split into
+	// components again. Components without cycles are skipped.
+	work := scc.components(g.all(), nil)
+	for len(work) > 0 {
+		comp := work[len(work)-1]
+		work = work[:len(work)-1]
+		if len(comp) == 1 && !g.hasSelfLoop(comp[0]) {
+			continue
+		}
+
+		for _, v := range comp {
+			c.include[v] = true
+			c.blocked[v] = false
+			c.b[v] = nil
+		}
+		if _, err := c.circuit(comp[0], comp[0]); err != nil {
+			return nil, err
+		}
+		c.include[comp[0]] = false
+		work = append(work, scc.components(comp[1:], c.include)...)
+		for _, v := range comp {
+			c.include[v] = false
+		}
+	}
+	return c.cycles, nil
+}
+
+func (c *cycleFinder) circuit(s, v int) (bool, error) {
+	if c.bctx.Cancel != nil && c.bctx.Cancel.Cancelled() {
+		return false, Halt{
+			Err: &Error{
+				Code:     CancelErr,
+				Message:  ast.GraphCycles.Name + ": timed out before finding all cycles",
+				Location: c.bctx.Location,
+			},
+		}
+	}
+
+	found := false
+	c.stack = append(c.stack, v)
+	c.blocked[v] = true
+
+	for _, w := range c.g.edges[v] {
+		if !c.include[w] {
+			continue
+		}
+		if w == s {
+			c.cycles = append(c.cycles, slices.Clone(c.stack))
+			found = true
+		} else if !c.blocked[w] {
+			ok, err := c.circuit(s, w)
+			if err != nil {
+				return false, err
+			}
+			found = found || ok
+		}
+	}
+
+	if found {
+		c.unblock(v)
+	} else {
+		for _, w := range c.g.edges[v] {
+			if !c.include[w] {
+				continue
+			}
+			if c.b[w] == nil {
+				c.b[w] = map[int]struct{}{}
+			}
+			c.b[w][v] = struct{}{}
+		}
+	}
+
+	c.stack = c.stack[:len(c.stack)-1]
+	return found, nil
+}
+
+func (c *cycleFinder) unblock(v int) {
+	c.blocked[v] = false
+	for w := range c.b[v] {
+		delete(c.b[v], w)
+		if c.blocked[w] {
+			c.unblock(w)
+		}
+	}
+}
+
+// shortestPath returns the path with the fewest edges from one vertex to
+// another, and the least one in vertex order if there are several.
+func (g *indexedGraph) shortestPath(from, to int) ([]int, bool) {
+	if from == to {
+		return []int{from}, true
+	}
+
+	reverse := make([][]int, len(g.vertices))
+	for v, edges := range g.edges {
+		for _, w := range edges {
+			reverse[w] = append(reverse[w], v)
+		}
+	}
+
+	// The distances to the target are found with a breadth-first search
+	// backwards from it, which can stop once the source is reached, as all
+	// vertices closer to the target have been reached by then.
+	dist := make([]int, len(g.vertices))
+	for i := range dist {
+		dist[i] = -1
+	}
+	dist[to] = 0
+	queue := []int{to}
+	for len(queue) > 0 && dist[from] == -1 {
+		v := queue[0]
+		queue = queue[1:]
+		for _, u := range reverse[v] {
+			if dist[u] == -1 {
+				dist[u] = dist[v] + 1
+				queue = append(queue, u)
+			}
+		}
+	}
+	if dist[from] == -1 {
+		return nil, false
+	}
+
+	path := []int{from}
+	for v := from; v != to; {
+		for _, w := range g.edges[v] {
+			if dist[w] == dist[v]-1 {
+				v = w
+				break
+			}
+		}
+		path = append(path, v)
+	}
+	return path, true
+}
+
+var errGraphCycle = errors.New("graph contains a cycle")
+
+func builtinGraphTopologicalSort(_ BuiltinContext, operands []*ast.Term, iter func(*ast.Term) error) error {
+	g, err := newIndexedGraph(operands[0].Value, 1)
+	if err != nil {
+		return err
+	}
+
+	order, ok := g.topologicalOrder()
+	if !ok {
+		return errGraphCycle
+	}
+	return iter(ast.ArrayTerm(g.terms(order)...))
+}
+
+func builtinGraphHasCycle(_ BuiltinContext, operands []*ast.Term, iter func(*ast.Term) error) error {
+	g, err := newIndexedGraph(operands[0].Value, 1)
+	if err != nil {
+		return err
+	}
+
+	_, ok := g.topologicalOrder()
+	return iter(ast.InternedTerm(!ok))
+}
+
+func builtinGraphCycles(bctx BuiltinContext, operands []*ast.Term, iter func(*ast.Term) error) error {
+	g, err := newIndexedGraph(operands[0].Value, 1)
+	if err != nil {
+		return err
+	}
+
+	cycles, err := g.cycles(bctx)
+	if err != nil {
+		return err
+	}
+
+	result := ast.NewSetWithCapacity(len(cycles))
+	for _, cycle := range cycles {
+		result.Add(ast.ArrayTerm(g.terms(cycle)...))
+	}
+	return iter(ast.NewTerm(result))
+}
+
+func builtinGraphShortestPath(_ BuiltinContext, operands []*ast.Term, iter func(*ast.Term) error) error {
+	g, err := newIndexedGraph(operands[0].Value, 1)
+	if err != nil {
+		return err
+	}
+
+	from, ok := g.index(operands[1])
+	if !ok {
+		return nil
+	}
+	to, ok := g.index(operands[2])
+	if !ok {
+		return nil
+	}
+
+	path, ok := g.shortestPath(from, to)
+	if !ok {
+		return nil
+	}
+	return iter(ast.ArrayTerm(g.terms(path)...))
+}
+
+func builtinGraphSCC(_ BuiltinContext, operands []*ast.Term, iter func(*ast.Term) error) error {
+	g, err := newIndexedGraph(operands[0].Value, 1)
+	if err != nil {
+		return err
+	}
+
+	comps := newSCCFinder(g).components(g.all(), nil)
+	result := ast.NewSetWithCapacity(len(comps))
+	for _, comp := range comps {
+		result.Add(ast.SetTerm(g.terms(comp)...))
+	}
+	return iter(ast.NewTerm(result))
+}
+
+func init() {
+	RegisterBuiltinFunc(ast.GraphTopologicalSort.Name, builtinGraphTopologicalSort)
+	RegisterBuiltinFunc(ast.GraphHasCycle.Name, builtinGraphHasCycle)
+	RegisterBuiltinFunc(ast.GraphCycles.Name, builtinGraphCycles)
+	RegisterBuiltinFunc(ast.GraphShortestPath.Name, builtinGraphShortestPath)
+	RegisterBuiltinFunc(ast.GraphSCC.Name, builtinGraphSCC)
+}
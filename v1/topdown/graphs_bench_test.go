@@ -0,0 +1,102 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package topdown
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/storage"
+	inmem "github.com/open-policy-agent/opa/v1/storage/inmem/test"
+)
+
+// genDAGBenchmarkData returns a random directed acyclic graph of n vertices
+// with m edges from each vertex to later ones, where there are any.
+func genDAGBenchmarkData(n, m int) ast.Value {
+	r := rand.New(rand.NewSource(1))
+	g := ast.NewObject()
+	for i := range n {
+		neighbors := ast.NewSet()
+		for range m {
+			if i+1 < n {
+				neighbors.Add(ast.StringTerm(fmt.Sprintf("v%d", i+1+r.Intn(n-i-1))))
+			}
+		}
+		g.Insert(ast.StringTerm(fmt.Sprintf("v%d", i)), ast.NewTerm(neighbors))
+	}
+	return g
+}
+
+// genRingsBenchmarkData returns n disjoint rings of m vertices with chords,
+// which have few cycles each.
+func genRingsBenchmarkData(n, m int) ast.Value {
+	g := ast.NewObject()
+	for i := range n {
+		for j := range m {
+			g.Insert(ast.StringTerm(fmt.Sprintf("r%d-%d", i, j)), ast.SetTerm(
+				ast.StringTerm(fmt.Sprintf("r%d-%d", i, (j+1)%m)),
+				ast.StringTerm(fmt.Sprintf("r%d-%d", i, (j+2)%m)),
+			))
+		}
+	}
+	return g
+}
+
+// BenchmarkGraphBuiltins evaluates the graph built-ins on graphs of 100k
+// edges.
+func BenchmarkGraphBuiltins(b *testing.B) {
+	ctx := b.Context()
+
+	dag := genDAGBenchmarkData(20000, 5)
+	rings := genRingsBenchmarkData(10000, 5)
+
+	tests := []struct {
+		note  string
+		graph ast.Value
+		expr  string
+	}{
+		{"topological_sort", dag, `count(graph.topological_sort(data.graph))`},
+		{"has_cycle", dag, `graph.has_cycle(data.graph)`},
+		{"shortest_path", dag, `graph.shortest_path(data.graph, "v0", "v19999")`},
+		{"scc", dag, `count(graph.scc(data.graph))`},
+		{"cycles", rings, `count(graph.cycles(data.graph))`},
+		{"reachable", dag, `count(graph.reachable(data.graph, {"v0"}))`},
+	}
+
+	for _, tc := range tests {
+		b.Run(tc.note, func(b *testing.B) {
+			store := inmem.NewFromObject(map[string]any{"graph": tc.graph})
+
+			module := fmt.Sprintf(`package test
+
+			p := %s`, tc.expr)
+
+			query := ast.MustParseBody("x = data.test.p")
+			compiler := ast.MustCompileModules(map[string]string{
+				"test.rego": module,
+			})
+
+			for b.Loop() {
+				err := storage.Txn(ctx, store, storage.TransactionParams{}, func(txn storage.Transaction) error {
+					rs, err := NewQuery(query).
+						WithCompiler(compiler).
+						WithStore(store).
+						WithTransaction(txn).
+						Run(ctx)
+					if err == nil && len(rs) != 1 {
+						err = fmt.Errorf("expected 1 result, got %d", len(rs))
+					}
+					return err
+				})
+
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
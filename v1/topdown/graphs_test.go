@@ -0,0 +1,230 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package topdown
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+)
+
+// randomGraph returns a graph of n vertices named by numbers, with each
+// possible edge present with probability p.
+func randomGraph(r *rand.Rand, n int, p float64) ast.Object {
+	obj := ast.NewObject()
+	for v := range n {
+		neighbors := ast.NewSet()
+		for w := range n {
+			if r.Float64() < p {
+				neighbors.Add(ast.InternedTerm(w))
+			}
+		}
+		obj.Insert(ast.InternedTerm(v), ast.NewTerm(neighbors))
+	}
+	return obj
+}
+
+// bruteForceCycles enumerates elementary cycles by extending paths from each
+// vertex through larger vertices only.
+func bruteForceCycles(g *indexedGraph) [][]int {
+	var cycles [][]int
+	var extend func(path []int, onPath []bool)
+	extend = func(path []int, onPath []bool) {
+		s, v := path[0], path[len(path)-1]
+		for _, w := range g.edges[v] {
+			switch {
+			case w == s:
+				cycles = append(cycles, slices.Clone(path))
+			case w > s && !onPath[w]:
+				onPath[w] = true
+				extend(append(path, w), onPath)
+				onPath[w] = false
+			}
+		}
+	}
+	for s := range g.vertices {
+		onPath := make([]bool, len(g.vertices))
+		onPath[s] = true
+		extend([]int{s}, onPath)
+	}
+	return cycles
+}
+
+func reachability(g *indexedGraph) [][]bool {
+	n := len(g.vertices)
+	reach := make([][]bool, n)
+	for v := range n {
+		reach[v] = make([]bool, n)
+		reach[v][v] = true
+		queue := []int{v}
+		for len(queue) > 0 {
+			u := queue[0]
+			queue = queue[1:]
+			for _, w := range g.edges[u] {
+				if !reach[v][w] {
+					reach[v][w] = true
+					queue = append(queue, w)
+				}
+			}
+		}
+	}
+	return reach
+}
+
+func TestGraphAlgorithmsRandom(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+	for i := range 500 {
+		obj := randomGraph(r, 1+r.Intn(7), r.Float64()*0.5)
+		g, err := newIndexedGraph(obj, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		n := len(g.vertices)
+		reach := reachability(g)
+
+		// Cycles.
+		act, err := g.cycles(BuiltinContext{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		exp := bruteForceCycles(g)
+		slices.SortFunc(act, slices.Compare)
+		slices.SortFunc(exp, slices.Compare)
+		if !slices.EqualFunc(act, exp, slices.Equal) {
+			t.Fatalf("graph %d %v: expected cycles %v, got %v", i, obj, exp, act)
+		}
+
+		// Topological order exists iff there are no cycles, and respects all
+		// edges.
+		order, ok := g.topologicalOrder()
+		if ok != (len(exp) == 0) {
+			t.Fatalf("graph %d %v: expected acyclic to be %v", i, obj, len(exp) == 0)
+		}
+		if ok {
+			position := make([]int, n)
+			for j, v := range order {
+				position[v] = j
+			}
+			for v, edges := range g.edges {
+				for _, w := range edges {
+					if position[v] >= position[w] {
+						t.Fatalf("graph %d %v: %d is not before %d in %v", i, obj, v, w, order)
+					}
+				}
+			}
+		}
+
+		// Vertices are in the same component iff they reach each other.
+		comp := make([]int, n)
+		for j, c := range newSCCFinder(g).components(g.all(), nil) {
+			for _, v := range c {
+				comp[v] = j
+			}
+		}
+		for v := range n {
+			for w := range n {
+				if (comp[v] == comp[w]) != (reach[v][w] && reach[w][v]) {
+					t.Fatalf("graph %d %v: wrong components for %d and %d", i, obj, v, w)
+				}
+			}
+		}
+
+		// Shortest paths exist iff the target is reachable, and are paths of
+		// minimal length.
+		for v := range n {
+			for w := range n {
+				path, ok := g.shortestPath(v, w)
+				if ok != reach[v][w] {
+					t.Fatalf("graph %d %v: expected path from %d to %d to be %v", i, obj, v, w, reach[v][w])
+				}
+				if !ok {
+					continue
+				}
+				if path[0] != v || path[len(path)-1] != w {
+					t.Fatalf("graph %d %v: wrong ends of path %v from %d to %d", i, obj, path, v, w)
+				}
+				for j := 1; j < len(path); j++ {
+					if _, ok := slices.BinarySearch(g.edges[path[j-1]], path[j]); !ok {
+						t.Fatalf("graph %d %v: %v is not a path", i, obj, path)
+					}
+					// No shortcut from any vertex of the path to a later one.
+					for k := j + 1; k < len(path); k++ {
+						if _, ok := slices.BinarySearch(g.edges[path[j-1]], path[k]); ok {
+							t.Fatalf("graph %d %v: %v is not a shortest path", i, obj, path)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// TestGraphAlgorithmsLarge checks that the graph built-ins are linear on
+// graphs of 100k edges, including a single long cycle.
+func TestGraphAlgorithmsLarge(t *testing.T) {
+	t.Parallel()
+
+	const n = 100000
+
+	chain := make([][2]*ast.Term, n)
+	ring := make([][2]*ast.Term, n)
+	for i := range n {
+		v := ast.StringTerm(fmt.Sprintf("v%06d", i))
+		next := ast.ArrayTerm()
+		if i+1 < n {
+			next = ast.ArrayTerm(ast.StringTerm(fmt.Sprintf("v%06d", i+1)))
+		}
+		chain[i] = ast.Item(v, next)
+		ring[i] = ast.Item(v, ast.ArrayTerm(ast.StringTerm(fmt.Sprintf("v%06d", (i+1)%n))))
+	}
+	chainTerm, ringTerm := ast.ObjectTerm(chain...), ast.ObjectTerm(ring...)
+	first, last := ast.StringTerm("v000000"), ast.StringTerm(fmt.Sprintf("v%06d", n-1))
+
+	tests := []struct {
+		note     string
+		builtin  BuiltinFunc
+		operands []*ast.Term
+		check    func(*ast.Term) bool
+	}{
+		{"topological_sort", builtinGraphTopologicalSort, []*ast.Term{chainTerm}, func(x *ast.Term) bool {
+			return x.Value.(*ast.Array).Len() == n
+		}},
+		{"has_cycle", builtinGraphHasCycle, []*ast.Term{ringTerm}, func(x *ast.Term) bool {
+			return x.Equal(ast.InternedTerm(true))
+		}},
+		{"cycles", builtinGraphCycles, []*ast.Term{ringTerm}, func(x *ast.Term) bool {
+			s := x.Value.(ast.Set)
+			return s.Len() == 1 && s.Slice()[0].Value.(*ast.Array).Len() == n
+		}},
+		{"shortest_path", builtinGraphShortestPath, []*ast.Term{chainTerm, first, last}, func(x *ast.Term) bool {
+			return x.Value.(*ast.Array).Len() == n
+		}},
+		{"scc", builtinGraphSCC, []*ast.Term{ringTerm}, func(x *ast.Term) bool {
+			s := x.Value.(ast.Set)
+			return s.Len() == 1 && s.Slice()[0].Value.(ast.Set).Len() == n
+		}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			var result *ast.Term
+			err := tc.builtin(BuiltinContext{}, tc.operands, func(x *ast.Term) error {
+				result = x
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result == nil || !tc.check(result) {
+				t.Fatalf("unexpected result")
+			}
+		})
+	}
+}
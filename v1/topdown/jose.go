@@ -0,0 +1,229 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package topdown
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lestrrat-go/jwx/v3/jwe"
+	"github.com/lestrrat-go/jwx/v3/jwk"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/topdown/builtins"
+)
+
+// Implements JWE decryption of tokens in compact serialization.
+func builtinJWEDecrypt(_ BuiltinContext, operands []*ast.Term, iter func(*ast.Term) error) error {
+	token, err := builtins.StringOperand(operands[0].Value, 1)
+	if err != nil {
+		return err
+	}
+
+	keys, err := builtins.StringOperand(operands[1].Value, 2)
+	if err != nil {
+		return err
+	}
+
+	parts := strings.Split(string(token), ".")
+	if len(parts) != 5 {
+		return fmt.Errorf("encoded JWE must have 5 sections, found %d", len(parts))
+	}
+
+	bs, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return fmt.Errorf("bad JWE header: %v", err)
+	}
+	header, err := extractJSONObject(string(bs))
+	if err != nil {
+		return fmt.Errorf("bad JWE header: %v", err)
+	}
+	if header.Get(ast.InternedTerm("enc")) == nil {
+		return errors.New("bad JWE header: missing enc")
+	}
+
+	set, err := jwk.ParseString(string(keys))
+	if err != nil {
+		return fmt.Errorf("failed to parse a JWK key (set): %w", err)
+	}
+
+	// Keys are tried in order if the token doesn't name one by kid.
+	payload, err := jwe.Decrypt([]byte(token), jwe.WithKeySet(set, jwe.WithRequireKid(false)))
+	if err != nil {
+		return fmt.Errorf("failed to decrypt JWE: %w", err)
+	}
+
+	return iter(ast.ArrayTerm(ast.NewTerm(header), ast.StringTerm(string(payload))))
+}
+
+// Implements parsing of JWKs, and of PEM encoded keys and certificates.
+func builtinJWKParse(_ BuiltinContext, operands []*ast.Term, iter func(*ast.Term) error) error {
+	s, err := builtins.StringOperand(operands[0].Value, 1)
+	if err != nil {
+		return err
+	}
+
+	key, err := jwk.ParseKey([]byte(s), jwk.WithPEM(strings.HasPrefix(strings.TrimSpace(string(s)), "-----")))
+	if err != nil {
+		return fmt.Errorf("failed to parse a JWK key: %w", err)
+	}
+
+	v, err := jwkToValue(key)
+	if err != nil {
+		return err
+	}
+
+	return iter(ast.NewTerm(v))
+}
+
+// Implements RFC7638 JWK thumbprints.
+func builtinJWKThumbprint(_ BuiltinContext, operands []*ast.Term, iter func(*ast.Term) error) error {
+	bs, err := jwkOperand(operands[0].Value, 1)
+	if err != nil {
+		return err
+	}
+
+	key, err := jwk.ParseKey(bs)
+	if err != nil {
+		return fmt.Errorf("failed to parse a JWK key: %w", err)
+	}
+
+	thumbprint, err := key.Thumbprint(crypto.SHA256)
+	if err != nil {
+		return err
+	}
+
+	return iter(ast.StringTerm(base64.RawURLEncoding.EncodeToString(thumbprint)))
+}
+
+// Implements selection of a key from a JWKS.
+func builtinJWKSSelect(_ BuiltinContext, operands []*ast.Term, iter func(*ast.Term) error) error {
+	bs, err := jwkOperand(operands[0].Value, 1)
+	if err != nil {
+		return err
+	}
+
+	criteria, err := builtins.ObjectOperand(operands[1].Value, 2)
+	if err != nil {
+		return err
+	}
+
+	var kid, alg, use string
+	if err := criteria.Iter(func(k, v *ast.Term) error {
+		name, err := builtins.StringOperand(k.Value, 2)
+		if err != nil {
+			return err
+		}
+		var where *string
+		switch name {
+		case "kid":
+			where = &kid
+		case "alg":
+			where = &alg
+		case "use":
+			where = &use
+		default:
+			return fmt.Errorf("unknown JWKS selection criteria: %s", string(name))
+		}
+		return tokenConstraintString(string(name), v.Value, where)
+	}); err != nil {
+		return err
+	}
+
+	set, err := jwk.Parse(bs)
+	if err != nil {
+		return fmt.Errorf("failed to parse a JWK key set: %w", err)
+	}
+
+	for i := range set.Len() {
+		key, ok := set.Key(i)
+		if !ok || !jwkMatches(key, kid, alg, use) {
+			continue
+		}
+
+		v, err := jwkToValue(key)
+		if err != nil {
+			return err
+		}
+		return iter(ast.NewTerm(v))
+	}
+
+	return nil
+}
+
+func jwkMatches(key jwk.Key, kid, alg, use string) bool {
+	if kid != "" {
+		if k, ok := key.KeyID(); !ok || k != kid {
+			return false
+		}
+	}
+
+	if use != "" {
+		if u, ok := key.KeyUsage(); ok && u != use {
+			return false
+		}
+	}
+
+	if alg != "" {
+		if a, ok := key.Algorithm(); ok {
+			return a.String() == alg
+		}
+		return jwkTypeOfAlgorithm(alg) == key.KeyType().String()
+	}
+
+	return true
+}
+
+// jwkTypeOfAlgorithm returns the key type (kty) of the keys used with the
+// JWS or JWE algorithm.
+func jwkTypeOfAlgorithm(alg string) string {
+	switch {
+	case strings.HasPrefix(alg, "RS"), strings.HasPrefix(alg, "PS"), strings.HasPrefix(alg, "RSA"):
+		return "RSA"
+	case strings.HasPrefix(alg, "ES"), strings.HasPrefix(alg, "ECDH-ES"):
+		return "EC"
+	case alg == "EdDSA", alg == "Ed25519", alg == "Ed448":
+		return "OKP"
+	case strings.HasPrefix(alg, "HS"), strings.HasPrefix(alg, "A"), strings.HasPrefix(alg, "PBES2"), alg == "dir":
+		return "oct"
+	}
+	return ""
+}
+
+// jwkOperand returns the JSON encoding of a JWK or JWKS operand, which is
+// either a string or an object.
+func jwkOperand(v ast.Value, pos int) ([]byte, error) {
+	switch v := v.(type) {
+	case ast.String:
+		return []byte(v), nil
+	case ast.Object:
+		x, err := ast.JSON(v)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(x)
+	}
+	return nil, builtins.NewOperandTypeErr(pos, v, "string", "object")
+}
+
+func jwkToValue(key jwk.Key) (ast.Value, error) {
+	bs, err := json.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+	return ast.ValueFromReader(bytes.NewReader(bs))
+}
+
+func init() {
+	RegisterBuiltinFunc(ast.JWEDecrypt.Name, builtinJWEDecrypt)
+	RegisterBuiltinFunc(ast.JWKParse.Name, builtinJWKParse)
+	RegisterBuiltinFunc(ast.JWKThumbprint.Name, builtinJWKThumbprint)
+	RegisterBuiltinFunc(ast.JWKSSelect.Name, builtinJWKSSelect)
+}
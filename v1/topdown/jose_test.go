@@ -0,0 +1,240 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package topdown
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"testing"
+
+	"github.com/lestrrat-go/jwx/v3/jwa"
+	"github.com/lestrrat-go/jwx/v3/jwe"
+	"github.com/lestrrat-go/jwx/v3/jwk"
+)
+
+func TestTopDownJWEDecrypt(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rsaJWK := mustJWK(t, rsaKey, "rsa-1")
+	ecJWK := mustJWK(t, ecKey, "ec-1")
+	jwks := fmt.Sprintf(`{"keys": [%s, %s]}`, rsaJWK, ecJWK)
+
+	rsaToken, err := jwe.Encrypt([]byte("secret payload"), jwe.WithKey(jwa.RSA_OAEP_256(), &rsaKey.PublicKey), jwe.WithContentEncryption(jwa.A256GCM()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecToken, err := jwe.Encrypt([]byte(`{"sub":"alice"}`), jwe.WithKey(jwa.ECDH_ES_A128KW(), &ecKey.PublicKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherJWK := mustJWK(t, otherKey, "other")
+
+	tests := []struct {
+		note     string
+		rules    []string
+		expected any
+	}{
+		{
+			note:     "rsa key",
+			rules:    []string{fmt.Sprintf(`p = [h.alg, h.enc, x] { [h, x] := io.jwe.decrypt(%q, %q) }`, rsaToken, rsaJWK)},
+			expected: `["RSA-OAEP-256", "A256GCM", "secret payload"]`,
+		},
+		{
+			note:     "key set",
+			rules:    []string{fmt.Sprintf(`p = json.unmarshal(x) { [_, x] := io.jwe.decrypt(%q, %q) }`, ecToken, jwks)},
+			expected: `{"sub": "alice"}`,
+		},
+		{
+			note:     "wrong key",
+			rules:    []string{fmt.Sprintf(`p = x { [_, x] := io.jwe.decrypt(%q, %q) }`, rsaToken, otherJWK)},
+			expected: &Error{Code: BuiltinErr, Message: "failed to decrypt JWE"},
+		},
+		{
+			note:     "not a JWE",
+			rules:    []string{fmt.Sprintf(`p = x { [_, x] := io.jwe.decrypt("a.b.c", %q) }`, rsaJWK)},
+			expected: &Error{Code: BuiltinErr, Message: "encoded JWE must have 5 sections, found 3"},
+		},
+		{
+			note:     "bad key",
+			rules:    []string{fmt.Sprintf(`p = x { [_, x] := io.jwe.decrypt(%q, "{}") }`, rsaToken)},
+			expected: &Error{Code: BuiltinErr, Message: "failed to parse a JWK key (set)"},
+		},
+	}
+
+	for _, tc := range tests {
+		runTopDownTestCase(t, map[string]any{}, tc.note, tc.rules, tc.expected)
+	}
+}
+
+func TestTopDownJWKParse(t *testing.T) {
+	t.Parallel()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pemKey := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+	pub, err := jwk.Import(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp, err := json.Marshal(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		note     string
+		rules    []string
+		expected any
+	}{
+		{
+			note:     "pem public key",
+			rules:    []string{fmt.Sprintf(`p = x { x := io.jwk.parse(%q) }`, pemKey)},
+			expected: string(exp),
+		},
+		{
+			note:     "jwk",
+			rules:    []string{`p = x { x := io.jwk.parse("{\"kty\": \"oct\", \"k\": \"c2VjcmV0\", \"kid\": \"k1\"}") }`},
+			expected: `{"kty": "oct", "k": "c2VjcmV0", "kid": "k1"}`,
+		},
+		{
+			note:     "invalid",
+			rules:    []string{`p = x { x := io.jwk.parse("{\"kty\": \"foo\"}") }`},
+			expected: &Error{Code: BuiltinErr, Message: "failed to parse a JWK key"},
+		},
+	}
+
+	for _, tc := range tests {
+		runTopDownTestCase(t, map[string]any{}, tc.note, tc.rules, tc.expected)
+	}
+}
+
+func TestTopDownJWKThumbprint(t *testing.T) {
+	t.Parallel()
+
+	// RFC 7638, section 3.1
+	key := `{"kty": "RSA", "n": "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw", "e": "AQAB", "alg": "RS256", "kid": "2011-04-29"}`
+	thumbprint := `"NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"`
+
+	tests := []struct {
+		note     string
+		rules    []string
+		expected any
+	}{
+		{
+			note:     "string",
+			rules:    []string{fmt.Sprintf(`p = x { x := io.jwk.thumbprint(%q) }`, key)},
+			expected: thumbprint,
+		},
+		{
+			note:     "object",
+			rules:    []string{fmt.Sprintf(`p = x { x := io.jwk.thumbprint(%s) }`, key)},
+			expected: thumbprint,
+		},
+		{
+			note:     "invalid",
+			rules:    []string{`p = x { x := io.jwk.thumbprint({"kty": "RSA"}) }`},
+			expected: &Error{Code: BuiltinErr, Message: "failed to parse a JWK key"},
+		},
+	}
+
+	for _, tc := range tests {
+		runTopDownTestCase(t, map[string]any{}, tc.note, tc.rules, tc.expected)
+	}
+}
+
+func TestTopDownJWKSSelect(t *testing.T) {
+	t.Parallel()
+
+	jwks := `{"keys": [` +
+		`{"kty": "oct", "kid": "a", "k": "c2VjcmV0"}, ` +
+		`{"kty": "oct", "kid": "b", "alg": "HS256", "use": "sig", "k": "c2VjcmV0"}, ` +
+		`{"kty": "oct", "kid": "b", "alg": "A128KW", "use": "enc", "k": "c2VjcmV0c2VjcmV0MTI"}` +
+		`]}`
+
+	tests := []struct {
+		note     string
+		rules    []string
+		expected any
+	}{
+		{
+			note:     "kid",
+			rules:    []string{fmt.Sprintf(`p = k.alg { k := io.jwks.select(%s, {"kid": "b"}) }`, jwks)},
+			expected: `"HS256"`,
+		},
+		{
+			note:     "kid and alg",
+			rules:    []string{fmt.Sprintf(`p = k.use { k := io.jwks.select(%q, {"kid": "b", "alg": "A128KW"}) }`, jwks)},
+			expected: `"enc"`,
+		},
+		{
+			note:     "use",
+			rules:    []string{fmt.Sprintf(`p = k.alg { k := io.jwks.select(%s, {"kid": "b", "use": "enc"}) }`, jwks)},
+			expected: `"A128KW"`,
+		},
+		{
+			note:     "alg of key type",
+			rules:    []string{fmt.Sprintf(`p = k.kid { k := io.jwks.select(%s, {"alg": "HS512"}) }`, jwks)},
+			expected: `"a"`,
+		},
+		{
+			note:     "no match",
+			rules:    []string{fmt.Sprintf(`p = x { x := io.jwks.select(%s, {"kid": "c"}) }`, jwks)},
+			expected: "",
+		},
+		{
+			note:     "unknown criteria",
+			rules:    []string{fmt.Sprintf(`p = x { x := io.jwks.select(%s, {"x5t": "c"}) }`, jwks)},
+			expected: &Error{Code: BuiltinErr, Message: "unknown JWKS selection criteria: x5t"},
+		},
+	}
+
+	for _, tc := range tests {
+		runTopDownTestCase(t, map[string]any{}, tc.note, tc.rules, tc.expected)
+	}
+}
+
+func mustJWK(t *testing.T, raw any, kid string) string {
+	t.Helper()
+
+	key, err := jwk.Import(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := key.Set(jwk.KeyIDKey, kid); err != nil {
+		t.Fatal(err)
+	}
+	bs, err := json.Marshal(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(bs)
+}
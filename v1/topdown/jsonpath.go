@@ -0,0 +1,252 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package topdown
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/jmespath/go-jmespath"
+
+	"github.com/open-policy-agent/opa/internal/jsonpath"
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/topdown/builtins"
+	"github.com/open-policy-agent/opa/v1/topdown/cache"
+)
+
+const (
+	jsonPathCacheName                = "jsonpath"
+	jsonPathCacheMaxSize             = 100
+	jsonPathInterQueryValueCacheHits = "rego_builtin_jsonpath_interquery_value_cache_hits"
+	jmesPathCacheName                = "jmespath"
+	jmesPathCacheMaxSize             = 100
+	jmesPathInterQueryValueCacheHits = "rego_builtin_jmespath_interquery_value_cache_hits"
+)
+
+var (
+	jsonPathCacheLock = sync.RWMutex{}
+	jsonPathCache     = make(map[string]*jsonpath.Query)
+	jmesPathCacheLock = sync.RWMutex{}
+	jmesPathCache     = make(map[string]*jmespath.JMESPath)
+)
+
+func builtinJSONPathQuery(bctx BuiltinContext, operands []*ast.Term, iter func(*ast.Term) error) error {
+	expr, err := builtins.StringOperand(operands[1].Value, 2)
+	if err != nil {
+		return err
+	}
+
+	q, err := getJSONPath(bctx, string(expr))
+	if err != nil {
+		return err
+	}
+
+	nodes := q.Select(operands[0].Value)
+	terms := make([]*ast.Term, len(nodes))
+	for i, node := range nodes {
+		terms[i] = ast.NewTerm(node)
+	}
+	return iter(ast.ArrayTerm(terms...))
+}
+
+func getJSONPath(bctx BuiltinContext, expr string) (*jsonpath.Query, error) {
+	var c cache.InterQueryValueCacheBucket
+	if bctx.InterQueryBuiltinValueCache != nil {
+		c = bctx.InterQueryBuiltinValueCache.GetCache(jsonPathCacheName)
+	}
+
+	if c != nil {
+		key := ast.String(expr)
+		if val, ok := c.Get(key); ok {
+			if q, ok := val.(*jsonpath.Query); ok {
+				bctx.Metrics.Counter(jsonPathInterQueryValueCacheHits).Incr()
+				return q, nil
+			}
+		}
+
+		q, err := jsonpath.Parse(expr)
+		if err != nil {
+			return nil, err
+		}
+		c.Insert(key, q)
+		return q, nil
+	}
+
+	jsonPathCacheLock.RLock()
+	q, ok := jsonPathCache[expr]
+	jsonPathCacheLock.RUnlock()
+	if !ok {
+		var err error
+		q, err = jsonpath.Parse(expr)
+		if err != nil {
+			return nil, err
+		}
+
+		jsonPathCacheLock.Lock()
+		if len(jsonPathCache) >= jsonPathCacheMaxSize {
+			// Delete a (semi-)random key to make room for the new one.
+			for k := range jsonPathCache {
+				delete(jsonPathCache, k)
+				break
+			}
+		}
+		jsonPathCache[expr] = q
+		jsonPathCacheLock.Unlock()
+	}
+	return q, nil
+}
+
+func builtinJMESPathQuery(bctx BuiltinContext, operands []*ast.Term, iter func(*ast.Term) error) error {
+	expr, err := builtins.StringOperand(operands[1].Value, 2)
+	if err != nil {
+		return err
+	}
+
+	jp, err := getJMESPath(bctx, string(expr))
+	if err != nil {
+		return err
+	}
+
+	doc, err := jmesPathValue(operands[0].Value)
+	if err != nil {
+		return err
+	}
+
+	x, err := jp.Search(doc)
+	if err != nil {
+		return fmt.Errorf("jmespath: %w", err)
+	}
+
+	v, err := ast.InterfaceToValue(x)
+	if err != nil {
+		return err
+	}
+	return iter(ast.NewTerm(v))
+}
+
+func getJMESPath(bctx BuiltinContext, expr string) (*jmespath.JMESPath, error) {
+	var c cache.InterQueryValueCacheBucket
+	if bctx.InterQueryBuiltinValueCache != nil {
+		c = bctx.InterQueryBuiltinValueCache.GetCache(jmesPathCacheName)
+	}
+
+	if c != nil {
+		key := ast.String(expr)
+		if val, ok := c.Get(key); ok {
+			if jp, ok := val.(*jmespath.JMESPath); ok {
+				bctx.Metrics.Counter(jmesPathInterQueryValueCacheHits).Incr()
+				return jp, nil
+			}
+		}
+
+		jp, err := compileJMESPath(expr)
+		if err != nil {
+			return nil, err
+		}
+		c.Insert(key, jp)
+		return jp, nil
+	}
+
+	jmesPathCacheLock.RLock()
+	jp, ok := jmesPathCache[expr]
+	jmesPathCacheLock.RUnlock()
+	if !ok {
+		var err error
+		jp, err = compileJMESPath(expr)
+		if err != nil {
+			return nil, err
+		}
+
+		jmesPathCacheLock.Lock()
+		if len(jmesPathCache) >= jmesPathCacheMaxSize {
+			// Delete a (semi-)random key to make room for the new one.
+			for k := range jmesPathCache {
+				delete(jmesPathCache, k)
+				break
+			}
+		}
+		jmesPathCache[expr] = jp
+		jmesPathCacheLock.Unlock()
+	}
+	return jp, nil
+}
+
+func compileJMESPath(expr string) (*jmespath.JMESPath, error) {
+	jp, err := jmespath.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("jmespath: %w", err)
+	}
+	return jp, nil
+}
+
+// jmesPathValue converts v to the representation go-jmespath operates on,
+// where all numbers are float64. Sets are converted to arrays.
+func jmesPathValue(v ast.Value) (any, error) {
+	switch v := v.(type) {
+	case ast.Null:
+		return nil, nil
+	case ast.Boolean:
+		return bool(v), nil
+	case ast.Number:
+		f, ok := v.Float64()
+		if !ok {
+			return nil, fmt.Errorf("jmespath: number %v out of range", v)
+		}
+		return f, nil
+	case ast.String:
+		return string(v), nil
+	case *ast.Array:
+		arr := make([]any, v.Len())
+		for i := range v.Len() {
+			x, err := jmesPathValue(v.Elem(i).Value)
+			if err != nil {
+				return nil, err
+			}
+			arr[i] = x
+		}
+		return arr, nil
+	case ast.Set:
+		arr := make([]any, 0, v.Len())
+		err := v.Iter(func(t *ast.Term) error {
+			x, err := jmesPathValue(t.Value)
+			if err != nil {
+				return err
+			}
+			arr = append(arr, x)
+			return nil
+		})
+		return arr, err
+	case ast.Object:
+		obj := make(map[string]any, v.Len())
+		err := v.Iter(func(k, t *ast.Term) error {
+			key, ok := k.Value.(ast.String)
+			if !ok {
+				return fmt.Errorf("jmespath: object key %v is not a string", k)
+			}
+			x, err := jmesPathValue(t.Value)
+			if err != nil {
+				return err
+			}
+			obj[string(key)] = x
+			return nil
+		})
+		return obj, err
+	}
+	return nil, builtins.NewOperandTypeErr(1, v, "null", "boolean", "number", "string", "array", "set", "object")
+}
+
+func init() {
+	jsonPathCacheEntries := jsonPathCacheMaxSize
+	cache.RegisterDefaultInterQueryBuiltinValueCacheConfig(jsonPathCacheName, &cache.NamedValueCacheConfig{
+		MaxNumEntries: &jsonPathCacheEntries,
+	})
+	jmesPathCacheEntries := jmesPathCacheMaxSize
+	cache.RegisterDefaultInterQueryBuiltinValueCacheConfig(jmesPathCacheName, &cache.NamedValueCacheConfig{
+		MaxNumEntries: &jmesPathCacheEntries,
+	})
+
+	RegisterBuiltinFunc(ast.JSONPathQuery.Name, builtinJSONPathQuery)
+	RegisterBuiltinFunc(ast.JMESPathQuery.Name, builtinJMESPathQuery)
+}
@@ -0,0 +1,188 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package topdown
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/metrics"
+	"github.com/open-policy-agent/opa/v1/topdown/cache"
+)
+
+func TestJSONPathBuiltinCache(t *testing.T) {
+	t.Parallel()
+
+	ctx := BuiltinContext{}
+	iter := func(*ast.Term) error { return nil }
+	doc := ast.MustParseTerm(`{"foo": {"bar": 1}}`)
+
+	// A novel query is cached.
+	query1 := "$.foo.bar"
+	if err := builtinJSONPathQuery(ctx, []*ast.Term{doc, ast.StringTerm(query1)}, iter); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	jsonPathCacheLock.RLock()
+	_, ok := jsonPathCache[query1]
+	jsonPathCacheLock.RUnlock()
+	if !ok {
+		t.Fatalf("Expected query to be cached: %v", query1)
+	}
+
+	// Fill up the cache.
+	for i := range jsonPathCacheMaxSize {
+		query := fmt.Sprintf("$.foo[%d]", i)
+		if err := builtinJSONPathQuery(ctx, []*ast.Term{doc, ast.StringTerm(query)}, iter); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	// A new query is cached and a random query is evicted.
+	query2 := "$..bar"
+	if err := builtinJSONPathQuery(ctx, []*ast.Term{doc, ast.StringTerm(query2)}, iter); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	jsonPathCacheLock.RLock()
+	defer jsonPathCacheLock.RUnlock()
+	if len(jsonPathCache) != jsonPathCacheMaxSize {
+		t.Fatalf("Expected cache be capped at %d, was %d", jsonPathCacheMaxSize, len(jsonPathCache))
+	}
+	if _, ok := jsonPathCache[query2]; !ok {
+		t.Fatalf("Expected query to be cached: %v", query2)
+	}
+}
+
+func TestJMESPathBuiltinCache(t *testing.T) {
+	t.Parallel()
+
+	ctx := BuiltinContext{}
+	iter := func(*ast.Term) error { return nil }
+	doc := ast.MustParseTerm(`{"foo": {"bar": 1}}`)
+
+	// A novel expression is cached.
+	expr1 := "foo.bar"
+	if err := builtinJMESPathQuery(ctx, []*ast.Term{doc, ast.StringTerm(expr1)}, iter); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	jmesPathCacheLock.RLock()
+	_, ok := jmesPathCache[expr1]
+	jmesPathCacheLock.RUnlock()
+	if !ok {
+		t.Fatalf("Expected expression to be cached: %v", expr1)
+	}
+
+	// Fill up the cache.
+	for i := range jmesPathCacheMaxSize {
+		expr := fmt.Sprintf("foo[%d]", i)
+		if err := builtinJMESPathQuery(ctx, []*ast.Term{doc, ast.StringTerm(expr)}, iter); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	// A new expression is cached and a random expression is evicted.
+	expr2 := "foo.*"
+	if err := builtinJMESPathQuery(ctx, []*ast.Term{doc, ast.StringTerm(expr2)}, iter); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	jmesPathCacheLock.RLock()
+	defer jmesPathCacheLock.RUnlock()
+	if len(jmesPathCache) != jmesPathCacheMaxSize {
+		t.Fatalf("Expected cache be capped at %d, was %d", jmesPathCacheMaxSize, len(jmesPathCache))
+	}
+	if _, ok := jmesPathCache[expr2]; !ok {
+		t.Fatalf("Expected expression to be cached: %v", expr2)
+	}
+}
+
+func TestJSONQueryBuiltinsInterQueryValueCache(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		note    string
+		builtin BuiltinFunc
+		expr    string
+		cache   string
+		counter string
+		exp     string
+	}{
+		{
+			note:    "jsonpath",
+			builtin: builtinJSONPathQuery,
+			expr:    "$.foo.bar",
+			cache:   jsonPathCacheName,
+			counter: jsonPathInterQueryValueCacheHits,
+			exp:     `[1]`,
+		},
+		{
+			note:    "jmespath",
+			builtin: builtinJMESPathQuery,
+			expr:    "foo.bar",
+			cache:   jmesPathCacheName,
+			counter: jmesPathInterQueryValueCacheHits,
+			exp:     `1`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			t.Parallel()
+
+			config, err := cache.ParseCachingConfig([]byte(`{"inter_query_builtin_value_cache": {"max_num_entries": 10}}`))
+			if err != nil {
+				t.Fatal(err)
+			}
+			interQueryValueCache := cache.NewInterQueryValueCache(t.Context(), config)
+
+			m := metrics.New()
+			ctx := BuiltinContext{InterQueryBuiltinValueCache: interQueryValueCache, Metrics: m}
+			operands := []*ast.Term{ast.MustParseTerm(`{"foo": {"bar": 1}}`), ast.StringTerm(tc.expr)}
+
+			var result *ast.Term
+			iter := func(t *ast.Term) error {
+				result = t
+				return nil
+			}
+
+			// The expression is compiled and cached on first use, and served
+			// from the cache afterwards.
+			for range 2 {
+				if err := tc.builtin(ctx, operands, iter); err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if exp := ast.MustParseTerm(tc.exp); !exp.Equal(result) {
+					t.Fatalf("Expected %v but got %v", exp, result)
+				}
+			}
+
+			if _, ok := interQueryValueCache.GetCache(tc.cache).Get(ast.String(tc.expr)); !ok {
+				t.Fatalf("Expected expression to be cached: %v", tc.expr)
+			}
+			if hits := m.Counter(tc.counter).Value(); hits != uint64(1) {
+				t.Fatalf("Expected 1 cache hit but got %v", hits)
+			}
+
+			// The expression is not cached in the shared cache, where it could
+			// collide with a compiled regex or glob of the same pattern.
+			if _, ok := interQueryValueCache.Get(ast.String(tc.expr)); ok {
+				t.Fatalf("Expected expression not to be cached in the shared cache: %v", tc.expr)
+			}
+			interQueryValueCache.Insert(ast.String(tc.expr), "bar")
+			if err := tc.builtin(ctx, operands, iter); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if exp := ast.MustParseTerm(tc.exp); !exp.Equal(result) {
+				t.Fatalf("Expected %v but got %v", exp, result)
+			}
+			if hits := m.Counter(tc.counter).Value(); hits != uint64(2) {
+				t.Fatalf("Expected 2 cache hits but got %v", hits)
+			}
+		})
+	}
+}
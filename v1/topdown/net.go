@@ -5,15 +5,40 @@
 package topdown
 
 import (
+	"context"
 	"net"
 	"strings"
+	"time"
 
 	"github.com/open-policy-agent/opa/v1/ast"
 	"github.com/open-policy-agent/opa/v1/topdown/builtins"
+	"github.com/open-policy-agent/opa/v1/topdown/cache"
 )
 
 type lookupIPAddrCacheKey string
 
+type lookupDNSCacheKey struct {
+	builtin string
+	name    string
+}
+
+// dnsInterQueryCacheTTL is how long DNS lookups are kept in the inter-query
+// cache. The resolver doesn't expose the TTLs of the records.
+const dnsInterQueryCacheTTL = time.Minute
+
+type dnsInterQueryCacheValue struct {
+	value     ast.Value
+	expiresAt time.Time
+}
+
+func (v dnsInterQueryCacheValue) SizeInBytes() int64 {
+	return int64(len(v.value.String()))
+}
+
+func (v dnsInterQueryCacheValue) Clone() (cache.InterQueryCacheValue, error) {
+	return v, nil
+}
+
 // resolv is the same as net.DefaultResolver -- this is for mocking it out in tests
 var resolv = &net.Resolver{}
 
@@ -59,6 +84,128 @@ func builtinLookupIPAddr(bctx BuiltinContext, operands []*ast.Term, iter func(*a
 	return iter(t)
 }
 
+func builtinLookupTXT(bctx BuiltinContext, operands []*ast.Term, iter func(*ast.Term) error) error {
+	return lookupDNS(bctx, ast.NetLookupTXT, operands, iter, func(ctx context.Context, name string) (ast.Value, error) {
+		txts, err := resolv.LookupTXT(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+
+		ret := ast.NewSetWithCapacity(len(txts))
+		for _, txt := range txts {
+			ret.Add(ast.StringTerm(txt))
+		}
+		return ret, nil
+	})
+}
+
+func builtinLookupSRV(bctx BuiltinContext, operands []*ast.Term, iter func(*ast.Term) error) error {
+	return lookupDNS(bctx, ast.NetLookupSRV, operands, iter, func(ctx context.Context, name string) (ast.Value, error) {
+		_, srvs, err := resolv.LookupSRV(ctx, "", "", name)
+		if err != nil {
+			return nil, err
+		}
+
+		ret := ast.NewSetWithCapacity(len(srvs))
+		for _, srv := range srvs {
+			ret.Add(ast.ObjectTerm(
+				ast.Item(ast.InternedTerm("target"), ast.StringTerm(srv.Target)),
+				ast.Item(ast.InternedTerm("port"), ast.InternedTerm(int(srv.Port))),
+				ast.Item(ast.InternedTerm("priority"), ast.InternedTerm(int(srv.Priority))),
+				ast.Item(ast.InternedTerm("weight"), ast.InternedTerm(int(srv.Weight))),
+			))
+		}
+		return ret, nil
+	})
+}
+
+func builtinLookupMX(bctx BuiltinContext, operands []*ast.Term, iter func(*ast.Term) error) error {
+	return lookupDNS(bctx, ast.NetLookupMX, operands, iter, func(ctx context.Context, name string) (ast.Value, error) {
+		mxs, err := resolv.LookupMX(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+
+		ret := ast.NewSetWithCapacity(len(mxs))
+		for _, mx := range mxs {
+			ret.Add(ast.ObjectTerm(
+				ast.Item(ast.InternedTerm("host"), ast.StringTerm(mx.Host)),
+				ast.Item(ast.InternedTerm("pref"), ast.InternedTerm(int(mx.Pref))),
+			))
+		}
+		return ret, nil
+	})
+}
+
+func builtinLookupCNAME(bctx BuiltinContext, operands []*ast.Term, iter func(*ast.Term) error) error {
+	return lookupDNS(bctx, ast.NetLookupCNAME, operands, iter, func(ctx context.Context, name string) (ast.Value, error) {
+		cname, err := resolv.LookupCNAME(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		return ast.String(cname), nil
+	})
+}
+
+// lookupDNS implements the DNS record lookups: the name is checked against
+// the allow_net capability, and results are cached for the query, and for a
+// minute across queries if the inter-query cache is enabled.
+func lookupDNS(bctx BuiltinContext, builtin *ast.Builtin, operands []*ast.Term, iter func(*ast.Term) error, lookup func(context.Context, string) (ast.Value, error)) error {
+	a, err := builtins.StringOperand(operands[0].Value, 1)
+	if err != nil {
+		return err
+	}
+	name := string(a)
+
+	err = verifyHost(bctx.Capabilities, name)
+	if err != nil {
+		return err
+	}
+
+	key := lookupDNSCacheKey{builtin: builtin.Name, name: name}
+	if val, ok := bctx.Cache.Get(key); ok {
+		return iter(val.(*ast.Term))
+	}
+
+	interQueryKey := ast.NewArray(ast.StringTerm(builtin.Name), ast.StringTerm(name))
+	if bctx.InterQueryBuiltinCache != nil {
+		if val, ok := bctx.InterQueryBuiltinCache.Get(interQueryKey); ok {
+			if v, ok := val.(dnsInterQueryCacheValue); ok && getCurrentTime(bctx.Time).Before(v.expiresAt) {
+				t := ast.NewTerm(v.value)
+				bctx.Cache.Put(key, t)
+				return iter(t)
+			}
+		}
+	}
+
+	v, err := lookup(bctx.Context, name)
+	if err != nil {
+		// NOTE(sr): We can't do better than this right now, see https://github.com/golang/go/issues/36208
+		if strings.Contains(err.Error(), "operation was canceled") || strings.Contains(err.Error(), "i/o timeout") {
+			return Halt{
+				Err: &Error{
+					Code:     CancelErr,
+					Message:  builtin.Name + ": " + err.Error(),
+					Location: bctx.Location,
+				},
+			}
+		}
+		return err
+	}
+
+	t := ast.NewTerm(v)
+	bctx.Cache.Put(key, t)
+	if bctx.InterQueryBuiltinCache != nil {
+		expiresAt := getCurrentTime(bctx.Time).Add(dnsInterQueryCacheTTL)
+		bctx.InterQueryBuiltinCache.InsertWithExpiry(interQueryKey, dnsInterQueryCacheValue{value: v, expiresAt: expiresAt}, expiresAt)
+	}
+	return iter(t)
+}
+
 func init() {
 	RegisterBuiltinFunc(ast.NetLookupIPAddr.Name, builtinLookupIPAddr)
+	RegisterBuiltinFunc(ast.NetLookupTXT.Name, builtinLookupTXT)
+	RegisterBuiltinFunc(ast.NetLookupSRV.Name, builtinLookupSRV)
+	RegisterBuiltinFunc(ast.NetLookupMX.Name, builtinLookupMX)
+	RegisterBuiltinFunc(ast.NetLookupCNAME.Name, builtinLookupCNAME)
 }
@@ -10,6 +10,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"testing"
 	"time"
 
@@ -17,6 +18,7 @@ import (
 
 	"github.com/open-policy-agent/opa/v1/ast"
 	"github.com/open-policy-agent/opa/v1/topdown/builtins"
+	"github.com/open-policy-agent/opa/v1/topdown/cache"
 )
 
 // TestNetLookupIPAddr replaces the resolver used by builtinLookupIPAddr.
@@ -216,6 +218,196 @@ func TestNetLookupIPAddr(t *testing.T) {
 	}
 }
 
+// TestNetLookupDNS isn't run in parallel, as it replaces the resolver used by
+// TestNetLookupIPAddr, too.
+func TestNetLookupDNS(t *testing.T) {
+	srv, err := mockdns.NewServerWithLogger(map[string]mockdns.Zone{
+		"example.org.": {
+			TXT: []string{"v=spf1 -all", "verification=abc"},
+			MX: []net.MX{
+				{Host: "mx1.example.org.", Pref: 10},
+				{Host: "mx2.example.org.", Pref: 20},
+			},
+		},
+		"_sip._tcp.example.org.": {
+			// NOTE: the mock server doesn't serve the weight of SRV records
+			SRV: []net.SRV{
+				{Target: "sip.example.org.", Port: 5060, Priority: 10},
+			},
+		},
+		"www.example.org.": {
+			CNAME: "web.example.org.",
+		},
+		"web.example.org.": {
+			A: []string{"1.2.3.4"},
+		},
+		"error.org.": {
+			Err: errors.New("OH NO"),
+		},
+	}, sink{}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { srv.Close() })
+
+	srvFail, err := mockdns.NewServerWithLogger(map[string]mockdns.Zone{}, sink{}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { srvFail.Close() })
+	t.Cleanup(func() { mockdns.UnpatchNet(resolv) })
+
+	tests := []struct {
+		note    string
+		builtin *ast.Builtin
+		f       BuiltinFunc
+		name    string
+		exp     string
+	}{
+		{
+			note:    "txt",
+			builtin: ast.NetLookupTXT,
+			f:       builtinLookupTXT,
+			name:    "example.org",
+			exp:     `{"v=spf1 -all", "verification=abc"}`,
+		},
+		{
+			note:    "srv",
+			builtin: ast.NetLookupSRV,
+			f:       builtinLookupSRV,
+			name:    "_sip._tcp.example.org",
+			exp:     `{{"target": "sip.example.org.", "port": 5060, "priority": 10, "weight": 0}}`,
+		},
+		{
+			note:    "mx",
+			builtin: ast.NetLookupMX,
+			f:       builtinLookupMX,
+			name:    "example.org",
+			exp:     `{{"host": "mx1.example.org.", "pref": 10}, {"host": "mx2.example.org.", "pref": 20}}`,
+		},
+		{
+			note:    "cname",
+			builtin: ast.NetLookupCNAME,
+			f:       builtinLookupCNAME,
+			name:    "www.example.org",
+			exp:     `"web.example.org."`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.note, func(t *testing.T) {
+			exp := ast.MustParseTerm(tc.exp)
+			check := func(act *ast.Term) error {
+				if !exp.Equal(act) {
+					t.Errorf("expected %v, got %v", exp, act)
+				}
+				return nil
+			}
+
+			config, _ := cache.ParseCachingConfig(nil)
+			interQueryCache := cache.NewInterQueryCache(config)
+			bctx := BuiltinContext{
+				Context:                t.Context(),
+				Cache:                  make(builtins.Cache),
+				InterQueryBuiltinCache: interQueryCache,
+				Time:                   ast.InternedTerm(time.Now().UnixNano()),
+			}
+			srv.PatchNet(resolv)
+			if err := tc.f(bctx, []*ast.Term{ast.StringTerm(tc.name)}, check); err != nil {
+				t.Fatal(err)
+			}
+
+			// check cache put
+			act, ok := bctx.Cache.Get(lookupDNSCacheKey{builtin: tc.builtin.Name, name: tc.name})
+			if !ok {
+				t.Fatal("result not put into cache")
+			}
+			if !exp.Equal(act.(*ast.Term)) {
+				t.Errorf("cache: expected %v, got %v", exp, act)
+			}
+
+			// exercise intra-query cache hit
+			srvFail.PatchNet(resolv)
+			if err := tc.f(bctx, []*ast.Term{ast.StringTerm(tc.name)}, check); err != nil {
+				t.Fatal(err)
+			}
+
+			// exercise inter-query cache hit
+			bctx.Cache = make(builtins.Cache)
+			if err := tc.f(bctx, []*ast.Term{ast.StringTerm(tc.name)}, check); err != nil {
+				t.Fatal(err)
+			}
+
+			// expired inter-query cache entries aren't used
+			bctx.Cache = make(builtins.Cache)
+			bctx.Time = ast.InternedTerm(time.Now().Add(2 * dnsInterQueryCacheTTL).UnixNano())
+			err := tc.f(bctx, []*ast.Term{ast.StringTerm(tc.name)}, func(*ast.Term) error {
+				t.Fatal("expected not to be called")
+				return nil
+			})
+			if err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+
+	t.Run("error", func(t *testing.T) {
+		for _, name := range []string{"error.org", "nosuch.org"} {
+			bctx := BuiltinContext{
+				Context: t.Context(),
+				Cache:   make(builtins.Cache),
+			}
+			srv.PatchNet(resolv)
+			err := builtinLookupTXT(bctx, []*ast.Term{ast.StringTerm(name)}, func(*ast.Term) error {
+				t.Fatal("expected not to be called")
+				return nil
+			})
+			if err == nil {
+				t.Errorf("%s: expected error", name)
+			}
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+		bctx := BuiltinContext{
+			Context: ctx,
+			Cache:   make(builtins.Cache),
+		}
+		srv.PatchNet(resolv)
+		err := builtinLookupMX(bctx, []*ast.Term{ast.StringTerm("example.org")}, func(*ast.Term) error {
+			t.Fatal("expected not to be called")
+			return nil
+		})
+		if _, ok := err.(Halt); !ok {
+			t.Errorf("expected Halt error, got %v (%[1]T)", err)
+		}
+		if !IsCancel(err) {
+			t.Errorf("expected wrapped Cancel error, got %v (%[1]T)", err)
+		}
+	})
+
+	t.Run("allow_net", func(t *testing.T) {
+		capabilities := ast.CapabilitiesForThisVersion()
+		capabilities.AllowNet = []string{"example.org"}
+		bctx := BuiltinContext{
+			Context:      t.Context(),
+			Cache:        make(builtins.Cache),
+			Capabilities: capabilities,
+		}
+		srv.PatchNet(resolv)
+		if err := builtinLookupTXT(bctx, []*ast.Term{ast.StringTerm("example.org")}, func(*ast.Term) error { return nil }); err != nil {
+			t.Error(err)
+		}
+		err := builtinLookupCNAME(bctx, []*ast.Term{ast.StringTerm("www.example.org")}, func(*ast.Term) error {
+			t.Fatal("expected not to be called")
+			return nil
+		})
+		assertError(t, errors.New("disallowed host: www.example.org"), err)
+	})
+}
+
 type sink struct{}
 
 func (sink) Printf(string, ...any) {}
@@ -631,6 +631,9 @@ func (q *Query) Iter(ctx context.Context, iter func(QueryResult) error) error {
 		responseMetadata:            q.responseMetadata,
 		evaluated:                   q.evaluated,
 	}
+	if a, ok := DecisionLogAnnotationsFromContext(ctx); ok {
+		e.decisionLogAnnotations = a
+	}
 	if e.requestMetadata == nil {
 		e.requestMetadata = map[string]any{}
 	}
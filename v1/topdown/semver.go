@@ -44,7 +44,85 @@ func builtinSemVerIsValid(_ BuiltinContext, operands []*ast.Term, iter func(*ast
 	return iter(ast.InternedTerm(err == nil))
 }
 
+func builtinSemVerSatisfies(_ BuiltinContext, operands []*ast.Term, iter func(*ast.Term) error) error {
+	versionString, err := builtins.StringOperand(operands[0].Value, 1)
+	if err != nil {
+		return err
+	}
+
+	constraint, err := semVerConstraintOperand(operands[1].Value, 2)
+	if err != nil {
+		return err
+	}
+
+	version, err := semver.Parse(string(versionString))
+	if err != nil {
+		return fmt.Errorf("operand 1: string %s is not a valid SemVer", versionString)
+	}
+
+	return iter(ast.InternedTerm(constraint.Check(version)))
+}
+
+func builtinSemVerMaxSatisfying(_ BuiltinContext, operands []*ast.Term, iter func(*ast.Term) error) error {
+	constraint, err := semVerConstraintOperand(operands[1].Value, 2)
+	if err != nil {
+		return err
+	}
+
+	var best *ast.Term
+	var bestVersion semver.Version
+
+	check := func(t *ast.Term) error {
+		s, err := builtins.StringOperand(t.Value, 1)
+		if err != nil {
+			return err
+		}
+		version, err := semver.Parse(string(s))
+		if err != nil || !constraint.Check(version) {
+			return nil
+		}
+		if best == nil || version.Compare(bestVersion) > 0 {
+			best, bestVersion = t, version
+		}
+		return nil
+	}
+
+	switch versions := operands[0].Value.(type) {
+	case *ast.Array:
+		err = versions.Iter(check)
+	case ast.Set:
+		err = versions.Iter(check)
+	default:
+		return builtins.NewOperandTypeErr(1, operands[0].Value, "array", "set")
+	}
+	if err != nil {
+		return err
+	}
+
+	if best == nil {
+		return nil
+	}
+
+	return iter(best)
+}
+
+func semVerConstraintOperand(v ast.Value, pos int) (semver.Constraint, error) {
+	s, err := builtins.StringOperand(v, pos)
+	if err != nil {
+		return semver.Constraint{}, err
+	}
+
+	c, err := semver.ParseConstraint(string(s))
+	if err != nil {
+		return semver.Constraint{}, fmt.Errorf("operand %d: %w", pos, err)
+	}
+
+	return c, nil
+}
+
 func init() {
 	RegisterBuiltinFunc(ast.SemVerCompare.Name, builtinSemVerCompare)
 	RegisterBuiltinFunc(ast.SemVerIsValid.Name, builtinSemVerIsValid)
+	RegisterBuiltinFunc(ast.SemVerSatisfies.Name, builtinSemVerSatisfies)
+	RegisterBuiltinFunc(ast.SemVerMaxSatisfying.Name, builtinSemVerMaxSatisfying)
 }
@@ -0,0 +1,64 @@
+// Copyright 2026 The OPA Authors.  All rights reserved.
+// Use of this source code is governed by an Apache2
+// license that can be found in the LICENSE file.
+
+package topdown
+
+import (
+	"github.com/open-policy-agent/opa/internal/sqlparse"
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/topdown/builtins"
+)
+
+func builtinSQLParse(_ BuiltinContext, operands []*ast.Term, iter func(*ast.Term) error) error {
+	dialect, err := sqlDialectOperand(operands[0].Value, 1)
+	if err != nil {
+		return err
+	}
+	statement, err := builtins.StringOperand(operands[1].Value, 2)
+	if err != nil {
+		return err
+	}
+
+	stmt, err := sqlparse.Parse(dialect, string(statement))
+	if err != nil {
+		return err
+	}
+
+	v, err := ast.InterfaceToValue(stmt)
+	if err != nil {
+		return err
+	}
+	return iter(ast.NewTerm(v))
+}
+
+func builtinSQLIsValid(_ BuiltinContext, operands []*ast.Term, iter func(*ast.Term) error) error {
+	dialect, err := sqlDialectOperand(operands[0].Value, 1)
+	if err != nil {
+		return iter(ast.InternedTerm(false))
+	}
+	statement, err := builtins.StringOperand(operands[1].Value, 2)
+	if err != nil {
+		return iter(ast.InternedTerm(false))
+	}
+
+	_, err = sqlparse.Parse(dialect, string(statement))
+	return iter(ast.InternedTerm(err == nil))
+}
+
+func sqlDialectOperand(x ast.Value, pos int) (sqlparse.Dialect, error) {
+	name, err := builtins.StringOperand(x, pos)
+	if err != nil {
+		return 0, err
+	}
+	dialect, ok := sqlparse.ParseDialect(string(name))
+	if !ok {
+		return 0, builtins.NewOperandErr(pos, "unknown dialect %q, must be one of {mysql, postgresql}", string(name))
+	}
+	return dialect, nil
+}
+
+func init() {
+	RegisterBuiltinFunc(ast.SQLParse.Name, builtinSQLParse)
+	RegisterBuiltinFunc(ast.SQLIsValid.Name, builtinSQLIsValid)
+}
@@ -16,6 +16,7 @@ import (
 	"time"
 	_ "time/tzdata" // this is needed to have LoadLocation when no filesystem tzdata is available
 
+	"github.com/open-policy-agent/opa/internal/cron"
 	"github.com/open-policy-agent/opa/v1/ast"
 	"github.com/open-policy-agent/opa/v1/topdown/builtins"
 	"github.com/open-policy-agent/opa/v1/topdown/durationparser"
@@ -349,30 +350,9 @@ func tzTime(a ast.Value) (t time.Time, lay string, err error) {
 				return time.Time{}, layout, err
 			}
 
-			tzName := string(tzVal)
-
-			switch tzName {
-			case "", "UTC":
-				// loc is already UTC
-
-			case "Local":
-				loc = time.Local
-
-			default:
-				var ok bool
-
-				tzCacheMutex.Lock()
-				loc, ok = tzCache[tzName]
-
-				if !ok {
-					loc, err = time.LoadLocation(tzName)
-					if err != nil {
-						tzCacheMutex.Unlock()
-						return time.Time{}, layout, err
-					}
-					tzCache[tzName] = loc
-				}
-				tzCacheMutex.Unlock()
+			loc, err = loadLocation(string(tzVal))
+			if err != nil {
+				return time.Time{}, layout, err
 			}
 		}
 
@@ -407,6 +387,249 @@ func tzTime(a ast.Value) (t time.Time, lay string, err error) {
 	return t, layout, nil
 }
 
+func loadLocation(tzName string) (*time.Location, error) {
+	switch tzName {
+	case "", "UTC":
+		return time.UTC, nil
+	case "Local":
+		return time.Local, nil
+	}
+
+	tzCacheMutex.Lock()
+	defer tzCacheMutex.Unlock()
+
+	if loc, ok := tzCache[tzName]; ok {
+		return loc, nil
+	}
+
+	loc, err := time.LoadLocation(tzName)
+	if err != nil {
+		return nil, err
+	}
+	tzCache[tzName] = loc
+
+	return loc, nil
+}
+
+// timeInLocation returns the time of the nanoseconds and timezone operands.
+func timeInLocation(ns ast.Value, nsPos int, tz ast.Value, tzPos int) (time.Time, error) {
+	n, err := builtins.NumberOperand(ns, nsPos)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	i64, acc := builtins.NumberToFloat(n).Int64()
+	if acc != big.Exact {
+		return time.Time{}, errors.New("timestamp too big")
+	}
+
+	tzName, err := builtins.StringOperand(tz, tzPos)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	loc, err := loadLocation(string(tzName))
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(0, i64).In(loc), nil
+}
+
+func builtinCronMatch(_ BuiltinContext, operands []*ast.Term, iter func(*ast.Term) error) error {
+	schedule, err := cronOperand(operands[0].Value, 1)
+	if err != nil {
+		return err
+	}
+
+	t, err := timeInLocation(operands[1].Value, 2, operands[2].Value, 3)
+	if err != nil {
+		return err
+	}
+
+	return iter(ast.InternedTerm(schedule.Match(t)))
+}
+
+func builtinNextCron(_ BuiltinContext, operands []*ast.Term, iter func(*ast.Term) error) error {
+	schedule, err := cronOperand(operands[0].Value, 1)
+	if err != nil {
+		return err
+	}
+
+	t, err := timeInLocation(operands[1].Value, 2, operands[2].Value, 3)
+	if err != nil {
+		return err
+	}
+
+	next, err := schedule.Next(t)
+	if errors.Is(err, cron.ErrNoActivation) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return toSafeUnixNano(next, iter)
+}
+
+func cronOperand(v ast.Value, pos int) (*cron.Schedule, error) {
+	expr, err := builtins.StringOperand(v, pos)
+	if err != nil {
+		return nil, err
+	}
+
+	return cron.Parse(string(expr))
+}
+
+func builtinInWindow(_ BuiltinContext, operands []*ast.Term, iter func(*ast.Term) error) error {
+	start, err := builtins.StringOperand(operands[0].Value, 1)
+	if err != nil {
+		return err
+	}
+
+	end, err := builtins.StringOperand(operands[1].Value, 2)
+	if err != nil {
+		return err
+	}
+
+	t, err := timeInLocation(operands[2].Value, 3, operands[3].Value, 4)
+	if err != nil {
+		return err
+	}
+
+	from, weekly, err := parseWindowTime(string(start))
+	if err != nil {
+		return err
+	}
+
+	to, weeklyEnd, err := parseWindowTime(string(end))
+	if err != nil {
+		return err
+	}
+
+	if weekly != weeklyEnd {
+		return errors.New("window start and end must both be either daily or weekly")
+	}
+
+	// seconds since the start of the day, or of the week, on the wall clock
+	hour, minute, second := t.Clock()
+	at := time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute + time.Duration(second)*time.Second
+	if weekly {
+		at += time.Duration(t.Weekday()) * 24 * time.Hour
+	}
+
+	// A window that ends where it starts is empty, as its end is exclusive.
+	if from <= to {
+		return iter(ast.InternedTerm(from <= at && at < to))
+	}
+
+	return iter(ast.InternedTerm(from <= at || at < to))
+}
+
+// parseWindowTime parses "HH:MM[:SS]", optionally prefixed by a weekday, to
+// the offset from the start of the day or week.
+func parseWindowTime(s string) (offset time.Duration, weekly bool, err error) {
+	clock := s
+	var day time.Weekday
+	if d, c, ok := strings.Cut(s, " "); ok {
+		if day, ok = parseWeekday(d); !ok {
+			return 0, false, fmt.Errorf("invalid window time %q: unknown weekday %q", s, d)
+		}
+		clock, weekly = strings.TrimSpace(c), true
+	}
+
+	var t time.Time
+	for _, layout := range []string{"15:04", "15:04:05"} {
+		if t, err = time.Parse(layout, clock); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid window time %q", s)
+	}
+
+	offset = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+	if weekly {
+		offset += time.Duration(day) * 24 * time.Hour
+	}
+
+	return offset, weekly, nil
+}
+
+// parseWeekday parses English weekday names, and their three letter
+// abbreviations.
+func parseWeekday(s string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if name := d.String(); strings.EqualFold(s, name) || strings.EqualFold(s, name[:3]) {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+func builtinTruncate(_ BuiltinContext, operands []*ast.Term, iter func(*ast.Term) error) error {
+	t, err := timeInLocation(operands[0].Value, 1, operands[2].Value, 3)
+	if err != nil {
+		return err
+	}
+
+	unit, err := builtins.StringOperand(operands[1].Value, 2)
+	if err != nil {
+		return err
+	}
+
+	y, m, d := t.Date()
+	loc := t.Location()
+
+	// The start of the minute and hour are found by going back on the wall
+	// clock, as they occur twice when the clock is set back.
+	var result time.Time
+	switch unit {
+	case "minute":
+		result = t.Add(-time.Duration(t.Second())*time.Second - time.Duration(t.Nanosecond()))
+	case "hour":
+		result = t.Add(-time.Duration(t.Minute())*time.Minute - time.Duration(t.Second())*time.Second - time.Duration(t.Nanosecond()))
+	case "day":
+		result = startOfDay(y, m, d, loc)
+	case "week":
+		// weeks start on Monday, as in ISO 8601
+		result = startOfDay(y, m, d-(int(t.Weekday())+6)%7, loc)
+	case "month":
+		result = startOfDay(y, m, 1, loc)
+	case "year":
+		result = startOfDay(y, time.January, 1, loc)
+	default:
+		return fmt.Errorf("unknown unit %q, expected one of minute, hour, day, week, month or year", string(unit))
+	}
+
+	return toSafeUnixNano(result, iter)
+}
+
+// startOfDay returns the first instant of the day on the wall clock. That's
+// midnight, unless a daylight saving time transition skips or repeats it.
+func startOfDay(year int, month time.Month, day int, loc *time.Location) time.Time {
+	t := time.Date(year, month, day, 0, 0, 0, 0, loc)
+	start, end := t.ZoneBounds()
+
+	// midnight is skipped, the day starts at the transition
+	if date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC); t.Day() != date.Day() {
+		return end
+	} else if t.Hour() != 0 || t.Minute() != 0 {
+		return start
+	}
+
+	// midnight is repeated, the day starts at its first occurrence
+	if !start.IsZero() {
+		_, before := start.Add(-time.Nanosecond).Zone()
+		_, after := t.Zone()
+		if first := t.Add(-time.Duration(before-after) * time.Second); first.Before(t) && first.Day() == t.Day() {
+			return first
+		}
+	}
+
+	return t
+}
+
 func int64ToJSONNumber(i int64) json.Number {
 	return json.Number(strconv.FormatInt(i, 10))
 }
@@ -422,4 +645,8 @@ func init() {
 	RegisterBuiltinFunc(ast.Weekday.Name, builtinWeekday)
 	RegisterBuiltinFunc(ast.AddDate.Name, builtinAddDate)
 	RegisterBuiltinFunc(ast.Diff.Name, builtinDiff)
+	RegisterBuiltinFunc(ast.CronMatch.Name, builtinCronMatch)
+	RegisterBuiltinFunc(ast.NextCron.Name, builtinNextCron)
+	RegisterBuiltinFunc(ast.InWindow.Name, builtinInWindow)
+	RegisterBuiltinFunc(ast.Truncate.Name, builtinTruncate)
 }
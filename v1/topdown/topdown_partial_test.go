@@ -3470,6 +3470,16 @@ func TestTopDownPartialEval(t *testing.T) {
 			},
 			wantQueries: []string{""}, // unconditional true
 		},
+		{
+			note:  "decision log annotations kept during PE",
+			query: "data.test.p = true",
+			modules: []string{`package test
+			p if {
+				opa.decision_log.annotate("role", "admin")
+				input.x = 1
+			}`},
+			wantQueries: []string{`opa.decision_log.annotate("role", "admin"); input.x = 1`},
+		},
 
 		{
 			note:  "default function, result not collected (non-false default value)",